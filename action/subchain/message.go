@@ -113,8 +113,8 @@ func (start *StartSubChain) OwnerAddress() string { return start.SrcAddr() }
 // OwnerPublicKey returns the owner public key, which is the wrapper of SrcPubkey
 func (start *StartSubChain) OwnerPublicKey() keypair.PublicKey { return start.SrcPubkey() }
 
-// Hash returns the hash of starting sub-chain message
-func (start *StartSubChain) Hash() hash.Hash32B {
+// ByteStream returns the byte representation of starting sub-chain message
+func (start *StartSubChain) ByteStream() []byte {
 	stream := []byte(reflect.TypeOf(start).String())
 	temp := make([]byte, 4)
 	enc.MachineEndian.PutUint32(stream, start.version)
@@ -145,7 +145,12 @@ func (start *StartSubChain) Hash() hash.Hash32B {
 	if start.gasPrice != nil && len(start.gasPrice.Bytes()) > 0 {
 		stream = append(stream, start.gasPrice.Bytes()...)
 	}
-	return blake2b.Sum256(stream)
+	return stream
+}
+
+// Hash returns the hash of starting sub-chain message
+func (start *StartSubChain) Hash() hash.Hash32B {
+	return blake2b.Sum256(start.ByteStream())
 }
 
 // Proto converts start sub-chain action into a proto message
@@ -179,6 +184,9 @@ func (start *StartSubChain) Proto() *iproto.ActionPb {
 	return act
 }
 
+// ConvertToActionPb converts start sub-chain action into a proto message, which is the wrapper of Proto
+func (start *StartSubChain) ConvertToActionPb() *iproto.ActionPb { return start.Proto() }
+
 // StopSubChain represents stop sub-chain message
 type StopSubChain struct {
 	action
@@ -258,3 +266,13 @@ func (start *StartSubChain) IntrinsicGas() (uint64, error) {
 	// TODO: implement intrinsic gas calculation
 	return 0, errcode.ErrNotImplemented
 }
+
+// Cost returns the total cost of an action
+func (start *StartSubChain) Cost() (*big.Int, error) {
+	intrinsicGas, err := start.IntrinsicGas()
+	if err != nil {
+		return nil, err
+	}
+	fee := big.NewInt(0).Mul(start.GasPrice(), big.NewInt(0).SetUint64(intrinsicGas))
+	return fee, nil
+}
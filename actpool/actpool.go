@@ -7,10 +7,16 @@
 package actpool
 
 import (
+	"container/heap"
+	"context"
 	"fmt"
+	"math/big"
 	"sync"
+	"time"
 
+	"github.com/facebookgo/clock"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/iotexproject/iotex-core/blockchain"
 	"github.com/iotexproject/iotex-core/blockchain/action"
@@ -18,6 +24,9 @@ import (
 	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/lifecycle"
+	"github.com/iotexproject/iotex-core/pkg/routine"
+	"github.com/iotexproject/iotex-core/proto"
 )
 
 const (
@@ -48,12 +57,40 @@ var (
 	ErrHash = errors.New("invalid hash")
 )
 
+const (
+	// evictedByExpiry is the eviction reason of a non-pending action staying in the pool longer than expiry
+	evictedByExpiry = "expired"
+	// evictedByPrice is the eviction reason of a non-pending action paying the lowest gas price in the full pool
+	evictedByPrice = "lowest_price"
+	// evictedByAge is the eviction reason of the oldest non-pending action in the full pool
+	evictedByAge = "oldest"
+	// evictionQueueSlack is the number of dropped entries tolerated in the eviction queue of a small pool
+	evictionQueueSlack = 64
+)
+
+var evictionMtc = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "iotex_actpool_eviction",
+		Help: "Actpool eviction counter.",
+	},
+	[]string{"reason"},
+)
+
+func init() {
+	prometheus.MustRegister(evictionMtc)
+}
+
 // ActPool is the interface of actpool
 type ActPool interface {
+	lifecycle.StartStopper
+
 	// Reset resets actpool state
 	Reset()
-	// PickActs returns all currently accepted transfers and votes in actpool
+	// PickActs returns all currently accepted transfers and votes in actpool, ordered by gas price across accounts
+	// and by nonce within an account
 	PickActs() ([]*action.Transfer, []*action.Vote, []*action.Execution, []action.Action)
+	// PickActsWithLimit is the same as PickActs, but picks at most the given number of actions. Zero means no limit
+	PickActsWithLimit(limit uint64) ([]*action.Transfer, []*action.Vote, []*action.Execution, []action.Action)
 	// AddTsf adds an transfer into the pool after passing validation
 	AddTsf(tsf *action.Transfer) error
 	// AddVote adds a vote into the pool after passing validation
@@ -86,7 +123,16 @@ type actPool struct {
 	bc          blockchain.Blockchain
 	accountActs map[string]ActQueue
 	allActions  map[hash.Hash32B]action.Action
-	validators  []ActionValidator
+	// timestamps records when each action was accepted into the pool
+	timestamps map[hash.Hash32B]time.Time
+	// evictable orders the actions in pool by the eviction policy. The entries of the actions which have left the pool
+	// or become pending are dropped lazily when they reach the top, and the queue is rebuilt on reset.
+	evictable  *evictionQueue
+	validators []ActionValidator
+	clk        clock.Clock
+	// journal persists the actions in pool across restarts if it is enabled
+	journal     *actJournal
+	compactTask *routine.RecurringTask
 }
 
 // NewActPool constructs a new actpool
@@ -99,11 +145,60 @@ func NewActPool(bc blockchain.Blockchain, cfg config.ActPool, validators ...Acti
 		bc:          bc,
 		accountActs: make(map[string]ActQueue),
 		allActions:  make(map[hash.Hash32B]action.Action),
+		timestamps:  make(map[hash.Hash32B]time.Time),
 		validators:  validators,
+		clk:         clock.New(),
 	}
+	ap.evictable = &evictionQueue{evictsBefore: ap.evictsBefore}
 	return ap, nil
 }
 
+// Start replays the actions recorded in the journal through the normal validation if the journal is enabled, and
+// starts compacting the journal periodically
+func (ap *actPool) Start(ctx context.Context) error {
+	if ap.cfg.JournalPath == "" {
+		return nil
+	}
+	journal := newActJournal(ap.cfg.JournalPath)
+	if err := journal.load(ap.addActionPb); err != nil {
+		return errors.Wrap(err, "error when loading actpool journal")
+	}
+
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+	// Rewrite the journal with the actions which are still valid
+	if err := journal.rotate(ap.allActs()); err != nil {
+		return errors.Wrap(err, "error when rotating actpool journal")
+	}
+	ap.journal = journal
+	if ap.cfg.JournalCompactInterval > 0 {
+		ap.compactTask = routine.NewRecurringTask(ap.compactJournal, ap.cfg.JournalCompactInterval)
+		if err := ap.compactTask.Start(ctx); err != nil {
+			return errors.Wrap(err, "error when starting actpool journal compaction")
+		}
+	}
+	return nil
+}
+
+// Stop stops compacting the journal and closes it
+func (ap *actPool) Stop(ctx context.Context) error {
+	if ap.compactTask != nil {
+		if err := ap.compactTask.Stop(ctx); err != nil {
+			return errors.Wrap(err, "error when stopping actpool journal compaction")
+		}
+	}
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+	if ap.journal == nil {
+		return nil
+	}
+	if err := ap.journal.close(); err != nil {
+		return errors.Wrap(err, "error when closing actpool journal")
+	}
+	ap.journal = nil
+	return nil
+}
+
 // Reset resets actpool state
 // Step I: remove all the actions in actpool that have already been committed to block
 // Step II: update pending balance of each account if it still exists in pool
@@ -118,6 +213,8 @@ func (ap *actPool) Reset() {
 
 	// Remove confirmed actions in actpool
 	ap.removeConfirmedActs()
+	// Remove non-pending actions staying in actpool for too long
+	ap.removeExpiredActs()
 	for from, queue := range ap.accountActs {
 		// Reset pending balance for each account
 		balance, err := ap.bc.Balance(from)
@@ -138,10 +235,22 @@ func (ap *actPool) Reset() {
 		queue.SetPendingNonce(pendingNonce)
 		ap.updateAccount(from)
 	}
+	// Pending actions may become non-pending after the pending nonces are reset
+	ap.rebuildEvictionQueue()
 }
 
-// PickActs returns all currently accepted transfers and votes for all accounts
+// PickActs returns all currently accepted transfers and votes for all accounts. Accounts are picked in the order of
+// the gas price of their next pending action, so that the actions paying more are picked first while the nonce order
+// within an account is kept.
 func (ap *actPool) PickActs() ([]*action.Transfer, []*action.Vote, []*action.Execution, []action.Action) {
+	return ap.PickActsWithLimit(ap.cfg.MaxNumActsToPick)
+}
+
+// PickActsWithLimit picks the actions in the same order as PickActs, and stops once the given number of actions are
+// picked. As each account's actions are picked in the nonce order, the picked ones are still executable.
+func (ap *actPool) PickActsWithLimit(
+	limit uint64,
+) ([]*action.Transfer, []*action.Vote, []*action.Execution, []action.Action) {
 	ap.mutex.Lock()
 	defer ap.mutex.Unlock()
 
@@ -150,26 +259,38 @@ func (ap *actPool) PickActs() ([]*action.Transfer, []*action.Vote, []*action.Exe
 	votes := make([]*action.Vote, 0)
 	executions := make([]*action.Execution, 0)
 	actions := make([]action.Action, 0)
-	for _, queue := range ap.accountActs {
-		for _, act := range queue.PendingActs() {
-			switch act.(type) {
-			case *action.Transfer:
-				transfers = append(transfers, act.(*action.Transfer))
-			case *action.Vote:
-				votes = append(votes, act.(*action.Vote))
-			case *action.Execution:
-				executions = append(executions, act.(*action.Execution))
-
-			default:
-				actions = append(actions, act)
-			}
-			numActs++
-			if ap.cfg.MaxNumActsToPick > 0 && numActs >= ap.cfg.MaxNumActsToPick {
-				logger.Debug().
-					Uint64("limit", ap.cfg.MaxNumActsToPick).
-					Msg("reach the max number of actions to pick")
-				return transfers, votes, executions, actions
-			}
+	pq := make(priceQueue, 0, len(ap.accountActs))
+	for sender, queue := range ap.accountActs {
+		if acts := queue.PendingActs(); len(acts) > 0 {
+			pq = append(pq, &pendingActsCursor{sender: sender, acts: acts})
+		}
+	}
+	heap.Init(&pq)
+	for pq.Len() > 0 {
+		cursor := pq[0]
+		act := cursor.acts[0]
+		if cursor.acts = cursor.acts[1:]; len(cursor.acts) > 0 {
+			heap.Fix(&pq, 0)
+		} else {
+			heap.Pop(&pq)
+		}
+		switch act.(type) {
+		case *action.Transfer:
+			transfers = append(transfers, act.(*action.Transfer))
+		case *action.Vote:
+			votes = append(votes, act.(*action.Vote))
+		case *action.Execution:
+			executions = append(executions, act.(*action.Execution))
+
+		default:
+			actions = append(actions, act)
+		}
+		numActs++
+		if limit > 0 && numActs >= limit {
+			logger.Debug().
+				Uint64("limit", limit).
+				Msg("reach the max number of actions to pick")
+			return transfers, votes, executions, actions
 		}
 	}
 	return transfers, votes, executions, actions
@@ -196,13 +317,6 @@ func (ap *actPool) AddTsf(tsf *action.Transfer) error {
 			Msg("Rejecting invalid transfer")
 		return err
 	}
-	// Reject transfer if pool space is full
-	if uint64(len(ap.allActions)) >= ap.cfg.MaxNumActsPerPool {
-		logger.Warn().
-			Hex("hash", hash[:]).
-			Msg("Rejecting transfer due to insufficient space")
-		return errors.Wrapf(ErrActPool, "insufficient space for transfer")
-	}
 
 	return ap.enqueueAction(tsf.Sender(), tsf, hash, tsf.Nonce())
 }
@@ -228,13 +342,6 @@ func (ap *actPool) AddVote(vote *action.Vote) error {
 			Msg("Rejecting invalid vote")
 		return err
 	}
-	// Reject vote if pool space is full
-	if uint64(len(ap.allActions)) >= ap.cfg.MaxNumActsPerPool {
-		logger.Warn().
-			Hex("hash", hash[:]).
-			Msg("Rejecting vote due to insufficient space")
-		return errors.Wrapf(ErrActPool, "insufficient space for vote")
-	}
 
 	return ap.enqueueAction(vote.Voter(), vote, hash, vote.Nonce())
 }
@@ -259,13 +366,6 @@ func (ap *actPool) AddExecution(exec *action.Execution) error {
 			Msg("Rejecting invalid execution")
 		return err
 	}
-	// Reject execution if pool space is full
-	if uint64(len(ap.allActions)) >= ap.cfg.MaxNumActsPerPool {
-		logger.Warn().
-			Hex("hash", hash[:]).
-			Msg("Rejecting execution due to insufficient space")
-		return errors.Wrapf(ErrActPool, "insufficient space for execution")
-	}
 
 	return ap.enqueueAction(exec.Executor(), exec, hash, exec.Nonce())
 }
@@ -273,10 +373,6 @@ func (ap *actPool) AddExecution(exec *action.Execution) error {
 func (ap *actPool) Add(act action.Action) error {
 	ap.mutex.Lock()
 	defer ap.mutex.Unlock()
-	// Reject action if pool space is full
-	if uint64(len(ap.allActions)) >= ap.cfg.MaxNumActsPerPool {
-		return errors.Wrapf(ErrActPool, "insufficient space for execution")
-	}
 	hash := act.Hash()
 	// Reject action if it already exists in pool
 	if ap.allActions[hash] != nil {
@@ -557,11 +653,8 @@ func (ap *actPool) enqueueAction(sender string, act action.Action, hash hash.Has
 		queue.SetPendingBalance(balance)
 	}
 	if queue.Overlaps(act) {
-		// Nonce already exists
-		logger.Error().
-			Hex("hash", hash[:]).
-			Msg("Rejecting action because replacement action is not supported")
-		return errors.Wrapf(ErrNonce, "duplicate nonce")
+		// Nonce already exists, try to replace the existing action
+		return ap.replaceAction(sender, queue, act, hash)
 	}
 
 	if actNonce-queue.StartNonce() >= ap.cfg.MaxNumActsPerAcct {
@@ -609,6 +702,15 @@ func (ap *actPool) enqueueAction(sender string, act action.Action, hash hash.Has
 		}
 	}
 
+	// Evict only once the action is known to be accepted, so that no action is evicted in favor of a rejected one
+	if !ap.makeRoom(act) {
+		logger.Warn().
+			Hex("hash", hash[:]).
+			Msg("Rejecting action due to insufficient space")
+		return errors.Wrapf(ErrActPool, "insufficient space for action")
+	}
+	// The queue is removed from pool if its only action has just been evicted
+	ap.accountActs[sender] = queue
 	err := queue.Put(act)
 	if err != nil {
 		logger.Warn().
@@ -618,6 +720,9 @@ func (ap *actPool) enqueueAction(sender string, act action.Action, hash hash.Has
 		return errors.Wrap(err, "cannot put act into ActQueue")
 	}
 	ap.allActions[hash] = act
+	ap.timestamps[hash] = ap.clk.Now()
+	ap.trackEviction(sender, act, hash)
+	ap.journalAct(act)
 	// If the pending nonce equals this nonce, update queue
 	nonce := queue.PendingNonce()
 	if actNonce == nonce {
@@ -626,8 +731,78 @@ func (ap *actPool) enqueueAction(sender string, act action.Action, hash hash.Has
 	return nil
 }
 
+// replaceAction substitutes the queued action of the same nonce with the given one, if the given one bumps the gas
+// price by at least the configured percentage and the account can afford it
+func (ap *actPool) replaceAction(sender string, queue ActQueue, act action.Action, hash hash.Hash32B) error {
+	old := queue.ActByNonce(act.Nonce())
+	minPrice := new(big.Int).Mul(gasPrice(old), big.NewInt(int64(100+ap.cfg.ReplacementPriceBump)))
+	minPrice.Div(minPrice, big.NewInt(100))
+	if gasPrice(act).Cmp(gasPrice(old)) <= 0 || gasPrice(act).Cmp(minPrice) < 0 {
+		logger.Error().
+			Hex("hash", hash[:]).
+			Uint64("nonce", act.Nonce()).
+			Msg("Rejecting replacement action due to insufficient gas price bump")
+		return errors.Wrapf(ErrNonce, "duplicate nonce with underpriced replacement")
+	}
+	cost, err := act.Cost()
+	if err != nil {
+		logger.Error().Err(err).Msg("Error when replacing action")
+		return errors.Wrap(err, "failed to get cost of replacement action")
+	}
+	oldCost, err := old.Cost()
+	if err != nil {
+		logger.Error().Err(err).Msg("Error when replacing action")
+		return errors.Wrap(err, "failed to get cost of replaced action")
+	}
+	// The cost of a pending action has already been deducted from the pending balance
+	isPending := act.Nonce() < queue.PendingNonce()
+	balance := new(big.Int).Set(queue.PendingBalance())
+	if isPending {
+		balance.Add(balance, oldCost)
+	}
+	if balance.Cmp(cost) < 0 {
+		logger.Warn().
+			Hex("hash", hash[:]).
+			Msg("Rejecting replacement action due to insufficient balance")
+		return errors.Wrapf(ErrBalance, "insufficient balance for replacement action")
+	}
+
+	if _, err := queue.Replace(act); err != nil {
+		logger.Warn().
+			Hex("hash", hash[:]).
+			Err(err).
+			Msg("cannot replace act in ActQueue")
+		return errors.Wrap(err, "cannot replace act in ActQueue")
+	}
+	oldHash := old.Hash()
+	delete(ap.allActions, oldHash)
+	delete(ap.timestamps, oldHash)
+	ap.allActions[hash] = act
+	ap.timestamps[hash] = ap.clk.Now()
+	ap.trackEviction(sender, act, hash)
+	ap.journalAct(act)
+	logger.Debug().
+		Hex("hash", hash[:]).
+		Hex("replacedHash", oldHash[:]).
+		Msg("Replaced action")
+	if isPending {
+		queue.SetPendingBalance(balance.Sub(balance, cost))
+	}
+	if act.Nonce() == queue.PendingNonce() {
+		ap.updateAccount(sender)
+	}
+	return nil
+}
+
 // removeConfirmedActs removes processed (committed to block) actions from pool
 func (ap *actPool) removeConfirmedActs() {
+	numConfirmed := 0
+	defer func() {
+		// Prune the confirmed actions from journal
+		if numConfirmed > 0 {
+			ap.rotateJournal()
+		}
+	}()
 	for from, queue := range ap.accountActs {
 		confirmedNonce, err := ap.bc.Nonce(from)
 		if err != nil {
@@ -638,6 +813,7 @@ func (ap *actPool) removeConfirmedActs() {
 		// Remove all actions that are committed to new block
 		acts := queue.FilterNonce(pendingNonce)
 		ap.removeInvalidActs(acts)
+		numConfirmed += len(acts)
 
 		// Delete the queue entry if it becomes empty
 		if queue.Empty() {
@@ -646,6 +822,184 @@ func (ap *actPool) removeConfirmedActs() {
 	}
 }
 
+// addActionPb adds an action recorded in journal into pool after passing validation
+func (ap *actPool) addActionPb(actPb *iproto.ActionPb) error {
+	if pbTsf := actPb.GetTransfer(); pbTsf != nil {
+		tsf := &action.Transfer{}
+		tsf.ConvertFromActionPb(actPb)
+		return ap.AddTsf(tsf)
+	}
+	if pbVote := actPb.GetVote(); pbVote != nil {
+		vote := &action.Vote{}
+		vote.ConvertFromActionPb(actPb)
+		return ap.AddVote(vote)
+	}
+	if pbExecution := actPb.GetExecution(); pbExecution != nil {
+		execution := &action.Execution{}
+		execution.ConvertFromActionPb(actPb)
+		return ap.AddExecution(execution)
+	}
+	act := action.NewActionFromProto(actPb)
+	if act == nil {
+		return errors.Wrap(ErrActPool, "unsupported action in journal")
+	}
+	return ap.Add(act)
+}
+
+// allActs returns all the actions in pool, which are ordered by nonce for each account
+func (ap *actPool) allActs() []action.Action {
+	acts := make([]action.Action, 0, len(ap.allActions))
+	for _, queue := range ap.accountActs {
+		acts = append(acts, queue.AllActs()...)
+	}
+	return acts
+}
+
+// journalAct appends a newly accepted action to journal if the journal is enabled
+func (ap *actPool) journalAct(act action.Action) {
+	if ap.journal == nil {
+		return
+	}
+	if err := ap.journal.insert(act); err != nil {
+		logger.Error().Err(err).Msg("Error when writing action into actpool journal")
+	}
+}
+
+// rotateJournal regenerates the journal from the actions in pool, dropping the records of the actions which have left
+// the pool
+func (ap *actPool) rotateJournal() {
+	if ap.journal == nil {
+		return
+	}
+	if err := ap.journal.rotate(ap.allActs()); err != nil {
+		logger.Error().Err(err).Msg("Error when rotating actpool journal")
+	}
+}
+
+// compactJournal periodically drops the records of the actions which have been evicted, expired or replaced
+func (ap *actPool) compactJournal() {
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+
+	ap.rotateJournal()
+}
+
+// removeExpiredActs removes non-pending actions which have stayed in pool longer than the action expiry
+func (ap *actPool) removeExpiredActs() {
+	if ap.cfg.ActionExpiry <= 0 {
+		return
+	}
+	now := ap.clk.Now()
+	for from, queue := range ap.accountActs {
+		for _, act := range queue.AllActs() {
+			if act.Nonce() < queue.PendingNonce() {
+				continue
+			}
+			hash := act.Hash()
+			if now.Sub(ap.timestamps[hash]) < ap.cfg.ActionExpiry {
+				continue
+			}
+			ap.evictAct(from, act, evictedByExpiry)
+		}
+	}
+}
+
+// makeRoom checks whether there is space in pool for the given action. If the pool is full, it evicts a non-pending
+// action according to the eviction policy. It returns false if no action can be evicted in favor of the given one.
+// Expired actions are removed on reset rather than here, which would scan the whole pool on every add.
+func (ap *actPool) makeRoom(act action.Action) bool {
+	if uint64(len(ap.allActions)) < ap.cfg.MaxNumActsPerPool {
+		return true
+	}
+	from, victim := ap.evictionCandidate()
+	if victim == nil {
+		return false
+	}
+	if ap.cfg.EvictionPolicy == config.OldestEviction {
+		ap.evictAct(from, victim, evictedByAge)
+		return true
+	}
+	// Only evict an action paying less than the given one
+	if gasPrice(act).Cmp(gasPrice(victim)) <= 0 {
+		return false
+	}
+	ap.evictAct(from, victim, evictedByPrice)
+	return true
+}
+
+// evictionCandidate returns the non-pending action to be evicted first according to the eviction policy, along with
+// its sender. The entries of the actions which have left the pool or become pending are dropped on the way.
+func (ap *actPool) evictionCandidate() (string, action.Action) {
+	for ap.evictable.Len() > 0 {
+		item := ap.evictable.items[0]
+		queue := ap.accountActs[item.sender]
+		if ap.allActions[item.hash] == nil || !ap.timestamps[item.hash].Equal(item.timestamp) || queue == nil ||
+			item.act.Nonce() < queue.PendingNonce() {
+			heap.Pop(ap.evictable)
+			continue
+		}
+		return item.sender, item.act
+	}
+	return "", nil
+}
+
+// trackEviction adds the action accepted into pool to the eviction queue
+func (ap *actPool) trackEviction(sender string, act action.Action, hash hash.Hash32B) {
+	// Rebuild the queue once the dropped entries outnumber the actions in pool, which keeps it linear to the pool size
+	if ap.evictable.Len() > 2*len(ap.allActions)+evictionQueueSlack {
+		ap.rebuildEvictionQueue()
+		return
+	}
+	heap.Push(ap.evictable, &evictionItem{sender: sender, act: act, hash: hash, timestamp: ap.timestamps[hash]})
+}
+
+// rebuildEvictionQueue rebuilds the eviction queue from the actions in pool
+func (ap *actPool) rebuildEvictionQueue() {
+	items := make([]*evictionItem, 0, len(ap.allActions))
+	for sender, queue := range ap.accountActs {
+		for _, act := range queue.AllActs() {
+			hash := act.Hash()
+			items = append(items, &evictionItem{sender: sender, act: act, hash: hash, timestamp: ap.timestamps[hash]})
+		}
+	}
+	ap.evictable.items = items
+	heap.Init(ap.evictable)
+}
+
+// evictsBefore returns whether action a should be evicted before action b according to the eviction policy
+func (ap *actPool) evictsBefore(a action.Action, aTime time.Time, b action.Action, bTime time.Time) bool {
+	cmp := gasPrice(a).Cmp(gasPrice(b))
+	if ap.cfg.EvictionPolicy == config.OldestEviction {
+		if !aTime.Equal(bTime) {
+			return aTime.Before(bTime)
+		}
+		return cmp < 0
+	}
+	if cmp != 0 {
+		return cmp < 0
+	}
+	return aTime.Before(bTime)
+}
+
+// evictAct removes a non-pending action from its account queue and the pool
+func (ap *actPool) evictAct(from string, act action.Action, reason string) {
+	queue := ap.accountActs[from]
+	queue.Remove(act.Nonce())
+	hash := act.Hash()
+	delete(ap.allActions, hash)
+	delete(ap.timestamps, hash)
+	logger.Debug().
+		Hex("hash", hash[:]).
+		Str("reason", reason).
+		Msg("Evicted action")
+	evictionMtc.WithLabelValues(reason).Inc()
+
+	// Delete the queue entry if it becomes empty
+	if queue.Empty() {
+		delete(ap.accountActs, from)
+	}
+}
+
 func (ap *actPool) removeInvalidActs(acts []action.Action) {
 	for _, act := range acts {
 		hash := act.Hash()
@@ -653,6 +1007,7 @@ func (ap *actPool) removeInvalidActs(acts []action.Action) {
 			Hex("hash", hash[:]).
 			Msg("Removed invalidated action")
 		delete(ap.allActions, hash)
+		delete(ap.timestamps, hash)
 	}
 }
 
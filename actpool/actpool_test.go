@@ -12,7 +12,9 @@ import (
 	"math/big"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/facebookgo/clock"
 	"github.com/golang/mock/gomock"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
@@ -432,6 +434,114 @@ func TestActPool_PickActs(t *testing.T) {
 	})
 }
 
+func TestActPool_PickActsByGasPrice(t *testing.T) {
+	require := require.New(t)
+	bc := blockchain.NewBlockchain(&config.Default, blockchain.InMemStateFactoryOption(), blockchain.InMemDaoOption())
+	require.NoError(bc.Start(context.Background()))
+	_, err := bc.CreateState(addr1.RawAddress, uint64(100000))
+	require.NoError(err)
+	_, err = bc.CreateState(addr2.RawAddress, uint64(100000))
+	require.NoError(err)
+	_, err = bc.GetFactory().RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.Nil(bc.GetFactory().Commit(nil))
+	// Create actpool
+	Ap, err := NewActPool(bc, getActPoolCfg())
+	require.NoError(err)
+	ap, ok := Ap.(*actPool)
+	require.True(ok)
+
+	tsf1, err := testutil.SignedTransfer(addr1, addr1, uint64(1), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	tsf2, err := testutil.SignedTransfer(addr1, addr1, uint64(2), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	tsf3, err := testutil.SignedTransfer(addr2, addr2, uint64(1), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(3))
+	require.NoError(err)
+	tsf4, err := testutil.SignedTransfer(addr2, addr2, uint64(2), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	require.NoError(ap.AddTsf(tsf1))
+	require.NoError(ap.AddTsf(tsf2))
+	require.NoError(ap.AddTsf(tsf3))
+	require.NoError(ap.AddTsf(tsf4))
+
+	// Accounts are ordered by the gas price of their next pending action, and nonces are ordered within an account
+	pickedTsfs, _, _, _ := ap.PickActs()
+	require.Equal([]*action.Transfer{tsf3, tsf1, tsf2, tsf4}, pickedTsfs)
+
+	ap.cfg.MaxNumActsToPick = 2
+	pickedTsfs, _, _, _ = ap.PickActs()
+	require.Equal([]*action.Transfer{tsf3, tsf1}, pickedTsfs)
+
+	// The given limit overrides the configured one
+	pickedTsfs, _, _, _ = ap.PickActsWithLimit(3)
+	require.Equal([]*action.Transfer{tsf3, tsf1, tsf2}, pickedTsfs)
+	pickedTsfs, _, _, _ = ap.PickActsWithLimit(0)
+	require.Equal([]*action.Transfer{tsf3, tsf1, tsf2, tsf4}, pickedTsfs)
+}
+
+func TestActPool_ReplaceAct(t *testing.T) {
+	require := require.New(t)
+	bc := blockchain.NewBlockchain(&config.Default, blockchain.InMemStateFactoryOption(), blockchain.InMemDaoOption())
+	require.NoError(bc.Start(context.Background()))
+	_, err := bc.CreateState(addr1.RawAddress, uint64(100000))
+	require.NoError(err)
+	_, err = bc.GetFactory().RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.Nil(bc.GetFactory().Commit(nil))
+	// Create actpool
+	apConfig := getActPoolCfg()
+	apConfig.ReplacementPriceBump = 50
+	Ap, err := NewActPool(bc, apConfig)
+	require.NoError(err)
+	ap, ok := Ap.(*actPool)
+	require.True(ok)
+
+	tsf1, err := testutil.SignedTransfer(addr1, addr1, uint64(1), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(2))
+	require.NoError(err)
+	tsf2, err := testutil.SignedTransfer(addr1, addr1, uint64(2), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	require.NoError(ap.AddTsf(tsf1))
+	require.NoError(ap.AddTsf(tsf2))
+	pBalance, _ := ap.getPendingBalance(addr1.RawAddress)
+	require.Equal(uint64(79980), pBalance.Uint64())
+
+	// Case I: Gas price is not bumped enough
+	underpricedTsf, err := testutil.SignedTransfer(addr1, addr1, uint64(1), big.NewInt(20),
+		[]byte{}, uint64(100000), big.NewInt(2))
+	require.NoError(err)
+	err = ap.AddTsf(underpricedTsf)
+	require.Equal(ErrNonce, errors.Cause(err))
+	// Case II: Insufficient balance for replacement
+	overBalTsf, err := testutil.SignedTransfer(addr1, addr1, uint64(1), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(100))
+	require.NoError(err)
+	err = ap.AddTsf(overBalTsf)
+	require.Equal(ErrBalance, errors.Cause(err))
+	// Case III: Replacement succeeds
+	replaceTsf, err := testutil.SignedTransfer(addr1, addr1, uint64(1), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(3))
+	require.NoError(err)
+	require.NoError(ap.AddTsf(replaceTsf))
+	require.Equal(uint64(2), ap.GetSize())
+	_, err = ap.GetActionByHash(tsf1.Hash())
+	require.Equal(ErrHash, errors.Cause(err))
+	act, err := ap.GetActionByHash(replaceTsf.Hash())
+	require.NoError(err)
+	require.Equal(replaceTsf, act)
+	pBalance, _ = ap.getPendingBalance(addr1.RawAddress)
+	require.Equal(uint64(69980), pBalance.Uint64())
+	pNonce, _ := ap.getPendingNonce(addr1.RawAddress)
+	require.Equal(uint64(3), pNonce)
+	pickedTsfs, _, _, _ := ap.PickActs()
+	require.Equal([]*action.Transfer{replaceTsf, tsf2}, pickedTsfs)
+}
+
 func TestActPool_removeConfirmedActs(t *testing.T) {
 	require := require.New(t)
 	bc := blockchain.NewBlockchain(&config.Default, blockchain.InMemStateFactoryOption(), blockchain.InMemDaoOption())
@@ -1063,6 +1173,147 @@ func TestActPool_GetSize(t *testing.T) {
 	require.Equal(uint64(0), ap.GetSize())
 }
 
+func TestActPool_removeExpiredActs(t *testing.T) {
+	require := require.New(t)
+	bc := blockchain.NewBlockchain(&config.Default, blockchain.InMemStateFactoryOption(), blockchain.InMemDaoOption())
+	require.NoError(bc.Start(context.Background()))
+	_, err := bc.CreateState(addr1.RawAddress, uint64(100))
+	require.NoError(err)
+	_, err = bc.GetFactory().RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.Nil(bc.GetFactory().Commit(nil))
+	// Create actpool
+	apConfig := getActPoolCfg()
+	apConfig.ActionExpiry = time.Minute
+	Ap, err := NewActPool(bc, apConfig)
+	require.NoError(err)
+	ap, ok := Ap.(*actPool)
+	require.True(ok)
+	clk := clock.NewMock()
+	ap.clk = clk
+
+	tsf1, err := testutil.SignedTransfer(addr1, addr1, uint64(1), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	tsf3, err := testutil.SignedTransfer(addr1, addr1, uint64(3), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	tsf4, err := testutil.SignedTransfer(addr1, addr1, uint64(4), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	require.NoError(ap.AddTsf(tsf1))
+	require.NoError(ap.AddTsf(tsf3))
+	clk.Add(30 * time.Second)
+	require.NoError(ap.AddTsf(tsf4))
+
+	clk.Add(40 * time.Second)
+	ap.removeExpiredActs()
+	// Pending action never expires, while the non-pending action added a minute ago expires
+	require.Equal(uint64(2), ap.GetSize())
+	_, err = ap.GetActionByHash(tsf1.Hash())
+	require.NoError(err)
+	_, err = ap.GetActionByHash(tsf3.Hash())
+	require.Equal(ErrHash, errors.Cause(err))
+
+	clk.Add(30 * time.Second)
+	ap.removeExpiredActs()
+	require.Equal(uint64(1), ap.GetSize())
+	require.Equal([]action.Action{tsf1}, ap.GetUnconfirmedActs(addr1.RawAddress))
+}
+
+func TestActPool_Eviction(t *testing.T) {
+	require := require.New(t)
+	bc := blockchain.NewBlockchain(&config.Default, blockchain.InMemStateFactoryOption(), blockchain.InMemDaoOption())
+	require.NoError(bc.Start(context.Background()))
+	_, err := bc.CreateState(addr1.RawAddress, uint64(100000))
+	require.NoError(err)
+	_, err = bc.CreateState(addr2.RawAddress, uint64(100000))
+	require.NoError(err)
+	_, err = bc.GetFactory().RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.Nil(bc.GetFactory().Commit(nil))
+
+	tsf1, err := testutil.SignedTransfer(addr1, addr1, uint64(1), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	tsf2, err := testutil.SignedTransfer(addr1, addr1, uint64(3), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	tsf3, err := testutil.SignedTransfer(addr2, addr2, uint64(2), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(2))
+	require.NoError(err)
+	tsf4, err := testutil.SignedTransfer(addr2, addr2, uint64(1), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	tsf5, err := testutil.SignedTransfer(addr2, addr2, uint64(1), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(3))
+	require.NoError(err)
+
+	createActPool := func(policy string) (*actPool, *clock.Mock) {
+		apConfig := getActPoolCfg()
+		apConfig.MaxNumActsPerPool = 3
+		apConfig.MaxNumActsPerAcct = 3
+		apConfig.EvictionPolicy = policy
+		Ap, err := NewActPool(bc, apConfig)
+		require.NoError(err)
+		ap, ok := Ap.(*actPool)
+		require.True(ok)
+		clk := clock.NewMock()
+		ap.clk = clk
+		require.NoError(ap.AddTsf(tsf1))
+		clk.Add(time.Second)
+		require.NoError(ap.AddTsf(tsf3))
+		clk.Add(time.Second)
+		require.NoError(ap.AddTsf(tsf2))
+		clk.Add(time.Second)
+		return ap, clk
+	}
+
+	t.Run("lowest-price", func(t *testing.T) {
+		ap, _ := createActPool(config.LowestPriceEviction)
+		// No action is evicted in favor of a rejected one
+		tsf6, err := testutil.SignedTransfer(addr2, addr2, uint64(5), big.NewInt(10),
+			[]byte{}, uint64(100000), big.NewInt(5))
+		require.NoError(err)
+		err = ap.AddTsf(tsf6)
+		require.Equal(ErrNonce, errors.Cause(err))
+		require.Equal(uint64(3), ap.GetSize())
+		// The incoming action doesn't pay more than any non-pending action
+		err := ap.AddTsf(tsf4)
+		require.Equal(ErrActPool, errors.Cause(err))
+		// The non-pending action of the lowest gas price is evicted
+		require.NoError(ap.AddTsf(tsf5))
+		require.Equal(uint64(3), ap.GetSize())
+		_, err = ap.GetActionByHash(tsf2.Hash())
+		require.Equal(ErrHash, errors.Cause(err))
+		pNonce, _ := ap.getPendingNonce(addr2.RawAddress)
+		require.Equal(uint64(3), pNonce)
+	})
+	t.Run("replacement", func(t *testing.T) {
+		ap, _ := createActPool(config.LowestPriceEviction)
+		// The replacement takes the place of the replaced action without evicting any other one
+		tsf6, err := testutil.SignedTransfer(addr1, addr1, uint64(3), big.NewInt(10),
+			[]byte{}, uint64(100000), big.NewInt(2))
+		require.NoError(err)
+		require.NoError(ap.AddTsf(tsf6))
+		require.Equal(uint64(3), ap.GetSize())
+		for _, tsf := range []*action.Transfer{tsf1, tsf3, tsf6} {
+			_, err = ap.GetActionByHash(tsf.Hash())
+			require.NoError(err)
+		}
+	})
+	t.Run("oldest", func(t *testing.T) {
+		ap, _ := createActPool(config.OldestEviction)
+		// The oldest non-pending action is evicted regardless of gas price
+		require.NoError(ap.AddTsf(tsf4))
+		require.Equal(uint64(3), ap.GetSize())
+		_, err := ap.GetActionByHash(tsf3.Hash())
+		require.Equal(ErrHash, errors.Cause(err))
+		_, err = ap.GetActionByHash(tsf2.Hash())
+		require.NoError(err)
+	})
+}
+
 // Helper function to return the correct pending nonce just in case of empty queue
 func (ap *actPool) getPendingNonce(addr string) (uint64, error) {
 	if queue, ok := ap.accountActs[addr]; ok {
@@ -10,10 +10,12 @@ import (
 	"container/heap"
 	"math/big"
 	"sort"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/pkg/hash"
 )
 
 type noncePriorityQueue []uint64
@@ -38,10 +40,93 @@ func (h *noncePriorityQueue) Pop() interface{} {
 	return x
 }
 
+// pendingActsCursor tracks the pending actions of an account that have not been picked yet
+type pendingActsCursor struct {
+	sender string
+	acts   []action.Action
+}
+
+// priceQueue is a priority queue of accounts ordered by the gas price of their next pending action, from the highest to
+// the lowest. Accounts with the same gas price are ordered by address to keep picking deterministic.
+type priceQueue []*pendingActsCursor
+
+func (h priceQueue) Len() int      { return len(h) }
+func (h priceQueue) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h priceQueue) Less(i, j int) bool {
+	if cmp := gasPrice(h[i].acts[0]).Cmp(gasPrice(h[j].acts[0])); cmp != 0 {
+		return cmp > 0
+	}
+	return h[i].sender < h[j].sender
+}
+
+func (h *priceQueue) Push(x interface{}) {
+	in, ok := x.(*pendingActsCursor)
+	if !ok {
+		return
+	}
+	*h = append(*h, in)
+}
+
+func (h *priceQueue) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[0 : n-1]
+	return x
+}
+
+// evictionItem is an action accepted into the pool, which is a candidate of eviction as long as it's non-pending
+type evictionItem struct {
+	sender    string
+	act       action.Action
+	hash      hash.Hash32B
+	timestamp time.Time
+}
+
+// evictionQueue is a priority queue of the actions in pool ordered by the eviction policy, the action to be evicted
+// first on the top
+type evictionQueue struct {
+	items        []*evictionItem
+	evictsBefore func(a action.Action, aTime time.Time, b action.Action, bTime time.Time) bool
+}
+
+func (h *evictionQueue) Len() int      { return len(h.items) }
+func (h *evictionQueue) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *evictionQueue) Less(i, j int) bool {
+	return h.evictsBefore(h.items[i].act, h.items[i].timestamp, h.items[j].act, h.items[j].timestamp)
+}
+
+func (h *evictionQueue) Push(x interface{}) {
+	in, ok := x.(*evictionItem)
+	if !ok {
+		return
+	}
+	h.items = append(h.items, in)
+}
+
+func (h *evictionQueue) Pop() interface{} {
+	old := h.items
+	n := len(old)
+	x := old[n-1]
+	h.items = old[0 : n-1]
+	return x
+}
+
+// gasPrice returns the gas price of an action, treating a missing gas price as zero
+func gasPrice(act action.Action) *big.Int {
+	if act.GasPrice() == nil {
+		return big.NewInt(0)
+	}
+	return act.GasPrice()
+}
+
 // ActQueue is the interface of actQueue
 type ActQueue interface {
 	Overlaps(action.Action) bool
 	Put(action.Action) error
+	Replace(action.Action) (action.Action, error)
+	ActByNonce(uint64) action.Action
+	Remove(uint64) action.Action
 	FilterNonce(uint64) []action.Action
 	SetStartNonce(uint64)
 	StartNonce() uint64
@@ -97,6 +182,38 @@ func (q *actQueue) Put(act action.Action) error {
 	return nil
 }
 
+// Replace substitutes the action of the same nonce in the map with the given one, and returns the replaced action
+func (q *actQueue) Replace(act action.Action) (action.Action, error) {
+	nonce := act.Nonce()
+	old := q.items[nonce]
+	if old == nil {
+		return nil, errors.Wrapf(ErrNonce, "nonce %d does not exist in queue", nonce)
+	}
+	q.items[nonce] = act
+	return old, nil
+}
+
+// ActByNonce returns the action of the given nonce in the map, or nil if there is none
+func (q *actQueue) ActByNonce(nonce uint64) action.Action {
+	return q.items[nonce]
+}
+
+// Remove deletes the action of the given nonce from the map, also updating the queue's nonce index
+func (q *actQueue) Remove(nonce uint64) action.Action {
+	act := q.items[nonce]
+	if act == nil {
+		return nil
+	}
+	delete(q.items, nonce)
+	for i, n := range q.index {
+		if n == nonce {
+			heap.Remove(&q.index, i)
+			break
+		}
+	}
+	return act
+}
+
 // FilterNonce removes all actions from the map with a nonce lower than the given threshold
 func (q *actQueue) FilterNonce(threshold uint64) []action.Action {
 	var removed []action.Action
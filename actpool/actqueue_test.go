@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 
 	"github.com/iotexproject/iotex-core/blockchain/action"
@@ -71,6 +72,24 @@ func TestActQueue_Put(t *testing.T) {
 	require.NotNil(err)
 }
 
+func TestActQueue_Replace(t *testing.T) {
+	require := require.New(t)
+	q := NewActQueue().(*actQueue)
+	tsf1, err := action.NewTransfer(uint64(1), big.NewInt(100), "1", "2", nil, uint64(0), big.NewInt(0))
+	require.NoError(err)
+	tsf2, err := action.NewTransfer(uint64(1), big.NewInt(100), "1", "2", nil, uint64(0), big.NewInt(1))
+	require.NoError(err)
+	_, err = q.Replace(tsf2)
+	require.Equal(ErrNonce, errors.Cause(err))
+	require.NoError(q.Put(tsf1))
+	old, err := q.Replace(tsf2)
+	require.NoError(err)
+	require.Equal(tsf1, old)
+	require.Equal(tsf2, q.ActByNonce(uint64(1)))
+	require.Equal(1, q.index.Len())
+	require.Nil(q.ActByNonce(uint64(2)))
+}
+
 func TestActQueue_FilterNonce(t *testing.T) {
 	require := require.New(t)
 	q := NewActQueue().(*actQueue)
@@ -0,0 +1,164 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package actpool
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"os"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/logger"
+	"github.com/iotexproject/iotex-core/pkg/util/fileutil"
+	"github.com/iotexproject/iotex-core/proto"
+)
+
+// maxJournalRecordSize is the size limit of a journal record, as an action can't be larger than a network message
+var maxJournalRecordSize = uint32(config.Default.Network.MaxMsgSize)
+
+// actJournal is an append-only file of the actions accepted into actpool, so that the pending actions survive a node
+// restart. Each record is a serialized ActionPb prefixed by its length as a 4-byte big endian integer.
+type actJournal struct {
+	path   string
+	writer *os.File
+}
+
+// newActJournal creates a journal at the given path. The file is not opened until the journal is loaded or rotated.
+func newActJournal(path string) *actJournal {
+	return &actJournal{path: path}
+}
+
+// load reads all the actions recorded in the journal, and calls add on each of them. A truncated record at the end of
+// the journal, which is left by a crash in the middle of a write, is ignored. A corrupt record doesn't fail the load
+// either: the loading stops at an oversized record, as the records after it can't be located, while an undecodable
+// record is skipped. The journal is rewritten with the loaded actions afterwards, which drops the corrupt records.
+func (j *actJournal) load(add func(*iproto.ActionPb) error) error {
+	if !fileutil.FileExists(j.path) {
+		return nil
+	}
+	file, err := os.Open(j.path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open actpool journal %s", j.path)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	total, dropped := 0, 0
+	for {
+		var size uint32
+		if err := binary.Read(reader, binary.BigEndian, &size); err != nil {
+			if err != io.EOF && err != io.ErrUnexpectedEOF {
+				return errors.Wrap(err, "failed to read actpool journal record size")
+			}
+			break
+		}
+		if size > maxJournalRecordSize {
+			logger.Warn().
+				Str("path", j.path).
+				Uint32("size", size).
+				Msg("Oversized record in actpool journal, the rest of the journal is ignored")
+			break
+		}
+		buf := make([]byte, size)
+		if _, err := io.ReadFull(reader, buf); err != nil {
+			if err != io.EOF && err != io.ErrUnexpectedEOF {
+				return errors.Wrap(err, "failed to read actpool journal record")
+			}
+			logger.Warn().Str("path", j.path).Msg("Truncated record at the end of actpool journal")
+			break
+		}
+		actPb := &iproto.ActionPb{}
+		if err := proto.Unmarshal(buf, actPb); err != nil {
+			logger.Warn().Err(err).Str("path", j.path).Msg("Undecodable record in actpool journal is skipped")
+			continue
+		}
+		total++
+		if err := add(actPb); err != nil {
+			dropped++
+		}
+	}
+	logger.Info().
+		Str("path", j.path).
+		Int("total", total).
+		Int("dropped", dropped).
+		Msg("Loaded actions from actpool journal")
+	return nil
+}
+
+// insert appends an action to the journal
+func (j *actJournal) insert(act action.Action) error {
+	if j.writer == nil {
+		return errors.New("actpool journal is not opened")
+	}
+	return writeJournalRecord(j.writer, act)
+}
+
+// rotate regenerates the journal with the given actions, which are the ones currently in actpool, and reopens it for
+// appending
+func (j *actJournal) rotate(acts []action.Action) error {
+	if j.writer != nil {
+		if err := j.writer.Close(); err != nil {
+			return errors.Wrap(err, "failed to close actpool journal")
+		}
+		j.writer = nil
+	}
+	tmpPath := j.path + ".new"
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create actpool journal %s", tmpPath)
+	}
+	writer := bufio.NewWriter(file)
+	for _, act := range acts {
+		if err := writeJournalRecord(writer, act); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return errors.Wrap(err, "failed to flush actpool journal")
+	}
+	if err := file.Close(); err != nil {
+		return errors.Wrap(err, "failed to close actpool journal")
+	}
+	if err := os.Rename(tmpPath, j.path); err != nil {
+		return errors.Wrap(err, "failed to replace actpool journal")
+	}
+	if j.writer, err = os.OpenFile(j.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600); err != nil {
+		return errors.Wrapf(err, "failed to open actpool journal %s", j.path)
+	}
+	return nil
+}
+
+// close closes the journal
+func (j *actJournal) close() error {
+	if j.writer == nil {
+		return nil
+	}
+	err := j.writer.Close()
+	j.writer = nil
+	return err
+}
+
+func writeJournalRecord(w io.Writer, act action.Action) error {
+	buf, err := proto.Marshal(act.ConvertToActionPb())
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal action into actpool journal record")
+	}
+	if err := binary.Write(w, binary.BigEndian, uint32(len(buf))); err != nil {
+		return errors.Wrap(err, "failed to write actpool journal record size")
+	}
+	if _, err := w.Write(buf); err != nil {
+		return errors.Wrap(err, "failed to write actpool journal record")
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package actpool
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/proto"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+const testJournalPath = "actpool.journal.test"
+
+func TestActJournal(t *testing.T) {
+	require := require.New(t)
+	testutil.CleanupPath(t, testJournalPath)
+	defer testutil.CleanupPath(t, testJournalPath)
+
+	tsf1, err := action.NewTransfer(uint64(1), big.NewInt(10), "1", "2", nil, uint64(0), big.NewInt(0))
+	require.NoError(err)
+	tsf2, err := action.NewTransfer(uint64(2), big.NewInt(20), "1", "2", nil, uint64(0), big.NewInt(0))
+	require.NoError(err)
+	vote3, err := action.NewVote(uint64(3), "1", "2", uint64(0), big.NewInt(0))
+	require.NoError(err)
+
+	loaded := make([]uint64, 0)
+	load := func(actPb *iproto.ActionPb) error {
+		loaded = append(loaded, actPb.Nonce)
+		return nil
+	}
+
+	// Loading a journal which doesn't exist yet
+	journal := newActJournal(testJournalPath)
+	require.NoError(journal.load(load))
+	require.Equal(0, len(loaded))
+
+	require.NoError(journal.rotate([]action.Action{tsf1}))
+	require.NoError(journal.insert(tsf2))
+	require.NoError(journal.insert(vote3))
+	require.NoError(journal.close())
+	require.NoError(newActJournal(testJournalPath).load(load))
+	require.Equal([]uint64{1, 2, 3}, loaded)
+
+	// Rotating drops the records of the actions not given
+	loaded = loaded[:0]
+	journal = newActJournal(testJournalPath)
+	require.NoError(journal.rotate([]action.Action{tsf2}))
+	require.NoError(journal.close())
+	require.NoError(newActJournal(testJournalPath).load(load))
+	require.Equal([]uint64{2}, loaded)
+
+	// A truncated record at the end of the journal is ignored
+	file, err := os.OpenFile(testJournalPath, os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(err)
+	_, err = file.Write([]byte{0, 0, 0, 100, 1, 2})
+	require.NoError(err)
+	require.NoError(file.Close())
+	loaded = loaded[:0]
+	require.NoError(newActJournal(testJournalPath).load(load))
+	require.Equal([]uint64{2}, loaded)
+
+	// An undecodable record is skipped, and the loading stops at an oversized record, whose size can't be trusted
+	journal = newActJournal(testJournalPath)
+	require.NoError(journal.rotate(nil))
+	appendRaw := func(raw []byte) {
+		file, err := os.OpenFile(testJournalPath, os.O_WRONLY|os.O_APPEND, 0600)
+		require.NoError(err)
+		_, err = file.Write(raw)
+		require.NoError(err)
+		require.NoError(file.Close())
+	}
+	appendRaw([]byte{0, 0, 0, 3, 0xff, 0xff, 0xff})
+	require.NoError(journal.insert(tsf1))
+	appendRaw([]byte{0xff, 0xff, 0xff, 0xff, 1, 2, 3})
+	require.NoError(journal.insert(tsf2))
+	require.NoError(journal.close())
+	loaded = loaded[:0]
+	require.NoError(newActJournal(testJournalPath).load(load))
+	require.Equal([]uint64{1}, loaded)
+
+	// Rotating rewrites a clean journal
+	journal = newActJournal(testJournalPath)
+	require.NoError(journal.rotate([]action.Action{tsf1}))
+	require.NoError(journal.close())
+	loaded = loaded[:0]
+	require.NoError(newActJournal(testJournalPath).load(load))
+	require.Equal([]uint64{1}, loaded)
+}
+
+func TestActPool_Journal(t *testing.T) {
+	require := require.New(t)
+	testutil.CleanupPath(t, testJournalPath)
+	defer testutil.CleanupPath(t, testJournalPath)
+
+	ctx := context.Background()
+	bc := blockchain.NewBlockchain(&config.Default, blockchain.InMemStateFactoryOption(), blockchain.InMemDaoOption())
+	require.NoError(bc.Start(ctx))
+	_, err := bc.CreateState(addr1.RawAddress, uint64(100))
+	require.NoError(err)
+	_, err = bc.GetFactory().RunActions(0, nil, nil, nil, nil)
+	require.NoError(err)
+	require.Nil(bc.GetFactory().Commit(nil))
+	apConfig := getActPoolCfg()
+	apConfig.JournalPath = testJournalPath
+
+	tsf1, err := testutil.SignedTransfer(addr1, addr1, uint64(1), big.NewInt(10),
+		[]byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	tsf2, err := testutil.SignedTransfer(addr1, addr1, uint64(2), big.NewInt(20),
+		[]byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	vote3, err := testutil.SignedVote(addr1, addr1, uint64(3), uint64(100000), big.NewInt(0))
+	require.NoError(err)
+
+	ap, err := NewActPool(bc, apConfig)
+	require.NoError(err)
+	require.NoError(ap.Start(ctx))
+	require.NoError(ap.AddTsf(tsf1))
+	require.NoError(ap.AddTsf(tsf2))
+	require.NoError(ap.AddVote(vote3))
+	require.NoError(ap.Stop(ctx))
+
+	// Actions are replayed after restart
+	ap, err = NewActPool(bc, apConfig)
+	require.NoError(err)
+	require.NoError(ap.Start(ctx))
+	require.Equal(uint64(3), ap.GetSize())
+	for _, act := range []action.Action{tsf1, tsf2, vote3} {
+		_, err := ap.GetActionByHash(act.Hash())
+		require.NoError(err)
+	}
+
+	// Confirmed actions are pruned from journal
+	_, err = bc.GetFactory().RunActions(0, []*action.Transfer{tsf1}, nil, nil, nil)
+	require.NoError(err)
+	require.Nil(bc.GetFactory().Commit(nil))
+	ap.Reset()
+	require.NoError(ap.Stop(ctx))
+
+	loaded := make([]uint64, 0)
+	require.NoError(newActJournal(testJournalPath).load(func(actPb *iproto.ActionPb) error {
+		loaded = append(loaded, actPb.Nonce)
+		return nil
+	}))
+	require.Equal([]uint64{2, 3}, loaded)
+
+	// Actions which become invalid are dropped while replaying
+	_, err = bc.GetFactory().RunActions(0, []*action.Transfer{tsf2}, []*action.Vote{vote3}, nil, nil)
+	require.NoError(err)
+	require.Nil(bc.GetFactory().Commit(nil))
+	ap, err = NewActPool(bc, apConfig)
+	require.NoError(err)
+	require.NoError(ap.Start(ctx))
+	require.Equal(uint64(0), ap.GetSize())
+	require.NoError(ap.Stop(ctx))
+}
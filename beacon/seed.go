@@ -0,0 +1,82 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package beacon
+
+import (
+	"bytes"
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+)
+
+// ErrInvalidSeed indicates that the seed isn't derived from the previous seed and the signature shares over it
+var ErrInvalidSeed = errors.New("seed is not derived from the previous seed")
+
+// Share is the DKG signature share over the seed of an epoch, which a delegate publishes in the header of the blocks
+// it produces in the epoch. The ID and the public key aren't checked here, so the caller should only pass the shares
+// signed with the keys dealt to their producers in the DKG.
+type Share struct {
+	ID        []uint8
+	PublicKey []byte
+	Signature []byte
+}
+
+// DeriveSeed derives the seed of the next epoch from the seed of an epoch and the signature shares over it. The first
+// Degree+1 valid shares of distinct delegates are aggregated into the DKG group signature over the seed, which is the
+// next seed. If there are not enough valid shares, the next seed is the hash of the seed. The group signature is the
+// same no matter which shares are aggregated only if the keys of the shares are the ones dealt in the same DKG, which
+// the caller must check. Even so, the delegates could still choose between the group signature and the hash of the
+// seed by withholding their shares, if the others don't publish enough.
+func DeriveSeed(seed []byte, shares []*Share) ([]byte, error) {
+	ids := make([][]uint8, 0, crypto.Degree+1)
+	pubkeys := make([][]byte, 0, crypto.Degree+1)
+	sigs := make([][]byte, 0, crypto.Degree+1)
+	signed := make(map[string]bool)
+	for _, share := range shares {
+		if len(ids) > crypto.Degree {
+			break
+		}
+		id := hex.EncodeToString(share.ID)
+		if signed[id] {
+			continue
+		}
+		if err := crypto.BLS.VerifyShare(share.PublicKey, seed, share.Signature); err != nil {
+			continue
+		}
+		signed[id] = true
+		ids = append(ids, share.ID)
+		pubkeys = append(pubkeys, share.PublicKey)
+		sigs = append(sigs, share.Signature)
+	}
+	if len(ids) <= crypto.Degree {
+		return hash.Hash256b(seed), nil
+	}
+	aggregateSig, err := crypto.BLS.SignAggregate(ids, sigs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to aggregate the signature shares over the seed")
+	}
+	if err := crypto.BLS.VerifyAggregate(ids, pubkeys, seed, aggregateSig); err != nil {
+		return nil, errors.Wrap(err, "failed to verify the aggregate signature over the seed")
+	}
+	return aggregateSig, nil
+}
+
+// VerifySeed verifies that the seed of the next epoch is derived from the seed of an epoch and the signature shares
+// over it
+func VerifySeed(prevSeed []byte, shares []*Share, seed []byte) error {
+	expected, err := DeriveSeed(prevSeed, shares)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(expected, seed) {
+		return errors.Wrapf(ErrInvalidSeed, "expecting %x but got %x", expected, seed)
+	}
+	return nil
+}
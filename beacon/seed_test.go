@@ -0,0 +1,78 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package beacon
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+)
+
+func TestDeriveSeed(t *testing.T) {
+	require := require.New(t)
+
+	// Run DKG among the delegates, and each of them signs the seed with its key share
+	n := crypto.NumNodes
+	ids := make([][]uint8, n)
+	for i := range ids {
+		ids[i] = iotxaddress.CreateID(fmt.Sprintf("delegate-%d", i))
+	}
+	sharesList := make([][][]uint32, n)
+	for i := range sharesList {
+		var err error
+		_, sharesList[i], _, err = crypto.DKG.Init(crypto.DKG.SkGeneration(), ids)
+		require.NoError(err)
+	}
+	statusMatrix := make([][crypto.NumNodes]bool, n)
+	for i := range statusMatrix {
+		for j := range statusMatrix[i] {
+			statusMatrix[i][j] = true
+		}
+	}
+	seed := crypto.CryptoSeed
+	shares := make([]*Share, n)
+	for i := range shares {
+		secrets := make([][]uint32, n)
+		for j := range secrets {
+			secrets[j] = sharesList[j][i]
+		}
+		_, pk, sk, err := crypto.DKG.KeyPairGeneration(secrets, statusMatrix)
+		require.NoError(err)
+		_, sig, err := crypto.BLS.SignShare(sk, seed)
+		require.NoError(err)
+		shares[i] = &Share{ID: ids[i], PublicKey: pk, Signature: sig}
+	}
+
+	// Any Degree+1 shares derive the same seed
+	next, err := DeriveSeed(seed, shares)
+	require.NoError(err)
+	require.NotEqual(hash.Hash256b(seed), next)
+	another, err := DeriveSeed(seed, shares[n-crypto.Degree-1:])
+	require.NoError(err)
+	require.Equal(next, another)
+	require.NoError(VerifySeed(seed, shares[n-crypto.Degree-1:], next))
+
+	// The duplicate and invalid shares are skipped
+	mixed := []*Share{shares[0], shares[0], {ID: ids[1], PublicKey: shares[1].PublicKey, Signature: shares[2].Signature}}
+	mixed = append(mixed, shares[2:crypto.Degree+3]...)
+	another, err = DeriveSeed(seed, mixed)
+	require.NoError(err)
+	require.Equal(next, another)
+
+	// Too few shares fall back to the hash of the seed
+	another, err = DeriveSeed(seed, shares[:crypto.Degree])
+	require.NoError(err)
+	require.Equal(hash.Hash256b(seed), another)
+	require.Equal(ErrInvalidSeed, errors.Cause(VerifySeed(seed, shares[:crypto.Degree], next)))
+	require.Equal(ErrInvalidSeed, errors.Cause(VerifySeed(next, shares, next)))
+}
@@ -58,7 +58,22 @@ type action struct {
 
 // NewActionFromProto converts a proto message into a corresponding action struct
 func NewActionFromProto(pbAct *iproto.ActionPb) Action {
-	// TODO: implement the logic
+	if pbSSC := pbAct.GetStopSubChain(); pbSSC != nil {
+		ssc := &StopSubChain{}
+		ssc.ConvertFromActionPb(pbAct)
+		return ssc
+	}
+	if pbDSE := pbAct.GetDoubleSignEvidence(); pbDSE != nil {
+		dse := &DoubleSignEvidence{}
+		dse.ConvertFromActionPb(pbAct)
+		return dse
+	}
+	if pbSV := pbAct.GetSignerVote(); pbSV != nil {
+		sv := &SignerVote{}
+		sv.ConvertFromActionPb(pbAct)
+		return sv
+	}
+	// TODO: implement the logic for the rest of the action types
 	return nil
 }
 
@@ -21,6 +21,10 @@ const (
 	GasSizeInBytes = 8
 	// StopSubChainIntrinsicGas is the instrinsic gas for stop sub chain action
 	StopSubChainIntrinsicGas = uint64(1000)
+	// DoubleSignEvidenceIntrinsicGas is the instrinsic gas for double sign evidence action
+	DoubleSignEvidenceIntrinsicGas = uint64(1000)
+	// SignerVoteIntrinsicGas is the instrinsic gas for signer vote action
+	SignerVoteIntrinsicGas = uint64(1000)
 	// GasLimit is the total gas limit to be consumed in a block
 	GasLimit = uint64(1000000000)
 )
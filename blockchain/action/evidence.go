@@ -0,0 +1,493 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package action
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/iotexproject/iotex-core/address"
+	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/pkg/enc"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/keypair"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/pkg/version"
+	"github.com/iotexproject/iotex-core/proto"
+)
+
+// ErrEvidence indicates that the evidence doesn't prove a double sign
+var ErrEvidence = errors.New("invalid double sign evidence")
+
+// EndorseByteStream returns the byte stream which an endorser signs to endorse a block
+func EndorseByteStream(height uint64, commit bool, blkHash hash.Hash32B, decision bool) []byte {
+	stream := make([]byte, 8)
+	enc.MachineEndian.PutUint64(stream, height)
+	if commit {
+		stream = append(stream, 1)
+	} else {
+		stream = append(stream, 0)
+	}
+	stream = append(stream, blkHash[:]...)
+	if decision {
+		stream = append(stream, 1)
+	} else {
+		stream = append(stream, 0)
+	}
+	return stream
+}
+
+// ProposalByteStream returns the byte stream which a proposer signs to propose a block in a round, together with its
+// network address which the endorses are sent to
+func ProposalByteStream(height uint64, round uint32, blkHash hash.Hash32B, proposerAddr string) []byte {
+	stream := make([]byte, 12)
+	enc.MachineEndian.PutUint64(stream, height)
+	enc.MachineEndian.PutUint32(stream[8:], round)
+	stream = append(stream, blkHash[:]...)
+	return append(stream, proposerAddr...)
+}
+
+// SignedEndorse is an endorse signed by a delegate, which is carried by the double sign evidence
+type SignedEndorse struct {
+	Height         uint64
+	Commit         bool
+	BlkHash        hash.Hash32B
+	Decision       bool
+	Endorser       string
+	EndorserPubkey keypair.PublicKey
+	Signature      []byte
+}
+
+// ByteStream returns a raw byte stream of the signed endorse
+func (en *SignedEndorse) ByteStream() []byte {
+	stream := EndorseByteStream(en.Height, en.Commit, en.BlkHash, en.Decision)
+	stream = append(stream, en.Endorser...)
+	stream = append(stream, en.EndorserPubkey[:]...)
+	return append(stream, en.Signature...)
+}
+
+// Verify checks that the endorse is signed by the endorser
+func (en *SignedEndorse) Verify() bool {
+	endorser, err := address.IotxAddressToAddress(en.Endorser)
+	if err != nil {
+		return false
+	}
+	pkHash := keypair.HashPubKey(en.EndorserPubkey)
+	if !bytes.Equal(endorser.Payload(), pkHash[:]) {
+		return false
+	}
+	hash := blake2b.Sum256(EndorseByteStream(en.Height, en.Commit, en.BlkHash, en.Decision))
+	return crypto.EC283.Verify(en.EndorserPubkey, hash[:], en.Signature)
+}
+
+// ConflictsWith returns true if both endorses commit the same height, and either reverse the decision on a block,
+// endorse two different blocks, or endorse a block and vote for the dummy block of the height
+func (en *SignedEndorse) ConflictsWith(other *SignedEndorse) bool {
+	if !en.Commit || !other.Commit || en.Height != other.Height || en.Endorser != other.Endorser {
+		return false
+	}
+	if en.BlkHash == other.BlkHash {
+		return en.Decision != other.Decision
+	}
+	if (en.Decision && other.isTimeoutVote()) || (en.isTimeoutVote() && other.Decision) {
+		return true
+	}
+	return en.Decision && other.Decision
+}
+
+// isTimeoutVote checks if the endorse votes for the dummy block of the height
+func (en *SignedEndorse) isTimeoutVote() bool {
+	return en.BlkHash == hash.ZeroHash32B && !en.Decision
+}
+
+func (en *SignedEndorse) convertToEndorsePb() *iproto.EndorsePb {
+	topic := iproto.EndorsePb_PROPOSAL
+	if en.Commit {
+		topic = iproto.EndorsePb_COMMIT
+	}
+	return &iproto.EndorsePb{
+		Height:         en.Height,
+		BlockHash:      en.BlkHash[:],
+		Topic:          topic,
+		Endorser:       en.Endorser,
+		EndorserPubKey: en.EndorserPubkey[:],
+		Decision:       en.Decision,
+		Signature:      en.Signature,
+	}
+}
+
+func (en *SignedEndorse) convertFromEndorsePb(pbEndorse *iproto.EndorsePb) {
+	en.Height = pbEndorse.GetHeight()
+	en.Commit = pbEndorse.GetTopic() == iproto.EndorsePb_COMMIT
+	copy(en.BlkHash[:], pbEndorse.GetBlockHash())
+	en.Decision = pbEndorse.GetDecision()
+	en.Endorser = pbEndorse.GetEndorser()
+	copy(en.EndorserPubkey[:], pbEndorse.GetEndorserPubKey())
+	en.Signature = pbEndorse.GetSignature()
+}
+
+// SignedProposal is a proposal signed by a delegate, which is carried by the double sign evidence
+type SignedProposal struct {
+	Height         uint64
+	Round          uint32
+	BlkHash        hash.Hash32B
+	ProposerAddr   string
+	Proposer       string
+	ProposerPubkey keypair.PublicKey
+	Signature      []byte
+}
+
+// ByteStream returns a raw byte stream of the signed proposal
+func (p *SignedProposal) ByteStream() []byte {
+	stream := ProposalByteStream(p.Height, p.Round, p.BlkHash, p.ProposerAddr)
+	stream = append(stream, p.Proposer...)
+	stream = append(stream, p.ProposerPubkey[:]...)
+	return append(stream, p.Signature...)
+}
+
+// Verify checks that the proposal is signed by the proposer
+func (p *SignedProposal) Verify() bool {
+	proposer, err := address.IotxAddressToAddress(p.Proposer)
+	if err != nil {
+		return false
+	}
+	pkHash := keypair.HashPubKey(p.ProposerPubkey)
+	if !bytes.Equal(proposer.Payload(), pkHash[:]) {
+		return false
+	}
+	hash := blake2b.Sum256(ProposalByteStream(p.Height, p.Round, p.BlkHash, p.ProposerAddr))
+	return crypto.EC283.Verify(p.ProposerPubkey, hash[:], p.Signature)
+}
+
+// ConflictsWith returns true if the proposer proposes two different blocks in the same round at the same height
+func (p *SignedProposal) ConflictsWith(other *SignedProposal) bool {
+	return p.Height == other.Height &&
+		p.Round == other.Round &&
+		p.Proposer == other.Proposer &&
+		p.BlkHash != other.BlkHash
+}
+
+func (p *SignedProposal) convertToProposalPb() *iproto.SignedProposalPb {
+	return &iproto.SignedProposalPb{
+		Height:         p.Height,
+		Round:          p.Round,
+		BlockHash:      p.BlkHash[:],
+		Proposer:       p.Proposer,
+		ProposerPubKey: p.ProposerPubkey[:],
+		Signature:      p.Signature,
+		ProposerAddr:   p.ProposerAddr,
+	}
+}
+
+func (p *SignedProposal) convertFromProposalPb(pbProposal *iproto.SignedProposalPb) {
+	p.Height = pbProposal.GetHeight()
+	p.Round = pbProposal.GetRound()
+	copy(p.BlkHash[:], pbProposal.GetBlockHash())
+	p.ProposerAddr = pbProposal.GetProposerAddr()
+	p.Proposer = pbProposal.GetProposer()
+	copy(p.ProposerPubkey[:], pbProposal.GetProposerPubKey())
+	p.Signature = pbProposal.GetSignature()
+}
+
+// DoubleSignEvidence defines the action to report a delegate which has signed two conflicting commit endorses, or two
+// conflicting proposals, at the same height. The offender is slashed once the evidence is confirmed on the chain.
+type DoubleSignEvidence struct {
+	action
+	endorse             *SignedEndorse
+	conflictingEndorse  *SignedEndorse
+	proposal            *SignedProposal
+	conflictingProposal *SignedProposal
+}
+
+// NewDoubleSignEvidence returns a DoubleSignEvidence instance
+func NewDoubleSignEvidence(
+	reporter string,
+	nonce uint64,
+	endorse *SignedEndorse,
+	conflictingEndorse *SignedEndorse,
+	gasLimit uint64,
+	gasPrice *big.Int,
+) (*DoubleSignEvidence, error) {
+	if endorse == nil || conflictingEndorse == nil {
+		return nil, errors.Wrap(ErrEvidence, "missing endorse")
+	}
+	return &DoubleSignEvidence{
+		action: action{
+			version:  version.ProtocolVersion,
+			nonce:    nonce,
+			srcAddr:  reporter,
+			dstAddr:  endorse.Endorser,
+			gasLimit: gasLimit,
+			gasPrice: gasPrice,
+		},
+		endorse:            endorse,
+		conflictingEndorse: conflictingEndorse,
+	}, nil
+}
+
+// NewDoubleProposeEvidence returns a DoubleSignEvidence instance of two conflicting proposals
+func NewDoubleProposeEvidence(
+	reporter string,
+	nonce uint64,
+	proposal *SignedProposal,
+	conflictingProposal *SignedProposal,
+	gasLimit uint64,
+	gasPrice *big.Int,
+) (*DoubleSignEvidence, error) {
+	if proposal == nil || conflictingProposal == nil {
+		return nil, errors.Wrap(ErrEvidence, "missing proposal")
+	}
+	return &DoubleSignEvidence{
+		action: action{
+			version:  version.ProtocolVersion,
+			nonce:    nonce,
+			srcAddr:  reporter,
+			dstAddr:  proposal.Proposer,
+			gasLimit: gasLimit,
+			gasPrice: gasPrice,
+		},
+		proposal:            proposal,
+		conflictingProposal: conflictingProposal,
+	}, nil
+}
+
+// Reporter returns the address of the reporter
+func (dse *DoubleSignEvidence) Reporter() string {
+	return dse.srcAddr
+}
+
+// Offender returns the address of the delegate which has double signed
+func (dse *DoubleSignEvidence) Offender() string {
+	return dse.dstAddr
+}
+
+// Height returns the height at which the offender has double signed
+func (dse *DoubleSignEvidence) Height() uint64 {
+	if dse.endorse != nil {
+		return dse.endorse.Height
+	}
+	if dse.proposal != nil {
+		return dse.proposal.Height
+	}
+	return 0
+}
+
+// Endorse returns the first endorse of the evidence
+func (dse *DoubleSignEvidence) Endorse() *SignedEndorse {
+	return dse.endorse
+}
+
+// ConflictingEndorse returns the endorse conflicting with the first one
+func (dse *DoubleSignEvidence) ConflictingEndorse() *SignedEndorse {
+	return dse.conflictingEndorse
+}
+
+// Proposal returns the first proposal of the evidence
+func (dse *DoubleSignEvidence) Proposal() *SignedProposal {
+	return dse.proposal
+}
+
+// ConflictingProposal returns the proposal conflicting with the first one
+func (dse *DoubleSignEvidence) ConflictingProposal() *SignedProposal {
+	return dse.conflictingProposal
+}
+
+// Verify checks that the evidence proves the offender has signed two conflicting commit endorses, or two conflicting
+// proposals
+func (dse *DoubleSignEvidence) Verify() error {
+	if dse.proposal != nil || dse.conflictingProposal != nil {
+		return dse.verifyProposals()
+	}
+	if dse.endorse == nil || dse.conflictingEndorse == nil {
+		return errors.Wrap(ErrEvidence, "missing endorse")
+	}
+	if dse.endorse.Endorser != dse.Offender() {
+		return errors.Wrapf(ErrEvidence, "endorse is not signed by the offender %s", dse.Offender())
+	}
+	if !dse.endorse.ConflictsWith(dse.conflictingEndorse) {
+		return errors.Wrapf(ErrEvidence, "endorses of the offender %s don't conflict", dse.Offender())
+	}
+	if !dse.endorse.Verify() || !dse.conflictingEndorse.Verify() {
+		return errors.Wrapf(ErrEvidence, "failed to verify the signatures of the offender %s", dse.Offender())
+	}
+	return nil
+}
+
+func (dse *DoubleSignEvidence) verifyProposals() error {
+	if dse.proposal == nil || dse.conflictingProposal == nil {
+		return errors.Wrap(ErrEvidence, "missing proposal")
+	}
+	if dse.endorse != nil || dse.conflictingEndorse != nil {
+		return errors.Wrap(ErrEvidence, "evidence carries both endorses and proposals")
+	}
+	if dse.proposal.Proposer != dse.Offender() {
+		return errors.Wrapf(ErrEvidence, "proposal is not signed by the offender %s", dse.Offender())
+	}
+	if !dse.proposal.ConflictsWith(dse.conflictingProposal) {
+		return errors.Wrapf(ErrEvidence, "proposals of the offender %s don't conflict", dse.Offender())
+	}
+	if !dse.proposal.Verify() || !dse.conflictingProposal.Verify() {
+		return errors.Wrapf(ErrEvidence, "failed to verify the signatures of the offender %s", dse.Offender())
+	}
+	return nil
+}
+
+// TotalSize returns the total size of this instance
+func (dse *DoubleSignEvidence) TotalSize() uint32 {
+	size := NonceSizeInBytes
+	size += VersionSizeInBytes
+	size += len(dse.srcPubkey)
+	size += len(dse.srcAddr)
+	size += len(dse.dstAddr)
+	size += GasSizeInBytes
+	if dse.gasPrice != nil && len(dse.gasPrice.Bytes()) > 0 {
+		size += len(dse.gasPrice.Bytes())
+	}
+	size += len(dse.signature)
+	for _, en := range []*SignedEndorse{dse.endorse, dse.conflictingEndorse} {
+		if en != nil {
+			size += len(en.ByteStream())
+		}
+	}
+	for _, p := range []*SignedProposal{dse.proposal, dse.conflictingProposal} {
+		if p != nil {
+			size += len(p.ByteStream())
+		}
+	}
+	return uint32(size)
+}
+
+// ByteStream returns a raw byte stream of this instance
+func (dse *DoubleSignEvidence) ByteStream() []byte {
+	stream := byteutil.Uint32ToBytes(dse.version)
+	stream = append(stream, byteutil.Uint64ToBytes(dse.nonce)...)
+	stream = append(stream, byteutil.Uint64ToBytes(dse.gasLimit)...)
+	stream = append(stream, dse.srcPubkey[:]...)
+	stream = append(stream, dse.srcAddr...)
+	stream = append(stream, dse.dstAddr...)
+	if dse.gasPrice != nil && len(dse.gasPrice.Bytes()) > 0 {
+		stream = append(stream, dse.gasPrice.Bytes()...)
+	}
+	for _, en := range []*SignedEndorse{dse.endorse, dse.conflictingEndorse} {
+		if en != nil {
+			stream = append(stream, en.ByteStream()...)
+		}
+	}
+	for _, p := range []*SignedProposal{dse.proposal, dse.conflictingProposal} {
+		if p != nil {
+			stream = append(stream, p.ByteStream()...)
+		}
+	}
+	return stream
+}
+
+// ConvertToActionPb converts DoubleSignEvidence to protobuf's ActionPb
+func (dse *DoubleSignEvidence) ConvertToActionPb() *iproto.ActionPb {
+	pbEvidence := &iproto.DoubleSignEvidencePb{
+		Reporter:       dse.srcAddr,
+		ReporterPubKey: dse.srcPubkey[:],
+		Offender:       dse.dstAddr,
+	}
+	if dse.endorse != nil {
+		pbEvidence.Endorse = dse.endorse.convertToEndorsePb()
+	}
+	if dse.conflictingEndorse != nil {
+		pbEvidence.ConflictingEndorse = dse.conflictingEndorse.convertToEndorsePb()
+	}
+	if dse.proposal != nil {
+		pbEvidence.Proposal = dse.proposal.convertToProposalPb()
+	}
+	if dse.conflictingProposal != nil {
+		pbEvidence.ConflictingProposal = dse.conflictingProposal.convertToProposalPb()
+	}
+	pbDSE := &iproto.ActionPb{
+		Action:    &iproto.ActionPb_DoubleSignEvidence{DoubleSignEvidence: pbEvidence},
+		Version:   dse.version,
+		Nonce:     dse.nonce,
+		GasLimit:  dse.gasLimit,
+		Signature: dse.signature,
+	}
+	if dse.gasPrice != nil {
+		pbDSE.GasPrice = dse.gasPrice.Bytes()
+	}
+	return pbDSE
+}
+
+// Serialize returns a serialized byte stream for the DoubleSignEvidence
+func (dse *DoubleSignEvidence) Serialize() ([]byte, error) {
+	return proto.Marshal(dse.ConvertToActionPb())
+}
+
+// ConvertFromActionPb converts a protobuf's ActionPb to DoubleSignEvidence
+func (dse *DoubleSignEvidence) ConvertFromActionPb(pbAct *iproto.ActionPb) {
+	dse.version = pbAct.Version
+	dse.nonce = pbAct.Nonce
+	dse.gasLimit = pbAct.GasLimit
+	if dse.gasPrice == nil {
+		dse.gasPrice = big.NewInt(0)
+	}
+	if len(pbAct.GasPrice) > 0 {
+		dse.gasPrice.SetBytes(pbAct.GasPrice)
+	}
+	dse.signature = pbAct.Signature
+	pbEvidence := pbAct.GetDoubleSignEvidence()
+	if pbEvidence != nil {
+		dse.srcAddr = pbEvidence.Reporter
+		copy(dse.srcPubkey[:], pbEvidence.ReporterPubKey)
+		dse.dstAddr = pbEvidence.Offender
+		if pbEndorse := pbEvidence.GetEndorse(); pbEndorse != nil {
+			dse.endorse = &SignedEndorse{}
+			dse.endorse.convertFromEndorsePb(pbEndorse)
+		}
+		if pbEndorse := pbEvidence.GetConflictingEndorse(); pbEndorse != nil {
+			dse.conflictingEndorse = &SignedEndorse{}
+			dse.conflictingEndorse.convertFromEndorsePb(pbEndorse)
+		}
+		if pbProposal := pbEvidence.GetProposal(); pbProposal != nil {
+			dse.proposal = &SignedProposal{}
+			dse.proposal.convertFromProposalPb(pbProposal)
+		}
+		if pbProposal := pbEvidence.GetConflictingProposal(); pbProposal != nil {
+			dse.conflictingProposal = &SignedProposal{}
+			dse.conflictingProposal.convertFromProposalPb(pbProposal)
+		}
+	}
+}
+
+// Deserialize parse the byte stream into DoubleSignEvidence
+func (dse *DoubleSignEvidence) Deserialize(buf []byte) error {
+	pbDSE := &iproto.ActionPb{}
+	if err := proto.Unmarshal(buf, pbDSE); err != nil {
+		return err
+	}
+	dse.ConvertFromActionPb(pbDSE)
+	return nil
+}
+
+// Hash returns the hash of the DoubleSignEvidence
+func (dse *DoubleSignEvidence) Hash() hash.Hash32B {
+	return blake2b.Sum256(dse.ByteStream())
+}
+
+// IntrinsicGas returns the intrinsic gas of a DoubleSignEvidence
+func (dse *DoubleSignEvidence) IntrinsicGas() (uint64, error) {
+	return DoubleSignEvidenceIntrinsicGas, nil
+}
+
+// Cost returns the total cost of a DoubleSignEvidence
+func (dse *DoubleSignEvidence) Cost() (*big.Int, error) {
+	intrinsicGas, err := dse.IntrinsicGas()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get intrinsic gas for the DoubleSignEvidence action")
+	}
+	fee := big.NewInt(0).Mul(dse.GasPrice(), big.NewInt(0).SetUint64(intrinsicGas))
+	return fee, nil
+}
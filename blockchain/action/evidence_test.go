@@ -0,0 +1,192 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package action
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+)
+
+func signEndorse(endorser *iotxaddress.Address, height uint64, blkHash hash.Hash32B, decision bool) *SignedEndorse {
+	en := &SignedEndorse{
+		Height:         height,
+		Commit:         true,
+		BlkHash:        blkHash,
+		Decision:       decision,
+		Endorser:       endorser.RawAddress,
+		EndorserPubkey: endorser.PublicKey,
+	}
+	hash := blake2b.Sum256(EndorseByteStream(height, true, blkHash, decision))
+	en.Signature = crypto.EC283.Sign(endorser.PrivateKey, hash[:])
+	return en
+}
+
+func TestDoubleSignEvidenceSerializedDeserialize(t *testing.T) {
+	require := require.New(t)
+	reporter, err := iotxaddress.NewAddress(iotxaddress.IsTestnet, chainid)
+	require.NoError(err)
+	offender, err := iotxaddress.NewAddress(iotxaddress.IsTestnet, chainid)
+	require.NoError(err)
+
+	dse, err := NewDoubleSignEvidence(
+		reporter.RawAddress,
+		1,
+		signEndorse(offender, 5, hash.Hash32B{1}, true),
+		signEndorse(offender, 5, hash.Hash32B{2}, true),
+		DoubleSignEvidenceIntrinsicGas,
+		big.NewInt(0),
+	)
+	require.NoError(err)
+	require.NoError(Sign(dse, reporter.PrivateKey))
+	raw, err := dse.Serialize()
+	require.NoError(err)
+
+	newDSE := &DoubleSignEvidence{}
+	require.NoError(newDSE.Deserialize(raw))
+	require.Equal(dse.Hash(), newDSE.Hash())
+	require.Equal(dse.TotalSize(), newDSE.TotalSize())
+	require.Equal(offender.RawAddress, newDSE.Offender())
+	require.Equal(uint64(5), newDSE.Height())
+	require.NoError(Verify(newDSE))
+	require.NoError(newDSE.Verify())
+	dse, ok := NewActionFromProto(dse.ConvertToActionPb()).(*DoubleSignEvidence)
+	require.True(ok)
+	require.Equal(newDSE.Hash(), dse.Hash())
+}
+
+func TestDoubleSignEvidenceVerify(t *testing.T) {
+	require := require.New(t)
+	reporter, err := iotxaddress.NewAddress(iotxaddress.IsTestnet, chainid)
+	require.NoError(err)
+	offender, err := iotxaddress.NewAddress(iotxaddress.IsTestnet, chainid)
+	require.NoError(err)
+	other, err := iotxaddress.NewAddress(iotxaddress.IsTestnet, chainid)
+	require.NoError(err)
+
+	verify := func(endorse *SignedEndorse, conflictingEndorse *SignedEndorse) error {
+		dse, err := NewDoubleSignEvidence(reporter.RawAddress, 1, endorse, conflictingEndorse, 0, big.NewInt(0))
+		require.NoError(err)
+		return dse.Verify()
+	}
+
+	// Endorsing two blocks, or reversing the decision on a block
+	require.NoError(verify(signEndorse(offender, 5, hash.Hash32B{1}, true), signEndorse(offender, 5, hash.Hash32B{2}, true)))
+	require.NoError(verify(signEndorse(offender, 5, hash.Hash32B{1}, true), signEndorse(offender, 5, hash.Hash32B{1}, false)))
+	// Endorsing a block and voting for the dummy block
+	require.NoError(verify(signEndorse(offender, 5, hash.Hash32B{1}, true), signEndorse(offender, 5, hash.ZeroHash32B, false)))
+	// Rejecting another block, the same endorse twice, or endorses of different heights don't conflict
+	err = verify(signEndorse(offender, 5, hash.Hash32B{1}, true), signEndorse(offender, 5, hash.Hash32B{2}, false))
+	require.Equal(ErrEvidence, errors.Cause(err))
+	err = verify(signEndorse(offender, 5, hash.Hash32B{1}, true), signEndorse(offender, 5, hash.Hash32B{1}, true))
+	require.Equal(ErrEvidence, errors.Cause(err))
+	err = verify(signEndorse(offender, 5, hash.Hash32B{1}, true), signEndorse(offender, 6, hash.Hash32B{2}, true))
+	require.Equal(ErrEvidence, errors.Cause(err))
+	// Endorses of two endorsers
+	err = verify(signEndorse(offender, 5, hash.Hash32B{1}, true), signEndorse(other, 5, hash.Hash32B{2}, true))
+	require.Equal(ErrEvidence, errors.Cause(err))
+	// Proposal endorses are not binding
+	en := signEndorse(offender, 5, hash.Hash32B{1}, true)
+	en.Commit = false
+	err = verify(en, signEndorse(offender, 5, hash.Hash32B{2}, true))
+	require.Equal(ErrEvidence, errors.Cause(err))
+	// A forged endorse
+	en = signEndorse(offender, 5, hash.Hash32B{2}, true)
+	en.Signature = signEndorse(other, 5, hash.Hash32B{2}, true).Signature
+	err = verify(signEndorse(offender, 5, hash.Hash32B{1}, true), en)
+	require.Equal(ErrEvidence, errors.Cause(err))
+	en = signEndorse(other, 5, hash.Hash32B{2}, true)
+	en.Endorser = offender.RawAddress
+	err = verify(signEndorse(offender, 5, hash.Hash32B{1}, true), en)
+	require.Equal(ErrEvidence, errors.Cause(err))
+	// A missing endorse
+	_, err = NewDoubleSignEvidence(reporter.RawAddress, 1, signEndorse(offender, 5, hash.Hash32B{1}, true), nil, 0, nil)
+	require.Equal(ErrEvidence, errors.Cause(err))
+}
+
+func signProposal(proposer *iotxaddress.Address, height uint64, round uint32, blkHash hash.Hash32B) *SignedProposal {
+	p := &SignedProposal{
+		Height:         height,
+		Round:          round,
+		BlkHash:        blkHash,
+		ProposerAddr:   "127.0.0.1:4689",
+		Proposer:       proposer.RawAddress,
+		ProposerPubkey: proposer.PublicKey,
+	}
+	hash := blake2b.Sum256(ProposalByteStream(height, round, blkHash, p.ProposerAddr))
+	p.Signature = crypto.EC283.Sign(proposer.PrivateKey, hash[:])
+	return p
+}
+
+func TestDoubleProposeEvidence(t *testing.T) {
+	require := require.New(t)
+	reporter, err := iotxaddress.NewAddress(iotxaddress.IsTestnet, chainid)
+	require.NoError(err)
+	offender, err := iotxaddress.NewAddress(iotxaddress.IsTestnet, chainid)
+	require.NoError(err)
+	other, err := iotxaddress.NewAddress(iotxaddress.IsTestnet, chainid)
+	require.NoError(err)
+
+	verify := func(proposal *SignedProposal, conflictingProposal *SignedProposal) error {
+		dse, err := NewDoubleProposeEvidence(reporter.RawAddress, 1, proposal, conflictingProposal, 0, big.NewInt(0))
+		require.NoError(err)
+		return dse.Verify()
+	}
+
+	// Proposing two blocks in the same round
+	dse, err := NewDoubleProposeEvidence(
+		reporter.RawAddress,
+		1,
+		signProposal(offender, 5, 1, hash.Hash32B{1}),
+		signProposal(offender, 5, 1, hash.Hash32B{2}),
+		DoubleSignEvidenceIntrinsicGas,
+		big.NewInt(0),
+	)
+	require.NoError(err)
+	require.NoError(dse.Verify())
+	require.NoError(Sign(dse, reporter.PrivateKey))
+	raw, err := dse.Serialize()
+	require.NoError(err)
+	newDSE := &DoubleSignEvidence{}
+	require.NoError(newDSE.Deserialize(raw))
+	require.Equal(dse.Hash(), newDSE.Hash())
+	require.Equal(dse.TotalSize(), newDSE.TotalSize())
+	require.Equal(offender.RawAddress, newDSE.Offender())
+	require.Equal(uint64(5), newDSE.Height())
+	require.Equal(dse.Proposal(), newDSE.Proposal())
+	require.NoError(Verify(newDSE))
+	require.NoError(newDSE.Verify())
+
+	// The same block again, blocks of different rounds or heights, or of two proposers don't conflict
+	err = verify(signProposal(offender, 5, 1, hash.Hash32B{1}), signProposal(offender, 5, 1, hash.Hash32B{1}))
+	require.Equal(ErrEvidence, errors.Cause(err))
+	err = verify(signProposal(offender, 5, 1, hash.Hash32B{1}), signProposal(offender, 5, 2, hash.Hash32B{2}))
+	require.Equal(ErrEvidence, errors.Cause(err))
+	err = verify(signProposal(offender, 5, 1, hash.Hash32B{1}), signProposal(offender, 6, 1, hash.Hash32B{2}))
+	require.Equal(ErrEvidence, errors.Cause(err))
+	err = verify(signProposal(offender, 5, 1, hash.Hash32B{1}), signProposal(other, 5, 1, hash.Hash32B{2}))
+	require.Equal(ErrEvidence, errors.Cause(err))
+	// A forged proposal
+	p := signProposal(offender, 5, 1, hash.Hash32B{2})
+	p.Signature = signProposal(other, 5, 1, hash.Hash32B{2}).Signature
+	err = verify(signProposal(offender, 5, 1, hash.Hash32B{1}), p)
+	require.Equal(ErrEvidence, errors.Cause(err))
+	p = signProposal(offender, 5, 1, hash.Hash32B{2})
+	p.ProposerAddr = "127.0.0.1:4690"
+	err = verify(signProposal(offender, 5, 1, hash.Hash32B{1}), p)
+	require.Equal(ErrEvidence, errors.Cause(err))
+	// A missing proposal
+	_, err = NewDoubleProposeEvidence(reporter.RawAddress, 1, signProposal(offender, 5, 1, hash.Hash32B{1}), nil, 0, nil)
+	require.Equal(ErrEvidence, errors.Cause(err))
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package action
+
+import (
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/pkg/version"
+	"github.com/iotexproject/iotex-core/proto"
+)
+
+// SignerVote defines the action of a proof of authority signer to vote for adding a candidate into the signer set, or
+// removing it from the set. The change takes effect once the majority of the signers vote for it.
+type SignerVote struct {
+	action
+	add bool
+}
+
+// NewSignerVote returns a SignerVote instance
+func NewSignerVote(
+	voter string,
+	nonce uint64,
+	candidate string,
+	add bool,
+	gasLimit uint64,
+	gasPrice *big.Int,
+) (*SignerVote, error) {
+	if voter == "" || candidate == "" {
+		return nil, errors.Wrap(ErrAddress, "address of the voter or the candidate is empty")
+	}
+	return &SignerVote{
+		action: action{
+			version:  version.ProtocolVersion,
+			nonce:    nonce,
+			srcAddr:  voter,
+			dstAddr:  candidate,
+			gasLimit: gasLimit,
+			gasPrice: gasPrice,
+		},
+		add: add,
+	}, nil
+}
+
+// Voter returns the address of the signer which votes
+func (sv *SignerVote) Voter() string {
+	return sv.srcAddr
+}
+
+// Candidate returns the address of the candidate to add or remove
+func (sv *SignerVote) Candidate() string {
+	return sv.dstAddr
+}
+
+// Add returns true if the vote is for adding the candidate, or false for removing it
+func (sv *SignerVote) Add() bool {
+	return sv.add
+}
+
+// TotalSize returns the total size of this instance
+func (sv *SignerVote) TotalSize() uint32 {
+	size := NonceSizeInBytes
+	size += VersionSizeInBytes
+	size += len(sv.srcPubkey)
+	size += len(sv.srcAddr)
+	size += len(sv.dstAddr)
+	size += GasSizeInBytes
+	if sv.gasPrice != nil && len(sv.gasPrice.Bytes()) > 0 {
+		size += len(sv.gasPrice.Bytes())
+	}
+	size += len(sv.signature)
+	// add
+	size++
+	return uint32(size)
+}
+
+// ByteStream returns a raw byte stream of this instance
+func (sv *SignerVote) ByteStream() []byte {
+	stream := byteutil.Uint32ToBytes(sv.version)
+	stream = append(stream, byteutil.Uint64ToBytes(sv.nonce)...)
+	stream = append(stream, byteutil.Uint64ToBytes(sv.gasLimit)...)
+	stream = append(stream, sv.srcPubkey[:]...)
+	stream = append(stream, sv.srcAddr...)
+	stream = append(stream, sv.dstAddr...)
+	if sv.gasPrice != nil && len(sv.gasPrice.Bytes()) > 0 {
+		stream = append(stream, sv.gasPrice.Bytes()...)
+	}
+	if sv.add {
+		stream = append(stream, 1)
+	} else {
+		stream = append(stream, 0)
+	}
+	return stream
+}
+
+// ConvertToActionPb converts SignerVote to protobuf's ActionPb
+func (sv *SignerVote) ConvertToActionPb() *iproto.ActionPb {
+	pbSV := &iproto.ActionPb{
+		Action: &iproto.ActionPb_SignerVote{
+			SignerVote: &iproto.SignerVotePb{
+				Voter:       sv.srcAddr,
+				VoterPubKey: sv.srcPubkey[:],
+				Candidate:   sv.dstAddr,
+				Add:         sv.add,
+			},
+		},
+		Version:   sv.version,
+		Nonce:     sv.nonce,
+		GasLimit:  sv.gasLimit,
+		Signature: sv.signature,
+	}
+	if sv.gasPrice != nil {
+		pbSV.GasPrice = sv.gasPrice.Bytes()
+	}
+	return pbSV
+}
+
+// Serialize returns a serialized byte stream for the SignerVote
+func (sv *SignerVote) Serialize() ([]byte, error) {
+	return proto.Marshal(sv.ConvertToActionPb())
+}
+
+// ConvertFromActionPb converts a protobuf's ActionPb to SignerVote
+func (sv *SignerVote) ConvertFromActionPb(pbAct *iproto.ActionPb) {
+	sv.version = pbAct.Version
+	sv.nonce = pbAct.Nonce
+	sv.gasLimit = pbAct.GasLimit
+	if sv.gasPrice == nil {
+		sv.gasPrice = big.NewInt(0)
+	}
+	if len(pbAct.GasPrice) > 0 {
+		sv.gasPrice.SetBytes(pbAct.GasPrice)
+	}
+	sv.signature = pbAct.Signature
+	pbSV := pbAct.GetSignerVote()
+	if pbSV != nil {
+		sv.srcAddr = pbSV.Voter
+		copy(sv.srcPubkey[:], pbSV.VoterPubKey)
+		sv.dstAddr = pbSV.Candidate
+		sv.add = pbSV.Add
+	}
+}
+
+// Deserialize parse the byte stream into SignerVote
+func (sv *SignerVote) Deserialize(buf []byte) error {
+	pbSV := &iproto.ActionPb{}
+	if err := proto.Unmarshal(buf, pbSV); err != nil {
+		return err
+	}
+	sv.ConvertFromActionPb(pbSV)
+	return nil
+}
+
+// Hash returns the hash of the SignerVote
+func (sv *SignerVote) Hash() hash.Hash32B {
+	return blake2b.Sum256(sv.ByteStream())
+}
+
+// IntrinsicGas returns the intrinsic gas of a SignerVote
+func (sv *SignerVote) IntrinsicGas() (uint64, error) {
+	return SignerVoteIntrinsicGas, nil
+}
+
+// Cost returns the total cost of a SignerVote
+func (sv *SignerVote) Cost() (*big.Int, error) {
+	intrinsicGas, err := sv.IntrinsicGas()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get intrinsic gas for the SignerVote action")
+	}
+	fee := big.NewInt(0).Mul(sv.GasPrice(), big.NewInt(0).SetUint64(intrinsicGas))
+	return fee, nil
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package action
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/iotxaddress"
+)
+
+func TestSignerVoteSerializedDeserialize(t *testing.T) {
+	require := require.New(t)
+	voter, err := iotxaddress.NewAddress(iotxaddress.IsTestnet, chainid)
+	require.NoError(err)
+	candidate, err := iotxaddress.NewAddress(iotxaddress.IsTestnet, chainid)
+	require.NoError(err)
+
+	sv, err := NewSignerVote(voter.RawAddress, 1, candidate.RawAddress, true, SignerVoteIntrinsicGas, big.NewInt(0))
+	require.NoError(err)
+	require.NoError(Sign(sv, voter.PrivateKey))
+	raw, err := sv.Serialize()
+	require.NoError(err)
+
+	newSV := &SignerVote{}
+	require.NoError(newSV.Deserialize(raw))
+	require.Equal(sv.Hash(), newSV.Hash())
+	require.Equal(sv.TotalSize(), newSV.TotalSize())
+	require.Equal(voter.RawAddress, newSV.Voter())
+	require.Equal(candidate.RawAddress, newSV.Candidate())
+	require.True(newSV.Add())
+	require.NoError(Verify(newSV))
+	sv, ok := NewActionFromProto(newSV.ConvertToActionPb()).(*SignerVote)
+	require.True(ok)
+	require.Equal(newSV.Hash(), sv.Hash())
+
+	_, err = NewSignerVote(voter.RawAddress, 1, "", false, 0, big.NewInt(0))
+	require.Error(err)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"golang.org/x/crypto/blake2b"
 
+	"github.com/iotexproject/iotex-core/action/subchain"
 	"github.com/iotexproject/iotex-core/blockchain/action"
 	"github.com/iotexproject/iotex-core/crypto"
 	"github.com/iotexproject/iotex-core/iotxaddress"
@@ -47,6 +48,7 @@ type BlockHeader struct {
 	DKGID         []byte            // dkg ID of producer
 	DKGPubkey     []byte            // dkg public key of producer
 	DKGBlockSig   []byte            // dkg signature of producer
+	Seed          []byte            // random seed of the epoch, which is carried by the first block of the epoch
 }
 
 // Timestamp returns the timestamp in the block header
@@ -63,8 +65,12 @@ type Block struct {
 	Executions      []*action.Execution
 	SecretProposals []*action.SecretProposal
 	SecretWitness   *action.SecretWitness
-	receipts        map[hash.Hash32B]*Receipt
-	workingSet      state.WorkingSet
+	// Endorsements is the certificate of the consensus to commit the block, which is not part of the block hash
+	Endorsements []*Endorsement
+	// AggregateEndorsement is the BLS aggregate of the commit endorsements, which is part of the certificate as well
+	AggregateEndorsement *AggregateEndorsement
+	receipts             map[hash.Hash32B]*Receipt
+	workingSet           state.WorkingSet
 }
 
 // NewBlock returns a new block
@@ -165,6 +171,7 @@ func (b *Block) ByteStreamHeader() []byte {
 	stream = append(stream, b.Header.stateRoot[:]...)
 	stream = append(stream, b.Header.receiptRoot[:]...)
 	stream = append(stream, b.Header.Pubkey[:]...)
+	stream = append(stream, b.Header.Seed[:]...)
 	return stream
 }
 
@@ -216,6 +223,7 @@ func (b *Block) ConvertToBlockHeaderPb() *iproto.BlockHeaderPb {
 	pbHeader.DkgID = b.Header.DKGID[:]
 	pbHeader.DkgPubkey = b.Header.DKGPubkey[:]
 	pbHeader.DkgSignature = b.Header.DKGBlockSig[:]
+	pbHeader.Seed = b.Header.Seed[:]
 	return &pbHeader
 }
 
@@ -240,7 +248,28 @@ func (b *Block) ConvertToBlockPb() *iproto.BlockPb {
 	for _, act := range b.Actions {
 		actions = append(actions, act.ConvertToActionPb())
 	}
-	return &iproto.BlockPb{Header: b.ConvertToBlockHeaderPb(), Actions: actions}
+	cert := b.ConvertToBlockCertificatePb()
+	return &iproto.BlockPb{
+		Header:               b.ConvertToBlockHeaderPb(),
+		Actions:              actions,
+		Endorsements:         cert.Endorsements,
+		AggregateEndorsement: cert.AggregateEndorsement,
+	}
+}
+
+// ConvertToBlockCertificatePb converts the commit certificate of the block to BlockCertificatePb
+func (b *Block) ConvertToBlockCertificatePb() *iproto.BlockCertificatePb {
+	cert := &iproto.BlockCertificatePb{}
+	if len(b.Endorsements) == 0 && b.AggregateEndorsement == nil {
+		return cert
+	}
+	for _, en := range b.Endorsements {
+		cert.Endorsements = append(cert.Endorsements, en.ConvertToEndorsePb(b.Header.height))
+	}
+	if b.AggregateEndorsement != nil {
+		cert.AggregateEndorsement = b.AggregateEndorsement.ConvertToEndorsePb(b.Header.height, b.HashBlock())
+	}
+	return cert
 }
 
 // Serialize returns the serialized byte stream of the block
@@ -265,6 +294,7 @@ func (b *Block) ConvertFromBlockHeaderPb(pbBlock *iproto.BlockPb) {
 	b.Header.DKGID = pbBlock.GetHeader().GetDkgID()
 	b.Header.DKGPubkey = pbBlock.GetHeader().GetDkgPubkey()
 	b.Header.DKGBlockSig = pbBlock.GetHeader().GetDkgSignature()
+	b.Header.Seed = pbBlock.GetHeader().GetSeed()
 }
 
 // ConvertFromBlockPb converts BlockPb to Block
@@ -298,11 +328,29 @@ func (b *Block) ConvertFromBlockPb(pbBlock *iproto.BlockPb) {
 			secretWitness := &action.SecretWitness{}
 			secretWitness.ConvertFromActionPb(actPb)
 			b.SecretWitness = secretWitness
-		} else {
-			act := action.NewActionFromProto(actPb)
+		} else if startPb := actPb.GetStartSubChain(); startPb != nil {
+			b.Actions = append(b.Actions, subchain.NewStartSubChainFromProto(actPb))
+		} else if act := action.NewActionFromProto(actPb); act != nil {
 			b.Actions = append(b.Actions, act)
 		}
 	}
+
+	b.Endorsements = nil
+	for _, enPb := range pbBlock.GetEndorsements() {
+		en := &Endorsement{}
+		// An endorsement which can't be converted doesn't count towards the certificate
+		if err := en.ConvertFromEndorsePb(enPb); err != nil {
+			continue
+		}
+		b.Endorsements = append(b.Endorsements, en)
+	}
+	b.AggregateEndorsement = nil
+	if aggPb := pbBlock.GetAggregateEndorsement(); aggPb != nil {
+		agg := &AggregateEndorsement{}
+		if err := agg.ConvertFromEndorsePb(aggPb); err == nil {
+			b.AggregateEndorsement = agg
+		}
+	}
 }
 
 // Deserialize parses the byte stream into a Block
@@ -363,6 +411,14 @@ func (b *Block) VerifyStateRoot(root hash.Hash32B) error {
 	return nil
 }
 
+// VerifyTxRoot verifies the tx root in header
+func (b *Block) VerifyTxRoot() error {
+	if b.Header.txRoot != b.TxRoot() {
+		return errors.New("Tx root hash does not match")
+	}
+	return nil
+}
+
 // SignBlock allows signer to sign the block b
 func (b *Block) SignBlock(signer *iotxaddress.Address) error {
 	if signer.PrivateKey == keypair.ZeroPrivateKey {
@@ -104,8 +104,9 @@ type Blockchain interface {
 		data string,
 	) (*Block, error)
 	// TODO: Merge the MintNewDKGBlock into MintNewBlock
-	// MintNewDKGBlock creates a new block with given actions and dkg keys
+	// MintNewDKGBlock creates a new block with given actions and dkg keys. The minting stops once the context is done.
 	MintNewDKGBlock(
+		ctx context.Context,
 		tsf []*action.Transfer,
 		vote []*action.Vote,
 		executions []*action.Execution,
@@ -124,12 +125,20 @@ type Blockchain interface {
 	CommitBlock(blk *Block) error
 	// ValidateBlock validates a new block before adding it to the blockchain
 	ValidateBlock(blk *Block, containCoinbase bool) error
+	// VerifyHeader verifies the header of a block from the network with its commit certificate
+	VerifyHeader(blk *Block) error
 
 	// For action operations
 	// Validator returns the current validator object
 	Validator() Validator
 	// SetValidator sets the current validator object
 	SetValidator(val Validator)
+	// SetEndorsersFunc sets the function which returns the endorsers to check the commit certificates of the blocks
+	// against
+	SetEndorsersFunc(f EndorsersFunc)
+	// SetAggregateVerifierFunc sets the function which checks the BLS aggregate endorsements in the commit certificates
+	// of the blocks
+	SetAggregateVerifierFunc(f AggregateVerifierFunc)
 
 	// For smart contract operations
 	// ExecuteContractRead runs a read-only smart contract operation, this is done off the network since it does not
@@ -155,6 +164,8 @@ type blockchain struct {
 	lifecycle     lifecycle.Lifecycle
 	clk           clock.Clock
 	blocklistener []chan *Block
+	endorsersFunc EndorsersFunc
+	aggVerifier   AggregateVerifierFunc
 
 	// used by account-based model
 	sf state.Factory
@@ -330,7 +341,7 @@ func (bc *blockchain) startEmptyBlockchain() error {
 		return errors.Wrap(err, "failed to add Creator into StateFactory")
 	}
 	// run execution and update state trie root hash
-	root, err := bc.runActions(genesis, ws, false)
+	root, err := bc.runActions(context.Background(), genesis, ws, false)
 	if err != nil {
 		return errors.Wrap(err, "failed to update state changes in Genesis block")
 	}
@@ -387,7 +398,7 @@ func (bc *blockchain) startExistingBlockchain(recoveryHeight uint64) error {
 			return err
 		}
 		// TODO: disable validation before resolve the state root doesn't match issue
-		if _, err := bc.runActions(blk, ws, false); err != nil {
+		if _, err := bc.runActions(context.Background(), blk, ws, false); err != nil {
 			return err
 		}
 		if err := bc.sf.Commit(ws); err != nil {
@@ -644,6 +655,37 @@ func (bc *blockchain) ValidateBlock(blk *Block, containCoinbase bool) error {
 	return bc.validateBlock(blk, containCoinbase)
 }
 
+// VerifyHeader verifies the header of a block from the network, which carries the commit certificate, e.g., the header
+// synced ahead of the block, or the block to commit from the sync. The header must be signed by its producer, and if
+// the consensus endorses the blocks, the producer must be an endorser at its height and the certificate must be valid.
+// Dummy headers are signed by no one, so they have to carry the timeout votes instead. An error other than
+// ErrInvalidBlock or ErrCertificate means that the header can't be verified yet, e.g., when the endorsers at its height
+// are unknown.
+func (bc *blockchain) VerifyHeader(blk *Block) error {
+	dummy := blk.IsDummyBlock()
+	if !dummy && !blk.VerifySignature() {
+		return errors.Wrapf(ErrInvalidBlock, "invalid signature of header %d", blk.Height())
+	}
+	if bc.endorsersFunc == nil {
+		return nil
+	}
+	if !dummy {
+		endorsers, _, err := bc.endorsersFunc(blk.Height())
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the endorsers of block %d", blk.Height())
+		}
+		producer := blk.ProducerAddress()
+		eligible := false
+		for _, endorser := range endorsers {
+			eligible = eligible || endorser == producer
+		}
+		if !eligible {
+			return errors.Wrapf(ErrInvalidBlock, "producer %s of header %d is not an endorser", producer, blk.Height())
+		}
+	}
+	return bc.verifyCertificate(blk)
+}
+
 // MintNewBlock creates a new block with given actions
 // Note: the coinbase transfer will be added to the given transfers
 // when minting a new block
@@ -668,7 +710,7 @@ func (bc *blockchain) MintNewBlock(
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to obtain working set from state factory")
 	}
-	root, err := bc.runActions(blk, ws, false)
+	root, err := bc.runActions(context.Background(), blk, ws, false)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to update state changes in new block %d", blk.Height())
 	}
@@ -685,6 +727,7 @@ func (bc *blockchain) MintNewBlock(
 // Note: the coinbase transfer will be added to the given transfers
 // when minting a new block
 func (bc *blockchain) MintNewDKGBlock(
+	ctx context.Context,
 	tsf []*action.Transfer,
 	vote []*action.Vote,
 	executions []*action.Execution,
@@ -715,7 +758,7 @@ func (bc *blockchain) MintNewDKGBlock(
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to obtain working set from state factory")
 	}
-	root, err := bc.runActions(blk, ws, false)
+	root, err := bc.runActions(ctx, blk, ws, false)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to update state changes in new DKG block %d", blk.Height())
 	}
@@ -743,7 +786,7 @@ func (bc *blockchain) MintNewSecretBlock(
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to obtain working set from state factory")
 	}
-	root, err := bc.runActions(blk, ws, false)
+	root, err := bc.runActions(context.Background(), blk, ws, false)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to update state changes in new block %d", blk.Height())
 	}
@@ -770,7 +813,7 @@ func (bc *blockchain) MintNewDummyBlock() *Block {
 	if err != nil {
 		return nil
 	}
-	root, err := bc.runActions(blk, ws, false)
+	root, err := bc.runActions(context.Background(), blk, ws, false)
 	if err != nil {
 		return nil
 	}
@@ -810,6 +853,17 @@ func (bc *blockchain) Validator() Validator {
 	return bc.validator
 }
 
+// SetEndorsersFunc sets the function which returns the endorsers to check the commit certificates of the blocks against
+func (bc *blockchain) SetEndorsersFunc(f EndorsersFunc) {
+	bc.endorsersFunc = f
+}
+
+// SetAggregateVerifierFunc sets the function which checks the BLS aggregate endorsements in the commit certificates of
+// the blocks
+func (bc *blockchain) SetAggregateVerifierFunc(f AggregateVerifierFunc) {
+	bc.aggVerifier = f
+}
+
 // ExecuteContractRead runs a read-only smart contract operation, this is done off the network since it does not
 // cause any state change
 func (bc *blockchain) ExecuteContractRead(ex *action.Execution) ([]byte, error) {
@@ -854,13 +908,16 @@ func (bc *blockchain) validateBlock(blk *Block, containCoinbase bool) error {
 	if err := bc.validator.Validate(blk, tipHeight, tipHash, containCoinbase); err != nil {
 		return errors.Wrapf(err, "Failed to validate block on height %d", tipHeight)
 	}
+	if err := bc.validateEvidences(blk); err != nil {
+		return err
+	}
 	// run actions and update state factory
 	ws, err := bc.sf.NewWorkingSet()
 	if err != nil {
 		return errors.Wrap(err, "Failed to obtain working set from state factory")
 	}
 	// TODO: disable validation before resolve the state root doesn't match issue
-	if _, err := bc.runActions(blk, ws, false); err != nil {
+	if _, err := bc.runActions(context.Background(), blk, ws, false); err != nil {
 		logger.Panic().Err(err).Msgf("Failed to update state on height %d", tipHeight)
 	}
 	// attach working set to be committed to state factory
@@ -868,6 +925,109 @@ func (bc *blockchain) validateBlock(blk *Block, containCoinbase bool) error {
 	return nil
 }
 
+// validateEvidences checks that the double sign evidences in the block are neither of a future height nor too old, and
+// that the offenders are the delegates at the heights they have double signed
+func (bc *blockchain) validateEvidences(blk *Block) error {
+	for _, act := range blk.Actions {
+		evidence, ok := act.(*action.DoubleSignEvidence)
+		if !ok {
+			continue
+		}
+		height := evidence.Height()
+		if height == 0 || height > blk.Height() {
+			return errors.Wrapf(ErrInvalidBlock, "evidence %x of height %d is from the future", evidence.Hash(), height)
+		}
+		if blk.Height()-height > bc.config.Chain.MaxEvidenceAge {
+			return errors.Wrapf(ErrInvalidBlock, "evidence %x of height %d is too old", evidence.Hash(), height)
+		}
+		if bc.endorsersFunc == nil {
+			continue
+		}
+		endorsers, _, err := bc.endorsersFunc(height)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the endorsers of block %d", height)
+		}
+		delegate := false
+		for _, endorser := range endorsers {
+			delegate = delegate || endorser == evidence.Offender()
+		}
+		if !delegate {
+			return errors.Wrapf(
+				ErrInvalidBlock,
+				"offender %s of evidence %x is not a delegate at height %d",
+				evidence.Offender(),
+				evidence.Hash(),
+				height,
+			)
+		}
+	}
+	return nil
+}
+
+// verifyCertificate checks that the block is endorsed by a quorum of the endorsers at its height, counting both the
+// individual endorsements and the endorsers in the aggregate one. A dummy block has to carry the timeout votes of a
+// quorum of the endorsers instead, so that it can't replace a block committed at the height. Every block but the genesis
+// one has to carry the certificate if the consensus endorses the blocks.
+func (bc *blockchain) verifyCertificate(blk *Block) error {
+	if bc.endorsersFunc == nil || blk.Height() == 0 {
+		return nil
+	}
+	if len(blk.Endorsements) == 0 && blk.AggregateEndorsement == nil {
+		return errors.Wrapf(ErrCertificate, "block %d has no commit certificate", blk.Height())
+	}
+	dummy := blk.IsDummyBlock()
+	if dummy && blk.AggregateEndorsement != nil {
+		return errors.Wrapf(ErrCertificate, "dummy block %d carries an aggregate endorsement", blk.Height())
+	}
+	endorsers, quorum, err := bc.endorsersFunc(blk.Height())
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the endorsers of block %d", blk.Height())
+	}
+	eligible := make(map[string]bool, len(endorsers))
+	for _, endorser := range endorsers {
+		eligible[endorser] = true
+	}
+	blkHash := blk.HashBlock()
+	endorsed := make(map[string]bool, len(blk.Endorsements))
+	for _, en := range blk.Endorsements {
+		if !eligible[en.Endorser] {
+			return errors.Wrapf(ErrCertificate, "%s is not an endorser of block %d", en.Endorser, blk.Height())
+		}
+		if endorsed[en.Endorser] {
+			return errors.Wrapf(ErrCertificate, "duplicate endorsement from %s", en.Endorser)
+		}
+		if dummy && !en.IsTimeoutVote() {
+			return errors.Wrapf(ErrCertificate, "endorsement from %s is not a timeout vote", en.Endorser)
+		}
+		if !dummy && (!en.Decision || en.BlkHash != blkHash) {
+			return errors.Wrapf(ErrCertificate, "endorsement from %s doesn't commit block %d", en.Endorser, blk.Height())
+		}
+		if !en.Verify(blk.Height()) {
+			return errors.Wrapf(ErrCertificate, "invalid endorsement signature from %s", en.Endorser)
+		}
+		endorsed[en.Endorser] = true
+	}
+	if blk.AggregateEndorsement != nil {
+		if bc.aggVerifier == nil {
+			return errors.Wrapf(ErrCertificate, "aggregate endorsement of block %d can't be verified", blk.Height())
+		}
+		aggEndorsers, err := bc.aggVerifier(blk.Height(), blkHash, blk.AggregateEndorsement)
+		if err != nil {
+			return errors.Wrapf(ErrCertificate, "invalid aggregate endorsement of block %d: %v", blk.Height(), err)
+		}
+		for _, endorser := range aggEndorsers {
+			if !eligible[endorser] {
+				return errors.Wrapf(ErrCertificate, "%s is not an endorser of block %d", endorser, blk.Height())
+			}
+			endorsed[endorser] = true
+		}
+	}
+	if len(endorsed) < quorum {
+		return errors.Wrapf(ErrCertificate, "%d endorsements are fewer than the quorum %d", len(endorsed), quorum)
+	}
+	return nil
+}
+
 // commitBlock commits a block to the chain
 func (bc *blockchain) commitBlock(blk *Block) error {
 	// write block into DB
@@ -894,13 +1054,23 @@ func (bc *blockchain) commitBlock(blk *Block) error {
 	return nil
 }
 
-func (bc *blockchain) runActions(blk *Block, ws state.WorkingSet, verify bool) (root hash.Hash32B, err error) {
+func (bc *blockchain) runActions(
+	ctx context.Context,
+	blk *Block,
+	ws state.WorkingSet,
+	verify bool,
+) (root hash.Hash32B, err error) {
 	if bc.sf == nil {
 		return root, nil
 	}
 	// run executions
 	if blk.Executions != nil {
-		ExecuteContracts(blk, ws, bc)
+		if err = executeContracts(ctx, blk, ws, bc); err != nil {
+			return root, err
+		}
+	}
+	if err = ctx.Err(); err != nil {
+		return root, err
 	}
 	// update state factory
 	if root, err = ws.RunActions(blk.Height(), blk.Transfers, blk.Votes, blk.Executions, blk.Actions); err != nil {
@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/facebookgo/clock"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 
 	"github.com/iotexproject/iotex-core/address"
@@ -1008,7 +1009,7 @@ func TestMintDKGBlock(t *testing.T) {
 			PrivateKey: ec283SKList[i],
 			RawAddress: addresses[i],
 		}
-		blk, err := chain.MintNewDKGBlock(nil, nil, nil, nil, &iotxAddr,
+		blk, err := chain.MintNewDKGBlock(context.Background(), nil, nil, nil, nil, &iotxAddr,
 			&iotxaddress.DKGAddress{PrivateKey: askList[i], PublicKey: pkList[i], ID: idList[i]},
 			lastSeed, "")
 		require.NoError(err)
@@ -1021,7 +1022,57 @@ func TestMintDKGBlock(t *testing.T) {
 	height, err := chain.GetFactory().Height()
 	require.NoError(err)
 	require.Equal(uint64(21), height)
+
+	// The minting stops once the context is done
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = chain.MintNewDKGBlock(cancelled, nil, nil, nil, nil, &iotxaddress.Address{
+		PublicKey:  ec283PKList[0],
+		PrivateKey: ec283SKList[0],
+		RawAddress: addresses[0],
+	}, &iotxaddress.DKGAddress{}, lastSeed, "")
+	require.Equal(context.Canceled, errors.Cause(err))
 	candidates, err := chain.CandidatesByHeight(height)
 	require.NoError(err)
 	require.Equal(21, len(candidates))
 }
+
+func TestValidateEvidences(t *testing.T) {
+	require := require.New(t)
+
+	cfg := config.Default
+	cfg.Chain.MaxEvidenceAge = 10
+	offender := ta.Addrinfo["bravo"]
+	bc := &blockchain{config: &cfg, endorsersFunc: func(height uint64) ([]string, int, error) {
+		return []string{ta.Addrinfo["alfa"].RawAddress, offender.RawAddress}, 2, nil
+	}}
+	evidenceAt := func(height uint64, offender *iotxaddress.Address) *Block {
+		endorse := func(blkHash hash.Hash32B) *action.SignedEndorse {
+			return &action.SignedEndorse{
+				Height:   height,
+				Commit:   true,
+				BlkHash:  blkHash,
+				Decision: true,
+				Endorser: offender.RawAddress,
+			}
+		}
+		evidence, err := action.NewDoubleSignEvidence(
+			ta.Addrinfo["producer"].RawAddress,
+			1,
+			endorse(hash.Hash32B{1}),
+			endorse(hash.Hash32B{2}),
+			action.DoubleSignEvidenceIntrinsicGas,
+			big.NewInt(0),
+		)
+		require.NoError(err)
+		return NewBlock(1, 20, hash.ZeroHash32B, testutil.TimestampNow(), nil, nil, nil, []action.Action{evidence})
+	}
+
+	require.NoError(bc.validateEvidences(evidenceAt(20, offender)))
+	require.NoError(bc.validateEvidences(evidenceAt(10, offender)))
+	// The evidence is too old, or from the future
+	require.Equal(ErrInvalidBlock, errors.Cause(bc.validateEvidences(evidenceAt(9, offender))))
+	require.Equal(ErrInvalidBlock, errors.Cause(bc.validateEvidences(evidenceAt(21, offender))))
+	// The offender isn't a delegate
+	require.Equal(ErrInvalidBlock, errors.Cause(bc.validateEvidences(evidenceAt(15, ta.Addrinfo["charlie"]))))
+}
@@ -49,6 +49,8 @@ var (
 	ErrBalance = errors.New("invalid balance")
 	// ErrDKGSecretProposal indicates the error of DKG secret proposal
 	ErrDKGSecretProposal = errors.New("invalid DKG secret proposal")
+	// ErrCertificate indicates the error of the commit certificate of the block
+	ErrCertificate = errors.New("invalid commit certificate")
 )
 
 // Validate validates the given block's content
@@ -232,6 +234,26 @@ func (v *validator) verifyActions(blk *Block, containCoinbase bool) error {
 			return errors.Wrapf(ErrBalance, "negative value")
 		}
 	}
+	for _, act := range blk.Actions {
+		if vote, ok := act.(*action.SignerVote); ok {
+			// Whether the voter is a signer is up to the proof of authority consensus
+			if err := action.Verify(vote); err != nil {
+				return errors.Wrapf(ErrInvalidBlock, "failed to verify the signature of signer vote %x", vote.Hash())
+			}
+			continue
+		}
+		// Verify the double sign evidence, which slashes the offender
+		evidence, ok := act.(*action.DoubleSignEvidence)
+		if !ok {
+			continue
+		}
+		if err := action.Verify(evidence); err != nil {
+			return errors.Wrapf(ErrInvalidBlock, "failed to verify the signature of evidence %x", evidence.Hash())
+		}
+		if err := evidence.Verify(); err != nil {
+			return errors.Wrapf(err, "failed to verify evidence %x", evidence.Hash())
+		}
+	}
 	wg.Wait()
 	// Verify coinbase transfer count
 	if (containCoinbase && coinbaseCount != 1) || (!containCoinbase && coinbaseCount != 0) {
@@ -0,0 +1,118 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/keypair"
+	"github.com/iotexproject/iotex-core/proto"
+)
+
+// EndorsersFunc returns the addresses eligible to endorse the block at the given height, and the number of endorsements
+// needed to commit it
+type EndorsersFunc func(height uint64) ([]string, int, error)
+
+// AggregateVerifierFunc checks the BLS aggregate endorsement of the block of the given height and hash, and returns the
+// endorsers marked in its bitmap
+type AggregateVerifierFunc func(height uint64, blkHash hash.Hash32B, agg *AggregateEndorsement) ([]string, error)
+
+// Endorsement is a commit endorsement of a block by a delegate. The endorsements of a block form the certificate that
+// the consensus commits the block. A dummy block is certified by the timeout votes instead, which reject the empty hash
+// to prove that the delegates timed out without committing any block at the height.
+type Endorsement struct {
+	Endorser       string
+	EndorserPubkey keypair.PublicKey
+	BlkHash        hash.Hash32B
+	Decision       bool
+	Signature      []byte
+}
+
+// Verify checks that the endorsement is signed by the endorser at the given height
+func (en *Endorsement) Verify(height uint64) bool {
+	pubkeyHash := keypair.HashPubKey(en.EndorserPubkey)
+	endorserPubkeyHash, err := iotxaddress.GetPubkeyHash(en.Endorser)
+	if err != nil {
+		return false
+	}
+	if !bytes.Equal(pubkeyHash[:], endorserPubkeyHash) {
+		return false
+	}
+	hash := blake2b.Sum256(action.EndorseByteStream(height, true, en.BlkHash, en.Decision))
+	return crypto.EC283.Verify(en.EndorserPubkey, hash[:], en.Signature)
+}
+
+// IsTimeoutVote returns true if the endorsement is the vote that the endorser timed out without committing any block
+func (en *Endorsement) IsTimeoutVote() bool {
+	return en.BlkHash == hash.ZeroHash32B && !en.Decision
+}
+
+// ConvertToEndorsePb converts Endorsement to EndorsePb of the given height
+func (en *Endorsement) ConvertToEndorsePb(height uint64) *iproto.EndorsePb {
+	return &iproto.EndorsePb{
+		Height:         height,
+		BlockHash:      en.BlkHash[:],
+		Topic:          iproto.EndorsePb_COMMIT,
+		Endorser:       en.Endorser,
+		EndorserPubKey: en.EndorserPubkey[:],
+		Decision:       en.Decision,
+		Signature:      en.Signature,
+	}
+}
+
+// ConvertFromEndorsePb converts EndorsePb to Endorsement
+func (en *Endorsement) ConvertFromEndorsePb(pbEndorse *iproto.EndorsePb) error {
+	pubkey, err := keypair.BytesToPublicKey(pbEndorse.GetEndorserPubKey())
+	if err != nil {
+		return errors.Wrapf(err, "invalid public key of endorser %s", pbEndorse.GetEndorser())
+	}
+	if len(pbEndorse.GetBlockHash()) != len(en.BlkHash) {
+		return errors.Errorf("invalid block hash in the endorsement of %s", pbEndorse.GetEndorser())
+	}
+	en.Endorser = pbEndorse.GetEndorser()
+	en.EndorserPubkey = pubkey
+	copy(en.BlkHash[:], pbEndorse.GetBlockHash())
+	en.Decision = pbEndorse.GetDecision()
+	en.Signature = pbEndorse.GetSignature()
+	return nil
+}
+
+// AggregateEndorsement is the BLS aggregate of the commit endorsements of a block. The endorsers are marked in the bitmap
+// over the endorsers at the block height, and the aggregate signature is the multi-signature of all of them.
+type AggregateEndorsement struct {
+	Bitmap    []byte
+	Signature []byte
+}
+
+// ConvertToEndorsePb converts AggregateEndorsement to EndorsePb of the block of the given height and hash
+func (agg *AggregateEndorsement) ConvertToEndorsePb(height uint64, blkHash hash.Hash32B) *iproto.EndorsePb {
+	return &iproto.EndorsePb{
+		Height:             height,
+		BlockHash:          blkHash[:],
+		Topic:              iproto.EndorsePb_COMMIT,
+		Decision:           true,
+		Bitmap:             agg.Bitmap,
+		AggregateSignature: agg.Signature,
+	}
+}
+
+// ConvertFromEndorsePb converts EndorsePb to AggregateEndorsement
+func (agg *AggregateEndorsement) ConvertFromEndorsePb(pbEndorse *iproto.EndorsePb) error {
+	if len(pbEndorse.GetBitmap()) == 0 || len(pbEndorse.GetAggregateSignature()) == 0 {
+		return errors.New("aggregate endorsement should have the bitmap and the signature")
+	}
+	agg.Bitmap = pbEndorse.GetBitmap()
+	agg.Signature = pbEndorse.GetAggregateSignature()
+	return nil
+}
@@ -0,0 +1,247 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	ta "github.com/iotexproject/iotex-core/test/testaddress"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func signEndorsement(blk *Block, endorser *iotxaddress.Address) *Endorsement {
+	return signCommit(blk.Height(), blk.HashBlock(), true, endorser)
+}
+
+func signTimeoutVote(height uint64, endorser *iotxaddress.Address) *Endorsement {
+	return signCommit(height, hash.ZeroHash32B, false, endorser)
+}
+
+func signCommit(height uint64, blkHash hash.Hash32B, decision bool, endorser *iotxaddress.Address) *Endorsement {
+	hash := blake2b.Sum256(action.EndorseByteStream(height, true, blkHash, decision))
+	return &Endorsement{
+		Endorser:       endorser.RawAddress,
+		EndorserPubkey: endorser.PublicKey,
+		BlkHash:        blkHash,
+		Decision:       decision,
+		Signature:      crypto.EC283.Sign(endorser.PrivateKey, hash[:]),
+	}
+}
+
+func TestVerifyCertificate(t *testing.T) {
+	require := require.New(t)
+
+	delegates := []*iotxaddress.Address{ta.Addrinfo["alfa"], ta.Addrinfo["bravo"], ta.Addrinfo["charlie"]}
+	bc := &blockchain{endorsersFunc: func(height uint64) ([]string, int, error) {
+		require.Equal(uint64(3), height)
+		var endorsers []string
+		for _, delegate := range delegates {
+			endorsers = append(endorsers, delegate.RawAddress)
+		}
+		return endorsers, 2, nil
+	}}
+	blk := NewBlock(1, 3, hash.ZeroHash32B, testutil.TimestampNow(), nil, nil, nil, nil)
+	require.NoError(blk.SignBlock(ta.Addrinfo["producer"]))
+
+	// A block without endorsements is rejected
+	require.Equal(ErrCertificate, errors.Cause(bc.verifyCertificate(blk)))
+
+	// The certificate survives the serialization
+	blk.Endorsements = []*Endorsement{signEndorsement(blk, delegates[0]), signEndorsement(blk, delegates[1])}
+	require.NoError(bc.verifyCertificate(blk))
+	blkBytes, err := blk.Serialize()
+	require.NoError(err)
+	received := &Block{}
+	require.NoError(received.Deserialize(blkBytes))
+	require.Equal(blk.HashBlock(), received.HashBlock())
+	require.Equal(2, len(received.Endorsements))
+	require.NoError(bc.verifyCertificate(received))
+
+	// Fewer endorsements than the quorum
+	blk.Endorsements = []*Endorsement{signEndorsement(blk, delegates[0])}
+	require.Equal(ErrCertificate, errors.Cause(bc.verifyCertificate(blk)))
+
+	// Duplicate endorsements
+	blk.Endorsements = []*Endorsement{signEndorsement(blk, delegates[0]), signEndorsement(blk, delegates[0])}
+	require.Equal(ErrCertificate, errors.Cause(bc.verifyCertificate(blk)))
+
+	// An endorsement from an address which is not an endorser
+	blk.Endorsements = []*Endorsement{signEndorsement(blk, delegates[0]), signEndorsement(blk, ta.Addrinfo["delta"])}
+	require.Equal(ErrCertificate, errors.Cause(bc.verifyCertificate(blk)))
+
+	// An endorsement of another block
+	other := NewBlock(1, 3, blk.HashBlock(), testutil.TimestampNow(), nil, nil, nil, nil)
+	blk.Endorsements = []*Endorsement{signEndorsement(blk, delegates[0]), signEndorsement(other, delegates[1])}
+	require.Equal(ErrCertificate, errors.Cause(bc.verifyCertificate(blk)))
+
+	// An endorsement signed with the key of another endorser
+	forged := signEndorsement(blk, delegates[2])
+	forged.Endorser = delegates[1].RawAddress
+	blk.Endorsements = []*Endorsement{signEndorsement(blk, delegates[0]), forged}
+	require.Equal(ErrCertificate, errors.Cause(bc.verifyCertificate(blk)))
+
+	// A rejection of the block
+	blk.Endorsements = []*Endorsement{signEndorsement(blk, delegates[0]), signCommit(3, blk.HashBlock(), false, delegates[1])}
+	require.Equal(ErrCertificate, errors.Cause(bc.verifyCertificate(blk)))
+	// The decision and the block hash are covered by the signature
+	flipped := signCommit(3, blk.HashBlock(), false, delegates[1])
+	flipped.Decision = true
+	blk.Endorsements = []*Endorsement{signEndorsement(blk, delegates[0]), flipped}
+	require.Equal(ErrCertificate, errors.Cause(bc.verifyCertificate(blk)))
+	// A timeout vote doesn't endorse a block
+	blk.Endorsements = []*Endorsement{signEndorsement(blk, delegates[0]), signTimeoutVote(3, delegates[1])}
+	require.Equal(ErrCertificate, errors.Cause(bc.verifyCertificate(blk)))
+}
+
+func TestVerifyDummyBlockCertificate(t *testing.T) {
+	require := require.New(t)
+
+	delegates := []*iotxaddress.Address{ta.Addrinfo["alfa"], ta.Addrinfo["bravo"], ta.Addrinfo["charlie"]}
+	bc := &blockchain{endorsersFunc: func(height uint64) ([]string, int, error) {
+		var endorsers []string
+		for _, delegate := range delegates {
+			endorsers = append(endorsers, delegate.RawAddress)
+		}
+		return endorsers, 2, nil
+	}}
+	dummy := NewBlock(1, 3, hash.ZeroHash32B, testutil.TimestampNow(), nil, nil, nil, nil)
+	require.True(dummy.IsDummyBlock())
+
+	// The dummy block is proven by the timeout votes of a quorum of the endorsers
+	require.Equal(ErrCertificate, errors.Cause(bc.VerifyHeader(dummy)))
+	dummy.Endorsements = []*Endorsement{signTimeoutVote(3, delegates[0])}
+	require.Equal(ErrCertificate, errors.Cause(bc.VerifyHeader(dummy)))
+	dummy.Endorsements = append(dummy.Endorsements, signTimeoutVote(3, delegates[1]))
+	require.NoError(bc.VerifyHeader(dummy))
+	blkBytes, err := dummy.Serialize()
+	require.NoError(err)
+	received := &Block{}
+	require.NoError(received.Deserialize(blkBytes))
+	require.True(received.IsDummyBlock())
+	require.NoError(bc.VerifyHeader(received))
+
+	// The votes of another height, or the endorsements of a block, don't prove it
+	dummy.Endorsements = []*Endorsement{signTimeoutVote(3, delegates[0]), signTimeoutVote(4, delegates[1])}
+	require.Equal(ErrCertificate, errors.Cause(bc.VerifyHeader(dummy)))
+	dummy.Endorsements = []*Endorsement{signTimeoutVote(3, delegates[0]), signEndorsement(dummy, delegates[1])}
+	require.Equal(ErrCertificate, errors.Cause(bc.VerifyHeader(dummy)))
+
+	// Nor does an aggregate endorsement
+	dummy.Endorsements = []*Endorsement{signTimeoutVote(3, delegates[0]), signTimeoutVote(3, delegates[1])}
+	dummy.AggregateEndorsement = &AggregateEndorsement{Bitmap: []byte{0x07}, Signature: []byte{1}}
+	require.Equal(ErrCertificate, errors.Cause(bc.VerifyHeader(dummy)))
+}
+
+func TestVerifyAggregateCertificate(t *testing.T) {
+	require := require.New(t)
+
+	delegates := []*iotxaddress.Address{ta.Addrinfo["alfa"], ta.Addrinfo["bravo"], ta.Addrinfo["charlie"]}
+	bc := &blockchain{endorsersFunc: func(height uint64) ([]string, int, error) {
+		var endorsers []string
+		for _, delegate := range delegates {
+			endorsers = append(endorsers, delegate.RawAddress)
+		}
+		return endorsers, 3, nil
+	}}
+	blk := NewBlock(1, 3, hash.ZeroHash32B, testutil.TimestampNow(), nil, nil, nil, nil)
+	require.NoError(blk.SignBlock(ta.Addrinfo["producer"]))
+	blk.Endorsements = []*Endorsement{signEndorsement(blk, delegates[0])}
+	blk.AggregateEndorsement = &AggregateEndorsement{Bitmap: []byte{0x06}, Signature: []byte{1}}
+
+	// The aggregate endorsement can't be verified without the verifier
+	require.Equal(ErrCertificate, errors.Cause(bc.verifyCertificate(blk)))
+
+	// The endorsers in the aggregate endorsement count towards the quorum together with the individual ones
+	aggEndorsers := []string{delegates[1].RawAddress, delegates[2].RawAddress}
+	bc.SetAggregateVerifierFunc(func(height uint64, blkHash hash.Hash32B, agg *AggregateEndorsement) ([]string, error) {
+		if blkHash != blk.HashBlock() {
+			return nil, errors.New("aggregate endorsement of another block")
+		}
+		return aggEndorsers, nil
+	})
+	require.NoError(bc.verifyCertificate(blk))
+	blkBytes, err := blk.Serialize()
+	require.NoError(err)
+	received := &Block{}
+	require.NoError(received.Deserialize(blkBytes))
+	require.Equal(blk.AggregateEndorsement, received.AggregateEndorsement)
+	require.NoError(bc.verifyCertificate(received))
+
+	// The same endorser counts once
+	aggEndorsers = []string{delegates[0].RawAddress, delegates[1].RawAddress}
+	require.Equal(ErrCertificate, errors.Cause(bc.verifyCertificate(blk)))
+
+	// An aggregated endorser which is not an endorser
+	aggEndorsers = []string{delegates[1].RawAddress, ta.Addrinfo["delta"].RawAddress}
+	require.Equal(ErrCertificate, errors.Cause(bc.verifyCertificate(blk)))
+
+	// The aggregate endorsement alone makes the certificate
+	blk.Endorsements = nil
+	blk.AggregateEndorsement.Bitmap = []byte{0x07}
+	aggEndorsers = []string{delegates[0].RawAddress, delegates[1].RawAddress, delegates[2].RawAddress}
+	require.NoError(bc.verifyCertificate(blk))
+
+	// An aggregate endorsement of another block
+	other := NewBlock(1, 3, blk.HashBlock(), testutil.TimestampNow(), nil, nil, nil, nil)
+	other.AggregateEndorsement = blk.AggregateEndorsement
+	require.Equal(ErrCertificate, errors.Cause(bc.verifyCertificate(other)))
+}
+
+func TestVerifyHeader(t *testing.T) {
+	require := require.New(t)
+
+	delegates := []*iotxaddress.Address{ta.Addrinfo["alfa"], ta.Addrinfo["bravo"], ta.Addrinfo["charlie"]}
+	bc := &blockchain{}
+	blk := NewBlock(1, 3, hash.ZeroHash32B, testutil.TimestampNow(), nil, nil, nil, nil)
+	require.NoError(blk.SignBlock(delegates[0]))
+
+	// Only the signature is verified if the consensus doesn't endorse the blocks
+	require.NoError(bc.VerifyHeader(blk))
+	forged := NewBlock(1, 3, hash.ZeroHash32B, testutil.TimestampNow(), nil, nil, nil, nil)
+	require.NoError(forged.SignBlock(delegates[0]))
+	forged.Header.Pubkey = delegates[1].PublicKey
+	require.Equal(ErrInvalidBlock, errors.Cause(bc.VerifyHeader(forged)))
+
+	// The header must carry the certificate
+	bc.endorsersFunc = func(height uint64) ([]string, int, error) {
+		if height > 3 {
+			return nil, 0, errors.New("unknown endorsers")
+		}
+		var endorsers []string
+		for _, delegate := range delegates {
+			endorsers = append(endorsers, delegate.RawAddress)
+		}
+		return endorsers, 2, nil
+	}
+	require.Equal(ErrCertificate, errors.Cause(bc.VerifyHeader(blk)))
+	blk.Endorsements = []*Endorsement{signEndorsement(blk, delegates[1])}
+	require.Equal(ErrCertificate, errors.Cause(bc.VerifyHeader(blk)))
+	blk.Endorsements = append(blk.Endorsements, signEndorsement(blk, delegates[2]))
+	require.NoError(bc.VerifyHeader(blk))
+
+	// The producer must be an endorser
+	other := NewBlock(1, 3, hash.ZeroHash32B, testutil.TimestampNow(), nil, nil, nil, nil)
+	require.NoError(other.SignBlock(ta.Addrinfo["producer"]))
+	other.Endorsements = []*Endorsement{signEndorsement(other, delegates[1]), signEndorsement(other, delegates[2])}
+	require.Equal(ErrInvalidBlock, errors.Cause(bc.VerifyHeader(other)))
+
+	// The header can't be verified while the endorsers at its height are unknown
+	later := NewBlock(1, 4, blk.HashBlock(), testutil.TimestampNow(), nil, nil, nil, nil)
+	require.NoError(later.SignBlock(delegates[0]))
+	err := bc.VerifyHeader(later)
+	require.Error(err)
+	require.NotEqual(ErrInvalidBlock, errors.Cause(err))
+	require.NotEqual(ErrCertificate, errors.Cause(err))
+}
@@ -7,6 +7,7 @@
 package blockchain
 
 import (
+	"context"
 	"math"
 	"math/big"
 
@@ -139,14 +140,24 @@ func securityDeposit(ps *EVMParams, stateDB vm.StateDB, gasLimit *uint64) error
 
 // ExecuteContracts process the contracts in a block
 func ExecuteContracts(blk *Block, ws state.WorkingSet, bc Blockchain) {
+	// The background context is never done
+	_ = executeContracts(context.Background(), blk, ws, bc)
+}
+
+// executeContracts processes the contracts in a block, and stops once the context is done
+func executeContracts(ctx context.Context, blk *Block, ws state.WorkingSet, bc Blockchain) error {
 	gasLimit := action.GasLimit
 	blk.receipts = make(map[hash.Hash32B]*Receipt)
 	for idx, execution := range blk.Executions {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		// TODO (zhi) log receipt to stateDB
 		if receipt, _ := executeContract(blk, ws, idx, execution, bc, &gasLimit); receipt != nil {
 			blk.receipts[execution.Hash()] = receipt
 		}
 	}
+	return nil
 }
 
 // executeContract processes a transfer which contains a contract
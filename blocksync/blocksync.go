@@ -8,7 +8,9 @@ package blocksync
 
 import (
 	"context"
+	"net"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 
 	"github.com/iotexproject/iotex-core/actpool"
@@ -17,10 +19,15 @@ import (
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/network"
 	"github.com/iotexproject/iotex-core/network/node"
+	"github.com/iotexproject/iotex-core/pkg/hash"
 	"github.com/iotexproject/iotex-core/pkg/lifecycle"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
 	pb "github.com/iotexproject/iotex-core/proto"
 )
 
+// syncBatchEnvelope is the room reserved in a message for the fields besides the blocks in a sync response batch
+const syncBatchEnvelope = 1024
+
 // BlockSync defines the interface of blocksyncer
 type BlockSync interface {
 	lifecycle.StartStopper
@@ -28,7 +35,11 @@ type BlockSync interface {
 	P2P() network.Overlay
 	ProcessSyncRequest(sender string, sync *pb.BlockSync) error
 	ProcessBlock(blk *blockchain.Block) error
-	ProcessBlockSync(blk *blockchain.Block) error
+	ProcessBlockSync(sender net.Addr, blk *blockchain.Block) error
+	ProcessChainTip(sender string, tip *pb.ChainTip) error
+	ProcessHeaderSyncRequest(sender string, sync *pb.BlockHeaderSync) error
+	ProcessBlockHeaders(sender net.Addr, headers []*blockchain.Block) error
+	SyncStatus() SyncStatus
 }
 
 // blockSyncer implements BlockSync interface
@@ -40,6 +51,11 @@ type blockSyncer struct {
 	worker         *syncWorker
 	bc             blockchain.Blockchain
 	p2p            network.Overlay
+	// maxBatchSize is the max size of the blocks in a sync response, which is unlimited if it is 0
+	maxBatchSize int
+	maxHeaders   uint64
+	// requireCert is true if the blocks have to carry the commit certificates of the consensus
+	requireCert bool
 }
 
 // NewBlockSyncer returns a new block syncer instance
@@ -60,6 +76,10 @@ func NewBlockSyncer(
 		size:   cfg.BlockSync.BufferSize,
 	}
 	w := newSyncWorker(chain.ChainID(), cfg, p2p, buf)
+	maxBatchSize := 0
+	if cfg.Network.MaxMsgSize > syncBatchEnvelope {
+		maxBatchSize = cfg.Network.MaxMsgSize - syncBatchEnvelope
+	}
 	return &blockSyncer{
 		ackBlockCommit: cfg.IsDelegate() || cfg.IsFullnode(),
 		ackBlockSync:   cfg.IsDelegate() || cfg.IsFullnode(),
@@ -68,6 +88,9 @@ func NewBlockSyncer(
 		buf:            buf,
 		p2p:            p2p,
 		worker:         w,
+		maxBatchSize:   maxBatchSize,
+		maxHeaders:     cfg.BlockSync.MaxHeadersPerRequest,
+		requireCert:    cfg.Consensus.Scheme == config.RollDPoSScheme,
 	}, nil
 }
 
@@ -76,6 +99,11 @@ func (bs *blockSyncer) P2P() network.Overlay {
 	return bs.p2p
 }
 
+// SyncStatus returns the progress of the block sync
+func (bs *blockSyncer) SyncStatus() SyncStatus {
+	return bs.worker.Status()
+}
+
 // Start starts a block syncer
 func (bs *blockSyncer) Start(ctx context.Context) error {
 	logger.Debug().Msg("Starting block syncer")
@@ -100,6 +128,9 @@ func (bs *blockSyncer) ProcessBlock(blk *blockchain.Block) error {
 		// node is not meant to handle latest committed block, simply exit
 		return nil
 	}
+	if err := bs.checkCertificate(blk); err != nil {
+		return err
+	}
 
 	var needSync bool
 	moved, re := bs.buf.Flush(blk)
@@ -120,15 +151,40 @@ func (bs *blockSyncer) ProcessBlock(blk *blockchain.Block) error {
 	return nil
 }
 
-func (bs *blockSyncer) ProcessBlockSync(blk *blockchain.Block) error {
+// ProcessBlockSync processes a block from the response of a sync request to the sender
+func (bs *blockSyncer) ProcessBlockSync(sender net.Addr, blk *blockchain.Block) error {
 	if !bs.ackBlockSync {
 		// node is not meant to handle sync block, simply exit
 		return nil
 	}
-	bs.buf.Flush(blk)
+	if err := bs.checkCertificate(blk); err != nil {
+		return err
+	}
+	if err := bs.worker.CheckBlock(sender, blk); err != nil {
+		return err
+	}
+	_, re := bs.buf.Flush(blk)
+	// Request the next blocks right away instead of waiting for the next round once a request is completed, rather than
+	// on every block
+	if bs.worker.Complete(sender.String(), blk.Height()) {
+		bs.worker.Sync()
+	}
+	if re == bCheckinLower || re == bCheckinExisting {
+		return errors.Wrapf(network.ErrUselessMsg, "sync block %d is already known", blk.Height())
+	}
 	return nil
 }
 
+// checkCertificate rejects the block without the commit certificate if the consensus embeds it into the blocks, so that
+// a single delegate can't feed the node an alternate chain. The dummy blocks aren't exempted, because they carry the
+// timeout votes as the certificate. The certificate itself is verified when the block is committed.
+func (bs *blockSyncer) checkCertificate(blk *blockchain.Block) error {
+	if !bs.requireCert || len(blk.Endorsements) > 0 || blk.AggregateEndorsement != nil {
+		return nil
+	}
+	return errors.Wrapf(network.ErrInvalidMsg, "block %d has no commit certificate", blk.Height())
+}
+
 // ProcessSyncRequest processes a block sync request
 func (bs *blockSyncer) ProcessSyncRequest(sender string, sync *pb.BlockSync) error {
 	if !bs.ackSyncReq {
@@ -136,15 +192,108 @@ func (bs *blockSyncer) ProcessSyncRequest(sender string, sync *pb.BlockSync) err
 		return nil
 	}
 
+	// The blocks are sent back in batches as large as a message could be
+	var (
+		batch []*pb.BlockPb
+		size  int
+	)
 	for i := sync.Start; i <= sync.End; i++ {
 		blk, err := bs.bc.GetBlockByHeight(i)
 		if err != nil {
+			bs.sendBlocks(sender, batch)
 			return err
 		}
-		// TODO: send back multiple blocks in one shot
-		if err := bs.p2p.Tell(bs.bc.ChainID(), node.NewTCPNode(sender), &pb.BlockContainer{Block: blk.ConvertToBlockPb()}); err != nil {
-			logger.Warn().Err(err).Msg("Failed to response to ProcessSyncRequest.")
+		pbBlk := blk.ConvertToBlockPb()
+		// Each block takes a tag and a length prefix of up to 5 bytes besides itself
+		blkSize := proto.Size(pbBlk) + 6
+		if len(batch) > 0 && bs.maxBatchSize > 0 && size+blkSize > bs.maxBatchSize {
+			bs.sendBlocks(sender, batch)
+			batch, size = nil, 0
 		}
+		batch = append(batch, pbBlk)
+		size += blkSize
 	}
+	bs.sendBlocks(sender, batch)
 	return nil
 }
+
+// ProcessChainTip processes the tip of the chain which a peer advertises
+func (bs *blockSyncer) ProcessChainTip(sender string, tip *pb.ChainTip) error {
+	if len(tip.Hash) != hash.HashSize {
+		return errors.Wrapf(network.ErrInvalidMsg, "chain tip hash of %d bytes", len(tip.Hash))
+	}
+	bs.worker.SetPeerTip(sender, tip.Height, byteutil.BytesTo32B(tip.Hash))
+	return nil
+}
+
+// ProcessHeaderSyncRequest processes a header sync request
+func (bs *blockSyncer) ProcessHeaderSyncRequest(sender string, sync *pb.BlockHeaderSync) error {
+	if !bs.ackSyncReq {
+		// node is not meant to handle sync request, simply exit
+		return nil
+	}
+
+	end := sync.End
+	if bs.maxHeaders > 0 && end >= sync.Start+bs.maxHeaders {
+		end = sync.Start + bs.maxHeaders - 1
+	}
+	// The headers carry the commit certificates, so that the requester could verify them before the blocks
+	data := &pb.BlockHeaderContainer{}
+	size := 0
+	for i := sync.Start; i <= end; i++ {
+		blk, err := bs.bc.GetBlockByHeight(i)
+		if err != nil {
+			bs.sendHeaders(sender, data)
+			return err
+		}
+		header := blk.ConvertToBlockHeaderPb()
+		cert := blk.ConvertToBlockCertificatePb()
+		size += proto.Size(header) + proto.Size(cert) + 12
+		if len(data.Headers) > 0 && bs.maxBatchSize > 0 && size > bs.maxBatchSize {
+			break
+		}
+		data.Headers = append(data.Headers, header)
+		data.Certificates = append(data.Certificates, cert)
+	}
+	bs.sendHeaders(sender, data)
+	return nil
+}
+
+// ProcessBlockHeaders processes the headers from the response of a header sync request
+func (bs *blockSyncer) ProcessBlockHeaders(sender net.Addr, headers []*blockchain.Block) error {
+	if !bs.ackBlockSync {
+		// node is not meant to handle sync block, simply exit
+		return nil
+	}
+	if err := bs.worker.AddHeaders(sender, headers); err != nil {
+		return err
+	}
+	// Request the blocks of the new headers right away instead of waiting for the next round
+	bs.worker.Sync()
+	return nil
+}
+
+// sendHeaders sends the headers to the requester of a header sync
+func (bs *blockSyncer) sendHeaders(sender string, data *pb.BlockHeaderContainer) {
+	if len(data.Headers) == 0 {
+		return
+	}
+	if err := bs.p2p.Tell(bs.bc.ChainID(), node.NewTCPNode(sender), data); err != nil {
+		logger.Warn().Err(err).Msg("Failed to response to ProcessHeaderSyncRequest.")
+	}
+}
+
+// sendBlocks sends a batch of blocks to the requester of a sync
+func (bs *blockSyncer) sendBlocks(sender string, blocks []*pb.BlockPb) {
+	if len(blocks) == 0 {
+		return
+	}
+	data := &pb.BlockContainer{Blocks: blocks}
+	if len(blocks) == 1 {
+		// A single block is sent in the field which the nodes of old versions read
+		data = &pb.BlockContainer{Block: blocks[0]}
+	}
+	if err := bs.p2p.Tell(bs.bc.ChainID(), node.NewTCPNode(sender), data); err != nil {
+		logger.Warn().Err(err).Msg("Failed to response to ProcessSyncRequest.")
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -19,6 +20,7 @@ import (
 	bc "github.com/iotexproject/iotex-core/blockchain"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/network"
+	"github.com/iotexproject/iotex-core/network/node"
 	"github.com/iotexproject/iotex-core/pkg/hash"
 	pb "github.com/iotexproject/iotex-core/proto"
 	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
@@ -58,7 +60,7 @@ func TestSyncTaskInterval(t *testing.T) {
 	assert.Equal(interval, fullNode)
 }
 
-func generateP2P() network.Overlay {
+func generateP2P(t *testing.T) network.Overlay {
 	c := &config.Network{
 		Host: "127.0.0.1",
 		Port: 10001,
@@ -76,14 +78,16 @@ func generateP2P() network.Overlay {
 		MaxMsgSize:              1024 * 1024 * 10,
 		PeerDiscovery:           true,
 	}
-	return network.NewOverlay(c)
+	p2p, err := network.NewOverlay(c, config.Default.Chain.ID)
+	require.NoError(t, err)
+	return p2p
 }
 
 func TestNewBlockSyncer(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
 
-	p2p := generateP2P()
+	p2p := generateP2P(t)
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -168,7 +172,7 @@ func TestBlockSyncerProcessSyncRequest(t *testing.T) {
 	require.Nil(err)
 	ap, err := actpool.NewActPool(mBc, cfg.ActPool)
 	assert.NoError(err)
-	p2p := generateP2P()
+	p2p := generateP2P(t)
 
 	cfgFullNode := &config.Config{
 		NodeType: config.FullNodeType,
@@ -203,7 +207,9 @@ func TestBlockSyncerProcessSyncRequestError(t *testing.T) {
 	ap, err := actpool.NewActPool(chain, cfg.ActPool)
 	require.NotNil(ap)
 	require.NoError(err)
-	bs, err := NewBlockSyncer(cfg, chain, ap, network.NewOverlay(&cfg.Network))
+	p2p, err := network.NewOverlay(&cfg.Network, cfg.Chain.ID)
+	require.NoError(err)
+	bs, err := NewBlockSyncer(cfg, chain, ap, p2p)
 	require.Nil(err)
 
 	defer func() {
@@ -235,7 +241,9 @@ func TestBlockSyncerProcessBlockTipHeight(t *testing.T) {
 	ap, err := actpool.NewActPool(chain, cfg.ActPool)
 	require.NotNil(ap)
 	require.NoError(err)
-	bs, err := NewBlockSyncer(cfg, chain, ap, network.NewOverlay(&cfg.Network))
+	p2p, err := network.NewOverlay(&cfg.Network, cfg.Chain.ID)
+	require.NoError(err)
+	bs, err := NewBlockSyncer(cfg, chain, ap, p2p)
 	require.Nil(err)
 
 	defer func() {
@@ -279,7 +287,9 @@ func TestBlockSyncerProcessBlockOutOfOrder(t *testing.T) {
 	ap1, err := actpool.NewActPool(chain1, cfg.ActPool)
 	require.NotNil(ap1)
 	require.NoError(err)
-	bs1, err := NewBlockSyncer(cfg, chain1, ap1, network.NewOverlay(&cfg.Network))
+	p2p1, err := network.NewOverlay(&cfg.Network, cfg.Chain.ID)
+	require.NoError(err)
+	bs1, err := NewBlockSyncer(cfg, chain1, ap1, p2p1)
 	require.Nil(err)
 	chain2 := bc.NewBlockchain(cfg, bc.InMemStateFactoryOption(), bc.InMemDaoOption())
 	require.NoError(chain2.Start(ctx))
@@ -287,7 +297,9 @@ func TestBlockSyncerProcessBlockOutOfOrder(t *testing.T) {
 	ap2, err := actpool.NewActPool(chain2, cfg.ActPool)
 	require.NotNil(ap2)
 	require.Nil(err)
-	bs2, err := NewBlockSyncer(cfg, chain2, ap2, network.NewOverlay(&cfg.Network))
+	p2p2, err := network.NewOverlay(&cfg.Network, cfg.Chain.ID)
+	require.NoError(err)
+	bs2, err := NewBlockSyncer(cfg, chain2, ap2, p2p2)
 	require.Nil(err)
 
 	defer func() {
@@ -335,7 +347,9 @@ func TestBlockSyncerProcessBlockSync(t *testing.T) {
 	ap1, err := actpool.NewActPool(chain1, cfg.ActPool)
 	require.NotNil(ap1)
 	require.Nil(err)
-	bs1, err := NewBlockSyncer(cfg, chain1, ap1, network.NewOverlay(&cfg.Network))
+	p2p1, err := network.NewOverlay(&cfg.Network, cfg.Chain.ID)
+	require.NoError(err)
+	bs1, err := NewBlockSyncer(cfg, chain1, ap1, p2p1)
 	require.Nil(err)
 	chain2 := bc.NewBlockchain(cfg, bc.InMemStateFactoryOption(), bc.InMemDaoOption())
 	require.NoError(chain2.Start(ctx))
@@ -343,7 +357,9 @@ func TestBlockSyncerProcessBlockSync(t *testing.T) {
 	ap2, err := actpool.NewActPool(chain2, cfg.ActPool)
 	require.NotNil(ap2)
 	require.Nil(err)
-	bs2, err := NewBlockSyncer(cfg, chain2, ap2, network.NewOverlay(&cfg.Network))
+	p2p2, err := network.NewOverlay(&cfg.Network, cfg.Chain.ID)
+	require.NoError(err)
+	bs2, err := NewBlockSyncer(cfg, chain2, ap2, p2p2)
 	require.Nil(err)
 
 	defer func() {
@@ -369,11 +385,21 @@ func TestBlockSyncerProcessBlockSync(t *testing.T) {
 	h1 := chain1.TipHeight()
 	assert.Equal(t, uint64(3), h1)
 
-	require.Nil(bs2.ProcessBlockSync(blk3))
-	require.Nil(bs2.ProcessBlockSync(blk2))
-	require.Nil(bs2.ProcessBlockSync(blk1))
+	// The blocks are only accepted from the peer which they are requested from
+	sender := node.NewTCPNode("127.0.0.1:10001")
+	require.Equal(network.ErrUselessMsg, errors.Cause(bs2.ProcessBlockSync(sender, blk3)))
+	w := bs2.(*blockSyncer).worker
+	for h := uint64(1); h <= 3; h++ {
+		w.pending = append(w.pending, &syncRequest{interval: syncBlocksInterval{Start: h, End: h}, peer: sender.String()})
+	}
+	require.Nil(bs2.ProcessBlockSync(sender, blk3))
+	require.Nil(bs2.ProcessBlockSync(sender, blk2))
+	require.Nil(bs2.ProcessBlockSync(sender, blk1))
 	h2 := chain2.TipHeight()
 	assert.Equal(t, h1, h2)
+	// Blocks already known are useless
+	w.pending = append(w.pending, &syncRequest{interval: syncBlocksInterval{Start: 2, End: 2}, peer: sender.String()})
+	require.Equal(network.ErrUselessMsg, errors.Cause(bs2.ProcessBlockSync(sender, blk2)))
 }
 
 func TestBlockSyncerSync(t *testing.T) {
@@ -391,7 +417,9 @@ func TestBlockSyncerSync(t *testing.T) {
 	require.NotNil(ap)
 	require.NoError(err)
 
-	bs, err := NewBlockSyncer(cfg, chain, ap, network.NewOverlay(&cfg.Network))
+	p2p, err := network.NewOverlay(&cfg.Network, cfg.Chain.ID)
+	require.NoError(err)
+	bs, err := NewBlockSyncer(cfg, chain, ap, p2p)
 	require.NotNil(bs)
 	require.NoError(err)
 	require.Nil(bs.Start(ctx))
@@ -416,6 +444,32 @@ func TestBlockSyncerSync(t *testing.T) {
 	time.Sleep(time.Millisecond << 7)
 }
 
+func TestBlockSyncerRequireCertificate(t *testing.T) {
+	require := require.New(t)
+
+	bs := &blockSyncer{ackBlockCommit: true, ackBlockSync: true, requireCert: true}
+	blk := bc.NewBlock(config.Default.Chain.ID, 1, hash.ZeroHash32B, testutil.TimestampNow(), nil, nil, nil, nil)
+	require.NoError(blk.SignBlock(ta.Addrinfo["producer"]))
+
+	// The blocks without the commit certificates are invalid
+	require.Equal(network.ErrInvalidMsg, errors.Cause(bs.ProcessBlock(blk)))
+	require.Equal(network.ErrInvalidMsg, errors.Cause(bs.ProcessBlockSync(node.NewTCPNode("127.0.0.1:10001"), blk)))
+
+	// The certificates are checked by the chain, and the dummy blocks have to carry the timeout votes
+	blk.Endorsements = []*bc.Endorsement{{Endorser: ta.Addrinfo["alfa"].RawAddress}}
+	require.NoError(bs.checkCertificate(blk))
+	dummy := bc.NewBlock(config.Default.Chain.ID, 1, hash.ZeroHash32B, testutil.TimestampNow(), nil, nil, nil, nil)
+	require.True(dummy.IsDummyBlock())
+	require.Equal(network.ErrInvalidMsg, errors.Cause(bs.checkCertificate(dummy)))
+	dummy.Endorsements = []*bc.Endorsement{{Endorser: ta.Addrinfo["alfa"].RawAddress}}
+	require.NoError(bs.checkCertificate(dummy))
+
+	// The certificates are not required if the consensus doesn't embed them
+	bs.requireCert = false
+	blk.Endorsements = nil
+	require.NoError(bs.checkCertificate(blk))
+}
+
 func newTestConfig() (*config.Config, error) {
 	cfg := config.Default
 	cfg.Chain.TrieDBPath = "trie.test"
@@ -134,6 +134,13 @@ func (b *blockBuffer) Flush(blk *blockchain.Block) (bool, bCheckinResult) {
 	return moved, bCheckinValid
 }
 
+// ConfirmedHeight returns the height of the highest block committed through the buffer
+func (b *blockBuffer) ConfirmedHeight() uint64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.confirmedHeight
+}
+
 // GetBlocksIntervalsToSync returns groups of syncBlocksInterval are missing upto targetHeight.
 func (b *blockBuffer) GetBlocksIntervalsToSync(targetHeight uint64) []syncBlocksInterval {
 	var (
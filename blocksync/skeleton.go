@@ -0,0 +1,129 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blocksync
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/network"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+)
+
+// peerTip is the tip of the chain which a peer advertises
+type peerTip struct {
+	height uint64
+	hash   hash.Hash32B
+}
+
+// skeletonHeader is a validated header in the skeleton
+type skeletonHeader struct {
+	hash     hash.Hash32B
+	prevHash hash.Hash32B
+	// supplier is the peer sending the header, which is to blame if the block doesn't match it
+	supplier net.Addr
+}
+
+// skeleton is the chain of the headers synced ahead of the blocks above the tip of the local chain. Each header links to
+// the previous one, and the first one links to the tip, so that a block downloaded from any peer could be checked
+// against the header at its height before it goes into the buffer.
+type skeleton struct {
+	headers map[uint64]*skeletonHeader
+	// tip is the height of the highest header, or the height of the local chain if there is no header
+	tip uint64
+}
+
+func newSkeleton() *skeleton {
+	return &skeleton{headers: make(map[uint64]*skeletonHeader)}
+}
+
+// hash returns the hash of the header at the given height, if it is in the skeleton
+func (s *skeleton) hash(height uint64) (hash.Hash32B, bool) {
+	header, ok := s.headers[height]
+	if !ok {
+		return hash.ZeroHash32B, false
+	}
+	return header.hash, true
+}
+
+// supplier returns the peer which sent the header at the given height, if it is in the skeleton
+func (s *skeleton) supplier(height uint64) (net.Addr, bool) {
+	header, ok := s.headers[height]
+	if !ok {
+		return nil, false
+	}
+	return header.supplier, true
+}
+
+// reset drops all the headers, so that the skeleton is built again from the tip of the local chain
+func (s *skeleton) reset(tipHeight uint64) {
+	s.headers = make(map[uint64]*skeletonHeader)
+	s.tip = tipHeight
+}
+
+// prune drops the headers up to the tip of the local chain. The whole skeleton is dropped if it doesn't link to the
+// tip, e.g., when the chain commits a block of another fork.
+func (s *skeleton) prune(tipHeight uint64, tipHash hash.Hash32B) {
+	for h := range s.headers {
+		if h <= tipHeight {
+			delete(s.headers, h)
+		}
+	}
+	if next, ok := s.headers[tipHeight+1]; ok && next.prevHash != tipHash {
+		s.headers = make(map[uint64]*skeletonHeader)
+	}
+	if len(s.headers) == 0 || s.tip < tipHeight {
+		s.tip = tipHeight
+	}
+}
+
+// extend appends the headers sent by the supplier to the skeleton. The headers have to be consecutive from the one above
+// the highest header, and link to it by the previous hash, which is looked up in the local chain if the skeleton is
+// empty.
+func (s *skeleton) extend(
+	headers []*blockchain.Block,
+	supplier net.Addr,
+	prevHashOf func(uint64) (hash.Hash32B, error),
+) error {
+	if len(headers) == 0 {
+		return nil
+	}
+	if headers[0].Height() != s.tip+1 {
+		return errors.Wrapf(network.ErrInvalidMsg, "header %d doesn't follow the skeleton at %d", headers[0].Height(), s.tip)
+	}
+	prevHash, ok := s.hash(s.tip)
+	if !ok {
+		var err error
+		if prevHash, err = prevHashOf(s.tip); err != nil {
+			return errors.Wrapf(err, "failed to get the hash of block %d", s.tip)
+		}
+	}
+	// The peer may be on another fork if the first header doesn't link up, which is not its fault
+	if headers[0].PrevHash() != prevHash {
+		return errors.Errorf("header %d doesn't link to the skeleton", headers[0].Height())
+	}
+	for i, header := range headers {
+		if header.Height() != s.tip+1+uint64(i) {
+			return errors.Wrapf(network.ErrInvalidMsg, "header %d is not consecutive", header.Height())
+		}
+		if header.PrevHash() != prevHash {
+			return errors.Wrapf(network.ErrInvalidMsg, "header %d doesn't link to the previous one", header.Height())
+		}
+		prevHash = header.HashBlock()
+	}
+	for _, header := range headers {
+		s.headers[header.Height()] = &skeletonHeader{
+			hash:     header.HashBlock(),
+			prevHash: header.PrevHash(),
+			supplier: supplier,
+		}
+	}
+	s.tip = headers[len(headers)-1].Height()
+	return nil
+}
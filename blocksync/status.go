@@ -0,0 +1,184 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blocksync
+
+import (
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// progressWindow is the period over which the sync speed is measured
+const progressWindow = time.Minute
+
+var (
+	statusMtc = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iotex_blocksync_status",
+			Help: "Block sync status.",
+		},
+		[]string{"status_type"},
+	)
+	failureMtc = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_blocksync_failure",
+			Help: "Block sync failure counter.",
+		},
+		[]string{"reason"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(statusMtc)
+	prometheus.MustRegister(failureMtc)
+}
+
+// SyncStatus is the progress of the block sync
+type SyncStatus struct {
+	// Synced is true if the chain is at most a few blocks behind the target height
+	Synced bool
+	// TipHeight is the height of the local chain
+	TipHeight uint64
+	// TargetHeight is the median of the tips advertised by the peers, or the top of the verified headers if it is
+	// higher, so that a few peers advertising fake tips can't keep the node from being synced
+	TargetHeight uint64
+	// ConfirmedHeight is the height of the highest block committed through the sync buffer
+	ConfirmedHeight uint64
+	// BlocksPerSecond is the number of blocks confirmed per second over the last minute
+	BlocksPerSecond float64
+	// ETA is the estimated time to reach the target height, which is 0 if the chain is synced or not progressing
+	ETA time.Duration
+	// Peers is the sync state of the connected peers
+	Peers []PeerSyncStatus
+}
+
+// PeerSyncStatus is the sync state of a peer
+type PeerSyncStatus struct {
+	Address string
+	// TipHeight is the height of the tip which the peer advertises, which is 0 if unknown
+	TipHeight uint64
+	// PendingRequests is the number of block and header sync requests pending at the peer
+	PendingRequests int
+	// Failures is the number of requests which the peer times out on or responds to with invalid data
+	Failures int
+}
+
+// progressSample is the confirmed height at some time
+type progressSample struct {
+	at     time.Time
+	height uint64
+}
+
+// Status returns the progress of the block sync, and updates the metrics
+func (w *syncWorker) Status() SyncStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	tipHeight := w.buf.bc.TipHeight()
+	confirmed := w.buf.ConfirmedHeight()
+	w.sample(confirmed)
+	status := SyncStatus{
+		TipHeight:       tipHeight,
+		TargetHeight:    w.medianTip(),
+		ConfirmedHeight: confirmed,
+		BlocksPerSecond: w.speed(),
+	}
+	if w.skel.tip > status.TargetHeight {
+		status.TargetHeight = w.skel.tip
+	}
+	if status.TargetHeight < tipHeight {
+		status.TargetHeight = tipHeight
+	}
+	status.Synced = tipHeight+w.syncedThreshold >= status.TargetHeight
+	if !status.Synced && status.BlocksPerSecond > 0 {
+		status.ETA = time.Duration(float64(status.TargetHeight-tipHeight) / status.BlocksPerSecond * float64(time.Second))
+	}
+
+	peersInUse := 0
+	for _, p := range w.p2p.GetPeers() {
+		peer := PeerSyncStatus{
+			Address:         p.String(),
+			PendingRequests: w.numPending(p.String()),
+			Failures:        w.failures[p.String()],
+		}
+		if tip, ok := w.tips[peer.Address]; ok {
+			peer.TipHeight = tip.height
+		}
+		if w.headerReq != nil && w.headerReq.peer == peer.Address {
+			peer.PendingRequests++
+		}
+		if peer.PendingRequests > 0 {
+			peersInUse++
+		}
+		status.Peers = append(status.Peers, peer)
+	}
+
+	synced := 0.0
+	if status.Synced {
+		synced = 1
+	}
+	statusMtc.WithLabelValues("synced").Set(synced)
+	statusMtc.WithLabelValues("tipHeight").Set(float64(status.TipHeight))
+	statusMtc.WithLabelValues("targetHeight").Set(float64(status.TargetHeight))
+	statusMtc.WithLabelValues("confirmedHeight").Set(float64(status.ConfirmedHeight))
+	statusMtc.WithLabelValues("blocksPerSecond").Set(status.BlocksPerSecond)
+	statusMtc.WithLabelValues("etaSeconds").Set(status.ETA.Seconds())
+	statusMtc.WithLabelValues("peersInUse").Set(float64(peersInUse))
+	return status
+}
+
+// medianTip returns the median of the tips advertised by the peers, which at least half of them reach, or 0 if no peer
+// advertises its tip
+func (w *syncWorker) medianTip() uint64 {
+	if len(w.tips) == 0 {
+		return 0
+	}
+	heights := make([]uint64, 0, len(w.tips))
+	for _, tip := range w.tips {
+		heights = append(heights, tip.height)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	return heights[(len(heights)-1)/2]
+}
+
+// fail counts a failure of the peer
+func (w *syncWorker) fail(peer string, reason string) {
+	w.failures[peer]++
+	failureMtc.WithLabelValues(reason).Inc()
+}
+
+// sample records the confirmed height, and drops the samples out of the progress window except the latest one of them,
+// which is the base of measuring the speed
+func (w *syncWorker) sample(height uint64) {
+	now := time.Now()
+	if n := len(w.progress); n > 1 && now.Sub(w.progress[n-1].at) < time.Second {
+		// Keep one sample a second at most
+		w.progress[n-1] = progressSample{at: now, height: height}
+	} else {
+		w.progress = append(w.progress, progressSample{at: now, height: height})
+	}
+	i := 0
+	for i+1 < len(w.progress) && now.Sub(w.progress[i+1].at) >= progressWindow {
+		i++
+	}
+	w.progress = w.progress[i:]
+}
+
+// speed returns the number of blocks confirmed per second in the progress window
+func (w *syncWorker) speed() float64 {
+	if len(w.progress) < 2 {
+		return 0
+	}
+	first := w.progress[0]
+	last := w.progress[len(w.progress)-1]
+	elapsed := last.at.Sub(first.at).Seconds()
+	if elapsed <= 0 || last.height <= first.height {
+		return 0
+	}
+	return float64(last.height-first.height) / elapsed
+}
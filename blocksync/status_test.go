@@ -0,0 +1,72 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blocksync
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/network/node"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
+	"github.com/iotexproject/iotex-core/test/mock/mock_network"
+)
+
+func TestSyncStatus(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	peers := []net.Addr{node.NewTCPNode("127.0.0.1:10001"), node.NewTCPNode("127.0.0.1:10002")}
+	p2p := mock_network.NewMockOverlay(ctrl)
+	p2p.EXPECT().GetPeers().Return(peers).AnyTimes()
+	mBc := mock_blockchain.NewMockBlockchain(ctrl)
+	mBc.EXPECT().TipHeight().Return(uint64(10)).AnyTimes()
+
+	cfg := config.Default
+	cfg.BlockSync.SyncedThreshold = 2
+	buf := &blockBuffer{blocks: make(map[uint64]*blockchain.Block), bc: mBc, size: 128, confirmedHeight: 10}
+	w := newSyncWorker(cfg.Chain.ID, &cfg, p2p, buf)
+
+	// The chain is synced if no peer is known to be higher
+	status := w.Status()
+	require.True(status.Synced)
+	require.Equal(uint64(10), status.TargetHeight)
+	require.Equal(time.Duration(0), status.ETA)
+	require.Equal(2, len(status.Peers))
+
+	// The speed is measured over the samples of the confirmed height, and the ETA follows from it
+	w.SetPeerTip(peers[0].String(), 50, hash.ZeroHash32B)
+	w.progress = []progressSample{{at: time.Now().Add(-10 * time.Second), height: 0}}
+	status = w.Status()
+	require.False(status.Synced)
+	require.Equal(uint64(50), status.TargetHeight)
+	require.InDelta(1, status.BlocksPerSecond, 0.01)
+	require.InDelta(40, status.ETA.Seconds(), 1)
+	require.Equal(uint64(50), status.Peers[0].TipHeight)
+
+	// A peer advertising a higher tip than the others doesn't raise the target height
+	w.SetPeerTip(peers[1].String(), 1000, hash.ZeroHash32B)
+	status = w.Status()
+	require.Equal(uint64(50), status.TargetHeight)
+	require.Equal(uint64(1000), status.Peers[1].TipHeight)
+
+	// The failures are counted per peer, and forgotten once the peer is disconnected
+	w.fail(peers[1].String(), "timeout")
+	w.fail(peers[1].String(), "timeout")
+	status = w.Status()
+	require.Equal(0, status.Peers[0].Failures)
+	require.Equal(2, status.Peers[1].Failures)
+	w.dropDisconnected(peers[:1])
+	require.Equal(0, w.failures[peers[1].String()])
+}
@@ -16,7 +16,11 @@ import (
 	"github.com/iotexproject/iotex-core/config"
 )
 
+// commitBlock verifies the block from the network with its commit certificate, and commits it if it's valid
 func commitBlock(bc blockchain.Blockchain, ap actpool.ActPool, blk *blockchain.Block) error {
+	if err := bc.VerifyHeader(blk); err != nil {
+		return err
+	}
 	if err := bc.ValidateBlock(blk, true); err != nil {
 		return err
 	}
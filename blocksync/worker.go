@@ -10,10 +10,17 @@ import (
 	"context"
 	"net"
 	"sync"
+	"time"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/blockchain"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/network"
+	"github.com/iotexproject/iotex-core/network/node"
+	"github.com/iotexproject/iotex-core/pkg/hash"
 	"github.com/iotexproject/iotex-core/pkg/routine"
 	pb "github.com/iotexproject/iotex-core/proto"
 )
@@ -23,6 +30,14 @@ type syncBlocksInterval struct {
 	End   uint64
 }
 
+// syncRequest is a sync request pending at a peer
+type syncRequest struct {
+	interval syncBlocksInterval
+	peer     string
+	sentAt   time.Time
+	msg      proto.Message
+}
+
 type syncWorker struct {
 	chainID      uint32
 	mu           sync.RWMutex
@@ -31,18 +46,42 @@ type syncWorker struct {
 	rrIdx        int
 	buf          *blockBuffer
 	task         *routine.RecurringTask
+	maxRange     uint64
+	maxPending   int
+	timeout      time.Duration
+	pending      []*syncRequest
+	maxHeaders   uint64
+	tips         map[string]*peerTip
+	advertised   map[string]uint64
+	skel         *skeleton
+	headerReq    *syncRequest
+	// slow is the time the peers time out on a request, which are not asked again for a while if other peers could be
+	slow            map[string]time.Time
+	failures        map[string]int
+	progress        []progressSample
+	syncedThreshold uint64
 }
 
 func newSyncWorker(chainID uint32, cfg *config.Config, p2p network.Overlay, buf *blockBuffer) *syncWorker {
 	w := &syncWorker{
-		chainID:      chainID,
-		p2p:          p2p,
-		buf:          buf,
-		targetHeight: 0,
-		rrIdx:        0,
+		chainID:         chainID,
+		p2p:             p2p,
+		buf:             buf,
+		targetHeight:    0,
+		rrIdx:           0,
+		maxRange:        cfg.BlockSync.MaxRangePerRequest,
+		maxPending:      cfg.BlockSync.MaxPendingRequests,
+		timeout:         cfg.BlockSync.RequestTimeout,
+		maxHeaders:      cfg.BlockSync.MaxHeadersPerRequest,
+		tips:            make(map[string]*peerTip),
+		advertised:      make(map[string]uint64),
+		skel:            newSkeleton(),
+		slow:            make(map[string]time.Time),
+		failures:        make(map[string]int),
+		syncedThreshold: cfg.BlockSync.SyncedThreshold,
 	}
 	if interval := syncTaskInterval(cfg); interval != 0 {
-		w.task = routine.NewRecurringTask(w.Sync, cfg.BlockSync.Interval)
+		w.task = routine.NewRecurringTask(w.run, cfg.BlockSync.Interval)
 	}
 	return w
 }
@@ -69,30 +108,443 @@ func (w *syncWorker) SetTargetHeight(h uint64) {
 	}
 }
 
-// Sync checks the sliding window and send more sync request if needed
+// SetPeerTip records the tip of the chain which the peer advertises
+func (w *syncWorker) SetPeerTip(peer string, height uint64, tipHash hash.Hash32B) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.tips[peer] = &peerTip{height: height, hash: tipHash}
+	if w.maxHeaders == 0 && height > w.targetHeight {
+		// Without the header-first sync, the blocks are synced up to the highest tip directly
+		w.targetHeight = height
+	}
+}
+
+// AddHeaders extends the skeleton with the headers which the sender sends in response to a header sync request. The
+// sender is kept with the headers, so that the connection they arrive on is reported if they turn out to be forged.
+func (w *syncWorker) AddHeaders(sender net.Addr, headers []*blockchain.Block) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	peer := sender.String()
+	req := w.headerReq
+	if req == nil || req.peer != peer {
+		return errors.Wrapf(network.ErrUselessMsg, "headers are not requested from %s", peer)
+	}
+	w.headerReq = nil
+	// The headers already in the skeleton are skipped, as the chain may grow in the meantime
+	for len(headers) > 0 && headers[0].Height() <= w.skel.tip {
+		headers = headers[1:]
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+	last := headers[len(headers)-1]
+	if last.Height() > req.interval.End {
+		w.fail(peer, "invalid_headers")
+		return errors.Wrapf(network.ErrInvalidMsg, "header %d is not requested", last.Height())
+	}
+	if tip, ok := w.tips[peer]; ok && tip.height == last.Height() && tip.hash != last.HashBlock() {
+		w.fail(peer, "invalid_headers")
+		return errors.Wrapf(network.ErrInvalidMsg, "header %d doesn't match the advertised tip", last.Height())
+	}
+	// Each header is verified before it goes into the skeleton. The headers which can't be verified yet, e.g., the
+	// ones whose endorsers are unknown before the chain gets into their epoch, are left to be synced again later.
+	for i, header := range headers {
+		err := w.buf.bc.VerifyHeader(header)
+		if err == nil {
+			continue
+		}
+		if cause := errors.Cause(err); cause == blockchain.ErrInvalidBlock || cause == blockchain.ErrCertificate {
+			w.fail(peer, "invalid_headers")
+			return errors.Wrap(network.ErrInvalidMsg, err.Error())
+		}
+		logger.Debug().Err(err).Uint64("height", header.Height()).Msg("Header can't be verified yet.")
+		headers = headers[:i]
+		break
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+	err := w.skel.extend(headers, sender, w.buf.bc.GetHashByHeight)
+	if errors.Cause(err) == network.ErrInvalidMsg {
+		w.fail(peer, "invalid_headers")
+	}
+	return err
+}
+
+// CheckBlock checks the block synced from the sender, which has to be in a pending request to the sender, against the
+// header at its height in the skeleton. The header is verified before it goes into the skeleton, while the block has
+// only been checked to carry a certificate, so a block not matching its header is verified first: if it fails, it's
+// blamed on the sender, and if it can't be verified yet, it's dropped. Only a block which verifies is blamed on the
+// supplier of the header, which is reported, and the skeleton is dropped to be synced again, while the block goes on to
+// be validated by the chain.
+func (w *syncWorker) CheckBlock(sender net.Addr, blk *blockchain.Block) error {
+	w.mu.Lock()
+	requested := w.isPendingAt(sender.String(), blk.Height())
+	h, ok := w.skel.hash(blk.Height())
+	w.mu.Unlock()
+	if !requested {
+		return errors.Wrapf(network.ErrUselessMsg, "block %d is not requested from %s", blk.Height(), sender)
+	}
+	if !ok || h == blk.HashBlock() {
+		return nil
+	}
+	if err := w.buf.bc.VerifyHeader(blk); err != nil {
+		if cause := errors.Cause(err); cause == blockchain.ErrInvalidBlock || cause == blockchain.ErrCertificate {
+			return errors.Wrap(network.ErrInvalidMsg, err.Error())
+		}
+		return errors.Wrapf(network.ErrUselessMsg, "block %d doesn't match its header, and isn't verifiable", blk.Height())
+	}
+
+	w.mu.Lock()
+	// The skeleton may have been synced again in the meantime
+	if h, ok = w.skel.hash(blk.Height()); !ok || h == blk.HashBlock() {
+		w.mu.Unlock()
+		return nil
+	}
+	supplier, _ := w.skel.supplier(blk.Height())
+	w.fail(supplier.String(), "invalid_headers")
+	w.skel.reset(w.buf.bc.TipHeight())
+	// The supplier isn't asked for the headers again until it advertises a new tip
+	delete(w.tips, supplier.String())
+	w.mu.Unlock()
+
+	w.p2p.ReportPeer(
+		supplier,
+		pb.MsgBlockHeaderSyncDataType,
+		errors.Wrapf(network.ErrInvalidMsg, "header %d doesn't match the synced block", blk.Height()),
+	)
+	return nil
+}
+
+// run advertises the tip of the chain to the peers, syncs the blocks and reports the status in each round of the
+// recurring task
+func (w *syncWorker) run() {
+	w.advertise()
+	w.Sync()
+	w.Status()
+}
+
+// advertise tells the peers the tip of the chain, if they haven't got it yet
+func (w *syncWorker) advertise() {
+	height := w.buf.bc.TipHeight()
+	tipHash := w.buf.bc.TipHash()
+	var peers []net.Addr
+	w.mu.Lock()
+	connected := make(map[string]bool)
+	for _, p := range w.p2p.GetPeers() {
+		connected[p.String()] = true
+		if h, ok := w.advertised[p.String()]; ok && h == height {
+			continue
+		}
+		peers = append(peers, p)
+	}
+	// The peers reconnecting later are told again
+	for p := range w.advertised {
+		if !connected[p] {
+			delete(w.advertised, p)
+		}
+	}
+	w.mu.Unlock()
+
+	// The lock isn't held while telling the peers, which may block on the network
+	for _, p := range peers {
+		if err := w.p2p.Tell(w.chainID, p, &pb.ChainTip{Height: height, Hash: tipHash[:]}); err != nil {
+			logger.Warn().Err(err).Str("peer", p.String()).Msg("Failed to advertise the chain tip.")
+			continue
+		}
+		w.mu.Lock()
+		w.advertised[p.String()] = height
+		w.mu.Unlock()
+	}
+}
+
+// Sync checks the sliding window and send more sync request if needed. The missing blocks are requested in ranges from
+// the peers in turn, and each peer has a limited number of pending requests, so that the blocks are pipelined from
+// multiple peers. If the peers advertise their tips, the headers are synced ahead from the peer with the highest tip to
+// build a skeleton of the chain, and the blocks are only requested up to the top of the skeleton.
 func (w *syncWorker) Sync() {
+	// The requests are planned under the lock, and sent after releasing it, as telling a peer may block on the network
+	for _, req := range w.plan() {
+		if err := w.p2p.Tell(w.chainID, node.NewTCPNode(req.peer), req.msg); err != nil {
+			logger.Warn().Err(err).Str("peer", req.peer).Msg("Failed to send the sync request.")
+			w.cancel(req)
+		}
+	}
+}
+
+// plan returns the sync requests to send, which are taken as pending already
+func (w *syncWorker) plan() []*syncRequest {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
+	w.sample(w.buf.ConfirmedHeight())
 	peers := w.p2p.GetPeers()
 	if len(peers) == 0 {
 		logger.Info().Msg("No peer exist to sync with.")
+		return nil
+	}
+	w.dropDisconnected(peers)
+	var reqs []*syncRequest
+	targetHeight := w.targetHeight
+	if w.headerFirst() {
+		w.skel.prune(w.buf.bc.TipHeight(), w.buf.bc.TipHash())
+		if req := w.syncHeaders(); req != nil {
+			reqs = append(reqs, req)
+		}
+		targetHeight = w.skel.tip
+	}
+	intervals := w.buf.GetBlocksIntervalsToSync(targetHeight)
+	logger.Info().Interface("intervals", intervals).Uint64("targetHeight", targetHeight).Msg("block sync intervals.")
+	w.expirePending(intervals)
+	for _, interval := range splitIntervals(w.excludePending(intervals), w.maxRange) {
+		p, ok := w.nextPeer(peers, interval.End)
+		if !ok {
+			logger.Debug().Msg("All peers are busy with sync requests.")
+			break
+		}
+		req := &syncRequest{
+			interval: interval,
+			peer:     p.String(),
+			sentAt:   time.Now(),
+			msg:      &pb.BlockSync{Start: interval.Start, End: interval.End},
+		}
+		if w.maxPending > 0 {
+			w.pending = append(w.pending, req)
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs
+}
+
+// cancel drops the request failing to be sent, so that it's planned again
+func (w *syncWorker) cancel(req *syncRequest) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.headerReq == req {
+		w.headerReq = nil
 		return
 	}
-	intervals := w.buf.GetBlocksIntervalsToSync(w.targetHeight)
-	logger.Info().Interface("intervals", intervals).Uint64("targetHeight", w.targetHeight).Msg("block sync intervals.")
+	for i, pending := range w.pending {
+		if pending == req {
+			w.pending = append(w.pending[:i], w.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+// Complete returns true if the block is the last one of a sync request pending at the peer, which is dropped then. A
+// request is completed once its last block arrives, as the blocks are sent back in order.
+func (w *syncWorker) Complete(peer string, height uint64) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for i, req := range w.pending {
+		if req.peer == peer && req.interval.End == height {
+			w.pending = append(w.pending[:i], w.pending[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// headerFirst returns true if the headers are synced ahead of the blocks, which needs the tips of the peers
+func (w *syncWorker) headerFirst() bool {
+	return w.maxHeaders > 0 && len(w.tips) > 0
+}
+
+// syncHeaders asks the peer with the highest tip for the headers above the skeleton, unless the skeleton is already a
+// buffer ahead of the chain or a header sync request is pending. The request is returned to be sent, or nil if no
+// header is needed.
+func (w *syncWorker) syncHeaders() *syncRequest {
+	if w.headerReq != nil {
+		if time.Since(w.headerReq.sentAt) < w.timeout {
+			return nil
+		}
+		w.slow[w.headerReq.peer] = time.Now()
+		w.fail(w.headerReq.peer, "timeout")
+		w.headerReq = nil
+	}
+	if w.skel.tip >= w.buf.bc.TipHeight()+w.buf.size {
+		return nil
+	}
+	var (
+		best    string
+		bestTip *peerTip
+	)
+	for peer, tip := range w.tips {
+		// The peers which time out lose the ties
+		if bestTip == nil || tip.height > bestTip.height || (tip.height == bestTip.height && w.isSlow(best)) {
+			best, bestTip = peer, tip
+		}
+	}
+	if bestTip == nil || bestTip.height <= w.skel.tip {
+		return nil
+	}
+	interval := syncBlocksInterval{Start: w.skel.tip + 1, End: bestTip.height}
+	if interval.End-interval.Start+1 > w.maxHeaders {
+		interval.End = interval.Start + w.maxHeaders - 1
+	}
+	w.headerReq = &syncRequest{
+		interval: interval,
+		peer:     best,
+		sentAt:   time.Now(),
+		msg:      &pb.BlockHeaderSync{Start: interval.Start, End: interval.End},
+	}
+	return w.headerReq
+}
+
+// dropDisconnected forgets the tips and failures of the peers no longer connected
+func (w *syncWorker) dropDisconnected(peers []net.Addr) {
+	connected := make(map[string]bool)
+	for _, p := range peers {
+		connected[p.String()] = true
+	}
+	for peer := range w.tips {
+		if !connected[peer] {
+			delete(w.tips, peer)
+		}
+	}
+	for peer := range w.failures {
+		if !connected[peer] {
+			delete(w.failures, peer)
+		}
+	}
+}
+
+// nextPeer returns the next peer in turn which could take one more request for the blocks up to the given height. The
+// peers advertising lower tips are skipped, and so are the peers timing out lately unless no other peer is available.
+func (w *syncWorker) nextPeer(peers []net.Addr, end uint64) (net.Addr, bool) {
+	for _, allowSlow := range []bool{false, true} {
+		for i := 0; i < len(peers); i++ {
+			w.rrIdx = w.rrIdx % len(peers)
+			p := peers[w.rrIdx]
+			w.rrIdx++
+			if w.maxPending > 0 && w.numPending(p.String()) >= w.maxPending {
+				continue
+			}
+			if tip, ok := w.tips[p.String()]; ok && tip.height < end {
+				continue
+			}
+			if !allowSlow && w.isSlow(p.String()) {
+				continue
+			}
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// isSlow returns true if the peer has timed out on a request lately
+func (w *syncWorker) isSlow(peer string) bool {
+	t, ok := w.slow[peer]
+	if !ok {
+		return false
+	}
+	if time.Since(t) >= w.timeout {
+		delete(w.slow, peer)
+		return false
+	}
+	return true
+}
+
+func (w *syncWorker) numPending(peer string) int {
+	n := 0
+	for _, req := range w.pending {
+		if req.peer == peer {
+			n++
+		}
+	}
+	return n
+}
+
+// expirePending drops the requests which have timed out or have got all the blocks missing before. The blocks of the
+// requests timing out are asked from other peers.
+func (w *syncWorker) expirePending(intervals []syncBlocksInterval) {
+	pending := w.pending[:0]
+	for _, req := range w.pending {
+		if !overlaps(req.interval, intervals) {
+			continue
+		}
+		if time.Since(req.sentAt) >= w.timeout {
+			w.slow[req.peer] = time.Now()
+			w.fail(req.peer, "timeout")
+			continue
+		}
+		pending = append(pending, req)
+	}
+	w.pending = pending
+}
+
+// excludePending removes the heights asked by the pending requests from the intervals
+func (w *syncWorker) excludePending(intervals []syncBlocksInterval) []syncBlocksInterval {
+	if len(w.pending) == 0 {
+		return intervals
+	}
+	var res []syncBlocksInterval
 	for _, interval := range intervals {
-		w.rrIdx = w.rrIdx % len(peers)
-		p := peers[w.rrIdx]
-		if err := w.sync(p, interval); err != nil {
-			logger.Warn().Err(err).Msg("Failed to sync block.")
+		inRun := false
+		for h := interval.Start; h <= interval.End; h++ {
+			if w.isPending(h) {
+				inRun = false
+				continue
+			}
+			if inRun {
+				res[len(res)-1].End = h
+			} else {
+				res = append(res, syncBlocksInterval{Start: h, End: h})
+				inRun = true
+			}
 		}
-		w.rrIdx++
 	}
+	return res
 }
 
-func (w *syncWorker) sync(p net.Addr, interval syncBlocksInterval) error {
-	return w.p2p.Tell(w.chainID, p, &pb.BlockSync{
-		Start: interval.Start, End: interval.End,
-	})
+func (w *syncWorker) isPending(h uint64) bool {
+	for _, req := range w.pending {
+		if req.interval.Start <= h && h <= req.interval.End {
+			return true
+		}
+	}
+	return false
+}
+
+// isPendingAt returns true if the block of the height is in a sync request pending at the peer
+func (w *syncWorker) isPendingAt(peer string, h uint64) bool {
+	for _, req := range w.pending {
+		if req.peer == peer && req.interval.Start <= h && h <= req.interval.End {
+			return true
+		}
+	}
+	return false
+}
+
+// overlaps returns true if the interval overlaps any of the intervals
+func overlaps(interval syncBlocksInterval, intervals []syncBlocksInterval) bool {
+	for _, other := range intervals {
+		if interval.Start <= other.End && other.Start <= interval.End {
+			return true
+		}
+	}
+	return false
+}
+
+// splitIntervals splits the intervals into the ones of at most the given number of blocks. A zero number doesn't split
+// the intervals.
+func splitIntervals(intervals []syncBlocksInterval, maxRange uint64) []syncBlocksInterval {
+	if maxRange == 0 {
+		return intervals
+	}
+	var res []syncBlocksInterval
+	for _, interval := range intervals {
+		for start := interval.Start; start <= interval.End; start += maxRange {
+			end := start + maxRange - 1
+			if end > interval.End {
+				end = interval.End
+			}
+			res = append(res, syncBlocksInterval{Start: start, End: end})
+		}
+	}
+	return res
 }
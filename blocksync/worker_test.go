@@ -0,0 +1,226 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blocksync
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/network"
+	"github.com/iotexproject/iotex-core/network/node"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	pb "github.com/iotexproject/iotex-core/proto"
+	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
+	"github.com/iotexproject/iotex-core/test/mock/mock_network"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestSplitIntervals(t *testing.T) {
+	require := require.New(t)
+
+	intervals := []syncBlocksInterval{{Start: 1, End: 5}, {Start: 8, End: 8}}
+	require.Equal(intervals, splitIntervals(intervals, 0))
+	require.Equal(
+		[]syncBlocksInterval{{Start: 1, End: 2}, {Start: 3, End: 4}, {Start: 5, End: 5}, {Start: 8, End: 8}},
+		splitIntervals(intervals, 2),
+	)
+}
+
+func TestSyncWorkerPipelining(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	peers := []net.Addr{node.NewTCPNode("127.0.0.1:10001"), node.NewTCPNode("127.0.0.1:10002")}
+	requests := make(map[string][]syncBlocksInterval)
+	var w *syncWorker
+	p2p := mock_network.NewMockOverlay(ctrl)
+	p2p.EXPECT().GetPeers().Return(peers).AnyTimes()
+	p2p.EXPECT().Tell(gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(_ uint32, p net.Addr, msg proto.Message) {
+			// The worker isn't locked while telling the peers
+			w.SetTargetHeight(0)
+			sync := msg.(*pb.BlockSync)
+			requests[p.String()] = append(requests[p.String()], syncBlocksInterval{Start: sync.Start, End: sync.End})
+		}).Return(nil).AnyTimes()
+
+	cfg := config.Default
+	cfg.BlockSync.MaxRangePerRequest = 10
+	cfg.BlockSync.MaxPendingRequests = 2
+	cfg.BlockSync.RequestTimeout = time.Minute
+	buf := &blockBuffer{blocks: make(map[uint64]*blockchain.Block), size: 128, startHeight: 1}
+	w = newSyncWorker(config.Default.Chain.ID, &cfg, p2p, buf)
+
+	// The missing blocks are requested in ranges from the peers in turn
+	w.SetTargetHeight(40)
+	w.Sync()
+	require.Equal([]syncBlocksInterval{{Start: 1, End: 10}, {Start: 21, End: 30}}, requests[peers[0].String()])
+	require.Equal([]syncBlocksInterval{{Start: 11, End: 20}, {Start: 31, End: 40}}, requests[peers[1].String()])
+
+	// Pending blocks are not requested again, and the peers with too many pending requests are skipped
+	w.SetTargetHeight(60)
+	w.Sync()
+	require.Equal(2, len(requests[peers[0].String()]))
+	require.Equal(2, len(requests[peers[1].String()]))
+
+	// The blocks of a timed out request are requested again
+	w.pending[0].sentAt = time.Now().Add(-time.Hour)
+	w.Sync()
+	require.Equal([]syncBlocksInterval{{Start: 1, End: 10}, {Start: 21, End: 30}, {Start: 1, End: 10}},
+		requests[peers[0].String()])
+	// A request is completed by its last block
+	require.False(w.Complete(peers[1].String(), 30))
+	require.True(w.Complete(peers[0].String(), 30))
+	require.False(w.Complete(peers[0].String(), 30))
+	require.Equal(1, w.numPending(peers[0].String()))
+}
+
+func TestProcessSyncRequestInBatches(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blk := blockchain.NewBlock(uint32(123), uint64(0), hash.Hash32B{}, testutil.TimestampNow(), nil, nil, nil, nil)
+	mBc := mock_blockchain.NewMockBlockchain(ctrl)
+	mBc.EXPECT().ChainID().AnyTimes().Return(config.Default.Chain.ID)
+	mBc.EXPECT().GetBlockByHeight(gomock.Any()).AnyTimes().Return(blk, nil)
+	var batches []*pb.BlockContainer
+	p2p := mock_network.NewMockOverlay(ctrl)
+	p2p.EXPECT().Tell(gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(_ uint32, _ net.Addr, msg proto.Message) {
+			batches = append(batches, msg.(*pb.BlockContainer))
+		}).Return(nil).AnyTimes()
+
+	bs := &blockSyncer{
+		ackSyncReq:   true,
+		bc:           mBc,
+		p2p:          p2p,
+		maxBatchSize: 2 * (proto.Size(blk.ConvertToBlockPb()) + 6),
+	}
+	require.NoError(bs.ProcessSyncRequest("127.0.0.1:10001", &pb.BlockSync{Start: 1, End: 5}))
+	require.Equal(3, len(batches))
+	require.Equal(2, len(batches[0].Blocks))
+	require.Equal(2, len(batches[1].Blocks))
+	// A single block is sent in the field which the nodes of old versions read
+	require.NotNil(batches[2].Block)
+	require.Equal(0, len(batches[2].Blocks))
+}
+
+func TestHeaderFirstSync(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// A chain of 6 blocks above the genesis block, whose tip is known to the first peer
+	chainID := config.Default.Chain.ID
+	genesis := blockchain.NewBlock(chainID, 0, hash.ZeroHash32B, testutil.TimestampNow(), nil, nil, nil, nil)
+	blks := []*blockchain.Block{genesis}
+	for h := uint64(1); h <= 6; h++ {
+		blks = append(blks, blockchain.NewBlock(chainID, h, blks[h-1].HashBlock(), testutil.TimestampNow(), nil, nil, nil, nil))
+	}
+	mBc := mock_blockchain.NewMockBlockchain(ctrl)
+	mBc.EXPECT().TipHeight().Return(uint64(0)).AnyTimes()
+	mBc.EXPECT().TipHash().Return(genesis.HashBlock()).AnyTimes()
+	mBc.EXPECT().GetHashByHeight(uint64(0)).Return(genesis.HashBlock(), nil).AnyTimes()
+	mBc.EXPECT().VerifyHeader(blks[6]).Return(errors.Wrap(blockchain.ErrCertificate, "no certificate")).Times(1)
+	mBc.EXPECT().VerifyHeader(blks[6]).Return(errors.New("unknown endorsers")).Times(1)
+	// The blocks at height 2 not matching the header, which fail the verification or can't be verified yet
+	invalid := blockchain.NewBlock(chainID+1, 2, blks[1].HashBlock(), testutil.TimestampNow(), nil, nil, nil, nil)
+	mBc.EXPECT().VerifyHeader(invalid).Return(errors.Wrap(blockchain.ErrInvalidBlock, "invalid signature")).Times(1)
+	unverifiable := blockchain.NewBlock(chainID+2, 2, blks[1].HashBlock(), testutil.TimestampNow(), nil, nil, nil, nil)
+	mBc.EXPECT().VerifyHeader(unverifiable).Return(errors.New("unknown endorsers")).Times(1)
+	mBc.EXPECT().VerifyHeader(gomock.Any()).Return(nil).AnyTimes()
+
+	peers := []net.Addr{node.NewTCPNode("127.0.0.1:10001"), node.NewTCPNode("127.0.0.1:10002")}
+	var (
+		headerReqs []*pb.BlockHeaderSync
+		blockReqs  = make(map[string][]syncBlocksInterval)
+	)
+	p2p := mock_network.NewMockOverlay(ctrl)
+	p2p.EXPECT().GetPeers().Return(peers).AnyTimes()
+	p2p.EXPECT().Tell(gomock.Any(), gomock.Any(), gomock.Any()).Do(
+		func(_ uint32, p net.Addr, msg proto.Message) {
+			switch msg := msg.(type) {
+			case *pb.BlockHeaderSync:
+				require.Equal(peers[0].String(), p.String())
+				headerReqs = append(headerReqs, msg)
+			case *pb.BlockSync:
+				blockReqs[p.String()] = append(blockReqs[p.String()], syncBlocksInterval{Start: msg.Start, End: msg.End})
+			}
+		}).Return(nil).AnyTimes()
+
+	cfg := config.Default
+	cfg.BlockSync.MaxRangePerRequest = 1
+	cfg.BlockSync.MaxHeadersPerRequest = 4
+	buf := &blockBuffer{blocks: make(map[uint64]*blockchain.Block), bc: mBc, size: 128, startHeight: 1}
+	w := newSyncWorker(chainID, &cfg, p2p, buf)
+	w.SetPeerTip(peers[0].String(), 6, blks[6].HashBlock())
+	w.SetPeerTip(peers[1].String(), 3, blks[3].HashBlock())
+
+	// The headers are asked from the peer with the highest tip before any block
+	w.Sync()
+	require.Equal([]*pb.BlockHeaderSync{{Start: 1, End: 4}}, headerReqs)
+	require.Equal(0, len(blockReqs))
+
+	// The headers not asked for are useless
+	err := w.AddHeaders(peers[1], blks[1:5])
+	require.Equal(network.ErrUselessMsg, errors.Cause(err))
+
+	// The blocks of the skeleton are asked from the peers whose tips are high enough, and more headers are asked
+	require.NoError(w.AddHeaders(peers[0], blks[1:5]))
+	w.Sync()
+	require.Equal([]*pb.BlockHeaderSync{{Start: 1, End: 4}, {Start: 5, End: 6}}, headerReqs)
+	require.Equal([]syncBlocksInterval{{Start: 1, End: 1}, {Start: 3, End: 3}, {Start: 4, End: 4}},
+		blockReqs[peers[0].String()])
+	require.Equal([]syncBlocksInterval{{Start: 2, End: 2}}, blockReqs[peers[1].String()])
+
+	// The headers not matching the advertised tip are invalid
+	forged := blockchain.NewBlock(chainID+1, 6, blks[5].HashBlock(), testutil.TimestampNow(), nil, nil, nil, nil)
+	err = w.AddHeaders(peers[0], []*blockchain.Block{blks[5], forged})
+	require.Equal(network.ErrInvalidMsg, errors.Cause(err))
+
+	// The headers failing the verification are invalid, and the ones which can't be verified yet are left for later
+	w.Sync()
+	err = w.AddHeaders(peers[0], blks[5:7])
+	require.Equal(network.ErrInvalidMsg, errors.Cause(err))
+	require.Equal(uint64(4), w.skel.tip)
+	w.Sync()
+	require.NoError(w.AddHeaders(peers[0], blks[5:7]))
+	require.Equal(uint64(5), w.skel.tip)
+
+	// The blocks not asked from the sender are useless
+	err = w.CheckBlock(peers[0], blks[2])
+	require.Equal(network.ErrUselessMsg, errors.Cause(err))
+	require.NoError(w.CheckBlock(peers[1], blks[2]))
+
+	// A block not matching its header is blamed on the sender if it fails the verification, and dropped if it can't be
+	// verified yet, while the skeleton is kept
+	err = w.CheckBlock(peers[1], invalid)
+	require.Equal(network.ErrInvalidMsg, errors.Cause(err))
+	err = w.CheckBlock(peers[1], unverifiable)
+	require.Equal(network.ErrUselessMsg, errors.Cause(err))
+	_, ok := w.skel.hash(2)
+	require.True(ok)
+
+	// A block verified but not matching its header is blamed on the supplier of the header, and the skeleton is synced
+	// again
+	p2p.EXPECT().ReportPeer(peers[0], pb.MsgBlockHeaderSyncDataType, gomock.Any()).Times(1)
+	forged = blockchain.NewBlock(chainID+3, 2, blks[1].HashBlock(), testutil.TimestampNow(), nil, nil, nil, nil)
+	require.NoError(w.CheckBlock(peers[1], forged))
+	_, ok = w.skel.hash(2)
+	require.False(ok)
+	_, ok = w.tips[peers[0].String()]
+	require.False(ok)
+}
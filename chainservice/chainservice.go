@@ -2,6 +2,7 @@ package chainservice
 
 import (
 	"context"
+	"net"
 	"os"
 
 	"github.com/pkg/errors"
@@ -118,7 +119,7 @@ func New(cfg *config.Config, p2p network.Overlay, dispatcher dispatcher.Dispatch
 		logger.Warn().Msg("Using test server with fake data...")
 		exp = explorer.NewTestSever(cfg.Explorer)
 	} else {
-		exp = explorer.NewServer(cfg.Explorer, chain, consensus, dispatcher, actPool, p2p)
+		exp = explorer.NewServer(cfg.Explorer, chain, consensus, dispatcher, actPool, p2p, bs)
 	}
 	return &ChainService{
 		actpool:      actPool,
@@ -135,6 +136,9 @@ func (cs *ChainService) Start(ctx context.Context) error {
 	if err := cs.chain.Start(ctx); err != nil {
 		return errors.Wrap(err, "error when starting blockchain")
 	}
+	if err := cs.actpool.Start(ctx); err != nil {
+		return errors.Wrap(err, "error when starting actpool")
+	}
 	if err := cs.consensus.Start(ctx); err != nil {
 		return errors.Wrap(err, "error when starting consensus")
 	}
@@ -172,6 +176,9 @@ func (cs *ChainService) Stop(ctx context.Context) error {
 	if err := cs.blocksync.Stop(ctx); err != nil {
 		return errors.Wrap(err, "error when stopping blocksync")
 	}
+	if err := cs.actpool.Stop(ctx); err != nil {
+		return errors.Wrap(err, "error when stopping actpool")
+	}
 	if err := cs.chain.Stop(ctx); err != nil {
 		return errors.Wrap(err, "error when stopping blockchain")
 	}
@@ -185,21 +192,44 @@ func (cs *ChainService) HandleAction(act *pb.ActionPb) error {
 		tsf.ConvertFromActionPb(act)
 		if err := cs.actpool.AddTsf(tsf); err != nil {
 			logger.Debug().Err(err)
-			return err
+			return actionError(err)
 		}
 	} else if pbVote := act.GetVote(); pbVote != nil {
 		vote := &action.Vote{}
 		vote.ConvertFromActionPb(act)
 		if err := cs.actpool.AddVote(vote); err != nil {
 			logger.Debug().Err(err)
-			return err
+			return actionError(err)
 		}
 	} else if pbExecution := act.GetExecution(); pbExecution != nil {
 		execution := &action.Execution{}
 		execution.ConvertFromActionPb(act)
 		if err := cs.actpool.AddExecution(execution); err != nil {
 			logger.Debug().Err(err).Msg("Failed to add execution")
-			return err
+			return actionError(err)
+		}
+	} else if pbEvidence := act.GetDoubleSignEvidence(); pbEvidence != nil {
+		evidence := &action.DoubleSignEvidence{}
+		evidence.ConvertFromActionPb(act)
+		if err := action.Verify(evidence); err != nil {
+			return actionError(err)
+		}
+		if err := evidence.Verify(); err != nil {
+			return errors.Wrap(network.ErrInvalidMsg, err.Error())
+		}
+		if err := cs.actpool.Add(evidence); err != nil {
+			logger.Debug().Err(err).Msg("Failed to add double sign evidence")
+			return actionError(err)
+		}
+	} else if pbSignerVote := act.GetSignerVote(); pbSignerVote != nil {
+		vote := &action.SignerVote{}
+		vote.ConvertFromActionPb(act)
+		if err := action.Verify(vote); err != nil {
+			return actionError(err)
+		}
+		if err := cs.actpool.Add(vote); err != nil {
+			logger.Debug().Err(err).Msg("Failed to add signer vote")
+			return actionError(err)
 		}
 	}
 	return nil
@@ -209,14 +239,20 @@ func (cs *ChainService) HandleAction(act *pb.ActionPb) error {
 func (cs *ChainService) HandleBlock(pbBlock *pb.BlockPb) error {
 	blk := &blockchain.Block{}
 	blk.ConvertFromBlockPb(pbBlock)
+	if err := verifyBlock(blk); err != nil {
+		return err
+	}
 	return cs.blocksync.ProcessBlock(blk)
 }
 
 // HandleBlockSync handles incoming block sync request.
-func (cs *ChainService) HandleBlockSync(pbBlock *pb.BlockPb) error {
+func (cs *ChainService) HandleBlockSync(sender net.Addr, pbBlock *pb.BlockPb) error {
 	blk := &blockchain.Block{}
 	blk.ConvertFromBlockPb(pbBlock)
-	return cs.blocksync.ProcessBlockSync(blk)
+	if err := verifyBlock(blk); err != nil {
+		return err
+	}
+	return cs.blocksync.ProcessBlockSync(sender, blk)
 }
 
 // HandleSyncRequest handles incoming sync request.
@@ -224,6 +260,43 @@ func (cs *ChainService) HandleSyncRequest(sender string, sync *pb.BlockSync) err
 	return cs.blocksync.ProcessSyncRequest(sender, sync)
 }
 
+// HandleChainTip handles the chain tip advertised by a peer.
+func (cs *ChainService) HandleChainTip(sender string, tip *pb.ChainTip) error {
+	return cs.blocksync.ProcessChainTip(sender, tip)
+}
+
+// HandleHeaderSyncRequest handles incoming header sync request.
+func (cs *ChainService) HandleHeaderSyncRequest(sender string, sync *pb.BlockHeaderSync) error {
+	return cs.blocksync.ProcessHeaderSyncRequest(sender, sync)
+}
+
+// HandleBlockHeaders handles incoming block headers in response to a header sync request.
+func (cs *ChainService) HandleBlockHeaders(sender net.Addr, headers *pb.BlockHeaderContainer) error {
+	if len(headers.Certificates) > 0 && len(headers.Certificates) != len(headers.Headers) {
+		return errors.Wrapf(
+			network.ErrInvalidMsg,
+			"%d certificates don't match %d headers",
+			len(headers.Certificates),
+			len(headers.Headers),
+		)
+	}
+	blks := make([]*blockchain.Block, 0, len(headers.Headers))
+	for i, header := range headers.Headers {
+		blkPb := &pb.BlockPb{Header: header}
+		if len(headers.Certificates) > 0 {
+			blkPb.Endorsements = headers.Certificates[i].Endorsements
+			blkPb.AggregateEndorsement = headers.Certificates[i].AggregateEndorsement
+		}
+		blk := &blockchain.Block{}
+		blk.ConvertFromBlockPb(blkPb)
+		if !blk.IsDummyBlock() && !blk.VerifySignature() {
+			return errors.Wrapf(network.ErrBadSignature, "block header %d", blk.Height())
+		}
+		blks = append(blks, blk)
+	}
+	return cs.blocksync.ProcessBlockHeaders(sender, blks)
+}
+
 // HandleBlockPropose handles incoming block propose request.
 func (cs *ChainService) HandleBlockPropose(propose *pb.ProposePb) error {
 	return cs.consensus.HandleBlockPropose(propose)
@@ -234,6 +307,33 @@ func (cs *ChainService) HandleEndorse(endorse *pb.EndorsePb) error {
 	return cs.consensus.HandleEndorse(endorse)
 }
 
+// actionError marks the error of adding an action from network into actpool, if the action is invalid by itself, so
+// that the peer sending it gets penalized
+func actionError(err error) error {
+	switch errors.Cause(err) {
+	case action.ErrAction:
+		return errors.Wrap(network.ErrBadSignature, err.Error())
+	case actpool.ErrGasHigherThanLimit, actpool.ErrInsufficientGas, actpool.ErrTransfer:
+		return errors.Wrap(network.ErrInvalidMsg, err.Error())
+	}
+	return err
+}
+
+// verifyBlock checks the block from network against the validity which doesn't depend on the chain state, so that the
+// peer sending an invalid block gets penalized
+func verifyBlock(blk *blockchain.Block) error {
+	if blk.IsDummyBlock() {
+		return nil
+	}
+	if err := blk.VerifyTxRoot(); err != nil {
+		return errors.Wrapf(network.ErrInvalidMsg, "block %d: %v", blk.Height(), err)
+	}
+	if !blk.VerifySignature() {
+		return errors.Wrapf(network.ErrBadSignature, "block %d", blk.Height())
+	}
+	return nil
+}
+
 // ChainID returns ChainID.
 func (cs *ChainService) ChainID() uint32 { return cs.chain.ChainID() }
 
@@ -9,6 +9,8 @@ package config
 import (
 	"flag"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -51,6 +53,13 @@ const (
 	StandaloneScheme = "STANDALONE"
 	// NOOPScheme means that the node does not create only block
 	NOOPScheme = "NOOP"
+	// PoAScheme means proof of authority, in which a set of signers take turns to create blocks
+	PoAScheme = "POA"
+
+	// LowestPriceEviction means that the actpool evicts the non-pending action of the lowest gas price first
+	LowestPriceEviction = "LOWEST_PRICE"
+	// OldestEviction means that the actpool evicts the oldest non-pending action first
+	OldestEviction = "OLDEST"
 )
 
 var (
@@ -85,6 +94,16 @@ var (
 			PeerDiscovery:                       true,
 			TopologyPath:                        "",
 			TTL:                                 3,
+			PeerBanThreshold:                    -100,
+			PeerBanDuration:                     30 * time.Minute,
+			PeerScoreRecoveryInterval:           time.Minute,
+			DHTBucketSize:                       16,
+			DHTLookupConcurrency:                3,
+			DHTRefreshInterval:                  time.Minute,
+			NodeKeyPath:                         "",
+			NodeAllowlist:                       make([]string, 0),
+			GossipAnnounceThreshold:             16 * 1024,
+			GossipBodyRetention:                 time.Minute,
 		},
 		Chain: Chain{
 			ChainDBPath:             "/tmp/chain.db",
@@ -96,11 +115,17 @@ var (
 			GenesisActionsPath:      "",
 			NumCandidates:           101,
 			EnableFallBackToFreshDB: false,
+			MaxEvidenceAge:          720,
 		},
 		ActPool: ActPool{
-			MaxNumActsPerPool: 32000,
-			MaxNumActsPerAcct: 2000,
-			MaxNumActsToPick:  0,
+			MaxNumActsPerPool:      32000,
+			MaxNumActsPerAcct:      2000,
+			MaxNumActsToPick:       0,
+			ReplacementPriceBump:   10,
+			ActionExpiry:           10 * time.Minute,
+			EvictionPolicy:         LowestPriceEviction,
+			JournalPath:            "",
+			JournalCompactInterval: time.Minute,
 		},
 		Consensus: Consensus{
 			Scheme: NOOPScheme,
@@ -120,15 +145,40 @@ var (
 				EnableDummyBlock:  true,
 				TimeBasedRotation: false,
 				EnableDKG:         false,
+				StateDBPath:       "",
+				MaxRoundsPerHeight:   1,
+				JournalPath:          "",
+				JournalMaxSize:       64 * 1024 * 1024,
+				JournalMaxFiles:      4,
+				AggregateEndorses:    false,
+				ProductionDeadline:   0.5,
+				MaxActsAfterDeadline: 100,
 			},
 			BlockCreationInterval: 10 * time.Second,
+			PoA: PoA{
+				CheckpointDBPath:   "",
+				CheckpointInterval: 1024,
+			},
 		},
 		BlockSync: BlockSync{
-			Interval:   10 * time.Second,
-			BufferSize: 16,
+			Interval:             10 * time.Second,
+			BufferSize:           128,
+			MaxRangePerRequest:   16,
+			MaxPendingRequests:   4,
+			RequestTimeout:       30 * time.Second,
+			MaxHeadersPerRequest: 256,
+			SyncedThreshold:      2,
 		},
 		Dispatcher: Dispatcher{
-			EventChanSize: 10000,
+			EventChanSize:     10000,
+			ConsensusChanSize: 1000,
+			BlockChanSize:     100,
+			SyncChanSize:      1000,
+			ActionChanSize:    10000,
+			ConsensusWeight:   8,
+			BlockWeight:       4,
+			SyncWeight:        2,
+			ActionWeight:      1,
 		},
 		Explorer: Explorer{
 			Enabled:                 false,
@@ -148,6 +198,13 @@ var (
 		DB: DB{
 			NumRetries: 3,
 		},
+		SubChain: SubChain{
+			Enabled:            false,
+			ConfigTemplatePath: "",
+			DataDir:            "/tmp/subchains",
+			ExplorerPortStart:  14010,
+			ExplorerPortEnd:    14109,
+		},
 	}
 
 	// ErrInvalidCfg indicates the invalid config value
@@ -158,11 +215,14 @@ var (
 		ValidateKeyPair,
 		ValidateConsensusScheme,
 		ValidateRollDPoS,
+		ValidatePoA,
 		ValidateDispatcher,
+		ValidateBlockSync,
 		ValidateExplorer,
 		ValidateNetwork,
 		ValidateActPool,
 		ValidateChain,
+		ValidateSubChain,
 	}
 )
 
@@ -197,6 +257,32 @@ type (
 		PeerDiscovery                       bool                        `yaml:"peerDiscovery"`
 		TopologyPath                        string                      `yaml:"topologyPath"`
 		TTL                                 int32                       `yaml:"ttl"`
+		// Peers whose reputation scores drop below the threshold are disconnected and banned for the given duration. A
+		// zero duration disables banning.
+		PeerBanThreshold int           `yaml:"peerBanThreshold"`
+		PeerBanDuration  time.Duration `yaml:"peerBanDuration"`
+		// A peer's reputation score recovers by 1 every given interval
+		PeerScoreRecoveryInterval time.Duration `yaml:"peerScoreRecoveryInterval"`
+		// Max number of contacts in a bucket of the DHT routing table. A zero size disables the DHT, and the peers are
+		// discovered by asking the current peers for theirs instead.
+		DHTBucketSize int `yaml:"dhtBucketSize"`
+		// Number of parallel queries in a DHT lookup
+		DHTLookupConcurrency int `yaml:"dhtLookupConcurrency"`
+		// The DHT looks up a random node ID in each bucket which has not been updated within the interval
+		DHTRefreshInterval time.Duration `yaml:"dhtRefreshInterval"`
+		// The file keeping the key pair of the node, which is generated on the first start. The node ID is the hash of
+		// the public key. The server keeps the file next to the chain DB by default (see DataFilePath).
+		NodeKeyPath string `yaml:"nodeKeyPath"`
+		// The hex encoded IDs of the nodes allowed to be the peers. An empty list allows every node.
+		NodeAllowlist []string `yaml:"nodeAllowlist"`
+		// Messages larger than the threshold in bytes are announced by their checksums, and the peers fetch the bodies
+		// they haven't seen from the announcer. Smaller messages are pushed in full. A zero threshold disables
+		// announcing.
+		GossipAnnounceThreshold int `yaml:"gossipAnnounceThreshold"`
+		// The announced message bodies are kept for the peers to fetch for the retention. It's longer than the message
+		// log retention, so that the peers several hops away may still fetch the bodies after the announcements are
+		// relayed to them.
+		GossipBodyRetention time.Duration `yaml:"gossipBodyRetention"`
 	}
 
 	// Chain is the config struct for blockchain package
@@ -213,6 +299,9 @@ type (
 		GenesisActionsPath      string `yaml:"genesisActionsPath"`
 		NumCandidates           uint   `yaml:"numCandidates"`
 		EnableFallBackToFreshDB bool   `yaml:"enablefallbacktofreshdb"`
+		// MaxEvidenceAge is the max number of blocks by which the double sign evidence in a block could be older than
+		// the block
+		MaxEvidenceAge uint64 `yaml:"maxEvidenceAge"`
 	}
 
 	// Consensus is the config struct for consensus package
@@ -220,13 +309,40 @@ type (
 		// There are three schemes that are supported
 		Scheme                string        `yaml:"scheme"`
 		RollDPoS              RollDPoS      `yaml:"rollDPoS"`
+		PoA                   PoA           `yaml:"poa"`
 		BlockCreationInterval time.Duration `yaml:"blockCreationInterval"`
 	}
 
+	// PoA is the config struct for proof of authority consensus
+	PoA struct {
+		// Signers are the addresses of the signers at the genesis, which may be changed later by the votes among them
+		Signers []string `yaml:"signers"`
+		// CheckpointDBPath is the path of the DB persisting the checkpoints of the signer set, so that a restarted node
+		// doesn't tally the votes from the genesis again. The server keeps the DB next to the chain DB by default (see
+		// DataFilePath).
+		CheckpointDBPath string `yaml:"checkpointDBPath"`
+		// CheckpointInterval is the number of blocks between two checkpoints of the signer set
+		CheckpointInterval uint64 `yaml:"checkpointInterval"`
+	}
+
 	// BlockSync is the config struct for the BlockSync
 	BlockSync struct {
 		Interval   time.Duration `yaml:"interval"` // update duration
 		BufferSize uint64        `yaml:"bufferSize"`
+		// Max number of blocks asked in a sync request, so that the missing blocks are requested from multiple peers in
+		// parallel. A zero number asks for each missing interval in a whole.
+		MaxRangePerRequest uint64 `yaml:"maxRangePerRequest"`
+		// Max number of sync requests pending at a peer. The blocks of a pending request are not asked again until the
+		// request times out. A zero number neither limits nor tracks the requests.
+		MaxPendingRequests int           `yaml:"maxPendingRequests"`
+		RequestTimeout     time.Duration `yaml:"requestTimeout"`
+		// Max number of headers asked in a header sync request. The headers are synced ahead of the blocks from the peer
+		// advertising the highest tip, and the blocks downloaded from multiple peers are checked against them. A zero
+		// number turns off the header-first sync.
+		MaxHeadersPerRequest uint64 `yaml:"maxHeadersPerRequest"`
+		// Max number of blocks which the chain could be behind the highest tip known from the peers to be reported as
+		// synced
+		SyncedThreshold uint64 `yaml:"syncedThreshold"`
 	}
 
 	// RollDPoS is the config struct for RollDPoS consensus package
@@ -246,11 +362,45 @@ type (
 		EnableDummyBlock         bool          `yaml:"enableDummyBlock"`
 		TimeBasedRotation        bool          `yaml:"timeBasedRotation"`
 		EnableDKG                bool          `yaml:"enableDKG"`
+		// StateDBPath is the path of the DB persisting the epoch and the messages signed by the node. The server keeps
+		// the DB next to the chain DB by default (see DataFilePath).
+		StateDBPath string `yaml:"stateDBPath"`
+		// MaxRoundsPerHeight is the max number of rounds at a height. Once the proposer of a round times out, the next
+		// delegate in the rotation proposes in the following round. 1 disables the rounds
+		MaxRoundsPerHeight uint `yaml:"maxRoundsPerHeight"`
+		// JournalPath is the path of the journal recording the events consumed by the consensus FSM and the state
+		// transitions they cause, which could be replayed to reproduce the transitions. Empty disables the journal
+		JournalPath string `yaml:"journalPath"`
+		// JournalMaxSize is the max size in bytes of the journal file before it's rotated, and JournalMaxFiles is the
+		// max number of the rotated files to keep
+		JournalMaxSize  uint64 `yaml:"journalMaxSize"`
+		JournalMaxFiles uint   `yaml:"journalMaxFiles"`
+		// AggregateEndorses makes the delegates sign the endorses with their DKG keys as well, and send them to the
+		// proposer, which gossips the BLS aggregate of a quorum of them instead. It requires DKG
+		AggregateEndorses bool `yaml:"aggregateEndorses"`
+		// ProductionDeadline is the fraction of ProposerInterval, within which the proposer has to mint its block since
+		// the round starts, but no later than AcceptProposeTTL, when the delegates stop waiting for the proposal. Once
+		// the deadline is exceeded, the proposer proposes a smaller block with at most MaxActsAfterDeadline actions
+		// instead. Zero disables the deadline
+		ProductionDeadline   float64 `yaml:"productionDeadline"`
+		MaxActsAfterDeadline uint64  `yaml:"maxActsAfterDeadline"`
 	}
 
 	// Dispatcher is the dispatcher config
 	Dispatcher struct {
+		// EventChanSize is the size of the queue of a message class which doesn't have its own queue size
 		EventChanSize uint `yaml:"eventChanSize"`
+		// Sizes of the queues of consensus messages, blocks, block sync messages and actions
+		ConsensusChanSize uint `yaml:"consensusChanSize"`
+		BlockChanSize     uint `yaml:"blockChanSize"`
+		SyncChanSize      uint `yaml:"syncChanSize"`
+		ActionChanSize    uint `yaml:"actionChanSize"`
+		// Max numbers of messages of each class handled in a round, while messages of multiple classes are pending. A
+		// zero weight counts as 1.
+		ConsensusWeight uint `yaml:"consensusWeight"`
+		BlockWeight     uint `yaml:"blockWeight"`
+		SyncWeight      uint `yaml:"syncWeight"`
+		ActionWeight    uint `yaml:"actionWeight"`
 	}
 
 	// Explorer is the explorer service config
@@ -286,6 +436,20 @@ type (
 		// MaxNumActsToPick indicates maximum number of actions to pick to mint a block. Default is 0, which means no
 		// limit on the number of actions to pick.
 		MaxNumActsToPick uint64 `yaml:"maxNumActsToPick"`
+		// ReplacementPriceBump indicates the minimum percentage by which the gas price of an action has to exceed the
+		// one of the pending action with the same nonce to replace it
+		ReplacementPriceBump uint64 `yaml:"replacementPriceBump"`
+		// ActionExpiry indicates how long a non-pending action can stay in the actpool. Default is 10 minutes, and 0
+		// means that non-pending actions never expire.
+		ActionExpiry time.Duration `yaml:"actionExpiry"`
+		// EvictionPolicy indicates which non-pending action to evict first when the actpool is full
+		EvictionPolicy string `yaml:"evictionPolicy"`
+		// JournalPath is the path to the journal file persisting the actions in actpool across restarts. Default is
+		// empty, which means that the journal is disabled.
+		JournalPath string `yaml:"journalPath"`
+		// JournalCompactInterval is the interval to drop the records of the actions which have left actpool from the
+		// journal
+		JournalCompactInterval time.Duration `yaml:"journalCompactInterval"`
 	}
 
 	// DB is the blotDB config
@@ -311,6 +475,21 @@ type (
 		AwsDBName string `yaml:"awsDBName"`
 	}
 
+	// SubChain is the config for automatically managing the sub-chains recorded on the root chain
+	SubChain struct {
+		// Enabled indicates whether to create, start and stop sub-chain services following the start and stop
+		// sub-chain records on the root chain
+		Enabled bool `yaml:"enabled"`
+		// ConfigTemplatePath is the path to the config file which is used as the template of every sub-chain
+		ConfigTemplatePath string `yaml:"configTemplatePath"`
+		// DataDir is the directory under which each sub-chain keeps its chain and trie DB files
+		DataDir string `yaml:"dataDir"`
+		// ExplorerPortStart and ExplorerPortEnd are the range of the ports, from which the explorer port of each
+		// sub-chain is allocated
+		ExplorerPortStart int `yaml:"explorerPortStart"`
+		ExplorerPortEnd   int `yaml:"explorerPortEnd"`
+	}
+
 	// Config is the root config struct, each package's config should be put as its sub struct
 	Config struct {
 		NodeType   string     `yaml:"nodeType"`
@@ -324,6 +503,7 @@ type (
 		Indexer    Indexer    `yaml:"indexer"`
 		System     System     `yaml:"system"`
 		DB         DB         `yaml:"db"`
+		SubChain   SubChain   `yaml:"subChain"`
 	}
 
 	// Validate is the interface of validating the config
@@ -370,10 +550,16 @@ func NewSub(validates ...Validate) (*Config, error) {
 	if _subChainPath == "" {
 		return nil, nil
 	}
+	return NewSubFromPath(_subChainPath, validates...)
+}
+
+// NewSubFromPath creates config for sub chain from the given config file path, which is either the sub chain config
+// file or the sub chain config template
+func NewSubFromPath(path string, validates ...Validate) (*Config, error) {
 	opts := make([]uconfig.YAMLOption, 0)
 	opts = append(opts, uconfig.Static(Default))
 	opts = append(opts, uconfig.Expand(os.LookupEnv))
-	opts = append(opts, uconfig.File(_subChainPath))
+	opts = append(opts, uconfig.File(path))
 	if _secretPath != "" {
 		opts = append(opts, uconfig.File(_secretPath))
 	}
@@ -414,6 +600,14 @@ func (cfg *Config) IsLightweight() bool {
 	return cfg.NodeType == LightweightType
 }
 
+// DataFilePath returns the path of a data file of the node with the given name, which is placed next to the chain DB
+// and named after it, e.g., /tmp/chain.node.key for /tmp/chain.db, so that the nodes with their own chain DBs don't
+// share the file even if the DBs are in the same directory
+func (cfg *Config) DataFilePath(name string) string {
+	dbPath := cfg.Chain.ChainDBPath
+	return strings.TrimSuffix(dbPath, filepath.Ext(dbPath)) + "." + name
+}
+
 // BlockchainAddress returns the address derived from the configured chain ID and public key
 func (cfg *Config) BlockchainAddress() (address.Address, error) {
 	pk, err := keypair.DecodePublicKey(cfg.Chain.ProducerPubKey)
@@ -476,8 +670,9 @@ func ValidateConsensusScheme(cfg *Config) error {
 	switch cfg.NodeType {
 	case DelegateType:
 	case FullNodeType:
-		if cfg.Consensus.Scheme != NOOPScheme {
-			return errors.Wrap(ErrInvalidCfg, "consensus scheme of fullnode should be NOOP")
+		// A full node of a proof of authority chain runs the scheme to validate the blocks, without signing any
+		if cfg.Consensus.Scheme != NOOPScheme && cfg.Consensus.Scheme != PoAScheme {
+			return errors.Wrap(ErrInvalidCfg, "consensus scheme of fullnode should be NOOP or POA")
 		}
 	case LightweightType:
 		if cfg.Consensus.Scheme != NOOPScheme {
@@ -497,6 +692,15 @@ func ValidateDispatcher(cfg *Config) error {
 	return nil
 }
 
+// ValidateBlockSync validates the block sync configs
+func ValidateBlockSync(cfg *Config) error {
+	pending := cfg.BlockSync.MaxPendingRequests > 0 || cfg.BlockSync.MaxHeadersPerRequest > 0
+	if pending && cfg.BlockSync.RequestTimeout <= 0 {
+		return errors.Wrap(ErrInvalidCfg, "block sync request timeout should be greater than 0")
+	}
+	return nil
+}
+
 // ValidateRollDPoS validates the roll-DPoS configs
 func ValidateRollDPoS(cfg *Config) error {
 	if cfg.Consensus.Scheme == RollDPoSScheme && cfg.Consensus.RollDPoS.EventChanSize <= 0 {
@@ -510,6 +714,39 @@ func ValidateRollDPoS(cfg *Config) error {
 		cfg.Consensus.RollDPoS.TimeBasedRotation {
 		return errors.Wrap(ErrInvalidCfg, "roll-DPoS should enable dummy block when doing time based rotation")
 	}
+	if cfg.Consensus.Scheme == RollDPoSScheme &&
+		cfg.Consensus.RollDPoS.EnableDKG &&
+		(cfg.Consensus.RollDPoS.NumDelegates <= crypto.Degree || cfg.Consensus.RollDPoS.NumDelegates > crypto.NumNodes) {
+		return errors.Wrapf(
+			ErrInvalidCfg,
+			"roll-DPoS should have %d to %d delegates to enable DKG",
+			crypto.Degree+1,
+			crypto.NumNodes,
+		)
+	}
+	if cfg.Consensus.Scheme == RollDPoSScheme &&
+		cfg.Consensus.RollDPoS.AggregateEndorses &&
+		!cfg.Consensus.RollDPoS.EnableDKG {
+		return errors.Wrap(ErrInvalidCfg, "roll-DPoS should enable DKG to aggregate the endorses")
+	}
+	if cfg.Consensus.Scheme == RollDPoSScheme &&
+		(cfg.Consensus.RollDPoS.ProductionDeadline < 0 || cfg.Consensus.RollDPoS.ProductionDeadline > 1) {
+		return errors.Wrap(ErrInvalidCfg, "roll-DPoS production deadline should be between 0 and 1")
+	}
+	return nil
+}
+
+// ValidatePoA validates the proof of authority configs
+func ValidatePoA(cfg *Config) error {
+	if cfg.Consensus.Scheme != PoAScheme {
+		return nil
+	}
+	if len(cfg.Consensus.PoA.Signers) == 0 {
+		return errors.Wrap(ErrInvalidCfg, "proof of authority should have at least one signer")
+	}
+	if cfg.Consensus.BlockCreationInterval <= 0 {
+		return errors.Wrap(ErrInvalidCfg, "proof of authority block creation interval should be greater than 0")
+	}
 	return nil
 }
 
@@ -526,6 +763,9 @@ func ValidateNetwork(cfg *Config) error {
 	if !cfg.Network.PeerDiscovery && cfg.Network.TopologyPath == "" {
 		return errors.Wrap(ErrInvalidCfg, "either peer discover should be enabled or a topology should be given")
 	}
+	if cfg.Network.PeerDiscovery && cfg.Network.DHTBucketSize > 0 && cfg.Network.DHTRefreshInterval <= 0 {
+		return errors.Wrap(ErrInvalidCfg, "DHT refresh interval should be greater than 0")
+	}
 	return nil
 }
 
@@ -545,6 +785,33 @@ func ValidateActPool(cfg *Config) error {
 			"maximum number of actions per pool cannot be less than maximum number of actions per account",
 		)
 	}
+	switch cfg.ActPool.EvictionPolicy {
+	case LowestPriceEviction, OldestEviction:
+	default:
+		return errors.Wrapf(ErrInvalidCfg, "unknown actpool eviction policy %s", cfg.ActPool.EvictionPolicy)
+	}
+	return nil
+}
+
+// ValidateSubChain validates the sub-chain management configs
+func ValidateSubChain(cfg *Config) error {
+	if cfg.SubChain.Enabled && cfg.SubChain.ConfigTemplatePath == "" {
+		return errors.Wrap(ErrInvalidCfg, "sub-chain config template should be given when sub-chain management is enabled")
+	}
+	if cfg.SubChain.Enabled && cfg.SubChain.DataDir == "" {
+		return errors.Wrap(ErrInvalidCfg, "sub-chain data dir should be given when sub-chain management is enabled")
+	}
+	if cfg.SubChain.Enabled &&
+		(cfg.SubChain.ExplorerPortStart <= 0 ||
+			cfg.SubChain.ExplorerPortStart > cfg.SubChain.ExplorerPortEnd ||
+			cfg.SubChain.ExplorerPortEnd > 65535) {
+		return errors.Wrapf(
+			ErrInvalidCfg,
+			"sub-chain explorer port range [%d, %d] is invalid",
+			cfg.SubChain.ExplorerPortStart,
+			cfg.SubChain.ExplorerPortEnd,
+		)
+	}
 	return nil
 }
 
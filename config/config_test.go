@@ -13,6 +13,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -276,6 +277,22 @@ func TestValidateDispatcher(t *testing.T) {
 	)
 }
 
+func TestValidateBlockSync(t *testing.T) {
+	cfg := Default
+	cfg.BlockSync.RequestTimeout = 0
+	err := ValidateBlockSync(&cfg)
+	require.NotNil(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(
+		t,
+		strings.Contains(err.Error(), "block sync request timeout should be greater than 0"),
+	)
+	cfg.BlockSync.MaxPendingRequests = 0
+	require.Error(t, ValidateBlockSync(&cfg))
+	cfg.BlockSync.MaxHeadersPerRequest = 0
+	require.NoError(t, ValidateBlockSync(&cfg))
+}
+
 func TestValidateRollDPoS(t *testing.T) {
 	cfg := Default
 	cfg.NodeType = DelegateType
@@ -309,6 +326,60 @@ func TestValidateRollDPoS(t *testing.T) {
 		t,
 		strings.Contains(err.Error(), "roll-DPoS should enable dummy block when doing time based rotation"),
 	)
+
+	cfg.Consensus.RollDPoS.TimeBasedRotation = false
+	cfg.Consensus.RollDPoS.AggregateEndorses = true
+	err = ValidateRollDPoS(&cfg)
+	require.NotNil(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(
+		t,
+		strings.Contains(err.Error(), "roll-DPoS should enable DKG to aggregate the endorses"),
+	)
+	cfg.Consensus.RollDPoS.EnableDKG = true
+	require.NoError(t, ValidateRollDPoS(&cfg))
+
+	cfg.Consensus.RollDPoS.NumDelegates = 10
+	err = ValidateRollDPoS(&cfg)
+	require.NotNil(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "roll-DPoS should have 11 to 21 delegates to enable DKG"))
+	cfg.Consensus.RollDPoS.NumDelegates = 15
+	require.NoError(t, ValidateRollDPoS(&cfg))
+
+	cfg.Consensus.RollDPoS.ProductionDeadline = 1.5
+	err = ValidateRollDPoS(&cfg)
+	require.NotNil(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "roll-DPoS production deadline should be between 0 and 1"))
+}
+
+func TestValidatePoA(t *testing.T) {
+	cfg := Default
+	cfg.NodeType = DelegateType
+	cfg.Consensus.Scheme = PoAScheme
+	err := ValidatePoA(&cfg)
+	require.NotNil(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(
+		t,
+		strings.Contains(err.Error(), "proof of authority should have at least one signer"),
+	)
+
+	cfg.Consensus.PoA.Signers = []string{"io1qyqsyqcy6nm58gjd2wr035wz5eyd5uq47zyqpng3gxe7nh"}
+	cfg.Consensus.BlockCreationInterval = 0
+	err = ValidatePoA(&cfg)
+	require.NotNil(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(
+		t,
+		strings.Contains(err.Error(), "proof of authority block creation interval should be greater than 0"),
+	)
+
+	cfg.Consensus.BlockCreationInterval = time.Second
+	require.NoError(t, ValidatePoA(&cfg))
+	cfg.NodeType = FullNodeType
+	require.NoError(t, ValidateConsensusScheme(&cfg))
 }
 
 func TestValidateNetwork(t *testing.T) {
@@ -321,6 +392,13 @@ func TestValidateNetwork(t *testing.T) {
 		t,
 		strings.Contains(err.Error(), "either peer discover should be enabled or a topology should be given"),
 	)
+
+	cfg = Default
+	cfg.Network.DHTRefreshInterval = 0
+	err = ValidateNetwork(&cfg)
+	require.Error(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "DHT refresh interval should be greater than 0"))
 }
 
 func TestValidateActPool(t *testing.T) {
@@ -361,6 +439,44 @@ func TestValidateActPool(t *testing.T) {
 			"maximum number of actions per pool cannot be less than maximum number of actions per account",
 		),
 	)
+
+	cfg.ActPool.MaxNumActsPerPool = 100
+	cfg.ActPool.EvictionPolicy = "RANDOM"
+	err = ValidateActPool(&cfg)
+	require.NotNil(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "unknown actpool eviction policy"))
+}
+
+func TestValidateSubChain(t *testing.T) {
+	cfg := Default
+	cfg.SubChain.Enabled = true
+	err := ValidateSubChain(&cfg)
+	require.NotNil(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(
+		t,
+		strings.Contains(err.Error(), "sub-chain config template should be given"),
+	)
+
+	cfg.SubChain.ConfigTemplatePath = "./subchain.yaml"
+	cfg.SubChain.DataDir = ""
+	err = ValidateSubChain(&cfg)
+	require.NotNil(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(
+		t,
+		strings.Contains(err.Error(), "sub-chain data dir should be given"),
+	)
+
+	cfg.SubChain.DataDir = "/tmp/subchains"
+	require.NoError(t, ValidateSubChain(&cfg))
+
+	cfg.SubChain.ExplorerPortEnd = 70000
+	err = ValidateSubChain(&cfg)
+	require.NotNil(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "sub-chain explorer port range [14010, 70000] is invalid"))
 }
 
 func TestCheckNodeType(t *testing.T) {
@@ -379,3 +495,11 @@ func TestCheckNodeType(t *testing.T) {
 	require.False(t, cfg.IsDelegate())
 	require.True(t, cfg.IsLightweight())
 }
+
+func TestDataFilePath(t *testing.T) {
+	cfg := Default
+	cfg.Chain.ChainDBPath = "/tmp/chain1.db"
+	require.Equal(t, "/tmp/chain1.node.key", cfg.DataFilePath("node.key"))
+	cfg.Chain.ChainDBPath = "./chain"
+	require.Equal(t, "./chain.node.key", cfg.DataFilePath("node.key"))
+}
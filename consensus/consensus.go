@@ -18,7 +18,9 @@ import (
 	"github.com/iotexproject/iotex-core/blockchain"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/consensus/scheme"
+	"github.com/iotexproject/iotex-core/consensus/scheme/poa"
 	"github.com/iotexproject/iotex-core/consensus/scheme/rolldpos"
+	"github.com/iotexproject/iotex-core/db"
 	explorerapi "github.com/iotexproject/iotex-core/explorer/idl/explorer"
 	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/logger"
@@ -80,14 +82,15 @@ func NewConsensus(
 
 	cs := &IotxConsensus{cfg: &cfg.Consensus}
 	mintBlockCB := func() (*blockchain.Block, error) {
-		transfers, votes, executions, _ := ap.PickActs()
+		transfers, votes, executions, actions := ap.PickActs()
 		logger.Debug().
 			Int("transfer", len(transfers)).
 			Int("votes", len(votes)).
 			Int("Executions", len(executions)).
+			Int("actions", len(actions)).
 			Msg("pick actions")
 
-		blk, err := bc.MintNewBlock(transfers, votes, executions, nil, GetAddr(cfg), "")
+		blk, err := bc.MintNewBlock(transfers, votes, executions, actions, GetAddr(cfg), "")
 		if err != nil {
 			logger.Error().Msg("Failed to mint a block")
 			return nil, err
@@ -116,7 +119,6 @@ func NewConsensus(
 		return nil
 	}
 
-	var err error
 	clock := clock.New()
 	switch cfg.Consensus.Scheme {
 	case config.RollDPoSScheme:
@@ -127,6 +129,9 @@ func NewConsensus(
 			SetActPool(ap).
 			SetClock(clock).
 			SetP2P(p2p)
+		if cfg.Consensus.RollDPoS.StateDBPath != "" {
+			bd = bd.SetStateDB(db.NewBoltDB(cfg.Consensus.RollDPoS.StateDBPath, &cfg.DB))
+		}
 		if ops.rootChainAPI != nil {
 			bd = bd.SetCandidatesByHeightFunc(func(h uint64) ([]*state.Candidate, error) {
 				rawcs, err := ops.rootChainAPI.GetCandidateMetricsByHeight(int64(h))
@@ -157,10 +162,38 @@ func NewConsensus(
 				return cs, nil
 			})
 		}
-		cs.scheme, err = bd.Build()
+		r, err := bd.Build()
 		if err != nil {
 			logger.Panic().Err(err).Msg("error when constructing RollDPoS")
 		}
+		// The blocks committed by RollDPoS carry the commit endorsements, which are checked against the candidates
+		bc.SetEndorsersFunc(r.Endorsers)
+		bc.SetAggregateVerifierFunc(r.VerifyAggregate)
+		// Every block, either created locally or received from the network, is checked to carry the derived seed
+		bc.SetValidator(r.Validator())
+		cs.scheme = r
+	case config.PoAScheme:
+		var checkpointDB db.KVStore
+		if cfg.Consensus.PoA.CheckpointDBPath != "" {
+			checkpointDB = db.NewBoltDB(cfg.Consensus.PoA.CheckpointDBPath, &cfg.DB)
+		}
+		p, err := poa.NewPoA(
+			cfg.Consensus.PoA,
+			GetAddr(cfg).RawAddress,
+			bc,
+			checkpointDB,
+			mintBlockCB,
+			commitBlockCB,
+			broadcastBlockCB,
+			cfg.Consensus.BlockCreationInterval,
+			clock,
+		)
+		if err != nil {
+			logger.Panic().Err(err).Msg("error when constructing PoA")
+		}
+		// Every block, either created locally or received from the network, is checked to be signed by a signer
+		bc.SetValidator(p.Validator())
+		cs.scheme = p
 	case config.NOOPScheme:
 		cs.scheme = scheme.NewNoop()
 	case config.StandaloneScheme:
@@ -0,0 +1,182 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package poa
+
+import (
+	"context"
+	"time"
+
+	"github.com/facebookgo/clock"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/consensus/scheme"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/logger"
+	"github.com/iotexproject/iotex-core/pkg/routine"
+	"github.com/iotexproject/iotex-core/proto"
+)
+
+// PoA is the proof of authority consensus scheme, in which the signers take turns to create blocks. The signer in turn
+// creates the block right away, while each of the other signers waits an interval for every signer before it in turn
+// since the previous block, so that an offline signer doesn't stall the chain.
+type PoA struct {
+	addr      string
+	chain     blockchain.Blockchain
+	kvstore   db.KVStore
+	signers   *signerSet
+	validator *validator
+	interval  time.Duration
+	clock     clock.Clock
+	createCb  scheme.CreateBlockCB
+	commitCb  scheme.ConsensusDoneCB
+	pubCb     scheme.BroadcastCB
+	task      *routine.RecurringTask
+}
+
+// NewPoA creates a PoA struct. The node signs the blocks with the producer address when it's a signer, otherwise it
+// only validates the blocks. The checkpoints of the signer set are kept in memory if the checkpoint DB is nil.
+func NewPoA(
+	cfg config.PoA,
+	addr string,
+	chain blockchain.Blockchain,
+	checkpointDB db.KVStore,
+	create scheme.CreateBlockCB,
+	commit scheme.ConsensusDoneCB,
+	pub scheme.BroadcastCB,
+	interval time.Duration,
+	c clock.Clock,
+) (*PoA, error) {
+	if len(cfg.Signers) == 0 {
+		return nil, errors.New("proof of authority should have at least one signer")
+	}
+	if checkpointDB == nil {
+		checkpointDB = db.NewMemKVStore()
+	}
+	signers := newSignerSet(chain, checkpointDB, cfg.CheckpointInterval, cfg.Signers)
+	p := &PoA{
+		addr:     addr,
+		chain:    chain,
+		kvstore:  checkpointDB,
+		signers:  signers,
+		interval: interval,
+		clock:    c,
+		createCb: create,
+		commitCb: commit,
+		pubCb:    pub,
+		validator: &validator{
+			Validator: chain.Validator(),
+			chain:     chain,
+			signers:   signers,
+			interval:  interval,
+			clock:     c,
+			closest:   make(map[uint64]uint64),
+		},
+	}
+	p.task = routine.NewRecurringTask(p.produce, interval)
+	return p, nil
+}
+
+// Validator returns the validator checking that the blocks are signed by the signers, which is set to the chain
+func (p *PoA) Validator() blockchain.Validator {
+	return p.validator
+}
+
+// Start resumes the signer set from the checkpoint, and starts creating blocks
+func (p *PoA) Start(ctx context.Context) error {
+	if err := p.kvstore.Start(ctx); err != nil {
+		return errors.Wrap(err, "error when starting the checkpoint DB")
+	}
+	if err := p.signers.load(); err != nil {
+		return errors.Wrap(err, "error when loading the checkpoint of the signer set")
+	}
+	return p.task.Start(ctx)
+}
+
+// Stop stops creating blocks
+func (p *PoA) Stop(ctx context.Context) error {
+	if err := p.task.Stop(ctx); err != nil {
+		return err
+	}
+	return errors.Wrap(p.kvstore.Stop(ctx), "error when stopping the checkpoint DB")
+}
+
+// SetDoneStream does nothing in PoA (only used in simulator)
+func (p *PoA) SetDoneStream(done chan bool) {}
+
+// HandleBlockPropose handles incoming block propose
+func (p *PoA) HandleBlockPropose(propose *iproto.ProposePb) error {
+	logger.Warn().Msg("PoA scheme does not handle incoming block propose requests")
+	return nil
+}
+
+// HandleEndorse handles incoming endorse
+func (p *PoA) HandleEndorse(endorse *iproto.EndorsePb) error {
+	logger.Warn().Msg("PoA scheme does not handle incoming endorse requests")
+	return nil
+}
+
+// Metrics returns the signers and the one in turn for the next block
+func (p *PoA) Metrics() (scheme.ConsensusMetrics, error) {
+	height := p.chain.TipHeight()
+	signers, err := p.signers.signersAt(height + 1)
+	if err != nil {
+		return scheme.ConsensusMetrics{}, errors.Wrap(err, "error when getting the signers")
+	}
+	return scheme.ConsensusMetrics{
+		LatestHeight:        height,
+		LatestDelegates:     signers,
+		LatestBlockProducer: signers[(height+1)%uint64(len(signers))],
+		Candidates:          signers,
+	}, nil
+}
+
+// produce creates, commits and broadcasts the next block if it's the turn of the node
+func (p *PoA) produce() {
+	height := p.chain.TipHeight() + 1
+	signers, err := p.signers.signersAt(height)
+	if err != nil {
+		logger.Error().Err(err).Uint64("height", height).Msg("error when getting the signers")
+		return
+	}
+	offset, ok := turnOffset(signers, p.addr, height)
+	if !ok {
+		return
+	}
+	if offset > 0 {
+		prev, err := p.chain.GetBlockByHeight(height - 1)
+		if err != nil {
+			logger.Error().Err(err).Uint64("height", height-1).Msg("error when getting the previous block")
+			return
+		}
+		if p.clock.Now().Before(prev.Header.Timestamp().Add(time.Duration(offset) * p.interval)) {
+			return
+		}
+		logger.Warn().
+			Uint64("height", height).
+			Uint64("offset", offset).
+			Msg("signer in turn is silent, so the block is created out of turn")
+	}
+	blk, err := p.createCb()
+	if err != nil {
+		logger.Error().Err(err).Uint64("height", height).Msg("error when creating a block")
+		return
+	}
+	// The block timestamp is in seconds, which may still be too early
+	if err := p.validator.checkTurn(blk, signers); err != nil {
+		logger.Debug().Err(err).Uint64("height", height).Msg("block is not created in turn")
+		return
+	}
+	if err := p.commitCb(blk); err != nil {
+		logger.Error().Err(err).Uint64("height", height).Msg("error when committing a block")
+		return
+	}
+	if err := p.pubCb(blk); err != nil {
+		logger.Error().Err(err).Uint64("height", height).Msg("error when broadcasting a block")
+	}
+}
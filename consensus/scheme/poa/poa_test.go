@@ -0,0 +1,241 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package poa
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/clock"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/pkg/enc"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
+)
+
+type contentValidator struct{}
+
+func (v *contentValidator) Validate(*blockchain.Block, uint64, hash.Hash32B, bool) error { return nil }
+
+func newTestAddrs(t *testing.T, n int) []*iotxaddress.Address {
+	chainID := make([]byte, 4)
+	enc.MachineEndian.PutUint32(chainID, config.Default.Chain.ID)
+	addrs := make([]*iotxaddress.Address, n)
+	for i := range addrs {
+		addr, err := iotxaddress.NewAddress(iotxaddress.IsTestnet, chainID)
+		require.NoError(t, err)
+		addrs[i] = addr
+	}
+	return addrs
+}
+
+func newTestBlock(t *testing.T, height uint64, ts time.Time, producer *iotxaddress.Address, votes ...*action.SignerVote) *blockchain.Block {
+	actions := make([]action.Action, 0, len(votes))
+	for _, vote := range votes {
+		actions = append(actions, vote)
+	}
+	blk := blockchain.NewBlock(config.Default.Chain.ID, height, hash.ZeroHash32B, uint64(ts.Unix()), nil, nil, nil, actions)
+	require.NoError(t, blk.SignBlock(producer))
+	return blk
+}
+
+func newTestVote(t *testing.T, voter *iotxaddress.Address, candidate *iotxaddress.Address, add bool) *action.SignerVote {
+	vote, err := action.NewSignerVote(voter.RawAddress, 1, candidate.RawAddress, add, action.SignerVoteIntrinsicGas, big.NewInt(0))
+	require.NoError(t, err)
+	require.NoError(t, action.Sign(vote, voter.PrivateKey))
+	return vote
+}
+
+func TestSignerSetTally(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	addrs := newTestAddrs(t, 5)
+	a, b, c, d, e := addrs[0], addrs[1], addrs[2], addrs[3], addrs[4]
+	now := time.Now()
+	blocks := []*blockchain.Block{
+		newTestBlock(t, 1, now, a, newTestVote(t, a, d, true), newTestVote(t, e, d, true)),
+		// The majority of the signers vote for adding d
+		newTestBlock(t, 2, now, b, newTestVote(t, b, d, true)),
+		newTestBlock(t, 3, now, c, newTestVote(t, a, c, false), newTestVote(t, d, c, false), newTestVote(t, c, e, true)),
+		newTestBlock(t, 4, now, d, newTestVote(t, b, c, false)),
+		newTestBlock(t, 5, now, a, newTestVote(t, a, e, true)),
+	}
+	chain := mock_blockchain.NewMockBlockchain(ctrl)
+	for i, blk := range blocks {
+		chain.EXPECT().GetBlockByHeight(uint64(i+1)).Return(blk, nil).AnyTimes()
+	}
+	chain.EXPECT().GetBlockByHeight(uint64(len(blocks)+1)).Return(nil, errors.New("block doesn't exist")).AnyTimes()
+
+	kvstore := db.NewMemKVStore()
+	genesis := []string{a.RawAddress, b.RawAddress, c.RawAddress}
+	signers := newSignerSet(chain, kvstore, 2, genesis)
+	s, err := signers.signersAt(3)
+	require.NoError(err)
+	require.Equal([]string{a.RawAddress, b.RawAddress, c.RawAddress, d.RawAddress}, s)
+	s, err = signers.signersAt(2)
+	require.NoError(err)
+	require.Equal([]string{a.RawAddress, b.RawAddress, c.RawAddress}, s)
+	// c is removed by 3 out of 4 signers, and its vote for e doesn't count anymore
+	s, err = signers.signersAt(6)
+	require.NoError(err)
+	require.Equal([]string{a.RawAddress, b.RawAddress, d.RawAddress}, s)
+	require.Equal(1, len(signers.votes[e.RawAddress]))
+	_, err = signers.signersAt(7)
+	require.Error(err)
+
+	// A restarted node resumes tallying from the checkpoint at block 4
+	chain.EXPECT().TipHeight().Return(uint64(5)).Times(1)
+	restarted := newSignerSet(chain, kvstore, 2, genesis)
+	require.NoError(restarted.load())
+	require.Equal(uint64(4), restarted.tallied)
+	s, err = restarted.signersAt(6)
+	require.NoError(err)
+	require.Equal([]string{a.RawAddress, b.RawAddress, d.RawAddress}, s)
+	require.Equal(signers.votes, restarted.votes)
+	// The checkpoint beyond the tip is ignored
+	chain.EXPECT().TipHeight().Return(uint64(3)).Times(1)
+	restarted = newSignerSet(chain, kvstore, 2, genesis)
+	require.NoError(restarted.load())
+	require.Equal(uint64(0), restarted.tallied)
+	// The checkpoint of another chain is ignored
+	fork := mock_blockchain.NewMockBlockchain(ctrl)
+	fork.EXPECT().TipHeight().Return(uint64(5)).Times(1)
+	fork.EXPECT().GetBlockByHeight(uint64(4)).Return(newTestBlock(t, 4, now, a), nil).Times(1)
+	restarted = newSignerSet(fork, kvstore, 2, genesis)
+	require.NoError(restarted.load())
+	require.Equal(uint64(0), restarted.tallied)
+}
+
+func TestValidator(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	addrs := newTestAddrs(t, 4)
+	interval := 10 * time.Second
+	clk := clock.NewMock()
+	clk.Add(time.Hour)
+	now := clk.Now()
+	prev := newTestBlock(t, 2, now, addrs[2])
+	chain := mock_blockchain.NewMockBlockchain(ctrl)
+	chain.EXPECT().GetBlockByHeight(uint64(1)).Return(newTestBlock(t, 1, now, addrs[1]), nil).AnyTimes()
+	chain.EXPECT().GetBlockByHeight(uint64(2)).Return(prev, nil).AnyTimes()
+	newValidator := func() *validator {
+		return &validator{
+			Validator: &contentValidator{},
+			chain:     chain,
+			signers: newSignerSet(
+				chain,
+				db.NewMemKVStore(),
+				0,
+				[]string{addrs[0].RawAddress, addrs[1].RawAddress, addrs[2].RawAddress},
+			),
+			interval: interval,
+			clock:    clk,
+			closest:  make(map[uint64]uint64),
+		}
+	}
+	v := newValidator()
+	validate := func(blk *blockchain.Block) error {
+		return v.Validate(blk, 2, prev.HashBlock(), true)
+	}
+
+	// addrs[1] is a turn away, which has to wait an interval by both the block timestamp and the local clock
+	err := validate(newTestBlock(t, 3, now.Add(interval-time.Second), addrs[1]))
+	require.Equal(ErrOutOfTurn, errors.Cause(err))
+	err = validate(newTestBlock(t, 3, now.Add(interval), addrs[1]))
+	require.Equal(ErrOutOfTurn, errors.Cause(err))
+	clk.Add(interval)
+	require.NoError(validate(newTestBlock(t, 3, now.Add(interval), addrs[1])))
+	err = validate(newTestBlock(t, 3, now.Add(interval), addrs[2]))
+	require.Equal(ErrOutOfTurn, errors.Cause(err))
+	// addrs[0] is in turn at height 3, and the block out of turn is rejected once the one in turn is known
+	require.NoError(validate(newTestBlock(t, 3, now, addrs[0])))
+	err = validate(newTestBlock(t, 3, now.Add(interval), addrs[1]))
+	require.Equal(ErrOutOfTurn, errors.Cause(err))
+	// The record is dropped once the height is committed
+	require.NoError(v.preferCloser(4, 3, 1))
+	require.Equal(map[uint64]uint64{4: 1}, v.closest)
+
+	v = newValidator()
+	// Neither the producer nor the voter is a signer
+	err = validate(newTestBlock(t, 3, now, addrs[3]))
+	require.Equal(ErrNotSigner, errors.Cause(err))
+	err = validate(newTestBlock(t, 3, now, addrs[0], newTestVote(t, addrs[3], addrs[3], true)))
+	require.Equal(ErrNotSigner, errors.Cause(err))
+	err = validate(blockchain.NewBlock(config.Default.Chain.ID, 3, hash.ZeroHash32B, uint64(now.Unix()), nil, nil, nil, nil))
+	require.Equal(ErrNotSigner, errors.Cause(err))
+}
+
+func TestPoAProduce(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	addrs := newTestAddrs(t, 3)
+	interval := 10 * time.Second
+	clk := clock.NewMock()
+	clk.Add(time.Hour)
+	prev := newTestBlock(t, 2, clk.Now(), addrs[2])
+	chain := mock_blockchain.NewMockBlockchain(ctrl)
+	chain.EXPECT().Validator().Return(&contentValidator{}).AnyTimes()
+	chain.EXPECT().TipHeight().Return(uint64(2)).AnyTimes()
+	chain.EXPECT().GetBlockByHeight(uint64(1)).Return(newTestBlock(t, 1, clk.Now(), addrs[1]), nil).AnyTimes()
+	chain.EXPECT().GetBlockByHeight(uint64(2)).Return(prev, nil).AnyTimes()
+	cfg := config.PoA{Signers: []string{addrs[0].RawAddress, addrs[1].RawAddress, addrs[2].RawAddress}}
+
+	newPoA := func(signer *iotxaddress.Address, committed *[]*blockchain.Block) *PoA {
+		p, err := NewPoA(
+			cfg,
+			signer.RawAddress,
+			chain,
+			nil,
+			func() (*blockchain.Block, error) {
+				return newTestBlock(t, 3, clk.Now(), signer), nil
+			},
+			func(blk *blockchain.Block) error {
+				*committed = append(*committed, blk)
+				return nil
+			},
+			func(blk *blockchain.Block) error { return nil },
+			interval,
+			clk,
+		)
+		require.NoError(err)
+		return p
+	}
+
+	// The signer in turn creates the block right away
+	var committed []*blockchain.Block
+	newPoA(addrs[0], &committed).produce()
+	require.Equal(1, len(committed))
+	// The next signer waits an interval for the one in turn
+	committed = nil
+	p := newPoA(addrs[1], &committed)
+	p.produce()
+	require.Equal(0, len(committed))
+	clk.Add(interval)
+	p.produce()
+	require.Equal(1, len(committed))
+	require.Equal(addrs[1].RawAddress, committed[0].ProducerAddress())
+
+	metrics, err := p.Metrics()
+	require.NoError(err)
+	require.Equal(cfg.Signers, metrics.LatestDelegates)
+	require.Equal(addrs[0].RawAddress, metrics.LatestBlockProducer)
+}
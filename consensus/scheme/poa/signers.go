@@ -0,0 +1,235 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package poa
+
+import (
+	"bytes"
+	"encoding/gob"
+	"sync"
+
+	"github.com/boltdb/bolt"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/logger"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+)
+
+const poaNS = "poa"
+
+var checkpointKey = []byte("checkpoint")
+
+// signerSet tracks the signers at each height. Starting from the genesis signers, the set is changed by the signer
+// votes on the chain: once the majority of the signers vote for adding a candidate or removing a signer, the change
+// takes effect from the next block on. The set is checkpointed every interval of blocks, from which a restarted node
+// resumes tallying.
+type signerSet struct {
+	mutex    sync.Mutex
+	chain    blockchain.Blockchain
+	kvstore  db.KVStore
+	interval uint64
+	// heights are the heights from which the signers of the same index in history are effective
+	heights []uint64
+	history [][]string
+	// tallied is the height of the last block whose votes have been tallied
+	tallied uint64
+	// votes are the pending votes to each candidate from each signer, true for adding and false for removing
+	votes map[string]map[string]bool
+}
+
+// checkpoint is the persisted signer set, which has tallied the votes up to the block of the hash at the height
+type checkpoint struct {
+	Tallied uint64
+	Hash    hash.Hash32B
+	Heights []uint64
+	History [][]string
+	Votes   map[string]map[string]bool
+}
+
+func newSignerSet(
+	chain blockchain.Blockchain,
+	kvstore db.KVStore,
+	interval uint64,
+	genesisSigners []string,
+) *signerSet {
+	signers := make([]string, len(genesisSigners))
+	copy(signers, genesisSigners)
+	return &signerSet{
+		chain:    chain,
+		kvstore:  kvstore,
+		interval: interval,
+		heights:  []uint64{0},
+		history:  [][]string{signers},
+		votes:    make(map[string]map[string]bool),
+	}
+}
+
+// load resumes the signer set from the checkpoint, unless it's beyond the tip or of another chain
+func (s *signerSet) load() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	value, err := s.kvstore.Get(poaNS, checkpointKey)
+	if cause := errors.Cause(err); cause == db.ErrNotExist || cause == bolt.ErrBucketNotFound {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to get the checkpoint of the signer set")
+	}
+	var cp checkpoint
+	if err := gob.NewDecoder(bytes.NewBuffer(value)).Decode(&cp); err != nil {
+		return errors.Wrap(err, "failed to decode the checkpoint of the signer set")
+	}
+	if cp.Tallied > s.chain.TipHeight() {
+		logger.Warn().Uint64("height", cp.Tallied).Msg("checkpoint of the signer set is beyond the tip")
+		return nil
+	}
+	blk, err := s.chain.GetBlockByHeight(cp.Tallied)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get block %d of the checkpoint", cp.Tallied)
+	}
+	if blk.HashBlock() != cp.Hash {
+		logger.Warn().Uint64("height", cp.Tallied).Msg("checkpoint of the signer set doesn't match the chain")
+		return nil
+	}
+	s.tallied = cp.Tallied
+	s.heights = cp.Heights
+	s.history = cp.History
+	s.votes = cp.Votes
+	if s.votes == nil {
+		s.votes = make(map[string]map[string]bool)
+	}
+	return nil
+}
+
+// save persists the signer set which has tallied the votes up to the block
+func (s *signerSet) save(blk *blockchain.Block) error {
+	cp := checkpoint{
+		Tallied: s.tallied,
+		Hash:    blk.HashBlock(),
+		Heights: s.heights,
+		History: s.history,
+		Votes:   s.votes,
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&cp); err != nil {
+		return errors.Wrap(err, "failed to encode the checkpoint of the signer set")
+	}
+	return errors.Wrap(s.kvstore.Put(poaNS, checkpointKey, buf.Bytes()), "failed to put the checkpoint of the signer set")
+}
+
+// signersAt returns the signers of the block at the given height, after tallying the votes of the blocks before it
+func (s *signerSet) signersAt(height uint64) ([]string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for s.tallied+1 < height {
+		blk, err := s.chain.GetBlockByHeight(s.tallied + 1)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get block %d to tally the signer votes", s.tallied+1)
+		}
+		s.tally(blk)
+		s.tallied++
+		if s.interval > 0 && s.tallied%s.interval == 0 {
+			if err := s.save(blk); err != nil {
+				logger.Error().Err(err).Uint64("height", s.tallied).Msg("error when checkpointing the signer set")
+			}
+		}
+	}
+	for i := len(s.heights) - 1; i > 0; i-- {
+		if s.heights[i] <= height {
+			return s.history[i], nil
+		}
+	}
+	return s.history[0], nil
+}
+
+// tally counts the signer votes in the block
+func (s *signerSet) tally(blk *blockchain.Block) {
+	for _, act := range blk.Actions {
+		vote, ok := act.(*action.SignerVote)
+		if !ok {
+			continue
+		}
+		signers := s.history[len(s.history)-1]
+		if !contains(signers, vote.Voter()) {
+			continue
+		}
+		if _, ok := s.votes[vote.Candidate()]; !ok {
+			s.votes[vote.Candidate()] = make(map[string]bool)
+		}
+		s.votes[vote.Candidate()][vote.Voter()] = vote.Add()
+
+		// Only the votes changing the set count, i.e., adding a candidate not in the set, or removing a signer
+		add := !contains(signers, vote.Candidate())
+		count := 0
+		for _, a := range s.votes[vote.Candidate()] {
+			if a == add {
+				count++
+			}
+		}
+		if count <= len(signers)/2 || (!add && len(signers) == 1) {
+			continue
+		}
+		s.change(blk.Height()+1, vote.Candidate(), add)
+	}
+}
+
+// change adds or removes the candidate from the given height on
+func (s *signerSet) change(height uint64, candidate string, add bool) {
+	signers := s.history[len(s.history)-1]
+	next := make([]string, 0, len(signers)+1)
+	for _, signer := range signers {
+		if signer != candidate {
+			next = append(next, signer)
+		}
+	}
+	if add {
+		next = append(next, candidate)
+	}
+	if s.heights[len(s.heights)-1] == height {
+		s.history[len(s.history)-1] = next
+	} else {
+		s.heights = append(s.heights, height)
+		s.history = append(s.history, next)
+	}
+	delete(s.votes, candidate)
+	if !add {
+		// The votes of the removed signer don't count anymore
+		for _, votes := range s.votes {
+			delete(votes, candidate)
+		}
+	}
+	logger.Info().
+		Str("candidate", candidate).
+		Bool("add", add).
+		Uint64("height", height).
+		Int("signers", len(next)).
+		Msg("signer set is changed by the votes")
+}
+
+// turnOffset returns how many turns the signer is away from the one in turn at the height
+func turnOffset(signers []string, signer string, height uint64) (uint64, bool) {
+	n := uint64(len(signers))
+	for i, s := range signers {
+		if s == signer {
+			return (uint64(i) + n - height%n) % n, true
+		}
+	}
+	return 0, false
+}
+
+func contains(signers []string, signer string) bool {
+	for _, s := range signers {
+		if s == signer {
+			return true
+		}
+	}
+	return false
+}
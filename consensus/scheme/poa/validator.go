@@ -0,0 +1,117 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package poa
+
+import (
+	"sync"
+	"time"
+
+	"github.com/facebookgo/clock"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+)
+
+var (
+	// ErrNotSigner indicates that the block or the signer vote isn't signed by a signer
+	ErrNotSigner = errors.New("not signed by a signer")
+	// ErrOutOfTurn indicates that the block is signed by a signer out of turn too early, or further out of turn than
+	// another block known at the height
+	ErrOutOfTurn = errors.New("signed out of turn too early")
+)
+
+// validator checks that a block is signed by a signer, on top of the validation of the block content. As the chain
+// doesn't reorg, the block signed closest in turn is preferred at each height: a block out of turn isn't accepted
+// before its turn comes on the local clock, and is rejected once a block closer in turn is known at the height.
+type validator struct {
+	blockchain.Validator
+	chain    blockchain.Blockchain
+	signers  *signerSet
+	interval time.Duration
+	clock    clock.Clock
+	mutex    sync.Mutex
+	// closest are the smallest turn offsets of the blocks validated at the heights not committed yet
+	closest map[uint64]uint64
+}
+
+// Validate validates the block content, and then the signer of the block
+func (v *validator) Validate(blk *blockchain.Block, tipHeight uint64, tipHash hash.Hash32B, containCoinbase bool) error {
+	if err := v.Validator.Validate(blk, tipHeight, tipHash, containCoinbase); err != nil {
+		return err
+	}
+	if blk.IsDummyBlock() {
+		return errors.Wrap(ErrNotSigner, "proof of authority doesn't accept dummy blocks")
+	}
+	signers, err := v.signers.signersAt(blk.Height())
+	if err != nil {
+		return err
+	}
+	if err := v.checkTurn(blk, signers); err != nil {
+		return err
+	}
+	for _, act := range blk.Actions {
+		if vote, ok := act.(*action.SignerVote); ok && !contains(signers, vote.Voter()) {
+			return errors.Wrapf(ErrNotSigner, "voter %s of signer vote %x", vote.Voter(), vote.Hash())
+		}
+	}
+	offset, _ := turnOffset(signers, blk.ProducerAddress(), blk.Height())
+	return v.preferCloser(blk.Height(), tipHeight, offset)
+}
+
+// preferCloser records the turn offset of the block validated at the height, unless a block closer in turn is known
+func (v *validator) preferCloser(height uint64, tipHeight uint64, offset uint64) error {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	for h := range v.closest {
+		if h <= tipHeight {
+			delete(v.closest, h)
+		}
+	}
+	if closest, ok := v.closest[height]; ok && closest < offset {
+		return errors.Wrapf(
+			ErrOutOfTurn,
+			"block %d is %d turns away, while a block %d turns away is known",
+			height,
+			offset,
+			closest,
+		)
+	}
+	v.closest[height] = offset
+	return nil
+}
+
+// checkTurn checks that the producer is a signer, and that it has waited an interval for each signer before it in turn
+// since the previous block, by both the block timestamp and the local clock
+func (v *validator) checkTurn(blk *blockchain.Block, signers []string) error {
+	producer := blk.ProducerAddress()
+	offset, ok := turnOffset(signers, producer, blk.Height())
+	if !ok {
+		return errors.Wrapf(ErrNotSigner, "producer %s of block %d", producer, blk.Height())
+	}
+	if offset == 0 {
+		return nil
+	}
+	prev, err := v.chain.GetBlockByHeight(blk.Height() - 1)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get block %d", blk.Height()-1)
+	}
+	earliest := prev.Header.Timestamp().Add(time.Duration(offset) * v.interval)
+	if blk.Header.Timestamp().Before(earliest) || v.clock.Now().Before(earliest) {
+		return errors.Wrapf(
+			ErrOutOfTurn,
+			"producer %s is %d turns away at block %d, which is signed before %s",
+			producer,
+			offset,
+			blk.Height(),
+			earliest,
+		)
+	}
+	return nil
+}
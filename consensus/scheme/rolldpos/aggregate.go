@@ -0,0 +1,376 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/logger"
+	"github.com/iotexproject/iotex-core/network"
+	"github.com/iotexproject/iotex-core/network/node"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+)
+
+// errUnknownBLSKey indicates that the DKG key of an endorser hasn't been published in the blocks of the epoch yet
+var errUnknownBLSKey = errors.New("DKG key of the endorser is unknown")
+
+// blsKey is the DKG key which a delegate publishes in the header of the blocks it produces
+type blsKey struct {
+	pubkey []byte
+}
+
+// blsKeyCache caches the DKG keys published in the blocks of an epoch, which are scanned up to the given height
+type blsKeyCache struct {
+	mutex   sync.Mutex
+	epoch   uint64
+	seed    []byte
+	scanned uint64
+	keys    map[string]blsKey
+}
+
+// shareKey identifies the endorses which are aggregated together
+type shareKey struct {
+	topic    bool
+	blkHash  hash.Hash32B
+	decision bool
+}
+
+// endorsers returns the addresses eligible to endorse the block at the given height, which are the delegates of the
+// epoch picked with the on-chain seed, and the number of endorsements needed to commit it
+func (ctx *rollDPoSCtx) endorsers(height uint64) ([]string, int, error) {
+	numDlgs := ctx.cfg.NumDelegates
+	if height == 0 || numDlgs == 0 {
+		return nil, 0, errors.Errorf("no endorser of block %d", height)
+	}
+	delegates, err := ctx.epochDelegates(ctx.epochNumOf(height))
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "error when getting the endorsers of block %d", height)
+	}
+	return delegates, quorum(int(numDlgs)), nil
+}
+
+// isEndorser checks if the address is eligible to endorse the block at the given height
+func (ctx *rollDPoSCtx) isEndorser(addr string, height uint64) (bool, error) {
+	endorsers, _, err := ctx.endorsers(height)
+	if err != nil {
+		return false, err
+	}
+	for _, endorser := range endorsers {
+		if endorser == addr {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// blsKeys returns the DKG keys of the delegates, which are published in the blocks of the epoch before the given height.
+// As the keys of the signers are summed up to verify an aggregate signature, a key only counts once its producer proves
+// owning it, so that no one could publish a key canceling out the keys of the others.
+func (ctx *rollDPoSCtx) blsKeys(height uint64) (map[string]blsKey, error) {
+	numDlgs := ctx.cfg.NumDelegates
+	if height == 0 || numDlgs == 0 {
+		return nil, errors.Errorf("no DKG key of block %d", height)
+	}
+	epochNum := ctx.epochNumOf(height)
+
+	cache := &ctx.keyCache
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if cache.epoch != epochNum || cache.scanned >= height {
+		seed, err := ctx.epochSeed(epochNum)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error when getting the seed of epoch %d to verify the DKG keys", epochNum)
+		}
+		cache.epoch = epochNum
+		cache.seed = seed
+		cache.scanned = ctx.epochStartHeight(epochNum) - 1
+		cache.keys = make(map[string]blsKey)
+	}
+	for h := cache.scanned + 1; h < height; h++ {
+		blk, err := ctx.chain.GetBlockByHeight(h)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error when getting block %d to read the DKG key", h)
+		}
+		if len(blk.Header.DKGID) > 0 && len(blk.Header.DKGPubkey) > 0 {
+			if err := verifyDKGKey(blk, cache.seed); err != nil {
+				logger.Warn().Err(err).Uint64("height", h).Msg("ignore the DKG key of the block")
+			} else {
+				cache.keys[blk.ProducerAddress()] = blsKey{pubkey: blk.Header.DKGPubkey}
+			}
+		}
+		cache.scanned = h
+	}
+	keys := make(map[string]blsKey, len(cache.keys))
+	for addr, key := range cache.keys {
+		keys[addr] = key
+	}
+	return keys, nil
+}
+
+// newBitmap marks the signers among the endorsers in a bitmap, and returns it with the signers in the order of the
+// endorsers
+func newBitmap(endorsers []string, signed map[string]bool) ([]byte, []string) {
+	bitmap := make([]byte, (len(endorsers)+7)/8)
+	signers := make([]string, 0, len(signed))
+	for i, endorser := range endorsers {
+		if signed[endorser] {
+			bitmap[i/8] |= 1 << uint(i%8)
+			signers = append(signers, endorser)
+		}
+	}
+	return bitmap, signers
+}
+
+// bitmapSigners returns the endorsers marked in the bitmap
+func bitmapSigners(endorsers []string, bitmap []byte) ([]string, error) {
+	if len(bitmap) != (len(endorsers)+7)/8 {
+		return nil, errors.Errorf("bitmap of %d bytes doesn't match %d endorsers", len(bitmap), len(endorsers))
+	}
+	signers := make([]string, 0)
+	for i := 0; i < len(bitmap)*8; i++ {
+		if bitmap[i/8]&(1<<uint(i%8)) == 0 {
+			continue
+		}
+		if i >= len(endorsers) {
+			return nil, errors.Errorf("bit %d is beyond %d endorsers", i, len(endorsers))
+		}
+		signers = append(signers, endorsers[i])
+	}
+	return signers, nil
+}
+
+// verifyDKGKey checks that the DKG key published in the block is the one of its producer, which proves owning the key
+// with the DKG signature over the seed of the epoch
+func verifyDKGKey(blk *blockchain.Block, seed []byte) error {
+	producer := blk.ProducerAddress()
+	if !bytes.Equal(blk.Header.DKGID, iotxaddress.CreateID(producer)) {
+		return errors.Errorf("DKG ID of block %d isn't the one of the producer %s", blk.Height(), producer)
+	}
+	if err := crypto.BLS.PkValidation(blk.Header.DKGPubkey); err != nil {
+		return errors.Wrapf(err, "invalid DKG public key of block %d", blk.Height())
+	}
+	if err := verifyDKGSignature(blk, seed); err != nil {
+		return errors.Wrapf(err, "DKG signature of block %d doesn't prove owning the DKG key", blk.Height())
+	}
+	return nil
+}
+
+// aggregate aggregates the BLS signatures of the endorses into a multi-signature, which should be the ones of the known
+// DKG keys
+func (ctx *rollDPoSCtx) aggregate(height uint64, shares map[string]*endorse) (*blockchain.AggregateEndorsement, error) {
+	endorsers, _, err := ctx.endorsers(height)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := ctx.blsKeys(height)
+	if err != nil {
+		return nil, err
+	}
+	signed := make(map[string]bool, len(shares))
+	for endorser := range shares {
+		signed[endorser] = true
+	}
+	bitmap, signers := newBitmap(endorsers, signed)
+	if len(signers) == 0 {
+		return nil, errors.New("no signature of the endorsers to aggregate")
+	}
+	sigs := make([][]byte, 0, len(signers))
+	for _, signer := range signers {
+		if _, ok := keys[signer]; !ok {
+			return nil, errors.Wrapf(errUnknownBLSKey, "endorser %s", signer)
+		}
+		sigs = append(sigs, shares[signer].blsSignature)
+	}
+	sig, err := crypto.BLS.AggregateSignatures(sigs)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when aggregating the signatures")
+	}
+	return &blockchain.AggregateEndorsement{Bitmap: bitmap, Signature: sig}, nil
+}
+
+// verifyAggregate verifies the multi-signature of the message against the DKG keys of the endorsers marked in the
+// bitmap, and returns them. Every endorser in the bitmap has signed, as its key is part of the aggregate key.
+func (ctx *rollDPoSCtx) verifyAggregate(height uint64, msg []byte, bitmap []byte, sig []byte) ([]string, error) {
+	endorsers, _, err := ctx.endorsers(height)
+	if err != nil {
+		return nil, err
+	}
+	signers, err := bitmapSigners(endorsers, bitmap)
+	if err != nil {
+		return nil, err
+	}
+	if len(signers) == 0 {
+		return nil, errors.New("no endorser in the bitmap")
+	}
+	keys, err := ctx.blsKeys(height)
+	if err != nil {
+		return nil, err
+	}
+	pubkeys := make([][]byte, 0, len(signers))
+	for _, signer := range signers {
+		key, ok := keys[signer]
+		if !ok {
+			return nil, errors.Wrapf(errUnknownBLSKey, "endorser %s", signer)
+		}
+		pubkeys = append(pubkeys, key.pubkey)
+	}
+	if err := crypto.BLS.VerifyMultiSig(pubkeys, msg, sig); err != nil {
+		return nil, errors.Wrap(err, "error when verifying the aggregate signature")
+	}
+	return signers, nil
+}
+
+// VerifyAggregate checks the BLS aggregate endorsement of the block committed at the given height, and returns the
+// endorsers marked in its bitmap
+func (r *RollDPoS) VerifyAggregate(height uint64, blkHash hash.Hash32B, agg *blockchain.AggregateEndorsement) ([]string, error) {
+	msg := action.EndorseByteStream(height, endorseCommit, blkHash, true)
+	return r.ctx.verifyAggregate(height, msg, agg.Bitmap, agg.Signature)
+}
+
+// signShare signs the endorse with the DKG private key as well, if the endorses are aggregated
+func (m *cFSM) signShare(en *endorse) {
+	if !m.ctx.cfg.AggregateEndorses || len(m.ctx.epoch.dkgAddress.PrivateKey) == 0 {
+		return
+	}
+	_, sig, err := crypto.BLS.SignShare(m.ctx.epoch.dkgAddress.PrivateKey, en.ByteStream())
+	if err != nil {
+		logger.Error().Err(err).Uint64("height", en.height).Msg("error when signing the endorse with the DKG key")
+		return
+	}
+	en.blsSignature = sig
+}
+
+// sendEndorse sends the endorse signed with the DKG key to the proposer of the round to aggregate, unless the node is
+// the proposer or its DKG key isn't known to the others yet. Otherwise, the endorse is broadcast.
+func (m *cFSM) sendEndorse(en *endorse) {
+	msg := en.toProtoMsg()
+	proposerAddr := m.ctx.round.proposerAddr
+	if m.ctx.cfg.AggregateEndorses && en.blsSignature != nil && proposerAddr != "" &&
+		m.ctx.round.proposer != m.ctx.addr.RawAddress {
+		keys, err := m.ctx.blsKeys(en.height)
+		if _, ok := keys[m.ctx.addr.RawAddress]; err == nil && ok {
+			err := m.ctx.p2p.Tell(m.ctx.chain.ChainID(), node.NewTCPNode(proposerAddr), msg)
+			if err == nil {
+				return
+			}
+			logger.Warn().
+				Err(err).
+				Str("proposer", proposerAddr).
+				Msg("error when sending the endorse to the proposer, which is broadcast instead")
+		}
+	}
+	if err := m.ctx.p2p.Broadcast(m.ctx.chain.ChainID(), msg); err != nil {
+		logger.Error().
+			Err(err).
+			Bool("topic", en.topic).
+			Msg("error when broadcasting the endorse")
+	}
+}
+
+// collectShare keeps the endorse carrying a valid BLS signature share, if the node is the proposer of the round
+func (m *cFSM) collectShare(en *endorse) {
+	if !m.ctx.cfg.AggregateEndorses || m.ctx.round.proposer != m.ctx.addr.RawAddress || en.blsSignature == nil ||
+		en.aggregate != nil {
+		return
+	}
+	keys, err := m.ctx.blsKeys(en.height)
+	if err != nil {
+		logger.Warn().Err(err).Uint64("height", en.height).Msg("error when getting the DKG keys")
+		return
+	}
+	key, ok := keys[en.endorser]
+	if !ok {
+		return
+	}
+	if err := crypto.BLS.VerifyShare(key.pubkey, en.ByteStream(), en.blsSignature); err != nil {
+		logger.Warn().Err(err).Str("endorser", en.endorser).Msg("invalid signature share of the endorse")
+		return
+	}
+	k := shareKey{topic: en.topic, blkHash: en.blkHash, decision: en.decision}
+	if m.ctx.round.shares == nil {
+		m.ctx.round.shares = make(map[shareKey]map[string]*endorse)
+	}
+	if m.ctx.round.shares[k] == nil {
+		m.ctx.round.shares[k] = make(map[string]*endorse)
+	}
+	m.ctx.round.shares[k][en.endorser] = en
+}
+
+// aggregateEndorses gossips the BLS aggregate of the endorses on the block, once the proposer of the round collects a
+// quorum of them. If the shares can't be aggregated, the endorses are relayed individually instead.
+func (m *cFSM) aggregateEndorses(topic bool, blkHash hash.Hash32B, decision bool) {
+	if !m.ctx.cfg.AggregateEndorses || m.ctx.round.proposer != m.ctx.addr.RawAddress {
+		return
+	}
+	shares := m.ctx.round.shares[shareKey{topic: topic, blkHash: blkHash, decision: decision}]
+	agg, err := m.ctx.aggregate(m.ctx.round.height, shares)
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Uint64("height", m.ctx.round.height).
+			Bool("topic", topic).
+			Msg("error when aggregating the endorses, which are relayed individually")
+		for endorser, en := range shares {
+			if endorser == m.ctx.addr.RawAddress {
+				continue
+			}
+			if err := m.ctx.p2p.Broadcast(m.ctx.chain.ChainID(), en.toProtoMsg()); err != nil {
+				logger.Error().Err(err).Str("endorser", endorser).Msg("error when relaying the endorse")
+			}
+		}
+		return
+	}
+	if topic == endorseCommit && decision {
+		m.setAggregate(blkHash, agg)
+	}
+	en := &endorse{
+		topic:     topic,
+		height:    m.ctx.round.height,
+		blkHash:   blkHash,
+		decision:  decision,
+		aggregate: agg,
+	}
+	if err := m.ctx.p2p.Broadcast(m.ctx.chain.ChainID(), en.toProtoMsg()); err != nil {
+		logger.Error().
+			Err(err).
+			Bool("topic", topic).
+			Msg("error when broadcasting the aggregate endorse")
+	}
+}
+
+// setAggregate keeps the aggregate of the yes commit endorses on the block, which is embedded into it when committed
+func (m *cFSM) setAggregate(blkHash hash.Hash32B, agg *blockchain.AggregateEndorsement) {
+	if m.ctx.round.aggregates == nil {
+		m.ctx.round.aggregates = make(map[hash.Hash32B]*blockchain.AggregateEndorsement)
+	}
+	m.ctx.round.aggregates[blkHash] = agg
+}
+
+// handleAggregateEndorse verifies the aggregate endorse, and feeds the FSM with the endorse of each endorser in it
+func (r *RollDPoS) handleAggregateEndorse(en *endorse) error {
+	msg := en.ByteStream()
+	endorsers, err := r.ctx.verifyAggregate(en.height, msg, en.aggregate.Bitmap, en.aggregate.Signature)
+	if err != nil {
+		if errors.Cause(err) == errUnknownBLSKey {
+			return err
+		}
+		return errors.Wrapf(network.ErrBadSignature, "aggregate endorse of block %d: %v", en.height, err)
+	}
+	for _, endorser := range endorsers {
+		signer := *en
+		signer.endorser = endorser
+		r.cfsm.produce(newEndorseEvtWithEndorse(&signer, r.ctx.clock), 0)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"testing"
+
+	"github.com/facebookgo/clock"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/test/mock/mock_actpool"
+	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
+	"github.com/iotexproject/iotex-core/test/mock/mock_network"
+)
+
+// newTestDKGKeys runs the DKG among the delegates of the given IDs, and returns their public and private keys
+func newTestDKGKeys(t *testing.T, ids [][]uint8) ([][]byte, [][]uint32) {
+	require := require.New(t)
+	n := len(ids)
+	sharesList := make([][][]uint32, n)
+	witnessesList := make([][][]byte, n)
+	for i := 0; i < n; i++ {
+		var err error
+		_, sharesList[i], witnessesList[i], err = crypto.DKG.Init(crypto.DKG.SkGeneration(), ids)
+		require.NoError(err)
+	}
+	pks := make([][]byte, n)
+	sks := make([][]uint32, n)
+	shares := make([][]uint32, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			shares[j] = sharesList[j][i]
+		}
+		statusMatrix := make([][21]bool, n)
+		for j := 0; j < n; j++ {
+			for k := 0; k < n; k++ {
+				statusMatrix[j][k] = true
+			}
+		}
+		var err error
+		_, pks[i], sks[i], err = crypto.DKG.KeyPairGeneration(shares, statusMatrix)
+		require.NoError(err)
+	}
+	return pks, sks
+}
+
+func TestBitmap(t *testing.T) {
+	require := require.New(t)
+
+	endorsers := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+	bitmap, signers := newBitmap(endorsers, map[string]bool{"j": true, "a": true, "i": true, "x": true})
+	require.Equal([]byte{0x01, 0x03}, bitmap)
+	require.Equal([]string{"a", "i", "j"}, signers)
+	decoded, err := bitmapSigners(endorsers, bitmap)
+	require.NoError(err)
+	require.Equal(signers, decoded)
+
+	_, err = bitmapSigners(endorsers, []byte{0x01})
+	require.Error(err)
+	// The bit beyond the endorsers is invalid
+	_, err = bitmapSigners(endorsers, []byte{0x01, 0x04})
+	require.Error(err)
+}
+
+func TestAggregateEndorses(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const numDelegates = 21
+	addrs := make([]*iotxaddress.Address, numDelegates)
+	ids := make([][]uint8, numDelegates)
+	candidates := make([]*state.Candidate, numDelegates)
+	for i := range addrs {
+		addrs[i] = newTestAddr()
+		ids[i] = iotxaddress.CreateID(addrs[i].RawAddress)
+		candidates[i] = &state.Candidate{Address: addrs[i].RawAddress}
+	}
+	pks, sks := newTestDKGKeys(t, ids)
+
+	// The delegates publish their DKG keys in the blocks they produce, except the last one. The key of another one's ID,
+	// or the key which the producer doesn't prove owning with the DKG signature over the seed, doesn't count.
+	height := uint64(numDelegates)
+	const numKeys = numDelegates - 3
+	cfg := config.Default.Consensus.RollDPoS
+	cfg.NumDelegates = numDelegates
+	cfg.AggregateEndorses = true
+	ctx := makeTestRollDPoSCtx(
+		addrs[0],
+		ctrl,
+		cfg,
+		func(chain *mock_blockchain.MockBlockchain) {
+			for h := uint64(1); h < height; h++ {
+				i := h - 1
+				blk := blockchain.NewBlock(config.Default.Chain.ID, h, hash.ZeroHash32B, 0, nil, nil, nil, nil)
+				blk.Header.DKGID = ids[i]
+				blk.Header.DKGPubkey = pks[i]
+				signer := sks[i]
+				switch i {
+				case numKeys:
+					blk.Header.DKGID = ids[0]
+				case numKeys + 1:
+					signer = sks[0]
+				}
+				_, sig, err := crypto.BLS.Sign(signer, crypto.CryptoSeed)
+				require.NoError(err)
+				blk.Header.DKGBlockSig = sig
+				require.NoError(blk.SignBlock(addrs[i]))
+				chain.EXPECT().GetBlockByHeight(h).Return(blk, nil).AnyTimes()
+			}
+		},
+		func(_ *mock_actpool.MockActPool) {},
+		func(_ *mock_network.MockOverlay) {},
+		clock.NewMock(),
+	)
+	ctx.candidatesByHeightFunc = func(uint64) ([]*state.Candidate, error) { return candidates, nil }
+	r := &RollDPoS{ctx: ctx}
+
+	blkHash := hash.Hash256b([]byte("block"))
+	shares := make(map[string]*endorse)
+	for i := 0; i < numKeys; i++ {
+		en := &endorse{topic: endorseCommit, height: height, blkHash: blkHash, decision: true}
+		require.NoError(en.Sign(addrs[i]))
+		_, en.blsSignature, _ = crypto.BLS.SignShare(sks[i], en.ByteStream())
+		require.NoError(crypto.BLS.VerifyShare(pks[i], en.ByteStream(), en.blsSignature))
+		shares[en.endorser] = en
+	}
+	keys, err := ctx.blsKeys(height)
+	require.NoError(err)
+	require.Equal(numKeys, len(keys))
+	for _, addr := range addrs[numKeys:] {
+		_, ok := keys[addr.RawAddress]
+		require.False(ok)
+	}
+
+	agg, err := ctx.aggregate(height, shares)
+	require.NoError(err)
+	endorsers, err := r.VerifyAggregate(height, blkHash, agg)
+	require.NoError(err)
+	require.Equal(numKeys, len(endorsers))
+	_, err = r.VerifyAggregate(height, hash.ZeroHash32B, agg)
+	require.Error(err)
+	// The aggregate endorse is carried over the wire
+	aggEn := &endorse{topic: endorseCommit, height: height, blkHash: blkHash, decision: true, aggregate: agg}
+	var restored endorse
+	require.NoError(restored.fromProtoMsg(aggEn.toProtoMsg()))
+	require.Equal(*aggEn, restored)
+
+	// The bitmap can't mark an endorser whose signature isn't aggregated
+	delete(shares, addrs[0].RawAddress)
+	partial, err := ctx.aggregate(height, shares)
+	require.NoError(err)
+	forged := &blockchain.AggregateEndorsement{Bitmap: agg.Bitmap, Signature: partial.Signature}
+	_, err = r.VerifyAggregate(height, blkHash, forged)
+	require.Error(err)
+	forged = &blockchain.AggregateEndorsement{Bitmap: partial.Bitmap, Signature: agg.Signature}
+	_, err = r.VerifyAggregate(height, blkHash, forged)
+	require.Error(err)
+
+	// The shares of the endorsers whose keys are unknown can't be aggregated
+	shares[addrs[numKeys+1].RawAddress] = &endorse{blsSignature: []byte{}}
+	_, err = ctx.aggregate(height, shares)
+	require.Equal(errUnknownBLSKey, errors.Cause(err))
+	_, err = ctx.aggregate(height, map[string]*endorse{})
+	require.Error(err)
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/logger"
+)
+
+var (
+	// ErrDKGCommitteeSize indicates that the number of delegates is out of the range which DKG supports
+	ErrDKGCommitteeSize = errors.Errorf("DKG should run among %d to %d delegates", crypto.Degree+1, crypto.NumNodes)
+	// errMissingShare indicates that the dealer doesn't deal a secret share to the delegate
+	errMissingShare = errors.New("secret share is missing")
+	// errInvalidShare indicates that the secret share doesn't match the witness of the dealer
+	errInvalidShare = errors.New("secret share doesn't match the witness")
+)
+
+// dkgComplaint is the complaint of a delegate against the dealer of a secret block, whose share to the delegate is
+// missing or invalid
+type dkgComplaint struct {
+	dealer     string
+	complainer string
+	reason     error
+}
+
+// validateDKGCommittee checks that DKG could run among the given number of delegates. The DKG library works with a fixed
+// number of nodes, so a smaller committee is padded with the placeholder ones, and it needs more delegates than the
+// degree of the polynomial to share the secrets.
+func validateDKGCommittee(numDelegates int) error {
+	if numDelegates <= crypto.Degree || numDelegates > crypto.NumNodes {
+		return errors.Wrapf(ErrDKGCommitteeSize, "%d delegates", numDelegates)
+	}
+	return nil
+}
+
+// dkgIDs returns the DKG IDs of the delegates, padded with the IDs of the placeholder nodes
+func dkgIDs(delegates []string) [][]uint8 {
+	ids := make([][]uint8, 0, crypto.NumNodes)
+	for _, delegate := range delegates {
+		ids = append(ids, iotxaddress.CreateID(delegate))
+	}
+	for i := len(ids); i < crypto.NumNodes; i++ {
+		ids = append(ids, iotxaddress.CreateID(fmt.Sprintf("dkg-placeholder-%d", i)))
+	}
+	return ids
+}
+
+// verifySecretBlock verifies the secret share dealt to each delegate in the secret block against the witness of the
+// dealer, and returns the complaints of the delegates whose shares are missing or invalid. The shares are carried by the
+// block in plaintext, so the share committed by the dealer is the justification of the complaint, which every delegate
+// settles in the same way.
+func verifySecretBlock(blk *blockchain.Block, delegates []string) []*dkgComplaint {
+	dealer := blk.ProducerAddress()
+	shares := make(map[string]*action.SecretProposal, len(blk.SecretProposals))
+	for _, sp := range blk.SecretProposals {
+		if sp.SrcAddr() == dealer {
+			shares[sp.DstAddr()] = sp
+		}
+	}
+	complaints := make([]*dkgComplaint, 0)
+	for _, delegate := range delegates {
+		complaint := &dkgComplaint{dealer: dealer, complainer: delegate}
+		sp, ok := shares[delegate]
+		if !ok || blk.SecretWitness == nil {
+			complaint.reason = errMissingShare
+			complaints = append(complaints, complaint)
+			continue
+		}
+		valid, err := crypto.DKG.ShareVerify(iotxaddress.CreateID(delegate), sp.Secret(), blk.SecretWitness.Witness())
+		if err != nil || !valid {
+			complaint.reason = errInvalidShare
+			if err != nil {
+				complaint.reason = errors.Wrap(errInvalidShare, err.Error())
+			}
+			complaints = append(complaints, complaint)
+		}
+	}
+	return complaints
+}
+
+// dkgKeyPair generates the DKG key pair of a delegate from the secret shares dealt to it by the qualified dealers among
+// the delegates, which secretOf returns
+func dkgKeyPair(delegates []string, secretOf func(dealer string) ([]uint32, bool)) ([]byte, []uint32, error) {
+	if err := validateDKGCommittee(len(delegates)); err != nil {
+		return nil, nil, err
+	}
+	shares := make([][]uint32, crypto.NumNodes)
+	shareStatusMatrix := make([][crypto.NumNodes]bool, crypto.NumNodes)
+	for i := range shares {
+		shares[i] = make([]uint32, sigSize)
+	}
+	qualified := 0
+	for i, dealer := range delegates {
+		secret, ok := secretOf(dealer)
+		if !ok {
+			continue
+		}
+		shares[i] = secret
+		for j := 0; j < crypto.NumNodes; j++ {
+			shareStatusMatrix[j][i] = true
+		}
+		qualified++
+	}
+	if qualified == 0 {
+		return nil, nil, errors.New("no qualified dealer of the DKG secrets")
+	}
+	_, dkgPubKey, dkgPriKey, err := crypto.DKG.KeyPairGeneration(shares, shareStatusMatrix)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to generate DKG key pair")
+	}
+	return dkgPubKey, dkgPriKey, nil
+}
+
+// commitSecretBlock takes the secret share dealt to the current node from the committed secret block, unless any
+// delegate complains against the dealer, which is then excluded from the qualified dealers of the epoch
+func (ctx *rollDPoSCtx) commitSecretBlock(blk *blockchain.Block) {
+	if blk.SecretWitness == nil && len(blk.SecretProposals) == 0 {
+		return
+	}
+	dealer := blk.ProducerAddress()
+	if complaints := verifySecretBlock(blk, ctx.epoch.delegates); len(complaints) > 0 {
+		if ctx.epoch.complaints == nil {
+			ctx.epoch.complaints = make(map[string][]*dkgComplaint)
+		}
+		ctx.epoch.complaints[dealer] = complaints
+		delete(ctx.epoch.committedSecrets, dealer)
+		for _, complaint := range complaints {
+			logger.Warn().
+				Err(complaint.reason).
+				Str("dealer", dealer).
+				Str("complainer", complaint.complainer).
+				Msg("the dealer of the DKG secrets is disqualified")
+		}
+		return
+	}
+	if ctx.epoch.committedSecrets == nil {
+		ctx.epoch.committedSecrets = make(map[string][]uint32)
+	}
+	for _, sp := range blk.SecretProposals {
+		if sp.DstAddr() == ctx.addr.RawAddress {
+			ctx.epoch.committedSecrets[dealer] = sp.Secret()
+			break
+		}
+	}
+}
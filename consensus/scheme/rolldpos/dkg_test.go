@@ -0,0 +1,143 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+// newTestSecretBlock deals the secrets of the dealer to the delegates, and returns the secret block and the shares
+func newTestSecretBlock(t *testing.T, dealer *iotxaddress.Address, delegates []string) (*blockchain.Block, [][]uint32) {
+	require := require.New(t)
+	_, secrets, witness, err := crypto.DKG.Init(crypto.DKG.SkGeneration(), dkgIDs(delegates))
+	require.NoError(err)
+	secretProposals := make([]*action.SecretProposal, 0, len(delegates))
+	for i, delegate := range delegates {
+		sp, err := action.NewSecretProposal(uint64(i+1), dealer.RawAddress, delegate, secrets[i])
+		require.NoError(err)
+		secretProposals = append(secretProposals, sp)
+	}
+	secretWitness, err := action.NewSecretWitness(uint64(len(delegates)+1), dealer.RawAddress, witness)
+	require.NoError(err)
+	blk := blockchain.NewSecretBlock(
+		config.Default.Chain.ID,
+		1,
+		hash.ZeroHash32B,
+		testutil.TimestampNow(),
+		secretProposals,
+		secretWitness,
+	)
+	require.NoError(blk.SignBlock(dealer))
+	return blk, secrets[:len(delegates)]
+}
+
+func TestVerifySecretBlock(t *testing.T) {
+	require := require.New(t)
+
+	addrs := make([]*iotxaddress.Address, 15)
+	delegates := make([]string, len(addrs))
+	for i := range addrs {
+		addrs[i] = newTestAddr()
+		delegates[i] = addrs[i].RawAddress
+	}
+	blk, _ := newTestSecretBlock(t, addrs[0], delegates)
+	require.Equal(0, len(verifySecretBlock(blk, delegates)))
+
+	// The share to delegates[2] is falsified, and the one to delegates[3] is missing
+	falsified, err := action.NewSecretProposal(3, delegates[0], delegates[2], []uint32{1, 2, 3, 4, 5})
+	require.NoError(err)
+	blk.SecretProposals[2] = falsified
+	blk.SecretProposals = append(blk.SecretProposals[:3], blk.SecretProposals[4:]...)
+	complaints := verifySecretBlock(blk, delegates)
+	require.Equal(2, len(complaints))
+	require.Equal(delegates[0], complaints[0].dealer)
+	require.Equal(delegates[2], complaints[0].complainer)
+	require.Equal(errInvalidShare, errors.Cause(complaints[0].reason))
+	require.Equal(delegates[3], complaints[1].complainer)
+	require.Equal(errMissingShare, complaints[1].reason)
+}
+
+func TestDKGCommittee(t *testing.T) {
+	require := require.New(t)
+
+	// 15 delegates run DKG, and the one dealing a bad share is disqualified
+	addrs := make([]*iotxaddress.Address, 15)
+	delegates := make([]string, len(addrs))
+	for i := range addrs {
+		addrs[i] = newTestAddr()
+		delegates[i] = addrs[i].RawAddress
+	}
+	blocks := make([]*blockchain.Block, len(addrs))
+	shares := make([][][]uint32, len(addrs))
+	for i, dealer := range addrs {
+		blocks[i], shares[i] = newTestSecretBlock(t, dealer, delegates)
+	}
+	falsified, err := action.NewSecretProposal(1, delegates[4], delegates[0], []uint32{1, 2, 3, 4, 5})
+	require.NoError(err)
+	blocks[4].SecretProposals[0] = falsified
+
+	ctxs := make([]*rollDPoSCtx, crypto.Degree+1)
+	for i := range ctxs {
+		ctxs[i] = &rollDPoSCtx{
+			cfg:   config.RollDPoS{NumDelegates: uint(len(delegates)), EnableDKG: true},
+			addr:  addrs[i],
+			epoch: epochCtx{delegates: delegates, committedSecrets: make(map[string][]uint32)},
+		}
+	}
+	_, _, err = ctxs[0].generateDKGSecrets()
+	require.NoError(err)
+	require.Equal(iotxaddress.CreateID(delegates[0]), ctxs[0].epoch.dkgAddress.ID)
+	for _, blk := range blocks {
+		ctxs[0].commitSecretBlock(blk)
+	}
+	require.Equal(len(delegates)-1, len(ctxs[0].epoch.committedSecrets))
+	require.Equal(1, len(ctxs[0].epoch.complaints[delegates[4]]))
+	for i := 1; i < len(ctxs); i++ {
+		// The other delegates settle the same complaint against the dealer
+		ctxs[i].epoch.complaints = ctxs[0].epoch.complaints
+		for j, dealer := range delegates {
+			ctxs[i].epoch.committedSecrets[dealer] = shares[j][i]
+		}
+	}
+
+	// The key shares of the delegates make up the same group key
+	seed := []byte("seed")
+	ids := make([][]uint8, 0, len(ctxs))
+	pubkeys := make([][]byte, 0, len(ctxs))
+	sigs := make([][]byte, 0, len(ctxs))
+	for i, ctx := range ctxs {
+		pk, sk, err := ctx.generateDKGKeyPair()
+		require.NoError(err)
+		_, sig, err := crypto.BLS.SignShare(sk, seed)
+		require.NoError(err)
+		require.NoError(crypto.BLS.VerifyShare(pk, seed, sig))
+		ids = append(ids, iotxaddress.CreateID(delegates[i]))
+		pubkeys = append(pubkeys, pk)
+		sigs = append(sigs, sig)
+	}
+	aggSig, err := crypto.BLS.SignAggregate(ids, sigs)
+	require.NoError(err)
+	require.NoError(crypto.BLS.VerifyAggregate(ids, pubkeys, seed, aggSig))
+
+	// Too few delegates to run DKG
+	ctx := &rollDPoSCtx{addr: addrs[0], epoch: epochCtx{delegates: delegates[:crypto.Degree]}}
+	_, _, err = ctx.generateDKGSecrets()
+	require.Equal(ErrDKGCommitteeSize, errors.Cause(err))
+	_, _, err = ctx.generateDKGKeyPair()
+	require.Equal(ErrDKGCommitteeSize, errors.Cause(err))
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/logger"
+)
+
+// evidenceCollector spots the delegates which sign conflicting commit endorses, or propose different blocks in the same
+// round, at the same height. Only the messages around the tip are kept, because the ones of older heights can't affect
+// the consensus anymore.
+type evidenceCollector struct {
+	mutex sync.Mutex
+	// endorses are the first commit endorses seen from each endorser at each height
+	endorses map[uint64]map[string]*action.SignedEndorse
+	// reported are the endorsers which have been reported at each height
+	reported map[uint64]map[string]bool
+	// proposals are the first proposals seen from each proposer in each round at each height
+	proposals map[uint64]map[proposalKey]*action.SignedProposal
+	// reportedProposals are the proposers which have been reported in each round at each height
+	reportedProposals map[uint64]map[proposalKey]bool
+}
+
+type proposalKey struct {
+	round    uint32
+	proposer string
+}
+
+func newEvidenceCollector() *evidenceCollector {
+	return &evidenceCollector{
+		endorses:          make(map[uint64]map[string]*action.SignedEndorse),
+		reported:          make(map[uint64]map[string]bool),
+		proposals:         make(map[uint64]map[proposalKey]*action.SignedProposal),
+		reportedProposals: make(map[uint64]map[proposalKey]bool),
+	}
+}
+
+// collect records the commit endorse, and returns the one seen before which it conflicts with. The conflict of an
+// endorser is only returned once per height.
+func (c *evidenceCollector) collect(en *action.SignedEndorse, tipHeight uint64) *action.SignedEndorse {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.prune(tipHeight)
+	if !en.Commit || en.Height < tipHeight || en.Height > tipHeight+1 {
+		return nil
+	}
+	if _, ok := c.endorses[en.Height]; !ok {
+		c.endorses[en.Height] = make(map[string]*action.SignedEndorse)
+		c.reported[en.Height] = make(map[string]bool)
+	}
+	seen, ok := c.endorses[en.Height][en.Endorser]
+	if !ok {
+		c.endorses[en.Height][en.Endorser] = en
+		return nil
+	}
+	if c.reported[en.Height][en.Endorser] || !seen.ConflictsWith(en) {
+		return nil
+	}
+	c.reported[en.Height][en.Endorser] = true
+	return seen
+}
+
+// collectProposal records the proposal, and returns the one seen before which it conflicts with. The conflict of a
+// proposer is only returned once per round.
+func (c *evidenceCollector) collectProposal(p *action.SignedProposal, tipHeight uint64) *action.SignedProposal {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.prune(tipHeight)
+	if p.Height < tipHeight || p.Height > tipHeight+1 {
+		return nil
+	}
+	if _, ok := c.proposals[p.Height]; !ok {
+		c.proposals[p.Height] = make(map[proposalKey]*action.SignedProposal)
+		c.reportedProposals[p.Height] = make(map[proposalKey]bool)
+	}
+	key := proposalKey{round: p.Round, proposer: p.Proposer}
+	seen, ok := c.proposals[p.Height][key]
+	if !ok {
+		c.proposals[p.Height][key] = p
+		return nil
+	}
+	if c.reportedProposals[p.Height][key] || !seen.ConflictsWith(p) {
+		return nil
+	}
+	c.reportedProposals[p.Height][key] = true
+	return seen
+}
+
+// prune forgets the endorses and the proposals of the heights lower than the tip
+func (c *evidenceCollector) prune(tipHeight uint64) {
+	for height := range c.endorses {
+		if height < tipHeight {
+			delete(c.endorses, height)
+			delete(c.reported, height)
+		}
+	}
+	for height := range c.proposals {
+		if height < tipHeight {
+			delete(c.proposals, height)
+			delete(c.reportedProposals, height)
+		}
+	}
+}
+
+// collectEvidence reports the endorser if the commit endorse conflicts with another one it has signed
+func (r *RollDPoS) collectEvidence(en *endorse) {
+	signed := en.toSignedEndorse()
+	seen := r.evidence.collect(signed, r.ctx.chain.TipHeight())
+	if seen == nil {
+		return
+	}
+	if err := r.ctx.reportDoubleSign(seen, signed); err != nil {
+		logger.Error().
+			Err(err).
+			Str("offender", en.endorser).
+			Uint64("height", en.height).
+			Msg("error when reporting the double sign")
+	}
+}
+
+// collectProposalEvidence reports the proposer if it has proposed another block in the same round
+func (r *RollDPoS) collectProposalEvidence(evt *proposeBlkEvt) {
+	signed := evt.toSignedProposal()
+	seen := r.evidence.collectProposal(signed, r.ctx.chain.TipHeight())
+	if seen == nil {
+		return
+	}
+	if err := r.ctx.reportDoubleProposal(seen, signed); err != nil {
+		logger.Error().
+			Err(err).
+			Str("offender", signed.Proposer).
+			Uint64("height", signed.Height).
+			Msg("error when reporting the double proposal")
+	}
+}
+
+// reportDoubleSign creates the evidence of the conflicting endorses, and gossips it like the other actions
+func (ctx *rollDPoSCtx) reportDoubleSign(endorse *action.SignedEndorse, conflictingEndorse *action.SignedEndorse) error {
+	logger.Warn().
+		Str("offender", endorse.Endorser).
+		Uint64("height", endorse.Height).
+		Msg("delegate signed conflicting commit endorses")
+	nonce, err := ctx.actPool.GetPendingNonce(ctx.addr.RawAddress)
+	if err != nil {
+		return errors.Wrap(err, "error when getting the pending nonce of the reporter")
+	}
+	evidence, err := action.NewDoubleSignEvidence(
+		ctx.addr.RawAddress,
+		nonce,
+		endorse,
+		conflictingEndorse,
+		action.DoubleSignEvidenceIntrinsicGas,
+		big.NewInt(0),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error when creating the double sign evidence")
+	}
+	return ctx.report(evidence)
+}
+
+// reportDoubleProposal creates the evidence of the conflicting proposals, and gossips it like the other actions
+func (ctx *rollDPoSCtx) reportDoubleProposal(proposal *action.SignedProposal, conflictingProposal *action.SignedProposal) error {
+	logger.Warn().
+		Str("offender", proposal.Proposer).
+		Uint64("height", proposal.Height).
+		Uint32("round", proposal.Round).
+		Msg("delegate proposed conflicting blocks")
+	nonce, err := ctx.actPool.GetPendingNonce(ctx.addr.RawAddress)
+	if err != nil {
+		return errors.Wrap(err, "error when getting the pending nonce of the reporter")
+	}
+	evidence, err := action.NewDoubleProposeEvidence(
+		ctx.addr.RawAddress,
+		nonce,
+		proposal,
+		conflictingProposal,
+		action.DoubleSignEvidenceIntrinsicGas,
+		big.NewInt(0),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error when creating the double proposal evidence")
+	}
+	return ctx.report(evidence)
+}
+
+func (ctx *rollDPoSCtx) report(evidence *action.DoubleSignEvidence) error {
+	if err := action.Sign(evidence, ctx.addr.PrivateKey); err != nil {
+		return errors.Wrap(err, "error when signing the double sign evidence")
+	}
+	if err := ctx.actPool.Add(evidence); err != nil {
+		return errors.Wrap(err, "error when adding the double sign evidence into the action pool")
+	}
+	if err := ctx.p2p.Broadcast(ctx.chain.ChainID(), evidence.ConvertToActionPb()); err != nil {
+		return errors.Wrap(err, "error when broadcasting the double sign evidence")
+	}
+	return nil
+}
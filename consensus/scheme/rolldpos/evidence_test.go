@@ -0,0 +1,179 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"testing"
+
+	"github.com/facebookgo/clock"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/proto"
+	"github.com/iotexproject/iotex-core/test/mock/mock_actpool"
+	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
+	"github.com/iotexproject/iotex-core/test/mock/mock_network"
+)
+
+func TestEvidenceCollector(t *testing.T) {
+	require := require.New(t)
+
+	signed := func(topic bool, blkHash hash.Hash32B, decision bool, height uint64, endorser *iotxaddress.Address) *action.SignedEndorse {
+		eEvt, err := newEndorseEvt(topic, blkHash, decision, height, endorser, clock.New())
+		require.NoError(err)
+		return eEvt.endorse.toSignedEndorse()
+	}
+
+	c := newEvidenceCollector()
+	require.Nil(c.collect(signed(endorseCommit, hash.Hash32B{1}, true, 5, testAddrs[0]), 4))
+	require.Nil(c.collect(signed(endorseCommit, hash.Hash32B{1}, true, 5, testAddrs[0]), 4))
+	require.Nil(c.collect(signed(endorseCommit, hash.Hash32B{2}, false, 5, testAddrs[0]), 4))
+	// Proposal endorses are not binding
+	require.Nil(c.collect(signed(endorseProposal, hash.Hash32B{2}, true, 5, testAddrs[0]), 4))
+	// Another endorser
+	require.Nil(c.collect(signed(endorseCommit, hash.Hash32B{2}, true, 5, testAddrs[1]), 4))
+	seen := c.collect(signed(endorseCommit, hash.Hash32B{2}, true, 5, testAddrs[0]), 4)
+	require.NotNil(seen)
+	require.Equal(hash.Hash32B{1}, seen.BlkHash)
+	// The conflict is only reported once
+	require.Nil(c.collect(signed(endorseCommit, hash.Hash32B{1}, false, 5, testAddrs[0]), 4))
+	// The endorses far from the tip are not kept
+	require.Nil(c.collect(signed(endorseCommit, hash.Hash32B{1}, true, 7, testAddrs[2]), 5))
+	require.Nil(c.collect(signed(endorseCommit, hash.Hash32B{2}, true, 7, testAddrs[2]), 5))
+	require.Nil(c.collect(signed(endorseCommit, hash.Hash32B{1}, true, 4, testAddrs[2]), 5))
+	require.Nil(c.collect(signed(endorseCommit, hash.Hash32B{2}, true, 4, testAddrs[2]), 5))
+	require.Nil(c.collect(signed(endorseCommit, hash.Hash32B{1}, true, 6, testAddrs[2]), 6))
+	require.Equal(1, len(c.endorses))
+}
+
+func TestEvidenceCollectorProposals(t *testing.T) {
+	require := require.New(t)
+
+	signed := func(blkHash hash.Hash32B, round uint32, height uint64, proposer *iotxaddress.Address) *action.SignedProposal {
+		return &action.SignedProposal{Height: height, Round: round, BlkHash: blkHash, Proposer: proposer.RawAddress}
+	}
+
+	c := newEvidenceCollector()
+	require.Nil(c.collectProposal(signed(hash.Hash32B{1}, 0, 5, testAddrs[0]), 4))
+	require.Nil(c.collectProposal(signed(hash.Hash32B{1}, 0, 5, testAddrs[0]), 4))
+	// Another round or another proposer
+	require.Nil(c.collectProposal(signed(hash.Hash32B{2}, 1, 5, testAddrs[0]), 4))
+	require.Nil(c.collectProposal(signed(hash.Hash32B{2}, 0, 5, testAddrs[1]), 4))
+	seen := c.collectProposal(signed(hash.Hash32B{2}, 0, 5, testAddrs[0]), 4)
+	require.NotNil(seen)
+	require.Equal(hash.Hash32B{1}, seen.BlkHash)
+	// The conflict is only reported once
+	require.Nil(c.collectProposal(signed(hash.Hash32B{3}, 0, 5, testAddrs[0]), 4))
+	// The proposals far from the tip are not kept
+	require.Nil(c.collectProposal(signed(hash.Hash32B{1}, 0, 7, testAddrs[2]), 5))
+	require.Nil(c.collectProposal(signed(hash.Hash32B{2}, 0, 7, testAddrs[2]), 5))
+	require.Nil(c.collectProposal(signed(hash.Hash32B{1}, 0, 6, testAddrs[2]), 6))
+	require.Equal(1, len(c.proposals))
+}
+
+func TestRollDPoSReportDoubleSign(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var evidence *action.DoubleSignEvidence
+	ctx := makeTestRollDPoSCtx(
+		testAddrs[0],
+		ctrl,
+		config.RollDPoS{},
+		func(chain *mock_blockchain.MockBlockchain) {
+			chain.EXPECT().TipHeight().Return(uint64(4)).AnyTimes()
+			chain.EXPECT().ChainID().Return(config.Default.Chain.ID).AnyTimes()
+		},
+		func(actPool *mock_actpool.MockActPool) {
+			actPool.EXPECT().GetPendingNonce(testAddrs[0].RawAddress).Return(uint64(3), nil).Times(1)
+			actPool.EXPECT().Add(gomock.Any()).Do(func(act action.Action) {
+				var ok bool
+				evidence, ok = act.(*action.DoubleSignEvidence)
+				require.True(ok)
+			}).Return(nil).Times(1)
+		},
+		func(p2p *mock_network.MockOverlay) {
+			p2p.EXPECT().Broadcast(config.Default.Chain.ID, gomock.Any()).Do(func(_ uint32, msg proto.Message) {
+				actPb, ok := msg.(*iproto.ActionPb)
+				require.True(ok)
+				require.NotNil(actPb.GetDoubleSignEvidence())
+			}).Return(nil).Times(1)
+		},
+		clock.New(),
+	)
+	r := &RollDPoS{ctx: ctx, evidence: newEvidenceCollector()}
+
+	for _, blkHash := range []hash.Hash32B{{1}, {1}, {2}, {3}} {
+		eEvt, err := newEndorseEvt(endorseCommit, blkHash, true, 5, testAddrs[1], ctx.clock)
+		require.NoError(err)
+		r.collectEvidence(eEvt.endorse)
+	}
+	require.NotNil(evidence)
+	require.Equal(testAddrs[0].RawAddress, evidence.Reporter())
+	require.Equal(testAddrs[1].RawAddress, evidence.Offender())
+	require.Equal(uint64(3), evidence.Nonce())
+	require.NoError(action.Verify(evidence))
+	require.NoError(evidence.Verify())
+}
+
+func TestRollDPoSReportDoubleProposal(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var evidence *action.DoubleSignEvidence
+	ctx := makeTestRollDPoSCtx(
+		testAddrs[0],
+		ctrl,
+		config.RollDPoS{},
+		func(chain *mock_blockchain.MockBlockchain) {
+			chain.EXPECT().TipHeight().Return(uint64(4)).AnyTimes()
+			chain.EXPECT().ChainID().Return(config.Default.Chain.ID).AnyTimes()
+		},
+		func(actPool *mock_actpool.MockActPool) {
+			actPool.EXPECT().GetPendingNonce(testAddrs[0].RawAddress).Return(uint64(3), nil).Times(1)
+			actPool.EXPECT().Add(gomock.Any()).Do(func(act action.Action) {
+				var ok bool
+				evidence, ok = act.(*action.DoubleSignEvidence)
+				require.True(ok)
+			}).Return(nil).Times(1)
+		},
+		func(p2p *mock_network.MockOverlay) {
+			p2p.EXPECT().Broadcast(config.Default.Chain.ID, gomock.Any()).Return(nil).Times(1)
+		},
+		clock.New(),
+	)
+	r := &RollDPoS{ctx: ctx, evidence: newEvidenceCollector()}
+
+	for _, ts := range []uint64{1, 1, 2, 3} {
+		blk := blockchain.NewBlock(config.Default.Chain.ID, 5, hash.ZeroHash32B, ts, nil, nil, nil, nil)
+		evt := newProposeBlkEvt(blk, ctx.clock)
+		require.NoError(evt.Sign(testAddrs[1]))
+		require.True(evt.VerifySignature())
+		r.collectProposalEvidence(evt)
+	}
+	require.NotNil(evidence)
+	require.Equal(testAddrs[0].RawAddress, evidence.Reporter())
+	require.Equal(testAddrs[1].RawAddress, evidence.Offender())
+	require.NotNil(evidence.Proposal())
+	require.NoError(action.Verify(evidence))
+	require.NoError(evidence.Verify())
+
+	// The proposal signed by another key is rejected
+	blk := blockchain.NewBlock(config.Default.Chain.ID, 5, hash.ZeroHash32B, 1, nil, nil, nil, nil)
+	evt := newProposeBlkEvt(blk, ctx.clock)
+	require.NoError(evt.Sign(testAddrs[1]))
+	evt.proposer = testAddrs[2].RawAddress
+	require.False(evt.VerifySignature())
+}
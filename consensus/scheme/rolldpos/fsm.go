@@ -20,6 +20,7 @@ import (
 	"golang.org/x/crypto/blake2b"
 
 	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/action"
 	"github.com/iotexproject/iotex-core/crypto"
 	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/logger"
@@ -124,6 +125,13 @@ func (e *consensusEvt) timestamp() time.Time { return e.ts }
 type proposeBlkEvt struct {
 	consensusEvt
 	block *blockchain.Block
+	round uint32
+	// proposerAddr is the network address of the proposer, which the endorses are sent to for aggregation
+	proposerAddr string
+	// proposer is the delegate proposing the block in the round, which could differ from the producer of a locked block
+	proposer       string
+	proposerPubkey keypair.PublicKey
+	signature      []byte
 }
 
 func newProposeBlkEvt(block *blockchain.Block, c clock.Clock) *proposeBlkEvt {
@@ -135,8 +143,12 @@ func newProposeBlkEvt(block *blockchain.Block, c clock.Clock) *proposeBlkEvt {
 
 func (e *proposeBlkEvt) toProtoMsg() *iproto.ProposePb {
 	return &iproto.ProposePb{
-		Block:    e.block.ConvertToBlockPb(),
-		Proposer: e.block.ProducerAddress(),
+		Block:          e.block.ConvertToBlockPb(),
+		Proposer:       e.proposer,
+		Round:          e.round,
+		ProposerAddr:   e.proposerAddr,
+		ProposerPubKey: e.proposerPubkey[:],
+		Signature:      e.signature,
 	}
 }
 
@@ -145,38 +157,71 @@ func (e *proposeBlkEvt) fromProtoMsg(pMsg *iproto.ProposePb) error {
 		e.block = &blockchain.Block{}
 		e.block.ConvertFromBlockPb(pMsg.Block)
 	}
+	e.round = pMsg.GetRound()
+	e.proposerAddr = pMsg.GetProposerAddr()
+	e.proposer = pMsg.GetProposer()
+	copy(e.proposerPubkey[:], pMsg.GetProposerPubKey())
+	e.signature = pMsg.GetSignature()
+	return nil
+}
+
+// Sign signs the block, the round and the network address with the proposer's private key
+func (e *proposeBlkEvt) Sign(proposer *iotxaddress.Address) error {
+	if proposer.PrivateKey == keypair.ZeroPrivateKey {
+		return errors.New("The proposer's private key is empty")
+	}
+	e.proposer = proposer.RawAddress
+	e.proposerPubkey = proposer.PublicKey
+	hash := blake2b.Sum256(action.ProposalByteStream(e.block.Height(), e.round, e.block.HashBlock(), e.proposerAddr))
+	e.signature = crypto.EC283.Sign(proposer.PrivateKey, hash[:])
 	return nil
 }
 
+// VerifySignature verifies that the proposal is signed by the proposer
+func (e *proposeBlkEvt) VerifySignature() bool {
+	return e.block != nil && e.toSignedProposal().Verify()
+}
+
+func (e *proposeBlkEvt) toSignedProposal() *action.SignedProposal {
+	return &action.SignedProposal{
+		Height:         e.block.Height(),
+		Round:          e.round,
+		BlkHash:        e.block.HashBlock(),
+		ProposerAddr:   e.proposerAddr,
+		Proposer:       e.proposer,
+		ProposerPubkey: e.proposerPubkey,
+		Signature:      e.signature,
+	}
+}
+
 const (
 	endorseProposal = false
 	endorseCommit   = true
 )
 
 type endorse struct {
-	topic          bool
-	height         uint64
+	topic  bool
+	height uint64
+	// round is the round of the proposal endorse, while the commit endorse is bound to the height only
+	round          uint32
 	blkHash        hash.Hash32B
 	decision       bool
 	endorser       string
 	endorserPubkey keypair.PublicKey
 	signature      []byte
+	// blsSignature is the signature share with the DKG key of the endorser, which is aggregated by the proposer
+	blsSignature []byte
+	// aggregate is set if the endorse comes from an aggregate one, which carries no individual signature
+	aggregate *blockchain.AggregateEndorsement
 }
 
 // ByteStream returns a raw byte stream
 func (en *endorse) ByteStream() []byte {
-	stream := make([]byte, 8)
-	enc.MachineEndian.PutUint64(stream, en.height)
-	if en.topic {
-		stream = append(stream, 1)
-	} else {
-		stream = append(stream, 0)
-	}
-	stream = append(stream, en.blkHash[:]...)
-	if en.decision {
-		stream = append(stream, 1)
-	} else {
-		stream = append(stream, 0)
+	stream := action.EndorseByteStream(en.height, en.topic, en.blkHash, en.decision)
+	if en.topic == endorseProposal {
+		round := make([]byte, 4)
+		enc.MachineEndian.PutUint32(round, en.round)
+		stream = append(stream, round...)
 	}
 	return stream
 }
@@ -220,6 +265,18 @@ func (en *endorse) toProtoMsg() *iproto.EndorsePb {
 	case endorseCommit:
 		topic = iproto.EndorsePb_COMMIT
 	}
+	if en.aggregate != nil {
+		return &iproto.EndorsePb{
+			Height:             en.height,
+			BlockHash:          en.blkHash[:],
+			Topic:              topic,
+			Endorser:           en.endorser,
+			Decision:           en.decision,
+			Bitmap:             en.aggregate.Bitmap,
+			AggregateSignature: en.aggregate.Signature,
+			Round:              en.round,
+		}
+	}
 	return &iproto.EndorsePb{
 		Height:         en.height,
 		BlockHash:      en.blkHash[:],
@@ -228,6 +285,32 @@ func (en *endorse) toProtoMsg() *iproto.EndorsePb {
 		EndorserPubKey: en.endorserPubkey[:],
 		Decision:       en.decision,
 		Signature:      en.signature[:],
+		BlsSignature:   en.blsSignature,
+		Round:          en.round,
+	}
+}
+
+// toEndorsement converts the endorse to the endorsement embedded into the block
+func (en *endorse) toEndorsement() *blockchain.Endorsement {
+	return &blockchain.Endorsement{
+		Endorser:       en.endorser,
+		EndorserPubkey: en.endorserPubkey,
+		BlkHash:        en.blkHash,
+		Decision:       en.decision,
+		Signature:      en.signature,
+	}
+}
+
+// toSignedEndorse converts the endorse to the one carried by the double sign evidence
+func (en *endorse) toSignedEndorse() *action.SignedEndorse {
+	return &action.SignedEndorse{
+		Height:         en.height,
+		Commit:         en.topic == endorseCommit,
+		BlkHash:        en.blkHash,
+		Decision:       en.decision,
+		Endorser:       en.endorser,
+		EndorserPubkey: en.endorserPubkey,
+		Signature:      en.signature,
 	}
 }
 
@@ -239,6 +322,14 @@ func (en *endorse) fromProtoMsg(endorsePb *iproto.EndorsePb) error {
 	case iproto.EndorsePb_COMMIT:
 		en.topic = endorseCommit
 	}
+	en.height = endorsePb.Height
+	en.round = endorsePb.GetRound()
+	en.endorser = endorsePb.Endorser
+	en.decision = endorsePb.Decision
+	if len(endorsePb.Bitmap) > 0 {
+		en.aggregate = &blockchain.AggregateEndorsement{}
+		return en.aggregate.ConvertFromEndorsePb(endorsePb)
+	}
 	pubKey, err := keypair.BytesToPublicKey(endorsePb.EndorserPubKey)
 	if err != nil {
 		logger.Error().
@@ -248,10 +339,11 @@ func (en *endorse) fromProtoMsg(endorsePb *iproto.EndorsePb) error {
 		return err
 	}
 	en.endorserPubkey = pubKey
-	en.height = endorsePb.Height
-	en.endorser = endorsePb.Endorser
-	en.decision = endorsePb.Decision
+	en.signature = make([]byte, len(endorsePb.Signature))
 	copy(en.signature, endorsePb.Signature)
+	if len(endorsePb.BlsSignature) > 0 {
+		en.blsSignature = endorsePb.BlsSignature
+	}
 	return nil
 }
 
@@ -322,6 +414,10 @@ type cFSM struct {
 	close chan interface{}
 	ctx   *rollDPoSCtx
 	wg    sync.WaitGroup
+	// journal records the consumed events if it's not nil
+	journal *journal
+	// replaying indicates that the events are fed from a journal instead of the queue
+	replaying bool
 }
 
 func newConsensusFSM(ctx *rollDPoSCtx) (*cFSM, error) {
@@ -351,6 +447,8 @@ func newConsensusFSM(ctx *rollDPoSCtx) (*cFSM, error) {
 			eProposeBlockTimeout,
 			cm.handleProposeBlockTimeout,
 			[]fsm.State{
+				sInitPropose,           // move to the next round, in which the node is the proposer
+				sAcceptPropose,         // move to the next round
 				sAcceptProposalEndorse, // no valid block, jump to next step
 			}).
 		AddTransition(
@@ -404,37 +502,7 @@ func (m *cFSM) Start(c context.Context) error {
 			case <-m.close:
 				running = false
 			case evt := <-m.evtq:
-				timeoutEvt, ok := evt.(*timeoutEvt)
-				if ok && timeoutEvt.timestamp().Before(m.ctx.round.timestamp) {
-					logger.Debug().Msg("timeoutEvt is stale")
-					continue
-				}
-				src := m.fsm.CurrentState()
-				if err := m.fsm.Handle(evt); err != nil {
-					if errors.Cause(err) == fsm.ErrTransitionNotFound {
-						if m.ctx.clock.Now().Sub(evt.timestamp()) <= m.ctx.cfg.UnmatchedEventTTL {
-							m.produce(evt, m.ctx.cfg.UnmatchedEventInterval)
-							logger.Debug().
-								Str("src", string(src)).
-								Str("evt", string(evt.Type())).
-								Err(err).
-								Msg("consensusEvt state transition could find the match")
-						}
-					} else {
-						logger.Error().
-							Str("src", string(src)).
-							Str("evt", string(evt.Type())).
-							Err(err).
-							Msg("consensusEvt state transition fails")
-					}
-				} else {
-					dst := m.fsm.CurrentState()
-					logger.Debug().
-						Str("src", string(src)).
-						Str("dst", string(dst)).
-						Str("evt", string(evt.Type())).
-						Msg("consensusEvt state transition happens")
-				}
+				m.handle(evt)
 			}
 		}
 		m.wg.Done()
@@ -442,6 +510,55 @@ func (m *cFSM) Start(c context.Context) error {
 	return nil
 }
 
+// handle handles an event consumed from the queue, and returns the record of the resulting state transition, which is
+// written into the journal if it's enabled
+func (m *cFSM) handle(evt iConsensusEvt) *journalRecord {
+	src := m.fsm.CurrentState()
+	record := newJournalRecord(evt, src, m.ctx.clock.Now())
+	defer func() {
+		if m.journal == nil {
+			return
+		}
+		if err := m.journal.write(record); err != nil {
+			logger.Error().Err(err).Msg("error when writing the consensus event into the journal")
+		}
+	}()
+
+	timeoutEvt, ok := evt.(*timeoutEvt)
+	if ok && timeoutEvt.timestamp().Before(m.ctx.round.timestamp) {
+		logger.Debug().Msg("timeoutEvt is stale")
+		record.Err = errStaleEvt.Error()
+		return record
+	}
+	if err := m.fsm.Handle(evt); err != nil {
+		record.Err = err.Error()
+		if errors.Cause(err) == fsm.ErrTransitionNotFound {
+			if m.ctx.clock.Now().Sub(evt.timestamp()) <= m.ctx.cfg.UnmatchedEventTTL {
+				m.produce(evt, m.ctx.cfg.UnmatchedEventInterval)
+				logger.Debug().
+					Str("src", string(src)).
+					Str("evt", string(evt.Type())).
+					Err(err).
+					Msg("consensusEvt state transition could find the match")
+			}
+		} else {
+			logger.Error().
+				Str("src", string(src)).
+				Str("evt", string(evt.Type())).
+				Err(err).
+				Msg("consensusEvt state transition fails")
+		}
+		return record
+	}
+	record.Dst = m.fsm.CurrentState()
+	logger.Debug().
+		Str("src", string(src)).
+		Str("dst", string(record.Dst)).
+		Str("evt", string(evt.Type())).
+		Msg("consensusEvt state transition happens")
+	return record
+}
+
 func (m *cFSM) Stop(_ context.Context) error {
 	close(m.close)
 	m.wg.Wait()
@@ -452,8 +569,12 @@ func (m *cFSM) currentState() fsm.State {
 	return m.fsm.CurrentState()
 }
 
-// produce adds an event into the queue for the consensus FSM to process
+// produce adds an event into the queue for the consensus FSM to process. Nothing is produced when replaying a journal,
+// in which the events produced by the FSM itself have been recorded when they were consumed
 func (m *cFSM) produce(evt iConsensusEvt, delay time.Duration) {
+	if m.replaying {
+		return
+	}
 	if delay > 0 {
 		m.wg.Add(1)
 		go func() {
@@ -479,10 +600,16 @@ func (m *cFSM) handleRollDelegatesEvt(_ fsm.Event) (fsm.State, error) {
 			"error when determining the epoch ordinal number and start height offset",
 		)
 	}
-	// Update CryptoSort seed
-	// TODO: Consider persist the most recent seed
-	if m.ctx.epoch.seed, err = m.ctx.updateSeed(); err != nil {
-		logger.Error().Err(err).Msg("Failed to generate new seed from last epoch")
+	// Update CryptoSort seed from the chain, unless the node has entered the epoch before restarting, in which case the
+	// seed of the epoch is restored already
+	if m.ctx.epoch.num != epochNum || m.ctx.epoch.seed == nil {
+		seed, err := m.ctx.updateSeed()
+		if err != nil {
+			// Even if error happens, we still need to schedule next check of delegate to tolerate transit error
+			m.produce(m.newCEvt(eRollDelegates), m.ctx.cfg.DelegateInterval)
+			return sInvalid, errors.Wrap(err, "error when getting the seed of the epoch")
+		}
+		m.ctx.epoch.seed = seed
 	}
 	delegates, err := m.ctx.rollingDelegates(epochNum)
 	if err != nil {
@@ -502,6 +629,10 @@ func (m *cFSM) handleRollDelegatesEvt(_ fsm.Event) (fsm.State, error) {
 		m.ctx.epoch.numSubEpochs = m.ctx.getNumSubEpochs()
 		m.ctx.epoch.subEpochNum = uint64(0)
 		m.ctx.epoch.committedSecrets = make(map[string][]uint32)
+		m.ctx.epoch.complaints = make(map[string][]*dkgComplaint)
+		if err := m.ctx.saveEpoch(); err != nil {
+			logger.Error().Err(err).Uint64("epoch", epochNum).Msg("error when persisting the epoch")
+		}
 
 		// Trigger the event to generate DKG
 		m.produce(m.newCEvt(eGenerateDKG), 0)
@@ -521,10 +652,6 @@ func (m *cFSM) handleRollDelegatesEvt(_ fsm.Event) (fsm.State, error) {
 
 func (m *cFSM) handleGenerateDKGEvt(_ fsm.Event) (fsm.State, error) {
 	if m.ctx.shouldHandleDKG() {
-		// TODO: numDelegates will be configurable later on
-		if len(m.ctx.epoch.delegates) != 21 {
-			logger.Panic().Msg("Number of delegates is incorrect for DKG generation")
-		}
 		secrets, witness, err := m.ctx.generateDKGSecrets()
 		if err != nil {
 			return sInvalid, err
@@ -555,37 +682,112 @@ func (m *cFSM) handleStartRoundEvt(_ fsm.Event) (fsm.State, error) {
 			Msg("error when getting the proposer")
 		return sInvalid, err
 	}
-	m.ctx.round = roundCtx{
+	round := roundCtx{
 		height:           height,
 		timestamp:        m.ctx.clock.Now(),
-		proposalEndorses: make(map[hash.Hash32B]map[string]bool),
+		proposalEndorses: make(map[roundHash]map[string]bool),
 		commitEndorses:   make(map[hash.Hash32B]map[string]bool),
+		certificates:     make(map[hash.Hash32B][]*blockchain.Endorsement),
 		proposer:         proposer,
 	}
+	if height == m.ctx.round.height && m.ctx.round.proposalEndorses != nil {
+		// The consensus wasn't reached at the height, so the endorses collected and the lock are kept when starting it over
+		round.proposalEndorses = m.ctx.round.proposalEndorses
+		round.commitEndorses = m.ctx.round.commitEndorses
+		round.certificates = m.ctx.round.certificates
+		round.aggregates = m.ctx.round.aggregates
+		round.locked = m.ctx.round.locked
+	}
+	m.ctx.round = round
+
+	return m.enterRound()
+}
+
+// enterRound proposes the block if the current node is the proposer of the round, otherwise waits for the proposal
+func (m *cFSM) enterRound() (fsm.State, error) {
+	proposer := m.ctx.round.proposer
 	if proposer == m.ctx.addr.RawAddress {
 		logger.Info().
 			Str("proposer", proposer).
-			Uint64("height", height).
+			Uint64("height", m.ctx.round.height).
+			Uint32("round", m.ctx.round.number).
 			Msg("current node is the proposer")
+		// The proposer doesn't block the round for too long, as it mints a smaller block once the production deadline
+		// is exceeded
 		m.produce(m.newCEvt(eInitBlock), 0)
-		// TODO: we may need timeout event for block producer too
 		return sInitPropose, nil
 	}
 	logger.Info().
 		Str("proposer", proposer).
-		Uint64("height", height).
+		Uint64("height", m.ctx.round.height).
+		Uint32("round", m.ctx.round.number).
 		Msg("current node is not the proposer")
+	if pending := m.ctx.round.pendingProposal; pending != nil && pending.round == m.ctx.round.number {
+		// Replay the proposal of the round received before the node times out the previous round
+		m.ctx.round.pendingProposal = nil
+		m.produce(pending, 0)
+	}
 	// Setup timeout for waiting for proposed block
 	m.produce(m.newTimeoutEvt(eProposeBlockTimeout, m.ctx.round.height), m.ctx.cfg.AcceptProposeTTL)
 	return sAcceptPropose, nil
 }
 
-func (m *cFSM) handleInitBlockEvt(evt fsm.Event) (fsm.State, error) {
-	blk, err := m.ctx.mintBlock()
+// moveToRound moves on to the given round at the same height, in which the next delegate in the rotation proposes. The
+// timeouts of the earlier rounds become stale.
+func (m *cFSM) moveToRound(number uint32) error {
+	proposer, err := m.ctx.calcProposer(m.ctx.round.height, number, m.ctx.epoch.delegates)
 	if err != nil {
-		return sInvalid, errors.Wrap(err, "error when minting a block")
+		return errors.Wrap(err, "error when calculating the proposer")
+	}
+	m.ctx.round.number = number
+	m.ctx.round.proposer = proposer
+	m.ctx.round.timestamp = m.ctx.clock.Now()
+	m.ctx.round.block = nil
+	m.ctx.round.proposerAddr = ""
+	m.ctx.round.shares = nil
+	return nil
+}
+
+func (m *cFSM) handleInitBlockEvt(evt fsm.Event) (fsm.State, error) {
+	// The node locked on a block of an earlier round proposes it again, because it can't endorse any other one
+	blk := m.ctx.round.locked
+	if blk == nil {
+		var err error
+		if blk, err = m.ctx.mintBlock(); err != nil {
+			return sInvalid, errors.Wrap(err, "error when minting a block")
+		}
+	}
+	// Persist the proposal before broadcasting it, so that the node doesn't propose another block in the round even
+	// after restarting
+	record := &roundRecord{
+		Height:   m.ctx.round.height,
+		Round:    m.ctx.round.number,
+		Proposer: m.ctx.addr.RawAddress,
+		BlkHash:  blk.HashBlock(),
+		Decision: true,
+	}
+	if err := m.ctx.dao.recordRound(record); err != nil {
+		if errors.Cause(err) != ErrConflictingEndorse {
+			return sInvalid, errors.Wrap(err, "error when recording the proposal to broadcast")
+		}
+		logger.Warn().
+			Err(err).
+			Uint64("height", m.ctx.round.height).
+			Uint32("round", m.ctx.round.number).
+			Msg("skip proposing a block conflicting with the one proposed before")
+		m.produce(m.newTimeoutEvt(eProposeBlockTimeout, m.ctx.round.height), m.ctx.cfg.AcceptProposeTTL)
+		return sAcceptPropose, nil
 	}
 	proposeBlkEvt := m.newProposeBlkEvt(blk)
+	if m.ctx.cfg.AggregateEndorses {
+		// Tell the delegates where to send the endorses to aggregate
+		if self := m.ctx.p2p.Self(); self != nil {
+			proposeBlkEvt.proposerAddr = self.String()
+		}
+	}
+	if err := proposeBlkEvt.Sign(m.ctx.addr); err != nil {
+		return sInvalid, errors.Wrap(err, "error when signing the proposal")
+	}
 	proposeBlkEvtProto := proposeBlkEvt.toProtoMsg()
 	// Notify itself
 	m.produce(proposeBlkEvt, 0)
@@ -598,24 +800,65 @@ func (m *cFSM) handleInitBlockEvt(evt fsm.Event) (fsm.State, error) {
 	return sAcceptPropose, nil
 }
 
-func (m *cFSM) validateProposeBlock(blk *blockchain.Block, expectedProposer string) bool {
+// isProposerOf checks if the proposal is signed by the proposer of its round
+func (m *cFSM) isProposerOf(evt *proposeBlkEvt) bool {
+	isProposer, err := m.ctx.isRoundProposer(evt.proposer, evt.block.Height(), evt.round)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Uint64("height", evt.block.Height()).
+			Uint32("round", evt.round).
+			Msg("error when calculating the proposer")
+		return false
+	}
+	return isProposer
+}
+
+// isLockable checks if the block could have been locked on before the given round, i.e., the node is locked on it, or
+// it has got a quorum of yes proposal endorses in an earlier round. Only such a block could be proposed again by a
+// delegate other than its producer.
+func (m *cFSM) isLockable(blkHash hash.Hash32B, round uint32) bool {
+	if locked := m.ctx.round.locked; locked != nil && locked.HashBlock() == blkHash {
+		return true
+	}
+	for key, endorses := range m.ctx.round.proposalEndorses {
+		if key.blkHash != blkHash || key.round >= round {
+			continue
+		}
+		if yes, no := m.ctx.calcQuorum(endorses); yes && !no {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *cFSM) validateProposeBlock(evt *proposeBlkEvt) bool {
+	blk, round := evt.block, evt.round
 	blkHash := blk.HashBlock()
 	errorLog := logger.Error().
 		Uint64("expectedHeight", m.ctx.round.height).
-		Str("expectedProposer", expectedProposer).
+		Uint32("round", round).
 		Str("hash", hex.EncodeToString(blkHash[:]))
 	if blk.Height() != m.ctx.round.height {
 		errorLog.Uint64("blockHeight", blk.Height()).
 			Msg("error when validating the block height")
 		return false
 	}
-	producer := blk.ProducerAddress()
-
-	if producer == "" || producer != expectedProposer {
-		errorLog.Str("proposer", producer).
+	if !m.isProposerOf(evt) {
+		errorLog.Str("proposer", evt.proposer).
 			Msg("error when validating the block proposer")
 		return false
 	}
+	producer := blk.ProducerAddress()
+	if producer == "" {
+		errorLog.Msg("error when validating the block producer")
+		return false
+	}
+	if producer != evt.proposer && !m.isLockable(blkHash, round) {
+		errorLog.Str("producer", producer).
+			Msg("error when validating the block proposed again, which isn't locked on in an earlier round")
+		return false
+	}
 	if !blk.VerifySignature() {
 		errorLog.Msg("error when validating the block signature")
 		return false
@@ -624,6 +867,10 @@ func (m *cFSM) validateProposeBlock(blk *blockchain.Block, expectedProposer stri
 		// If the block is self proposed, skip validation
 		return true
 	}
+	if err := m.ctx.validateBlockSeed(blk); err != nil {
+		errorLog.Err(err).Msg("error when validating the seed of the epoch")
+		return false
+	}
 	containCoinbase := true
 	if m.ctx.cfg.EnableDKG {
 		if m.ctx.shouldHandleDKG() {
@@ -653,32 +900,48 @@ func (m *cFSM) handleProposeBlockEvt(evt fsm.Event) (fsm.State, error) {
 	if evt.Type() != eProposeBlock {
 		return sInvalid, errors.Errorf("invalid event type %s", evt.Type())
 	}
-	m.ctx.round.block = nil
 	proposeBlkEvt, ok := evt.(*proposeBlkEvt)
 	if !ok {
 		return sInvalid, errors.Wrap(ErrEvtCast, "the event is not a proposeBlkEvt")
 	}
-	proposer, err := m.ctx.calcProposer(proposeBlkEvt.block.Height(), m.ctx.epoch.delegates)
-	if err != nil {
-		return sInvalid, errors.Wrap(err, "error when calculating the proposer")
+	// Only the proposal of the current round is accepted. The one of the next round is kept until the proposal of the
+	// current round times out locally, so that the next proposer can't cut the round short.
+	round := proposeBlkEvt.round
+	if round == m.ctx.round.number+1 && round < m.ctx.maxRounds() {
+		if m.isProposerOf(proposeBlkEvt) {
+			m.ctx.round.pendingProposal = proposeBlkEvt
+		}
+		return sAcceptPropose, nil
+	}
+	if round != m.ctx.round.number {
+		logger.Warn().
+			Uint64("height", m.ctx.round.height).
+			Uint32("round", m.ctx.round.number).
+			Uint32("proposalRound", round).
+			Msg("the proposal of another round is ignored")
+		return sAcceptPropose, nil
+	}
+	m.ctx.round.block = nil
+	if !m.validateProposeBlock(proposeBlkEvt) {
+		return sAcceptPropose, nil
 	}
-	if !m.validateProposeBlock(proposeBlkEvt.block, proposer) {
+	if locked := m.ctx.round.locked; locked != nil && locked.HashBlock() != proposeBlkEvt.block.HashBlock() {
+		logger.Warn().
+			Uint64("height", m.ctx.round.height).
+			Uint32("round", round).
+			Msg("the proposal is rejected, because the node is locked on another block")
 		return sAcceptPropose, nil
 	}
 	m.ctx.round.block = proposeBlkEvt.block
-	endorseEvt, err := m.newEndorseProposalEvt(m.ctx.round.block.HashBlock(), true)
+	m.ctx.round.proposerAddr = proposeBlkEvt.proposerAddr
+	endorseEvt, err := m.newEndorseProposalEvt(proposeBlkEvt.proposer, m.ctx.round.block.HashBlock(), true)
 	if err != nil {
 		return sInvalid, errors.Wrap(err, "error when generating new endorse proposal event")
 	}
-	endorseEvtProto := endorseEvt.toProtoMsg()
 	// Notify itself
 	m.produce(endorseEvt, 0)
 	// Notify other delegates
-	if err := m.ctx.p2p.Broadcast(m.ctx.chain.ChainID(), endorseEvtProto); err != nil {
-		logger.Error().
-			Err(err).
-			Msg("error when broadcasting endorseEvtProto")
-	}
+	m.sendEndorse(endorseEvt.endorse)
 
 	return m.moveToAcceptProposalEndorse()
 }
@@ -690,8 +953,15 @@ func (m *cFSM) handleProposeBlockTimeout(evt fsm.Event) (fsm.State, error) {
 	logger.Warn().
 		Str("proposer", m.ctx.round.proposer).
 		Uint64("height", m.ctx.round.height).
+		Uint32("round", m.ctx.round.number).
 		Msg("didn't receive the proposed block before timeout")
 
+	if m.ctx.round.number+1 < m.ctx.maxRounds() {
+		if err := m.moveToRound(m.ctx.round.number + 1); err != nil {
+			return sInvalid, err
+		}
+		return m.enterRound()
+	}
 	return m.moveToAcceptProposalEndorse()
 }
 
@@ -709,7 +979,32 @@ func (m *cFSM) validateEndorse(en *endorse, expectedEndorseTopic bool) bool {
 			Msg("error when validating the endorse height")
 		return false
 	}
-	// TODO verify that the endorser is one delegate, and verify signature via endorse.VerifySignature() with pub key
+	if en.topic == endorseProposal && en.round > m.ctx.round.number {
+		errorLog.Uint32("round", en.round).
+			Msg("error when validating the endorse round, which hasn't started yet")
+		return false
+	}
+	// The signature has been verified against the public key of the endorser when the endorse comes in
+	if !m.ctx.isDelegate(en.endorser) {
+		errorLog.Str("endorser", en.endorser).
+			Msg("error when validating the endorser, which is not a delegate of the epoch")
+		return false
+	}
+	return true
+}
+
+// addEndorse adds the decision of the endorse to the given ones on the same block, unless the endorser has endorsed the
+// block already
+func addEndorse(endorses map[string]bool, en *endorse) bool {
+	if _, ok := endorses[en.endorser]; ok {
+		logger.Warn().
+			Uint64("height", en.height).
+			Bool("topic", en.topic).
+			Str("endorser", en.endorser).
+			Msg("duplicate endorse is rejected")
+		return false
+	}
+	endorses[en.endorser] = en.decision
 	return true
 }
 
@@ -732,32 +1027,36 @@ func (m *cFSM) handleEndorseProposalEvt(evt fsm.Event) (fsm.State, error) {
 		return sAcceptProposalEndorse, nil
 	}
 	blkHash := endorse.blkHash
-	endorses := m.ctx.round.proposalEndorses[blkHash]
-	if endorses == nil {
-		endorses = map[string]bool{}
-		m.ctx.round.proposalEndorses[blkHash] = endorses
+	endorses := m.ctx.round.proposalEndorsesOf(endorse.round, blkHash)
+	if !addEndorse(endorses, endorse) {
+		return sAcceptProposalEndorse, nil
+	}
+	if endorse.round != m.ctx.round.number {
+		// The endorses of an earlier round are only kept as the proof of the block locked in it
+		return sAcceptProposalEndorse, nil
 	}
-	endorses[endorse.endorser] = endorse.decision
+	m.collectShare(endorse)
 	// if ether yes or no is true, block must exists and blkHash must be a valid one
-	yes, no := m.ctx.calcQuorum(m.ctx.round.proposalEndorses[blkHash])
+	yes, no := m.ctx.calcQuorum(endorses)
 	if !yes && !no {
 		// Wait for more preCommits to come
 		return sAcceptProposalEndorse, nil
 	}
+	m.aggregateEndorses(endorseProposal, blkHash, yes && !no)
 	// Reached the agreement
 	cEvt, err := m.newEndorseCommitEvt(blkHash, yes && !no)
 	if err != nil {
 		return sInvalid, errors.Wrap(err, "failed to generate endorse commit event")
 	}
-	cEvtProto := cEvt.toProtoMsg()
+	if yes && !no {
+		if blk := m.ctx.round.proposal(blkHash); blk != nil {
+			m.ctx.round.locked = blk
+		}
+	}
 	// Notify itself
 	m.produce(cEvt, 0)
 	// Notify other delegates
-	if err := m.ctx.p2p.Broadcast(m.ctx.chain.ChainID(), cEvtProto); err != nil {
-		logger.Error().
-			Err(err).
-			Msg("error when broadcasting commitEvtProto")
-	}
+	m.sendEndorse(cEvt.endorse)
 
 	return m.moveToAcceptCommitEndorse()
 }
@@ -768,12 +1067,36 @@ func (m *cFSM) handleEndorseProposalTimeout(evt fsm.Event) (fsm.State, error) {
 	}
 	logger.Warn().
 		Uint64("height", m.ctx.round.height).
-		Int("numberOfEndorses", len(m.ctx.round.proposalEndorses)).
+		Uint32("round", m.ctx.round.number).
 		Msg("didn't collect enough proposal endorses before timeout")
+	if m.ctx.cfg.EnableDummyBlock {
+		m.voteTimeout()
+	}
 
 	return m.moveToAcceptCommitEndorse()
 }
 
+// voteTimeout votes for the dummy block of the height, unless a block has been endorsed at the height. The votes of a
+// quorum of the delegates are embedded into the dummy block as the proof that the consensus wasn't reached. The vote is
+// broadcast instead of sent to the proposer, which may be the one failing the round.
+func (m *cFSM) voteTimeout() {
+	cEvt, err := m.newEndorseCommitEvt(hash.ZeroHash32B, false)
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Uint64("height", m.ctx.round.height).
+			Msg("skip voting for the dummy block")
+		return
+	}
+	m.produce(cEvt, 0)
+	if err := m.ctx.p2p.Broadcast(m.ctx.chain.ChainID(), cEvt.endorse.toProtoMsg()); err != nil {
+		logger.Error().
+			Err(err).
+			Uint64("height", m.ctx.round.height).
+			Msg("error when broadcasting the vote for the dummy block")
+	}
+}
+
 func (m *cFSM) handleEndorseCommitEvt(evt fsm.Event) (fsm.State, error) {
 	if evt.Type() != eEndorseCommit {
 		return sInvalid, errors.Errorf("invalid event type %s", evt.Type())
@@ -783,27 +1106,57 @@ func (m *cFSM) handleEndorseCommitEvt(evt fsm.Event) (fsm.State, error) {
 		return sInvalid, errors.Wrap(ErrEvtCast, "the event is not an endorseEvt")
 	}
 	endorse := endorseEvt.endorse
-	if endorse.topic != endorseCommit {
+	if !m.validateEndorse(endorse, endorseCommit) {
 		return sAcceptCommitEndorse, nil
 	}
-	// TODO verify that the endorse is one delegate, and verify signature via endorse.VerifySignature() with pub key
-	blkHash := endorse.blkHash
-	endorses := m.ctx.round.commitEndorses[blkHash]
-	if endorses == nil {
-		endorses = map[string]bool{}
-		m.ctx.round.commitEndorses[blkHash] = endorses
+	endorses := m.ctx.round.commitEndorsesOf(endorse.blkHash)
+	if !addEndorse(endorses, endorse) {
+		return sAcceptCommitEndorse, nil
+	}
+	if endorse.blkHash == hash.ZeroHash32B {
+		return m.handleTimeoutVote(endorse)
+	}
+	m.collectShare(endorse)
+	if endorse.decision && endorse.aggregate != nil {
+		// The aggregate of the endorses replaces the individual endorsements in the certificate
+		m.setAggregate(endorse.blkHash, endorse.aggregate)
+	} else if endorse.decision {
+		if m.ctx.round.certificates == nil {
+			m.ctx.round.certificates = make(map[hash.Hash32B][]*blockchain.Endorsement)
+		}
+		m.ctx.round.certificates[endorse.blkHash] = append(m.ctx.round.certificates[endorse.blkHash], endorse.toEndorsement())
 	}
-	endorses[endorse.endorser] = endorse.decision
 	// if either yes or no is true, block must exists and blkHash must be a valid one
 	yes, no := m.ctx.calcQuorum(endorses)
 	if !yes && !no {
 		// Wait for more votes to come
 		return sAcceptCommitEndorse, nil
 	}
+	m.aggregateEndorses(endorseCommit, endorse.blkHash, yes && !no)
+	if yes && !no {
+		// The block to commit is the one endorsed, which may be proposed in an earlier round
+		m.ctx.round.block = m.ctx.round.proposal(endorse.blkHash)
+	}
 
 	return m.processEndorseCommit(yes && !no)
 }
 
+// handleTimeoutVote collects the votes for the dummy block, and commits it once a quorum of the delegates vote for it
+func (m *cFSM) handleTimeoutVote(en *endorse) (fsm.State, error) {
+	if en.decision {
+		return sAcceptCommitEndorse, nil
+	}
+	if m.ctx.round.certificates == nil {
+		m.ctx.round.certificates = make(map[hash.Hash32B][]*blockchain.Endorsement)
+	}
+	votes := append(m.ctx.round.certificates[hash.ZeroHash32B], en.toEndorsement())
+	m.ctx.round.certificates[hash.ZeroHash32B] = votes
+	if len(votes) < quorum(len(m.ctx.epoch.delegates)) {
+		return sAcceptCommitEndorse, nil
+	}
+	return m.processEndorseCommit(false)
+}
+
 func (m *cFSM) handleEndorseCommitTimeout(evt fsm.Event) (fsm.State, error) {
 	if evt.Type() != eEndorseCommitTimeout {
 		return sInvalid, errors.Errorf("invalid event type %s", evt.Type())
@@ -821,6 +1174,11 @@ func (m *cFSM) processEndorseCommit(consensus bool) (fsm.State, error) {
 	height := m.ctx.round.height
 	if consensus {
 		pendingBlock = m.ctx.round.block
+		if pendingBlock != nil {
+			// Embed the commit endorsements as the certificate of the block, which the syncing nodes check
+			pendingBlock.Endorsements = m.ctx.round.certificates[pendingBlock.HashBlock()]
+			pendingBlock.AggregateEndorsement = m.ctx.round.aggregates[pendingBlock.HashBlock()]
+		}
 		logger.Info().
 			Uint64("block", height).
 			Msg("consensus reached")
@@ -833,6 +1191,11 @@ func (m *cFSM) processEndorseCommit(consensus bool) (fsm.State, error) {
 		consensusMtc.WithLabelValues("false").Inc()
 		if m.ctx.cfg.EnableDummyBlock {
 			pendingBlock = m.ctx.chain.MintNewDummyBlock()
+			// Embed the timeout votes as the proof of the dummy block, which the syncing nodes check. Without a
+			// quorum of them, the dummy block is only committed locally and is replaced by syncing the proven one.
+			if votes := m.ctx.round.certificates[hash.ZeroHash32B]; len(votes) >= quorum(len(m.ctx.epoch.delegates)) {
+				pendingBlock.Endorsements = votes
+			}
 			logger.Warn().
 				Uint64("block", pendingBlock.Height()).
 				Msg("dummy block is generated")
@@ -841,12 +1204,7 @@ func (m *cFSM) processEndorseCommit(consensus bool) (fsm.State, error) {
 	if pendingBlock != nil {
 		// If the pending block is a secret block, record the secret share generated by producer
 		if m.ctx.shouldHandleDKG() {
-			for _, secretProposal := range pendingBlock.SecretProposals {
-				if secretProposal.DstAddr() == m.ctx.addr.RawAddress {
-					m.ctx.epoch.committedSecrets[secretProposal.SrcAddr()] = secretProposal.Secret()
-					break
-				}
-			}
+			m.ctx.commitSecretBlock(pendingBlock)
 		}
 		// Commit and broadcast the pending block
 		if err := m.ctx.chain.CommitBlock(pendingBlock); err != nil {
@@ -858,8 +1216,13 @@ func (m *cFSM) processEndorseCommit(consensus bool) (fsm.State, error) {
 		}
 		// Remove transfers in this block from ActPool and reset ActPool state
 		m.ctx.actPool.Reset()
-		// Broadcast the committed block to the network
-		if blkProto := pendingBlock.ConvertToBlockPb(); blkProto != nil {
+		// Broadcast the committed block to the network, unless it's a dummy block without the quorum of the timeout
+		// votes, which the peers would reject as the one without the certificate. They sync the proven one instead.
+		if pendingBlock.IsDummyBlock() && len(pendingBlock.Endorsements) == 0 {
+			logger.Warn().
+				Uint64("block", pendingBlock.Height()).
+				Msg("dummy block without the proof isn't broadcast")
+		} else if blkProto := pendingBlock.ConvertToBlockPb(); blkProto != nil {
 			if err := m.ctx.p2p.Broadcast(m.ctx.chain.ChainID(), blkProto); err != nil {
 				logger.Error().
 					Err(err).
@@ -948,7 +1311,9 @@ func (m *cFSM) newCEvt(t fsm.EventType) *consensusEvt {
 }
 
 func (m *cFSM) newProposeBlkEvt(blk *blockchain.Block) *proposeBlkEvt {
-	return newProposeBlkEvt(blk, m.ctx.clock)
+	evt := newProposeBlkEvt(blk, m.ctx.clock)
+	evt.round = m.ctx.round.number
+	return evt
 }
 
 func (m *cFSM) newProposeBlkEvtFromProposePb(pb *iproto.ProposePb) (*proposeBlkEvt, error) {
@@ -967,12 +1332,46 @@ func (m *cFSM) newEndorseEvtWithEndorsePb(ePb *iproto.EndorsePb) (*endorseEvt, e
 	return newEndorseEvtWithEndorse(&en, m.ctx.clock), nil
 }
 
-func (m *cFSM) newEndorseProposalEvt(blkHash hash.Hash32B, decision bool) (*endorseEvt, error) {
-	return newEndorseEvt(endorseProposal, blkHash, decision, m.ctx.round.height, m.ctx.addr, m.ctx.clock)
+// newEndorseProposalEvt persists the proposal endorse on the block of the proposer before signing it, so that the node
+// doesn't sign a conflicting one in the round even after restarting
+func (m *cFSM) newEndorseProposalEvt(proposer string, blkHash hash.Hash32B, decision bool) (*endorseEvt, error) {
+	record := &roundRecord{
+		Height:   m.ctx.round.height,
+		Round:    m.ctx.round.number,
+		Proposer: proposer,
+		BlkHash:  blkHash,
+		Decision: decision,
+	}
+	if err := m.ctx.dao.recordRound(record); err != nil {
+		return nil, errors.Wrap(err, "error when recording the proposal endorse to sign")
+	}
+	en := &endorse{
+		topic:    endorseProposal,
+		height:   m.ctx.round.height,
+		round:    m.ctx.round.number,
+		blkHash:  blkHash,
+		decision: decision,
+	}
+	if err := en.Sign(m.ctx.addr); err != nil {
+		return nil, errors.Wrap(err, "error when signing the proposal endorse")
+	}
+	m.signShare(en)
+	return newEndorseEvtWithEndorse(en, m.ctx.clock), nil
 }
 
+// newEndorseCommitEvt persists the commit endorse before signing it, so that the node doesn't sign a conflicting one
+// even after restarting
 func (m *cFSM) newEndorseCommitEvt(blkHash hash.Hash32B, decision bool) (*endorseEvt, error) {
-	return newEndorseEvt(endorseCommit, blkHash, decision, m.ctx.round.height, m.ctx.addr, m.ctx.clock)
+	record := &commitRecord{Height: m.ctx.round.height, BlkHash: blkHash, Decision: decision}
+	if err := m.ctx.dao.recordCommit(record); err != nil {
+		return nil, errors.Wrap(err, "error when recording the commit endorse to sign")
+	}
+	evt, err := newEndorseEvt(endorseCommit, blkHash, decision, m.ctx.round.height, m.ctx.addr, m.ctx.clock)
+	if err != nil {
+		return nil, err
+	}
+	m.signShare(evt.endorse)
+	return evt, nil
 }
 
 func (m *cFSM) newTimeoutEvt(t fsm.EventType, height uint64) *timeoutEvt {
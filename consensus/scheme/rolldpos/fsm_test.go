@@ -25,6 +25,7 @@ import (
 	"github.com/iotexproject/iotex-core/crypto"
 	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/logger"
+	"github.com/iotexproject/iotex-core/network"
 	"github.com/iotexproject/iotex-core/pkg/hash"
 	"github.com/iotexproject/iotex-core/pkg/keypair"
 	"github.com/iotexproject/iotex-core/state"
@@ -281,7 +282,7 @@ func TestHandleInitBlockEvt(t *testing.T) {
 	)
 	cfsm.ctx.epoch.numSubEpochs = uint(2)
 	cfsm.ctx.round = roundCtx{
-		proposalEndorses: make(map[hash.Hash32B]map[string]bool),
+		proposalEndorses: make(map[roundHash]map[string]bool),
 		commitEndorses:   make(map[hash.Hash32B]map[string]bool),
 		proposer:         delegates[2],
 	}
@@ -301,6 +302,7 @@ func TestHandleInitBlockEvt(t *testing.T) {
 	})
 	t.Run("normal-block", func(t *testing.T) {
 		cfsm.ctx.epoch.subEpochNum = uint64(1)
+		cfsm.ctx.round.height++
 		s, err := cfsm.handleInitBlockEvt(cfsm.newCEvt(eInitBlock))
 		require.NoError(t, err)
 		require.Equal(t, sAcceptPropose, s)
@@ -312,6 +314,20 @@ func TestHandleInitBlockEvt(t *testing.T) {
 		require.Equal(t, 1, len(pbe.block.Transfers))
 		require.Equal(t, 1, len(pbe.block.Votes))
 	})
+	t.Run("conflicting-proposal", func(t *testing.T) {
+		// The node has proposed another block in the round before restarting
+		cfsm.ctx.round.height++
+		require.NoError(t, cfsm.ctx.dao.recordRound(&roundRecord{
+			Height:   cfsm.ctx.round.height,
+			Proposer: test21Addrs[2].RawAddress,
+			BlkHash:  hash.Hash32B{1},
+			Decision: true,
+		}))
+		s, err := cfsm.handleInitBlockEvt(cfsm.newCEvt(eInitBlock))
+		require.NoError(t, err)
+		require.Equal(t, sAcceptPropose, s)
+		require.Equal(t, eProposeBlockTimeout, (<-cfsm.evtq).Type())
+	})
 }
 
 func TestHandleProposeBlockEvt(t *testing.T) {
@@ -333,7 +349,7 @@ func TestHandleProposeBlockEvt(t *testing.T) {
 	}
 	round := roundCtx{
 		height:           2,
-		proposalEndorses: make(map[hash.Hash32B]map[string]bool),
+		proposalEndorses: make(map[roundHash]map[string]bool),
 		commitEndorses:   make(map[hash.Hash32B]map[string]bool),
 		proposer:         delegates[2],
 	}
@@ -354,10 +370,10 @@ func TestHandleProposeBlockEvt(t *testing.T) {
 		cfsm.ctx.epoch = epoch
 		cfsm.ctx.round = round
 
-		blk, err := cfsm.ctx.mintCommonBlock()
+		blk, err := cfsm.ctx.mintCommonBlock(context.Background())
 
 		assert.NoError(t, err)
-		state, err := cfsm.handleProposeBlockEvt(newProposeBlkEvt(blk, cfsm.ctx.clock))
+		state, err := cfsm.handleProposeBlockEvt(newSignedProposeBlkEvt(t, blk, 0, testAddrs[2], cfsm.ctx.clock))
 
 		assert.NoError(t, err)
 		assert.Equal(t, sAcceptProposalEndorse, state)
@@ -389,9 +405,9 @@ func TestHandleProposeBlockEvt(t *testing.T) {
 		cfsm.ctx.round = round
 
 		clock.Add(11 * time.Second)
-		blk, err := cfsm.ctx.mintCommonBlock()
+		blk, err := cfsm.ctx.mintCommonBlock(context.Background())
 		assert.NoError(t, err)
-		state, err := cfsm.handleProposeBlockEvt(newProposeBlkEvt(blk, cfsm.ctx.clock))
+		state, err := cfsm.handleProposeBlockEvt(newSignedProposeBlkEvt(t, blk, 0, testAddrs[2], cfsm.ctx.clock))
 		assert.NoError(t, err)
 		assert.Equal(t, sAcceptProposalEndorse, state)
 		e := <-cfsm.evtq
@@ -404,7 +420,7 @@ func TestHandleProposeBlockEvt(t *testing.T) {
 		clock.Add(10 * time.Second)
 		err = blk.SignBlock(testAddrs[3])
 		assert.NoError(t, err)
-		state, err = cfsm.handleProposeBlockEvt(newProposeBlkEvt(blk, cfsm.ctx.clock))
+		state, err = cfsm.handleProposeBlockEvt(newSignedProposeBlkEvt(t, blk, 0, testAddrs[3], cfsm.ctx.clock))
 		assert.NoError(t, err)
 		assert.Equal(t, sAcceptProposalEndorse, state)
 		e = <-cfsm.evtq
@@ -431,9 +447,9 @@ func TestHandleProposeBlockEvt(t *testing.T) {
 		cfsm.ctx.epoch = epoch
 		cfsm.ctx.round = round
 
-		blk, err := cfsm.ctx.mintCommonBlock()
+		blk, err := cfsm.ctx.mintCommonBlock(context.Background())
 		assert.NoError(t, err)
-		state, err := cfsm.handleProposeBlockEvt(newProposeBlkEvt(blk, cfsm.ctx.clock))
+		state, err := cfsm.handleProposeBlockEvt(newSignedProposeBlkEvt(t, blk, 0, testAddrs[2], cfsm.ctx.clock))
 		assert.NoError(t, err)
 		assert.Equal(t, sAcceptPropose, state)
 	})
@@ -456,9 +472,9 @@ func TestHandleProposeBlockEvt(t *testing.T) {
 		cfsm.ctx.epoch = epoch
 		cfsm.ctx.round = round
 
-		blk, err := cfsm.ctx.mintCommonBlock()
+		blk, err := cfsm.ctx.mintCommonBlock(context.Background())
 		assert.NoError(t, err)
-		state, err := cfsm.handleProposeBlockEvt(newProposeBlkEvt(blk, cfsm.ctx.clock))
+		state, err := cfsm.handleProposeBlockEvt(newSignedProposeBlkEvt(t, blk, 0, testAddrs[2], cfsm.ctx.clock))
 		assert.NoError(t, err)
 		assert.Equal(t, sAcceptProposalEndorse, state)
 		e := <-cfsm.evtq
@@ -483,9 +499,9 @@ func TestHandleProposeBlockEvt(t *testing.T) {
 		cfsm.ctx.epoch = epoch
 		cfsm.ctx.round = round
 
-		blk, err := cfsm.ctx.mintCommonBlock()
+		blk, err := cfsm.ctx.mintCommonBlock(context.Background())
 		assert.NoError(t, err)
-		state, err := cfsm.handleProposeBlockEvt(newProposeBlkEvt(blk, cfsm.ctx.clock))
+		state, err := cfsm.handleProposeBlockEvt(newSignedProposeBlkEvt(t, blk, 0, testAddrs[3], cfsm.ctx.clock))
 		assert.NoError(t, err)
 		assert.Equal(t, sAcceptPropose, state)
 		state, err = cfsm.handleProposeBlockTimeout(cfsm.newCEvt(eProposeBlockTimeout))
@@ -511,9 +527,9 @@ func TestHandleProposeBlockEvt(t *testing.T) {
 		cfsm.ctx.round = round
 
 		clock.Add(11 * time.Second)
-		blk, err := cfsm.ctx.mintCommonBlock()
+		blk, err := cfsm.ctx.mintCommonBlock(context.Background())
 		assert.NoError(t, err)
-		state, err := cfsm.handleProposeBlockEvt(newProposeBlkEvt(blk, cfsm.ctx.clock))
+		state, err := cfsm.handleProposeBlockEvt(newSignedProposeBlkEvt(t, blk, 0, testAddrs[3], cfsm.ctx.clock))
 		assert.NoError(t, err)
 		assert.Equal(t, sAcceptPropose, state)
 		state, err = cfsm.handleProposeBlockTimeout(cfsm.newCEvt(eProposeBlockTimeout))
@@ -546,6 +562,183 @@ func TestHandleProposeBlockEvt(t *testing.T) {
 	})
 }
 
+func TestProposerRounds(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	delegates := make([]string, 4)
+	for i := 0; i < 4; i++ {
+		delegates[i] = testAddrs[i].RawAddress
+	}
+
+	epoch := epochCtx{
+		delegates:    delegates,
+		num:          uint64(1),
+		height:       uint64(1),
+		numSubEpochs: uint(1),
+	}
+	newRound := func() roundCtx {
+		return roundCtx{
+			height:           2,
+			proposalEndorses: make(map[roundHash]map[string]bool),
+			commitEndorses:   make(map[hash.Hash32B]map[string]bool),
+			proposer:         delegates[2],
+		}
+	}
+
+	t.Run("timeout-rotates-proposer", func(t *testing.T) {
+		cfsm := newTestCFSM(t, testAddrs[0], testAddrs[2], ctrl, delegates, nil, nil, clock.New())
+		cfsm.ctx.cfg.MaxRoundsPerHeight = 3
+		cfsm.ctx.epoch = epoch
+		cfsm.ctx.round = newRound()
+
+		state, err := cfsm.handleProposeBlockTimeout(cfsm.newCEvt(eProposeBlockTimeout))
+		require.NoError(t, err)
+		require.Equal(t, sAcceptPropose, state)
+		require.Equal(t, uint32(1), cfsm.ctx.round.number)
+		require.Equal(t, delegates[3], cfsm.ctx.round.proposer)
+		require.Equal(t, eProposeBlockTimeout, (<-cfsm.evtq).Type())
+
+		// The node is the proposer of the next round
+		state, err = cfsm.handleProposeBlockTimeout(cfsm.newCEvt(eProposeBlockTimeout))
+		require.NoError(t, err)
+		require.Equal(t, sInitPropose, state)
+		require.Equal(t, uint32(2), cfsm.ctx.round.number)
+		require.Equal(t, delegates[0], cfsm.ctx.round.proposer)
+		require.Equal(t, eInitBlock, (<-cfsm.evtq).Type())
+
+		// No more round at the height
+		state, err = cfsm.handleProposeBlockTimeout(cfsm.newCEvt(eProposeBlockTimeout))
+		require.NoError(t, err)
+		require.Equal(t, sAcceptProposalEndorse, state)
+		require.Equal(t, eEndorseProposalTimeout, (<-cfsm.evtq).Type())
+	})
+
+	t.Run("defer-next-round", func(t *testing.T) {
+		cfsm := newTestCFSM(t, testAddrs[0], testAddrs[3], ctrl, delegates, nil, nil, clock.New())
+		cfsm.ctx.cfg.MaxRoundsPerHeight = 3
+		cfsm.ctx.epoch = epoch
+		cfsm.ctx.round = newRound()
+
+		blk, err := cfsm.ctx.mintCommonBlock(context.Background())
+		require.NoError(t, err)
+		// delegates[3] isn't the proposer of round 0
+		state, err := cfsm.handleProposeBlockEvt(newSignedProposeBlkEvt(t, blk, 0, testAddrs[3], cfsm.ctx.clock))
+		require.NoError(t, err)
+		require.Equal(t, sAcceptPropose, state)
+		// The node isn't allowed to skip a round
+		state, err = cfsm.handleProposeBlockEvt(newSignedProposeBlkEvt(t, blk, 2, testAddrs[3], cfsm.ctx.clock))
+		require.NoError(t, err)
+		require.Equal(t, sAcceptPropose, state)
+		require.Nil(t, cfsm.ctx.round.pendingProposal)
+		// The proposal of the next round signed by another delegate is dropped
+		state, err = cfsm.handleProposeBlockEvt(newSignedProposeBlkEvt(t, blk, 1, testAddrs[1], cfsm.ctx.clock))
+		require.NoError(t, err)
+		require.Equal(t, sAcceptPropose, state)
+		require.Nil(t, cfsm.ctx.round.pendingProposal)
+
+		// The proposal of the next round waits for the current one to time out
+		evt := newSignedProposeBlkEvt(t, blk, 1, testAddrs[3], cfsm.ctx.clock)
+		state, err = cfsm.handleProposeBlockEvt(evt)
+		require.NoError(t, err)
+		require.Equal(t, sAcceptPropose, state)
+		require.Equal(t, uint32(0), cfsm.ctx.round.number)
+		require.Nil(t, cfsm.ctx.round.block)
+		require.Equal(t, 0, len(cfsm.evtq))
+
+		state, err = cfsm.handleProposeBlockTimeout(cfsm.newCEvt(eProposeBlockTimeout))
+		require.NoError(t, err)
+		require.Equal(t, sAcceptPropose, state)
+		require.Equal(t, uint32(1), cfsm.ctx.round.number)
+		require.Equal(t, delegates[3], cfsm.ctx.round.proposer)
+		require.Equal(t, evt, <-cfsm.evtq)
+		require.Equal(t, eProposeBlockTimeout, (<-cfsm.evtq).Type())
+		state, err = cfsm.handleProposeBlockEvt(evt)
+		require.NoError(t, err)
+		require.Equal(t, sAcceptProposalEndorse, state)
+		require.Equal(t, blk, cfsm.ctx.round.block)
+		require.Equal(t, eEndorseProposal, (<-cfsm.evtq).Type())
+		require.Equal(t, eEndorseProposalTimeout, (<-cfsm.evtq).Type())
+	})
+
+	t.Run("locked", func(t *testing.T) {
+		proposer := newTestCFSM(t, testAddrs[2], testAddrs[2], ctrl, delegates, nil, nil, clock.New())
+		proposer.ctx.epoch = epoch
+		proposer.ctx.round = newRound()
+		lockedBlk, err := proposer.ctx.mintCommonBlock(context.Background())
+		require.NoError(t, err)
+
+		cfsm := newTestCFSM(t, testAddrs[3], testAddrs[3], ctrl, delegates, nil, nil, clock.New())
+		cfsm.ctx.cfg.MaxRoundsPerHeight = 3
+		cfsm.ctx.epoch = epoch
+		cfsm.ctx.round = newRound()
+		cfsm.ctx.round.locked = lockedBlk
+
+		// The node proposes the locked block in its round instead of minting a new one
+		require.NoError(t, cfsm.moveToRound(1))
+		state, err := cfsm.handleInitBlockEvt(cfsm.newCEvt(eInitBlock))
+		require.NoError(t, err)
+		require.Equal(t, sAcceptPropose, state)
+		e := <-cfsm.evtq
+		pbe, ok := e.(*proposeBlkEvt)
+		require.True(t, ok)
+		require.Equal(t, lockedBlk, pbe.block)
+		require.Equal(t, uint32(1), pbe.round)
+		state, err = cfsm.handleProposeBlockEvt(pbe)
+		require.NoError(t, err)
+		require.Equal(t, sAcceptProposalEndorse, state)
+		require.Equal(t, eEndorseProposal, (<-cfsm.evtq).Type())
+		require.Equal(t, eEndorseProposalTimeout, (<-cfsm.evtq).Type())
+
+		// Another block of the round is rejected
+		blk, err := cfsm.ctx.mintCommonBlock(context.Background())
+		require.NoError(t, err)
+		state, err = cfsm.handleProposeBlockEvt(newSignedProposeBlkEvt(t, blk, 1, testAddrs[3], cfsm.ctx.clock))
+		require.NoError(t, err)
+		require.Equal(t, sAcceptPropose, state)
+		require.Nil(t, cfsm.ctx.round.block)
+
+		// The proposal of an earlier round is ignored
+		state, err = cfsm.handleProposeBlockEvt(newSignedProposeBlkEvt(t, lockedBlk, 0, testAddrs[2], cfsm.ctx.clock))
+		require.NoError(t, err)
+		require.Equal(t, sAcceptPropose, state)
+	})
+
+	t.Run("propose-again-only-locked", func(t *testing.T) {
+		proposer := newTestCFSM(t, testAddrs[2], testAddrs[2], ctrl, delegates, nil, nil, clock.New())
+		proposer.ctx.epoch = epoch
+		proposer.ctx.round = newRound()
+		blk, err := proposer.ctx.mintCommonBlock(context.Background())
+		require.NoError(t, err)
+
+		cfsm := newTestCFSM(t, testAddrs[0], testAddrs[0], ctrl, delegates, nil, nil, clock.New())
+		cfsm.ctx.cfg.MaxRoundsPerHeight = 3
+		cfsm.ctx.epoch = epoch
+		cfsm.ctx.round = newRound()
+		require.NoError(t, cfsm.moveToRound(1))
+
+		// The proposer of round 1 can't propose the block of another delegate, which isn't locked on in round 0
+		evt := newSignedProposeBlkEvt(t, blk, 1, testAddrs[3], cfsm.ctx.clock)
+		state, err := cfsm.handleProposeBlockEvt(evt)
+		require.NoError(t, err)
+		require.Equal(t, sAcceptPropose, state)
+		require.Nil(t, cfsm.ctx.round.block)
+
+		// The block got a quorum of the proposal endorses in round 0
+		for i := 0; i < quorum(len(delegates)); i++ {
+			cfsm.ctx.round.proposalEndorsesOf(0, blk.HashBlock())[delegates[i]] = true
+		}
+		state, err = cfsm.handleProposeBlockEvt(evt)
+		require.NoError(t, err)
+		require.Equal(t, sAcceptProposalEndorse, state)
+		require.Equal(t, blk, cfsm.ctx.round.block)
+		require.Equal(t, eEndorseProposal, (<-cfsm.evtq).Type())
+		require.Equal(t, eEndorseProposalTimeout, (<-cfsm.evtq).Type())
+	})
+}
+
 func TestHandleProposalEndorseEvt(t *testing.T) {
 	t.Parallel()
 
@@ -564,7 +757,7 @@ func TestHandleProposalEndorseEvt(t *testing.T) {
 		numSubEpochs: uint(1),
 	}
 	round := roundCtx{
-		proposalEndorses: make(map[hash.Hash32B]map[string]bool),
+		proposalEndorses: make(map[roundHash]map[string]bool),
 		commitEndorses:   make(map[hash.Hash32B]map[string]bool),
 		proposer:         delegates[2],
 	}
@@ -587,7 +780,7 @@ func TestHandleProposalEndorseEvt(t *testing.T) {
 		cfsm.ctx.epoch = epoch
 		cfsm.ctx.round = round
 
-		blk, err := cfsm.ctx.mintCommonBlock()
+		blk, err := cfsm.ctx.mintCommonBlock(context.Background())
 		assert.NoError(t, err)
 		cfsm.ctx.round.block = blk
 
@@ -635,18 +828,230 @@ func TestHandleProposalEndorseEvt(t *testing.T) {
 		cfsm.ctx.epoch = epoch
 		cfsm.ctx.round = round
 
-		blk, err := cfsm.ctx.mintCommonBlock()
+		blk, err := cfsm.ctx.mintCommonBlock(context.Background())
 		assert.NoError(t, err)
 		cfsm.ctx.round.block = blk
 
 		state, err := cfsm.handleEndorseProposalTimeout(cfsm.newCEvt(eEndorseProposalTimeout))
 		assert.NoError(t, err)
 		assert.Equal(t, sAcceptCommitEndorse, state)
+		// The node votes for the dummy block of the height
+		vote, ok := (<-cfsm.evtq).(*endorseEvt)
+		require.True(t, ok)
+		assert.Equal(t, eEndorseCommit, vote.Type())
+		assert.Equal(t, hash.ZeroHash32B, vote.endorse.blkHash)
+		assert.False(t, vote.endorse.decision)
 		e := <-cfsm.evtq
 		evt, ok := e.(*timeoutEvt)
 		require.True(t, ok)
 		assert.Equal(t, eEndorseCommitTimeout, evt.Type())
 	})
+
+	t.Run("dummy-block-proven-by-timeout-votes", func(t *testing.T) {
+		var committed *blockchain.Block
+		cfsm := newTestCFSM(
+			t,
+			testAddrs[0],
+			testAddrs[2],
+			ctrl,
+			delegates,
+			func(chain *mock_blockchain.MockBlockchain) {
+				chain.EXPECT().ChainID().AnyTimes().Return(config.Default.Chain.ID)
+				chain.EXPECT().
+					MintNewDummyBlock().
+					Return(blockchain.NewBlock(0, round.height, hash.ZeroHash32B, testutil.TimestampNow(), nil, nil, nil, nil)).
+					Times(1)
+				chain.EXPECT().CommitBlock(gomock.Any()).Do(func(blk *blockchain.Block) { committed = blk }).Return(nil).Times(1)
+			},
+			func(p2p *mock_network.MockOverlay) {
+				// The proven dummy block is broadcast
+				p2p.EXPECT().Broadcast(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			},
+			clock.New(),
+		)
+		cfsm.ctx.epoch = epoch
+		cfsm.ctx.round = roundCtx{
+			height:           round.height,
+			proposalEndorses: make(map[roundHash]map[string]bool),
+			commitEndorses:   make(map[hash.Hash32B]map[string]bool),
+			proposer:         delegates[2],
+		}
+
+		// A yes vote on the zero hash isn't counted, and the dummy block is committed with a quorum of timeout votes
+		eEvt, err := newEndorseEvt(endorseCommit, hash.ZeroHash32B, true, round.height, testAddrs[0], cfsm.ctx.clock)
+		require.NoError(t, err)
+		state, err := cfsm.handleEndorseCommitEvt(eEvt)
+		require.NoError(t, err)
+		require.Equal(t, sAcceptCommitEndorse, state)
+		for i := 1; i < 4; i++ {
+			require.Equal(t, sAcceptCommitEndorse, state)
+			eEvt, err = newEndorseEvt(endorseCommit, hash.ZeroHash32B, false, round.height, testAddrs[i], cfsm.ctx.clock)
+			require.NoError(t, err)
+			state, err = cfsm.handleEndorseCommitEvt(eEvt)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, sRoundStart, state)
+		require.NotNil(t, committed)
+		assert.Equal(t, quorum(len(delegates)), len(committed.Endorsements))
+		for _, en := range committed.Endorsements {
+			assert.True(t, en.IsTimeoutVote())
+			assert.True(t, en.Verify(round.height))
+		}
+	})
+
+	t.Run("endorses-keyed-by-round", func(t *testing.T) {
+		cfsm := newTestCFSM(
+			t,
+			testAddrs[0],
+			testAddrs[2],
+			ctrl,
+			delegates,
+			func(chain *mock_blockchain.MockBlockchain) {
+				chain.EXPECT().ChainID().AnyTimes().Return(config.Default.Chain.ID)
+			},
+			nil,
+			clock.New(),
+		)
+		cfsm.ctx.cfg.MaxRoundsPerHeight = 3
+		cfsm.ctx.epoch = epoch
+		cfsm.ctx.round = roundCtx{
+			proposalEndorses: make(map[roundHash]map[string]bool),
+			commitEndorses:   make(map[hash.Hash32B]map[string]bool),
+			proposer:         delegates[2],
+		}
+		blk, err := cfsm.ctx.mintCommonBlock(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, cfsm.moveToRound(1))
+		cfsm.ctx.round.block = blk
+
+		endorseInRound := func(round uint32, endorser *iotxaddress.Address) *endorseEvt {
+			en := &endorse{topic: endorseProposal, round: round, blkHash: blk.HashBlock(), decision: true}
+			require.NoError(t, en.Sign(endorser))
+			return newEndorseEvtWithEndorse(en, cfsm.ctx.clock)
+		}
+		// The round is signed
+		en := endorseInRound(0, testAddrs[0]).endorse
+		require.True(t, en.VerifySignature(testAddrs[0].PublicKey))
+		en.round = 1
+		require.False(t, en.VerifySignature(testAddrs[0].PublicKey))
+
+		// The endorses of round 0 are kept without being counted in round 1, and the ones of round 2 are rejected
+		for i := 0; i < 3; i++ {
+			state, err := cfsm.handleEndorseProposalEvt(endorseInRound(0, testAddrs[i]))
+			require.NoError(t, err)
+			require.Equal(t, sAcceptProposalEndorse, state)
+			state, err = cfsm.handleEndorseProposalEvt(endorseInRound(2, testAddrs[i]))
+			require.NoError(t, err)
+			require.Equal(t, sAcceptProposalEndorse, state)
+		}
+		require.Equal(t, 3, len(cfsm.ctx.round.proposalEndorses[roundHash{round: 0, blkHash: blk.HashBlock()}]))
+		require.Equal(t, 0, len(cfsm.ctx.round.proposalEndorses[roundHash{round: 2, blkHash: blk.HashBlock()}]))
+		require.True(t, cfsm.isLockable(blk.HashBlock(), 1))
+		require.Equal(t, 0, len(cfsm.evtq))
+	})
+
+	t.Run("reject-invalid-endorses", func(t *testing.T) {
+		cfsm := newTestCFSM(
+			t,
+			testAddrs[0],
+			testAddrs[2],
+			ctrl,
+			delegates,
+			func(chain *mock_blockchain.MockBlockchain) {
+				chain.EXPECT().ChainID().AnyTimes().Return(config.Default.Chain.ID)
+				candidates := make([]*state.Candidate, 0)
+				for _, delegate := range delegates {
+					candidates = append(candidates, &state.Candidate{Address: delegate})
+				}
+				chain.EXPECT().CandidatesByHeight(gomock.Any()).Return(candidates, nil).AnyTimes()
+			},
+			nil,
+			clock.New(),
+		)
+		cfsm.ctx.epoch = epoch
+		cfsm.ctx.round = roundCtx{
+			proposalEndorses: make(map[roundHash]map[string]bool),
+			commitEndorses:   make(map[hash.Hash32B]map[string]bool),
+			proposer:         delegates[2],
+		}
+
+		blk, err := cfsm.ctx.mintCommonBlock(context.Background())
+		assert.NoError(t, err)
+		cfsm.ctx.round.block = blk
+
+		// The endorse from a node which is not a delegate is not counted
+		eEvt, err := newEndorseEvt(endorseProposal, blk.HashBlock(), true, round.height, testAddrs[4], cfsm.ctx.clock)
+		assert.NoError(t, err)
+		state, err := cfsm.handleEndorseProposalEvt(eEvt)
+		assert.NoError(t, err)
+		assert.Equal(t, sAcceptProposalEndorse, state)
+		assert.Equal(t, 0, len(cfsm.ctx.round.proposalEndorses[roundHash{blkHash: blk.HashBlock()}]))
+
+		// The duplicate endorse is not counted again, even if it changes the decision
+		for _, decision := range []bool{true, false} {
+			eEvt, err = newEndorseEvt(endorseProposal, blk.HashBlock(), decision, round.height, testAddrs[0], cfsm.ctx.clock)
+			assert.NoError(t, err)
+			state, err = cfsm.handleEndorseProposalEvt(eEvt)
+			assert.NoError(t, err)
+			assert.Equal(t, sAcceptProposalEndorse, state)
+		}
+		assert.Equal(t, map[string]bool{testAddrs[0].RawAddress: true}, cfsm.ctx.round.proposalEndorses[roundHash{blkHash: blk.HashBlock()}])
+
+		// The endorse with a bad signature is rejected before going into the FSM
+		r := &RollDPoS{cfsm: cfsm, ctx: cfsm.ctx}
+		ePb := eEvt.toProtoMsg()
+		ePb.Decision = true
+		assert.Equal(t, network.ErrBadSignature, errors.Cause(r.HandleEndorse(ePb)))
+		// The endorse from a node which is not a delegate is invalid, so that the peer sending it gets penalized
+		eEvt, err = newEndorseEvt(endorseProposal, blk.HashBlock(), true, round.height, testAddrs[4], cfsm.ctx.clock)
+		assert.NoError(t, err)
+		assert.Equal(t, network.ErrInvalidMsg, errors.Cause(r.HandleEndorse(eEvt.toProtoMsg())))
+		eEvt, err = newEndorseEvt(endorseProposal, blk.HashBlock(), true, round.height, testAddrs[1], cfsm.ctx.clock)
+		assert.NoError(t, err)
+		assert.NoError(t, r.HandleEndorse(eEvt.toProtoMsg()))
+		assert.Equal(t, eEndorseProposal, (<-cfsm.evtq).Type())
+	})
+
+	t.Run("conflicting-commit", func(t *testing.T) {
+		cfsm := newTestCFSM(
+			t,
+			testAddrs[0],
+			testAddrs[2],
+			ctrl,
+			delegates,
+			func(chain *mock_blockchain.MockBlockchain) {
+				chain.EXPECT().ChainID().AnyTimes().Return(config.Default.Chain.ID)
+			},
+			func(p2p *mock_network.MockOverlay) {
+				p2p.EXPECT().Broadcast(gomock.Any(), gomock.Any()).Return(nil).Times(0)
+			},
+			clock.New(),
+		)
+		cfsm.ctx.epoch = epoch
+		cfsm.ctx.round = roundCtx{
+			proposalEndorses: make(map[roundHash]map[string]bool),
+			commitEndorses:   make(map[hash.Hash32B]map[string]bool),
+			proposer:         delegates[2],
+		}
+
+		blk, err := cfsm.ctx.mintCommonBlock(context.Background())
+		assert.NoError(t, err)
+		cfsm.ctx.round.block = blk
+
+		// The node has endorsed another block to commit at the height before restarting
+		require.NoError(t, cfsm.ctx.dao.recordCommit(&commitRecord{BlkHash: hash.Hash32B{1}, Decision: true}))
+		for i := 0; i < 2; i++ {
+			eEvt, err := newEndorseEvt(endorseProposal, blk.HashBlock(), true, 0, testAddrs[i], cfsm.ctx.clock)
+			assert.NoError(t, err)
+			_, err = cfsm.handleEndorseProposalEvt(eEvt)
+			assert.NoError(t, err)
+		}
+		eEvt, err := newEndorseEvt(endorseProposal, blk.HashBlock(), true, 0, testAddrs[2], cfsm.ctx.clock)
+		assert.NoError(t, err)
+		_, err = cfsm.handleEndorseProposalEvt(eEvt)
+		assert.Equal(t, ErrConflictingEndorse, errors.Cause(err))
+		assert.Equal(t, 0, len(cfsm.evtq))
+	})
 }
 
 func TestHandleCommitEndorseEvt(t *testing.T) {
@@ -662,7 +1067,7 @@ func TestHandleCommitEndorseEvt(t *testing.T) {
 	}
 
 	round := roundCtx{
-		proposalEndorses: make(map[hash.Hash32B]map[string]bool),
+		proposalEndorses: make(map[roundHash]map[string]bool),
 		commitEndorses:   make(map[hash.Hash32B]map[string]bool),
 		proposer:         delegates[2],
 	}
@@ -712,6 +1117,7 @@ func TestHandleCommitEndorseEvt(t *testing.T) {
 		assert.Equal(t, 1, len(cfsm.ctx.epoch.committedSecrets))
 	})
 	t.Run("gather-commits-common-block", func(t *testing.T) {
+		var committed *blockchain.Block
 		cfsm := newTestCFSM(
 			t,
 			test21Addrs[0],
@@ -719,7 +1125,9 @@ func TestHandleCommitEndorseEvt(t *testing.T) {
 			ctrl,
 			delegates,
 			func(chain *mock_blockchain.MockBlockchain) {
-				chain.EXPECT().CommitBlock(gomock.Any()).Return(nil).Times(1)
+				chain.EXPECT().CommitBlock(gomock.Any()).Do(func(blk *blockchain.Block) {
+					committed = blk
+				}).Return(nil).Times(1)
 				chain.EXPECT().ChainID().AnyTimes().Return(config.Default.Chain.ID)
 			},
 			func(p2p *mock_network.MockOverlay) {
@@ -751,6 +1159,14 @@ func TestHandleCommitEndorseEvt(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, sRoundStart, state)
 		assert.Equal(t, eFinishEpoch, (<-cfsm.evtq).Type())
+
+		// The committed block carries the commit endorsements as its certificate
+		require.NotNil(t, committed)
+		require.Equal(t, 15, len(committed.Endorsements))
+		for i, en := range committed.Endorsements {
+			assert.Equal(t, test21Addrs[i].RawAddress, en.Endorser)
+			assert.True(t, en.Verify(round.height))
+		}
 	})
 	t.Run("timeout-blocking", func(t *testing.T) {
 		cfsm := newTestCFSM(
@@ -793,11 +1209,13 @@ func TestHandleCommitEndorseEvt(t *testing.T) {
 				chain.EXPECT().CommitBlock(gomock.Any()).Return(nil).Times(1)
 				chain.EXPECT().
 					MintNewDummyBlock().
-					Return(blockchain.NewBlock(0, 0, hash.ZeroHash32B, testutil.TimestampNow(), nil, nil, nil, nil)).Times(1)
+					Return(blockchain.NewBlock(0, 2, hash.ZeroHash32B, testutil.TimestampNow(), nil, nil, nil, nil)).Times(1)
 				chain.EXPECT().ChainID().AnyTimes().Return(config.Default.Chain.ID)
 			},
 			func(p2p *mock_network.MockOverlay) {
-				p2p.EXPECT().Broadcast(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				// Without a quorum of the timeout votes, the dummy block is only committed locally, as the peers would
+				// reject it without the proof
+				p2p.EXPECT().Broadcast(gomock.Any(), gomock.Any()).Return(nil).Times(0)
 			},
 			clock.New(),
 		)
@@ -832,7 +1250,7 @@ func TestHandleFinishEpochEvt(t *testing.T) {
 		numSubEpochs: uint(2),
 	}
 	round := roundCtx{
-		proposalEndorses: make(map[hash.Hash32B]map[string]bool),
+		proposalEndorses: make(map[roundHash]map[string]bool),
 		commitEndorses:   make(map[hash.Hash32B]map[string]bool),
 		proposer:         delegates[2],
 	}
@@ -946,6 +1364,20 @@ func TestHandleFinishEpochEvt(t *testing.T) {
 	})
 }
 
+// newSignedProposeBlkEvt returns the proposal of the block in the round signed by the proposer
+func newSignedProposeBlkEvt(
+	t *testing.T,
+	blk *blockchain.Block,
+	round uint32,
+	proposer *iotxaddress.Address,
+	c clock.Clock,
+) *proposeBlkEvt {
+	evt := newProposeBlkEvt(blk, c)
+	evt.round = round
+	require.NoError(t, evt.Sign(proposer))
+	return evt
+}
+
 func newTestCFSM(
 	t *testing.T,
 	addr *iotxaddress.Address,
@@ -1037,7 +1469,10 @@ func newTestCFSM(
 			blockchain.EXPECT().GetBlockByHeight(uint64(21)).Return(lastBlk, nil).AnyTimes()
 			blockchain.EXPECT().GetBlockByHeight(uint64(22)).Return(lastBlk, nil).AnyTimes()
 			blockchain.EXPECT().
-				MintNewDKGBlock(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				MintNewDKGBlock(
+					gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+					gomock.Any(),
+				).
 				Return(blkToMint, nil).
 				AnyTimes()
 			blockchain.EXPECT().
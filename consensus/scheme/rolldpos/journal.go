@@ -0,0 +1,266 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/facebookgo/clock"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/zjshen14/go-fsm"
+
+	"github.com/iotexproject/iotex-core/logger"
+	"github.com/iotexproject/iotex-core/proto"
+)
+
+// errStaleEvt indicates that the timeout event belongs to an earlier round, which is dropped
+var errStaleEvt = errors.New("stale timeout event")
+
+// journalRecord records an event consumed by the consensus FSM, and the state transition it causes
+type journalRecord struct {
+	// Time is when the event is consumed, and EventTime is when the event is created
+	Time      time.Time     `json:"time"`
+	EventTime time.Time     `json:"eventTime"`
+	Event     fsm.EventType `json:"event"`
+	// Propose and Endorse are the serialized proto messages of the block propose and the endorse events, and Backdoor
+	// is the dst state of the backdoor event
+	Propose  []byte    `json:"propose,omitempty"`
+	Endorse  []byte    `json:"endorse,omitempty"`
+	Backdoor fsm.State `json:"backdoor,omitempty"`
+	Src      fsm.State `json:"src"`
+	// Dst is empty if the transition doesn't happen, and Err tells why
+	Dst fsm.State `json:"dst,omitempty"`
+	Err string    `json:"err,omitempty"`
+}
+
+func newJournalRecord(evt iConsensusEvt, src fsm.State, now time.Time) *journalRecord {
+	record := &journalRecord{
+		Time:      now,
+		EventTime: evt.timestamp(),
+		Event:     evt.Type(),
+		Src:       src,
+	}
+	var err error
+	switch e := evt.(type) {
+	case *proposeBlkEvt:
+		if e.block != nil {
+			record.Propose, err = proto.Marshal(e.toProtoMsg())
+		}
+	case *endorseEvt:
+		record.Endorse, err = proto.Marshal(e.toProtoMsg())
+	case *backdoorEvt:
+		record.Backdoor = e.dst
+	}
+	if err != nil {
+		logger.Error().Err(err).Str("evt", string(evt.Type())).Msg("error when serializing the event to journal")
+	}
+	return record
+}
+
+// event restores the event from the record
+func (m *cFSM) event(record *journalRecord) (iConsensusEvt, error) {
+	var evt iConsensusEvt
+	switch {
+	case record.Propose != nil:
+		var pb iproto.ProposePb
+		if err := proto.Unmarshal(record.Propose, &pb); err != nil {
+			return nil, errors.Wrap(err, "error when unmarshaling the block propose")
+		}
+		pbEvt, err := m.newProposeBlkEvtFromProposePb(&pb)
+		if err != nil {
+			return nil, err
+		}
+		pbEvt.ts = record.EventTime
+		evt = pbEvt
+	case record.Endorse != nil:
+		var pb iproto.EndorsePb
+		if err := proto.Unmarshal(record.Endorse, &pb); err != nil {
+			return nil, errors.Wrap(err, "error when unmarshaling the endorse")
+		}
+		eEvt, err := m.newEndorseEvtWithEndorsePb(&pb)
+		if err != nil {
+			return nil, err
+		}
+		eEvt.ts = record.EventTime
+		evt = eEvt
+	case record.Event == eBackdoor:
+		bEvt := m.newBackdoorEvt(record.Backdoor)
+		bEvt.ts = record.EventTime
+		evt = bEvt
+	case record.Event == eProposeBlockTimeout || record.Event == eEndorseProposalTimeout ||
+		record.Event == eEndorseCommitTimeout:
+		tEvt := m.newTimeoutEvt(record.Event, 0)
+		tEvt.ts = record.EventTime
+		evt = tEvt
+	default:
+		cEvt := m.newCEvt(record.Event)
+		cEvt.ts = record.EventTime
+		evt = cEvt
+	}
+	return evt, nil
+}
+
+// journal writes the records into a file line by line, which is rotated once it exceeds the max size. The rotated
+// files are suffixed with .1, .2 and so on, the larger the older, and the ones beyond the max number are removed.
+type journal struct {
+	path     string
+	maxSize  uint64
+	maxFiles uint
+	file     *os.File
+	size     uint64
+}
+
+func openJournal(path string, maxSize uint64, maxFiles uint) (*journal, error) {
+	j := &journal{
+		path:     path,
+		maxSize:  maxSize,
+		maxFiles: maxFiles,
+	}
+	if err := j.open(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (j *journal) open() error {
+	file, err := os.OpenFile(j.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "error when opening the journal %s", j.path)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return errors.Wrapf(err, "error when getting the size of the journal %s", j.path)
+	}
+	j.file = file
+	j.size = uint64(info.Size())
+	return nil
+}
+
+func (j *journal) write(record *journalRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "error when marshaling the journal record")
+	}
+	data = append(data, '\n')
+	if j.maxSize > 0 && j.size > 0 && j.size+uint64(len(data)) > j.maxSize {
+		if err := j.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := j.file.Write(data)
+	j.size += uint64(n)
+	return errors.Wrapf(err, "error when writing the journal %s", j.path)
+}
+
+func (j *journal) rotate() error {
+	if err := j.file.Close(); err != nil {
+		return errors.Wrapf(err, "error when closing the journal %s", j.path)
+	}
+	if j.maxFiles == 0 {
+		if err := os.Remove(j.path); err != nil {
+			return errors.Wrapf(err, "error when removing the journal %s", j.path)
+		}
+		return j.open()
+	}
+	if err := os.Remove(j.rotatedPath(j.maxFiles)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "error when removing the oldest journal of %s", j.path)
+	}
+	for i := j.maxFiles - 1; i > 0; i-- {
+		if err := os.Rename(j.rotatedPath(i), j.rotatedPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "error when rotating the journal %s", j.rotatedPath(i))
+		}
+	}
+	if err := os.Rename(j.path, j.rotatedPath(1)); err != nil {
+		return errors.Wrapf(err, "error when rotating the journal %s", j.path)
+	}
+	return j.open()
+}
+
+func (j *journal) rotatedPath(i uint) string {
+	return fmt.Sprintf("%s.%d", j.path, i)
+}
+
+func (j *journal) close() error {
+	return errors.Wrapf(j.file.Close(), "error when closing the journal %s", j.path)
+}
+
+// Replay feeds the events recorded in the journal into the consensus FSM, and writes the state transitions into the
+// trace. The RollDPoS should be built with a mock clock, which is set to the time when each event was consumed, and
+// shouldn't be started. The blockchain should be in the state when the journal starts, e.g., a copy of the chain DB
+// taken then, and the FSM is set to the src state of the first record. It returns the number of transitions which
+// don't match the recorded ones.
+func (r *RollDPoS) Replay(ctx context.Context, journal io.Reader, trace io.Writer) (int, error) {
+	mockClock, ok := r.ctx.clock.(*clock.Mock)
+	if !ok {
+		return 0, errors.New("the journal should be replayed with a mock clock")
+	}
+	if err := r.ctx.dao.kvstore.Start(ctx); err != nil {
+		return 0, errors.Wrap(err, "error when starting the consensus state DB")
+	}
+	defer func() {
+		if err := r.ctx.dao.kvstore.Stop(ctx); err != nil {
+			logger.Error().Err(err).Msg("error when stopping the consensus state DB")
+		}
+	}()
+	if err := r.ctx.loadEpoch(); err != nil {
+		return 0, errors.Wrap(err, "error when loading the epoch from the consensus state DB")
+	}
+	r.cfsm.replaying = true
+	defer func() { r.cfsm.replaying = false }()
+
+	mismatches := 0
+	decoder := json.NewDecoder(journal)
+	for i := 0; ; i++ {
+		var record journalRecord
+		if err := decoder.Decode(&record); err == io.EOF {
+			break
+		} else if err != nil {
+			return mismatches, errors.Wrapf(err, "error when decoding the journal record %d", i)
+		}
+		if now := mockClock.Now(); record.Time.After(now) {
+			mockClock.Add(record.Time.Sub(now))
+		}
+		if i == 0 && r.cfsm.currentState() != record.Src {
+			if err := r.cfsm.fsm.Handle(r.cfsm.newBackdoorEvt(record.Src)); err != nil {
+				return mismatches, errors.Wrapf(err, "error when setting the FSM to %s", record.Src)
+			}
+		}
+		evt, err := r.cfsm.event(&record)
+		if err != nil {
+			return mismatches, errors.Wrapf(err, "error when restoring the event of journal record %d", i)
+		}
+		replayed := r.cfsm.handle(evt)
+		line := fmt.Sprintf(
+			"%s %s %s -> %s",
+			record.Time.Format(time.RFC3339Nano),
+			record.Event,
+			replayed.Src,
+			replayed.Dst,
+		)
+		if replayed.Err != "" {
+			line += fmt.Sprintf(" (%s)", replayed.Err)
+		}
+		if replayed.Src != record.Src || replayed.Dst != record.Dst || replayed.Err != record.Err {
+			mismatches++
+			line += fmt.Sprintf(" MISMATCH, journaled %s -> %s", record.Src, record.Dst)
+			if record.Err != "" {
+				line += fmt.Sprintf(" (%s)", record.Err)
+			}
+		}
+		if _, err := fmt.Fprintln(trace, line); err != nil {
+			return mismatches, errors.Wrap(err, "error when writing the trace")
+		}
+	}
+	return mismatches, nil
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/clock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/test/mock/mock_actpool"
+	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
+	"github.com/iotexproject/iotex-core/test/mock/mock_network"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func readJournal(t *testing.T, path string) []*journalRecord {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	records := make([]*journalRecord, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record journalRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, &record)
+	}
+	require.NoError(t, scanner.Err())
+	return records
+}
+
+func TestJournalRotate(t *testing.T) {
+	require := require.New(t)
+
+	path := "/tmp/test-rolldpos.journal"
+	paths := []string{path, path + ".1", path + ".2", path + ".3"}
+	for _, p := range paths {
+		testutil.CleanupPath(t, p)
+		defer testutil.CleanupPath(t, p)
+	}
+
+	record := &journalRecord{Event: eRollDelegates, Src: sEpochStart, Dst: sDKGGeneration}
+	data, err := json.Marshal(record)
+	require.NoError(err)
+	j, err := openJournal(path, uint64(2*(len(data)+1)), 2)
+	require.NoError(err)
+	for i := 0; i < 7; i++ {
+		require.NoError(j.write(record))
+	}
+	require.NoError(j.close())
+
+	// Each file holds 2 records, and the oldest 2 records are removed with the 3rd rotated file
+	require.Equal(1, len(readJournal(t, paths[0])))
+	require.Equal(2, len(readJournal(t, paths[1])))
+	require.Equal(2, len(readJournal(t, paths[2])))
+	_, err = os.Stat(paths[3])
+	require.True(os.IsNotExist(err))
+	require.Equal(*record, *readJournal(t, paths[1])[0])
+
+	// Reopening appends to the current file
+	j, err = openJournal(path, uint64(2*(len(data)+1)), 2)
+	require.NoError(err)
+	require.NoError(j.write(record))
+	require.NoError(j.close())
+	require.Equal(2, len(readJournal(t, paths[0])))
+}
+
+func TestJournalReplay(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	path := "/tmp/test-rolldpos-replay.journal"
+	testutil.CleanupPath(t, path)
+	defer testutil.CleanupPath(t, path)
+
+	newRollDPoS := func() *RollDPoS {
+		r, err := NewRollDPoSBuilder().
+			SetConfig(config.RollDPoS{EventChanSize: 10}).
+			SetAddr(testAddrs[0]).
+			SetBlockchain(mock_blockchain.NewMockBlockchain(ctrl)).
+			SetActPool(mock_actpool.NewMockActPool(ctrl)).
+			SetP2P(mock_network.NewMockOverlay(ctrl)).
+			SetClock(clock.NewMock()).
+			Build()
+		require.NoError(err)
+		return r
+	}
+
+	// Journal the events handled by a node
+	r := newRollDPoS()
+	clk := r.ctx.clock.(*clock.Mock)
+	clk.Add(time.Hour)
+	j, err := openJournal(path, 0, 0)
+	require.NoError(err)
+	r.cfsm.journal = j
+	blk := blockchain.NewBlock(
+		config.Default.Chain.ID,
+		2,
+		hash.ZeroHash32B,
+		testutil.TimestampNowFromClock(clk),
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	require.NoError(blk.SignBlock(testAddrs[1]))
+	eEvt, err := newEndorseEvt(endorseCommit, blk.HashBlock(), true, 2, testAddrs[1], clk)
+	require.NoError(err)
+	evts := []iConsensusEvt{
+		r.cfsm.newBackdoorEvt(sRoundStart),
+		newProposeBlkEvt(blk, clk),
+		eEvt,
+		r.cfsm.newBackdoorEvt(sEpochStart),
+		r.cfsm.newTimeoutEvt(eEndorseCommitTimeout, 2),
+	}
+	for _, evt := range evts {
+		clk.Add(time.Second)
+		r.cfsm.handle(evt)
+	}
+	require.NoError(r.cfsm.journal.close())
+
+	records := readJournal(t, path)
+	require.Equal(len(evts), len(records))
+	require.Equal(sRoundStart, records[0].Dst)
+	require.NotEmpty(records[1].Err)
+	evt, err := r.cfsm.event(records[1])
+	require.NoError(err)
+	pbEvt, ok := evt.(*proposeBlkEvt)
+	require.True(ok)
+	require.Equal(blk.HashBlock(), pbEvt.block.HashBlock())
+	require.Equal(records[1].EventTime, pbEvt.timestamp())
+	evt, err = r.cfsm.event(records[2])
+	require.NoError(err)
+	require.Equal(eEvt.endorse, evt.(*endorseEvt).endorse)
+
+	// The replay reproduces the same transitions with a fresh FSM
+	file, err := os.Open(path)
+	require.NoError(err)
+	defer file.Close()
+	var trace bytes.Buffer
+	mismatches, err := newRollDPoS().Replay(context.Background(), file, &trace)
+	require.NoError(err)
+	require.Equal(0, mismatches)
+	lines := strings.Split(strings.TrimSpace(trace.String()), "\n")
+	require.Equal(len(evts), len(lines))
+	require.Contains(lines[0], "E_BACKDOOR S_EPOCH_START -> S_ROUND_START")
+	require.Contains(lines[1], "E_PROPOSE_BLOCK S_ROUND_START -> ")
+
+	// A different transition is reported
+	records[3].Dst = sAcceptPropose
+	var journal bytes.Buffer
+	encoder := json.NewEncoder(&journal)
+	for _, record := range records {
+		require.NoError(encoder.Encode(record))
+	}
+	trace.Reset()
+	mismatches, err = newRollDPoS().Replay(context.Background(), &journal, &trace)
+	require.NoError(err)
+	require.Equal(1, mismatches)
+	require.Contains(trace.String(), "MISMATCH, journaled S_ROUND_START -> S_ACCEPT_PROPOSE")
+
+	_, err = newRollDPoS().Replay(context.Background(), strings.NewReader("not a journal"), &trace)
+	require.Error(err)
+}
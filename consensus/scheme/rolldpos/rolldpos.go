@@ -22,6 +22,7 @@ import (
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/consensus/scheme"
 	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/db"
 	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/network"
@@ -38,12 +39,21 @@ var (
 		},
 		[]string{},
 	)
+	productionLatencyMtc = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "iotex_consensus_block_production_latency",
+			Help:    "Latency in seconds of minting the block to propose",
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 12),
+		},
+		[]string{"type"},
+	)
 )
 
 const sigSize = 5 // number of uint32s in BLS sig
 
 func init() {
 	prometheus.MustRegister(timeSlotMtc)
+	prometheus.MustRegister(productionLatencyMtc)
 }
 
 var (
@@ -65,6 +75,9 @@ type rollDPoSCtx struct {
 	// candidatesByHeightFunc is only used for testing purpose
 	candidatesByHeightFunc func(uint64) ([]*state.Candidate, error)
 	sync                   blocksync.BlockSync
+	dao                    *stateDAO
+	// keyCache caches the DKG keys of the delegates, which the aggregate endorses are verified against
+	keyCache blsKeyCache
 }
 
 var (
@@ -74,6 +87,17 @@ var (
 
 // rollingDelegates will only allows the delegates chosen for given epoch to enter the epoch
 func (ctx *rollDPoSCtx) rollingDelegates(epochNum uint64) ([]string, error) {
+	candidatesAddress, err := ctx.epochCandidates(epochNum)
+	if err != nil {
+		return []string{}, err
+	}
+	crypto.SortCandidates(candidatesAddress, epochNum, ctx.epoch.seed)
+
+	return candidatesAddress[:ctx.cfg.NumDelegates], nil
+}
+
+// epochCandidates returns the addresses of the candidates which the delegates of the epoch are picked from
+func (ctx *rollDPoSCtx) epochCandidates(epochNum uint64) ([]string, error) {
 	numDlgs := ctx.cfg.NumDelegates
 	height := uint64(numDlgs) * uint64(ctx.cfg.NumSubEpochs) * (epochNum - 1)
 	var candidates []*state.Candidate
@@ -95,20 +119,14 @@ func (ctx *rollDPoSCtx) rollingDelegates(epochNum uint64) ([]string, error) {
 	for _, candidate := range candidates {
 		candidatesAddress = append(candidatesAddress, candidate.Address)
 	}
-	crypto.SortCandidates(candidatesAddress, epochNum, ctx.epoch.seed)
-
-	return candidatesAddress[:numDlgs], nil
+	return candidatesAddress, nil
 }
 
 // calcEpochNum calculates the epoch ordinal number and the epoch start height offset, which is based on the height of
 // the next block to be produced
 func (ctx *rollDPoSCtx) calcEpochNumAndHeight() (uint64, uint64, error) {
-	height := ctx.chain.TipHeight()
-	numDlgs := ctx.cfg.NumDelegates
-	numSubEpochs := ctx.getNumSubEpochs()
-	epochNum := height/(uint64(numDlgs)*uint64(numSubEpochs)) + 1
-	epochHeight := uint64(numDlgs)*uint64(numSubEpochs)*(epochNum-1) + 1
-	return epochNum, epochHeight, nil
+	epochNum := ctx.epochNumOf(ctx.chain.TipHeight() + 1)
+	return epochNum, ctx.epochStartHeight(epochNum), nil
 }
 
 // calcSubEpochNum calculates the sub-epoch ordinal number
@@ -132,46 +150,30 @@ func (ctx *rollDPoSCtx) shouldHandleDKG() bool {
 
 // generateDKGSecrets generates DKG secrets and witness
 func (ctx *rollDPoSCtx) generateDKGSecrets() ([][]uint32, [][]byte, error) {
-	idList := make([][]uint8, 0)
-	for _, addr := range ctx.epoch.delegates {
-		dkgID := iotxaddress.CreateID(addr)
-		idList = append(idList, dkgID)
+	if err := validateDKGCommittee(len(ctx.epoch.delegates)); err != nil {
+		return nil, nil, err
+	}
+	idList := dkgIDs(ctx.epoch.delegates)
+	for i, addr := range ctx.epoch.delegates {
 		if addr == ctx.addr.RawAddress {
-			ctx.epoch.dkgAddress = iotxaddress.DKGAddress{ID: dkgID}
+			ctx.epoch.dkgAddress = iotxaddress.DKGAddress{ID: idList[i]}
 		}
 	}
 	_, secrets, witness, err := crypto.DKG.Init(crypto.DKG.SkGeneration(), idList)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "failed to generate DKG Secrets and Witness")
 	}
-	return secrets, witness, nil
+	// The secrets of the placeholder nodes are dropped
+	return secrets[:len(ctx.epoch.delegates)], witness, nil
 }
 
-// TODO: numDlgs should also be configurable in BLS. For test purpose, let's make it 21.
-// generateDKGKeyPair generates DKG key pair
+// generateDKGKeyPair generates DKG key pair from the secret shares dealt by the qualified dealers, which are the
+// delegates having committed their secret blocks without any complaint
 func (ctx *rollDPoSCtx) generateDKGKeyPair() ([]byte, []uint32, error) {
-	numDlgs := ctx.cfg.NumDelegates
-	if numDlgs != 21 {
-		return nil, nil, errors.New("Number of delegates must be 21 for test purpose")
-	}
-	shares := make([][]uint32, numDlgs)
-	shareStatusMatrix := make([][21]bool, numDlgs)
-	for i := range shares {
-		shares[i] = make([]uint32, sigSize)
-	}
-	for i, delegate := range ctx.epoch.delegates {
-		if secret, ok := ctx.epoch.committedSecrets[delegate]; ok {
-			shares[i] = secret
-			for j := 0; j < int(numDlgs); j++ {
-				shareStatusMatrix[j][i] = true
-			}
-		}
-	}
-	_, dkgPubKey, dkgPriKey, err := crypto.DKG.KeyPairGeneration(shares, shareStatusMatrix)
-	if err != nil {
-		return nil, nil, errors.Wrap(err, "failed to generate DKG key pair")
-	}
-	return dkgPubKey, dkgPriKey, nil
+	return dkgKeyPair(ctx.epoch.delegates, func(dealer string) ([]uint32, bool) {
+		secret, ok := ctx.epoch.committedSecrets[dealer]
+		return secret, ok && len(ctx.epoch.complaints[dealer]) == 0
+	})
 }
 
 // getNumSubEpochs returns max(configured number, 1)
@@ -192,18 +194,19 @@ func (ctx *rollDPoSCtx) rotatedProposer() (string, uint64, error) {
 	height := ctx.chain.TipHeight()
 	// Next block height
 	height++
-	proposer, err := ctx.calcProposer(height, ctx.epoch.delegates)
+	proposer, err := ctx.calcProposer(height, 0, ctx.epoch.delegates)
 	return proposer, height, err
 }
 
-// calcProposer calculates the proposer for the block at a given height
-func (ctx *rollDPoSCtx) calcProposer(height uint64, delegates []string) (string, error) {
+// calcProposer calculates the proposer for the block at a given height and round. Each round moves on to the next
+// delegate in the rotation
+func (ctx *rollDPoSCtx) calcProposer(height uint64, round uint32, delegates []string) (string, error) {
 	numDelegates := len(delegates)
 	if numDelegates == 0 {
 		return "", ErrZeroDelegate
 	}
 	if !ctx.cfg.TimeBasedRotation {
-		return delegates[(height)%uint64(numDelegates)], nil
+		return delegates[(height+uint64(round))%uint64(numDelegates)], nil
 	}
 	duration, err := ctx.calcDurationSinceLastBlock()
 	if err != nil {
@@ -216,15 +219,49 @@ func (ctx *rollDPoSCtx) calcProposer(height uint64, delegates []string) (string,
 	// TODO: should downgrade to debug level in the future
 	logger.Info().Int64("slot", timeSlotIndex).Msg("calculate time slot offset")
 	timeSlotMtc.WithLabelValues().Set(float64(timeSlotIndex))
-	return delegates[(height+uint64(timeSlotIndex))%uint64(numDelegates)], nil
+	return delegates[(height+uint64(timeSlotIndex)+uint64(round))%uint64(numDelegates)], nil
+}
+
+// maxRounds returns the max number of rounds at a height
+func (ctx *rollDPoSCtx) maxRounds() uint32 {
+	if ctx.cfg.MaxRoundsPerHeight <= 1 {
+		return 1
+	}
+	return uint32(ctx.cfg.MaxRoundsPerHeight)
+}
+
+// isRoundProposer checks if the address is the proposer of the given round at the height
+func (ctx *rollDPoSCtx) isRoundProposer(addr string, height uint64, round uint32) (bool, error) {
+	proposer, err := ctx.calcProposer(height, round, ctx.epoch.delegates)
+	if err != nil {
+		return false, err
+	}
+	return proposer == addr, nil
 }
 
 // mintBlock mints a new block to propose
 func (ctx *rollDPoSCtx) mintBlock() (*blockchain.Block, error) {
+	var blk *blockchain.Block
+	var err error
+	start := ctx.clock.Now()
+	blkType := "secret"
 	if ctx.shouldHandleDKG() {
-		return ctx.mintSecretBlock()
+		blk, err = ctx.mintSecretBlock()
+	} else {
+		blk, blkType, err = ctx.mintCommonBlockBeforeDeadline()
 	}
-	return ctx.mintCommonBlock()
+	if err != nil {
+		return nil, err
+	}
+	productionLatencyMtc.WithLabelValues(blkType).Observe(ctx.clock.Now().Sub(start).Seconds())
+	// The first block of the epoch carries the seed of the epoch on chain
+	if blk.Height() == ctx.epoch.height {
+		blk.Header.Seed = ctx.epoch.seed
+		if err := blk.SignBlock(ctx.addr); err != nil {
+			return nil, errors.Wrap(err, "error when signing the block carrying the seed")
+		}
+	}
+	return blk, nil
 }
 
 // mintSecretBlock collects DKG secret proposals and witness and creates a block to propose
@@ -263,15 +300,88 @@ func (ctx *rollDPoSCtx) mintSecretBlock() (*blockchain.Block, error) {
 	return blk, nil
 }
 
-// mintCommonBlock picks the actions and creates a common block to propose
-func (ctx *rollDPoSCtx) mintCommonBlock() (*blockchain.Block, error) {
+// productionDeadline returns the time left until the deadline of minting the block to propose in the current round,
+// which is negative once the deadline is exceeded. It returns false if there is no deadline.
+func (ctx *rollDPoSCtx) productionDeadline() (time.Duration, bool) {
+	if ctx.cfg.ProductionDeadline <= 0 || ctx.cfg.ProposerInterval <= 0 {
+		return 0, false
+	}
+	deadline := time.Duration(float64(ctx.cfg.ProposerInterval) * ctx.cfg.ProductionDeadline)
+	if ctx.cfg.AcceptProposeTTL > 0 && deadline > ctx.cfg.AcceptProposeTTL {
+		deadline = ctx.cfg.AcceptProposeTTL
+	}
+	return deadline - ctx.clock.Now().Sub(ctx.round.timestamp), true
+}
+
+// mintCommonBlockBeforeDeadline mints a common block with all the actions picked from the action pool. If it isn't
+// minted before the production deadline, it gives up waiting and mints a smaller block with the capped number of
+// actions instead, so that a slow proposer doesn't block the round for everyone. It returns the type of the block
+// minted as well.
+func (ctx *rollDPoSCtx) mintCommonBlockBeforeDeadline() (*blockchain.Block, string, error) {
+	left, ok := ctx.productionDeadline()
+	if !ok {
+		blk, err := ctx.mintCommonBlock(context.Background())
+		return blk, "common", err
+	}
+	if left > 0 {
+		type result struct {
+			blk *blockchain.Block
+			err error
+		}
+		// The minting in the background is cancelled once the deadline is exceeded, and the channel is buffered, so that
+		// it doesn't leak
+		mintCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		minted := make(chan result, 1)
+		go func() {
+			blk, err := ctx.mintCommonBlock(mintCtx)
+			minted <- result{blk: blk, err: err}
+		}()
+		select {
+		case r := <-minted:
+			return r.blk, "common", r.err
+		case <-ctx.clock.After(left):
+			cancel()
+		}
+	}
+	logger.Warn().
+		Uint64("height", ctx.round.height).
+		Uint32("round", ctx.round.number).
+		Uint64("maxActs", ctx.cfg.MaxActsAfterDeadline).
+		Msg("block production deadline exceeded, minting a smaller block")
+	blk, err := ctx.mintCappedBlock()
+	return blk, "capped", err
+}
+
+// mintCommonBlock picks the actions and creates a common block to propose, unless the minting is cancelled
+func (ctx *rollDPoSCtx) mintCommonBlock(mintCtx context.Context) (*blockchain.Block, error) {
 	transfers, votes, executions, actions := ctx.actPool.PickActs()
+	if err := mintCtx.Err(); err != nil {
+		return nil, errors.Wrap(err, "minting is cancelled after picking the actions")
+	}
+	return ctx.mintBlockWithActs(mintCtx, transfers, votes, executions, actions)
+}
+
+// mintCappedBlock picks at most MaxActsAfterDeadline actions and creates a common block to propose
+func (ctx *rollDPoSCtx) mintCappedBlock() (*blockchain.Block, error) {
+	transfers, votes, executions, actions := ctx.actPool.PickActsWithLimit(ctx.cfg.MaxActsAfterDeadline)
+	return ctx.mintBlockWithActs(context.Background(), transfers, votes, executions, actions)
+}
+
+// mintBlockWithActs creates a common block with the given actions to propose, unless the minting is cancelled
+func (ctx *rollDPoSCtx) mintBlockWithActs(
+	mintCtx context.Context,
+	transfers []*action.Transfer,
+	votes []*action.Vote,
+	executions []*action.Execution,
+	actions []action.Action,
+) (*blockchain.Block, error) {
 	logger.Debug().
 		Int("transfer", len(transfers)).
 		Int("votes", len(votes)).
 		Msg("pick actions from the action pool")
-	blk, err := ctx.chain.MintNewDKGBlock(transfers, votes, executions, actions, ctx.addr, &ctx.epoch.dkgAddress,
-		ctx.epoch.seed, "")
+	blk, err := ctx.chain.MintNewDKGBlock(mintCtx, transfers, votes, executions, actions, ctx.addr,
+		&ctx.epoch.dkgAddress, ctx.epoch.seed, "")
 	if err != nil {
 		return nil, err
 	}
@@ -294,6 +404,16 @@ func (ctx *rollDPoSCtx) calcDurationSinceLastBlock() (time.Duration, error) {
 	return ctx.clock.Now().Sub(blk.Header.Timestamp()), nil
 }
 
+// isDelegate checks if the address is one of the delegates of the current epoch
+func (ctx *rollDPoSCtx) isDelegate(addr string) bool {
+	for _, delegate := range ctx.epoch.delegates {
+		if delegate == addr {
+			return true
+		}
+	}
+	return false
+}
+
 // calcQuorum calculates if more than 2/3 vote yes or no including self's vote
 func (ctx *rollDPoSCtx) calcQuorum(decisions map[string]bool) (bool, bool) {
 	yes := 0
@@ -306,7 +426,12 @@ func (ctx *rollDPoSCtx) calcQuorum(decisions map[string]bool) (bool, bool) {
 		}
 	}
 	numDelegates := len(ctx.epoch.delegates)
-	return yes >= numDelegates*2/3+1, no >= numDelegates*1/3
+	return yes >= quorum(numDelegates), no >= numDelegates*1/3
+}
+
+// quorum returns the number of yes decisions needed out of the given number of delegates
+func quorum(numDelegates int) int {
+	return numDelegates*2/3 + 1
 }
 
 // isEpochFinished checks the epoch is finished or not
@@ -326,42 +451,36 @@ func (ctx *rollDPoSCtx) isDKGFinished() bool {
 	return height >= ctx.epoch.height+uint64(len(ctx.epoch.delegates))-1
 }
 
-// updateSeed returns the seed for the next epoch
+// updateSeed returns the seed of the epoch which the next block belongs to
 func (ctx *rollDPoSCtx) updateSeed() ([]byte, error) {
-	epochNum, epochHeight, err := ctx.calcEpochNumAndHeight()
+	epochNum, _, err := ctx.calcEpochNumAndHeight()
 	if err != nil {
-		return hash.Hash256b(ctx.epoch.seed), errors.Wrap(err, "Failed to do decode seed")
-	}
-	if epochNum <= 1 {
-		return crypto.CryptoSeed, nil
-	}
-	selectedID := make([][]uint8, 0)
-	selectedSig := make([][]byte, 0)
-	selectedPK := make([][]byte, 0)
-	for h := uint64(ctx.cfg.NumDelegates)*uint64(ctx.cfg.NumSubEpochs)*(epochNum-2) + 1; h < epochHeight && len(selectedID) <= crypto.Degree; h++ {
-		blk, err := ctx.chain.GetBlockByHeight(h)
-		if err != nil {
-			continue
-		}
-		if len(blk.Header.DKGID) > 0 && len(blk.Header.DKGPubkey) > 0 && len(blk.Header.DKGBlockSig) > 0 {
-			selectedID = append(selectedID, blk.Header.DKGID)
-			selectedSig = append(selectedSig, blk.Header.DKGBlockSig)
-			selectedPK = append(selectedPK, blk.Header.DKGPubkey)
-		}
+		return nil, errors.Wrap(err, "Failed to do decode seed")
 	}
+	return ctx.epochSeed(epochNum)
+}
 
-	if len(selectedID) <= crypto.Degree {
-		return hash.Hash256b(ctx.epoch.seed), errors.New("DKG signature/pubic key is not enough to aggregate")
+// loadEpoch restores the epoch which the node has entered before restarting
+func (ctx *rollDPoSCtx) loadEpoch() error {
+	epoch, err := ctx.dao.getEpoch()
+	if err != nil || epoch == nil {
+		return err
 	}
+	ctx.epoch.num = epoch.Num
+	ctx.epoch.height = epoch.Height
+	ctx.epoch.delegates = epoch.Delegates
+	ctx.epoch.seed = epoch.Seed
+	return nil
+}
 
-	aggregateSig, err := crypto.BLS.SignAggregate(selectedID, selectedSig)
-	if err != nil {
-		return hash.Hash256b(ctx.epoch.seed), errors.Wrap(err, "Failed to generate aggregate signature to update Seed")
-	}
-	if err = crypto.BLS.VerifyAggregate(selectedID, selectedPK, ctx.epoch.seed, aggregateSig); err != nil {
-		return hash.Hash256b(ctx.epoch.seed), errors.Wrap(err, "Failed to verify aggregate signature to update Seed")
-	}
-	return aggregateSig, nil
+// saveEpoch persists the epoch which the node enters
+func (ctx *rollDPoSCtx) saveEpoch() error {
+	return ctx.dao.putEpoch(&epochRecord{
+		Num:       ctx.epoch.num,
+		Height:    ctx.epoch.height,
+		Delegates: ctx.epoch.delegates,
+		Seed:      ctx.epoch.seed,
+	})
 }
 
 // epochCtx keeps the context data for the current epoch
@@ -380,9 +499,11 @@ type epochCtx struct {
 	witness [][]byte
 	// committedSecrets are the secret shares within the secret blocks committed by current node
 	committedSecrets map[string][]uint32
-	delegates        []string
-	dkgAddress       iotxaddress.DKGAddress
-	seed             []byte
+	// complaints are the complaints against the dealers of the secret blocks, which disqualify them
+	complaints map[string][]*dkgComplaint
+	delegates  []string
+	dkgAddress iotxaddress.DKGAddress
+	seed       []byte
 }
 
 // roundCtx keeps the context data for the current round and block.
@@ -390,19 +511,83 @@ type roundCtx struct {
 	height           uint64
 	timestamp        time.Time
 	block            *blockchain.Block
-	proposalEndorses map[hash.Hash32B]map[string]bool
+	proposalEndorses map[roundHash]map[string]bool
 	commitEndorses   map[hash.Hash32B]map[string]bool
-	proposer         string
+	// certificates are the yes commit endorsements, which are embedded into the committed block
+	certificates map[hash.Hash32B][]*blockchain.Endorsement
+	proposer     string
+	// number is the ordinal number of the round at the height, starting from 0
+	number uint32
+	// locked is the block which the node has endorsed to commit at the height. Once locked, the node only endorses the
+	// same block in the later rounds, and proposes it again when it's the proposer
+	locked *blockchain.Block
+	// proposerAddr is the network address of the proposer of the round, which the endorses are sent to for aggregation
+	proposerAddr string
+	// shares are the endorses carrying the BLS signature shares, which the proposer aggregates
+	shares map[shareKey]map[string]*endorse
+	// aggregates are the BLS aggregates of the yes commit endorses, which are embedded into the committed block
+	aggregates map[hash.Hash32B]*blockchain.AggregateEndorsement
+	// pendingProposal is the proposal of the next round, which is handled once the node moves on to the round
+	pendingProposal *proposeBlkEvt
+}
+
+// roundHash identifies the proposal endorses on a block in a round
+type roundHash struct {
+	round   uint32
+	blkHash hash.Hash32B
+}
+
+// proposalEndorsesOf returns the proposal endorses on the block in the given round
+func (round *roundCtx) proposalEndorsesOf(number uint32, blkHash hash.Hash32B) map[string]bool {
+	key := roundHash{round: number, blkHash: blkHash}
+	if round.proposalEndorses[key] == nil {
+		round.proposalEndorses[key] = make(map[string]bool)
+	}
+	return round.proposalEndorses[key]
+}
+
+// commitEndorsesOf returns the commit endorses on the block
+func (round *roundCtx) commitEndorsesOf(blkHash hash.Hash32B) map[string]bool {
+	if round.commitEndorses[blkHash] == nil {
+		round.commitEndorses[blkHash] = make(map[string]bool)
+	}
+	return round.commitEndorses[blkHash]
+}
+
+// proposal returns the block of the given hash, which is either proposed in the current round or locked in an earlier
+// one
+func (round *roundCtx) proposal(blkHash hash.Hash32B) *blockchain.Block {
+	if round.block != nil && round.block.HashBlock() == blkHash {
+		return round.block
+	}
+	if round.locked != nil && round.locked.HashBlock() == blkHash {
+		return round.locked
+	}
+	return nil
 }
 
 // RollDPoS is Roll-DPoS consensus main entrance
 type RollDPoS struct {
-	cfsm *cFSM
-	ctx  *rollDPoSCtx
+	cfsm     *cFSM
+	ctx      *rollDPoSCtx
+	evidence *evidenceCollector
 }
 
 // Start starts RollDPoS consensus
 func (r *RollDPoS) Start(ctx context.Context) error {
+	if err := r.ctx.dao.kvstore.Start(ctx); err != nil {
+		return errors.Wrap(err, "error when starting the consensus state DB")
+	}
+	if err := r.ctx.loadEpoch(); err != nil {
+		return errors.Wrap(err, "error when loading the epoch from the consensus state DB")
+	}
+	if r.ctx.cfg.JournalPath != "" {
+		journal, err := openJournal(r.ctx.cfg.JournalPath, r.ctx.cfg.JournalMaxSize, r.ctx.cfg.JournalMaxFiles)
+		if err != nil {
+			return errors.Wrap(err, "error when opening the consensus journal")
+		}
+		r.cfsm.journal = journal
+	}
 	if err := r.cfsm.Start(ctx); err != nil {
 		return errors.Wrap(err, "error when starting the consensus FSM")
 	}
@@ -412,29 +597,80 @@ func (r *RollDPoS) Start(ctx context.Context) error {
 
 // Stop stops RollDPoS consensus
 func (r *RollDPoS) Stop(ctx context.Context) error {
-	return errors.Wrap(r.cfsm.Stop(ctx), "error when stopping the consensus FSM")
+	if err := r.cfsm.Stop(ctx); err != nil {
+		return errors.Wrap(err, "error when stopping the consensus FSM")
+	}
+	if r.cfsm.journal != nil {
+		if err := r.cfsm.journal.close(); err != nil {
+			return errors.Wrap(err, "error when closing the consensus journal")
+		}
+		r.cfsm.journal = nil
+	}
+	return errors.Wrap(r.ctx.dao.kvstore.Stop(ctx), "error when stopping the consensus state DB")
 }
 
-// HandleBlockPropose handles incoming block propose
+// HandleBlockPropose handles incoming block propose. The proposal has to be signed by one of the delegates of the
+// height, and the one proposing another block in the same round gets reported with the double sign evidence.
 func (r *RollDPoS) HandleBlockPropose(propose *iproto.ProposePb) error {
 	pbEvt, err := r.cfsm.newProposeBlkEvtFromProposePb(propose)
 	if err != nil {
 		return errors.Wrap(err, "error when casting a proto msg to proposeBlkEvt")
 	}
+	if !pbEvt.VerifySignature() {
+		return errors.Wrapf(network.ErrBadSignature, "proposal of round %d from %s", pbEvt.round, pbEvt.proposer)
+	}
+	height := pbEvt.block.Height()
+	ok, err := r.ctx.isEndorser(pbEvt.proposer, height)
+	if err != nil {
+		return errors.Wrapf(err, "error when checking the proposer of block %d", height)
+	}
+	if !ok {
+		return errors.Wrapf(network.ErrInvalidMsg, "%s is not a delegate of block %d", pbEvt.proposer, height)
+	}
+	r.collectProposalEvidence(pbEvt)
 	r.cfsm.produce(pbEvt, 0)
 	return nil
 }
 
-// HandleEndorse handles incoming endorse
+// HandleEndorse handles incoming endorse. The signature and the endorser, which has to be eligible to endorse the block,
+// are verified before the endorse goes into the FSM, so that the peer sending a forged one gets penalized. The endorse
+// whose endorsers aren't known yet, e.g., the one of a future epoch, is dropped without blaming the peer. A commit
+// endorse conflicting with another one of the same endorser gets it reported with the double sign evidence. An
+// aggregate endorse is verified against the DKG keys of the endorsers in it instead, and counts as the endorse of each
+// of them.
 func (r *RollDPoS) HandleEndorse(ePb *iproto.EndorsePb) error {
 	eEvt, err := r.cfsm.newEndorseEvtWithEndorsePb(ePb)
 	if err != nil {
-		return errors.Wrap(err, "error when casting a proto msg to endorse")
+		return errors.Wrap(network.ErrInvalidMsg, err.Error())
+	}
+	en := eEvt.endorse
+	if en.aggregate != nil {
+		return r.handleAggregateEndorse(en)
+	}
+	if !en.VerifySignature(en.endorserPubkey) {
+		return errors.Wrapf(network.ErrBadSignature, "endorse of block %d from %s", en.height, en.endorser)
+	}
+	ok, err := r.ctx.isEndorser(en.endorser, en.height)
+	if err != nil {
+		return errors.Wrapf(err, "error when checking the endorser of block %d", en.height)
+	}
+	if !ok {
+		return errors.Wrapf(network.ErrInvalidMsg, "%s is not an endorser of block %d", en.endorser, en.height)
+	}
+	if en.topic == endorseCommit {
+		r.collectEvidence(en)
 	}
 	r.cfsm.produce(eEvt, 0)
 	return nil
 }
 
+// Endorsers returns the addresses eligible to endorse the block at the given height, and the number of endorsements
+// needed to commit it. The endorsers are the delegates of the epoch, picked with the seed on the chain, so the
+// previous epoch has to be committed before the certificates of the epoch could be checked.
+func (r *RollDPoS) Endorsers(height uint64) ([]string, int, error) {
+	return r.ctx.endorsers(height)
+}
+
 // SetDoneStream does nothing for Noop (only used in simulator)
 func (r *RollDPoS) SetDoneStream(simMsgReady chan bool) {}
 
@@ -454,7 +690,7 @@ func (r *RollDPoS) Metrics() (scheme.ConsensusMetrics, error) {
 	// Compute the height
 	height := r.ctx.chain.TipHeight()
 	// Compute block producer
-	producer, err := r.ctx.calcProposer(height+1, delegates)
+	producer, err := r.ctx.calcProposer(height+1, 0, delegates)
 	if err != nil {
 		return metrics, errors.Wrap(err, "error when calculating the block producer")
 	}
@@ -499,6 +735,7 @@ type Builder struct {
 	p2p                    network.Overlay
 	clock                  clock.Clock
 	candidatesByHeightFunc func(uint64) ([]*state.Candidate, error)
+	stateDB                db.KVStore
 }
 
 // NewRollDPoSBuilder instantiates a Builder instance
@@ -542,6 +779,12 @@ func (b *Builder) SetClock(clock clock.Clock) *Builder {
 	return b
 }
 
+// SetStateDB sets the DB persisting the consensus state
+func (b *Builder) SetStateDB(stateDB db.KVStore) *Builder {
+	b.stateDB = stateDB
+	return b
+}
+
 // SetCandidatesByHeightFunc sets candidatesByHeightFunc, which is only used by tests
 func (b *Builder) SetCandidatesByHeightFunc(
 	candidatesByHeightFunc func(uint64) ([]*state.Candidate, error),
@@ -564,6 +807,10 @@ func (b *Builder) Build() (*RollDPoS, error) {
 	if b.clock == nil {
 		b.clock = clock.New()
 	}
+	if b.stateDB == nil {
+		// The consensus state is not persisted across restarts
+		b.stateDB = db.NewMemKVStore()
+	}
 	ctx := rollDPoSCtx{
 		cfg:     b.cfg,
 		addr:    b.addr,
@@ -572,13 +819,15 @@ func (b *Builder) Build() (*RollDPoS, error) {
 		p2p:     b.p2p,
 		clock:   b.clock,
 		candidatesByHeightFunc: b.candidatesByHeightFunc,
+		dao:                    newStateDAO(b.stateDB),
 	}
 	cfsm, err := newConsensusFSM(&ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "error when constructing the consensus FSM")
 	}
 	return &RollDPoS{
-		cfsm: cfsm,
-		ctx:  &ctx,
+		cfsm:     cfsm,
+		ctx:      &ctx,
+		evidence: newEvidenceCollector(),
 	}, nil
 }
@@ -7,7 +7,6 @@
 package rolldpos
 
 import (
-	"encoding/hex"
 	"fmt"
 	"math/big"
 	"net"
@@ -24,10 +23,12 @@ import (
 
 	"github.com/iotexproject/iotex-core/actpool"
 	"github.com/iotexproject/iotex-core/address"
+	"github.com/iotexproject/iotex-core/beacon"
 	"github.com/iotexproject/iotex-core/blockchain"
 	"github.com/iotexproject/iotex-core/blockchain/action"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/db"
 	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/network/node"
 	"github.com/iotexproject/iotex-core/pkg/hash"
@@ -503,7 +504,8 @@ func TestRollDPoS_convertToConsensusEvt(t *testing.T) {
 
 func TestUpdateSeed(t *testing.T) {
 	require := require.New(t)
-	lastSeed, _ := hex.DecodeString("9de6306b08158c423330f7a27243a1a5cbe39bfd764f07818437882d21241567")
+	// The blocks of the first epoch are signed over the genesis seed
+	lastSeed := crypto.CryptoSeed
 	chain := blockchain.NewBlockchain(&config.Default, blockchain.InMemStateFactoryOption(), blockchain.InMemDaoOption())
 	require.NoError(chain.Start(context.Background()))
 	ctx := rollDPoSCtx{cfg: config.Default.Consensus.RollDPoS, chain: chain, epoch: epochCtx{seed: lastSeed}}
@@ -580,7 +582,7 @@ func TestUpdateSeed(t *testing.T) {
 			PrivateKey: ec283SKList[i],
 			RawAddress: addresses[i],
 		}
-		blk, err := chain.MintNewDKGBlock(nil, nil, nil, nil, &iotxAddr,
+		blk, err := chain.MintNewDKGBlock(context.Background(), nil, nil, nil, nil, &iotxAddr,
 			&iotxaddress.DKGAddress{PrivateKey: askList[i], PublicKey: pkList[i], ID: idList[i]},
 			lastSeed, "")
 		require.NoError(err)
@@ -598,8 +600,107 @@ func TestUpdateSeed(t *testing.T) {
 	require.NoError(err)
 	require.True(len(newSeed) > 0)
 	require.NotEqual(fsm.ctx.epoch.seed, newSeed)
+	// The DKG keys aren't dealt on the chain, so the shares don't count and the seed is the hash of the last one
+	require.Equal(hash.Hash256b(lastSeed), newSeed)
 	fmt.Println(fsm.ctx.epoch.seed)
 	fmt.Println(newSeed)
+
+	// The first block of the next epoch carries the seed, which is verified from the block data
+	producer := iotxaddress.Address{
+		PublicKey:  ec283PKList[0],
+		PrivateKey: ec283SKList[0],
+		RawAddress: addresses[0],
+	}
+	blk, err := chain.MintNewBlock(nil, nil, nil, nil, &producer, "")
+	require.NoError(err)
+	ctx.epoch.height = blk.Height()
+	ctx.epoch.seed = newSeed
+	require.Error(ctx.validateBlockSeed(blk))
+	blk.Header.Seed = newSeed
+	require.NoError(blk.SignBlock(&producer))
+	require.NoError(ctx.validateBlockSeed(blk))
+	require.NoError(chain.ValidateBlock(blk, true))
+	require.NoError(chain.CommitBlock(blk))
+	seed, err := ctx.verifySeed(2)
+	require.NoError(err)
+	require.Equal(newSeed, seed)
+	seed, err = ctx.epochSeed(2)
+	require.NoError(err)
+	require.Equal(newSeed, seed)
+	seed, err = ctx.verifySeed(1)
+	require.NoError(err)
+	require.Equal(crypto.CryptoSeed, seed)
+	_, err = ctx.verifySeed(3)
+	require.Error(err)
+	ctx.epoch.height = 1
+	require.Equal(beacon.ErrInvalidSeed, errors.Cause(ctx.validateBlockSeed(blk)))
+}
+
+func TestMintCommonBlockBeforeDeadline(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	addr := newTestAddr()
+	blk := blockchain.NewBlock(config.Default.Chain.ID, 2, hash.ZeroHash32B, 0, nil, nil, nil, nil)
+	// release unblocks the slow picking of all the actions
+	release := make(chan struct{})
+	defer close(release)
+	var actPool *mock_actpool.MockActPool
+	ctx := makeTestRollDPoSCtx(
+		addr,
+		ctrl,
+		config.RollDPoS{
+			ProposerInterval:     100 * time.Millisecond,
+			AcceptProposeTTL:     time.Second,
+			ProductionDeadline:   0,
+			MaxActsAfterDeadline: 2,
+		},
+		func(chain *mock_blockchain.MockBlockchain) {
+			chain.EXPECT().
+				MintNewDKGBlock(
+					gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+					gomock.Any(),
+				).
+				Return(blk, nil).
+				AnyTimes()
+		},
+		func(ap *mock_actpool.MockActPool) { actPool = ap },
+		func(_ *mock_network.MockOverlay) {},
+		clock.New(),
+	)
+
+	// There is no deadline
+	actPool.EXPECT().PickActs().Return(nil, nil, nil, nil).Times(1)
+	minted, blkType, err := ctx.mintCommonBlockBeforeDeadline()
+	require.NoError(t, err)
+	require.Equal(t, blk, minted)
+	require.Equal(t, "common", blkType)
+
+	// The block is minted before the deadline
+	ctx.cfg.ProductionDeadline = 0.5
+	ctx.round.timestamp = ctx.clock.Now()
+	actPool.EXPECT().PickActs().Return(nil, nil, nil, nil).Times(1)
+	_, blkType, err = ctx.mintCommonBlockBeforeDeadline()
+	require.NoError(t, err)
+	require.Equal(t, "common", blkType)
+
+	// The deadline is exceeded before minting
+	ctx.round.timestamp = ctx.clock.Now().Add(-50 * time.Millisecond)
+	actPool.EXPECT().PickActsWithLimit(uint64(2)).Return(nil, nil, nil, nil).Times(1)
+	_, blkType, err = ctx.mintCommonBlockBeforeDeadline()
+	require.NoError(t, err)
+	require.Equal(t, "capped", blkType)
+
+	// The deadline is exceeded while minting
+	ctx.round.timestamp = ctx.clock.Now()
+	actPool.EXPECT().PickActs().Do(func() { <-release }).Return(nil, nil, nil, nil).Times(1)
+	actPool.EXPECT().PickActsWithLimit(uint64(2)).Return(nil, nil, nil, nil).Times(1)
+	_, blkType, err = ctx.mintCommonBlockBeforeDeadline()
+	require.NoError(t, err)
+	require.Equal(t, "capped", blkType)
+	require.True(t, ctx.clock.Now().Sub(ctx.round.timestamp) >= 50*time.Millisecond)
 }
 
 func makeTestRollDPoSCtx(
@@ -624,6 +725,7 @@ func makeTestRollDPoSCtx(
 		actPool: actPool,
 		p2p:     p2p,
 		clock:   clock,
+		dao:     newStateDAO(db.NewMemKVStore()),
 	}
 }
 
@@ -660,6 +762,10 @@ func (o *directOverlay) Tell(uint32, net.Addr, proto.Message) error { return nil
 
 func (o *directOverlay) Self() net.Addr { return o.addr }
 
+func (o *directOverlay) PeerScore(net.Addr) int { return 0 }
+
+func (o *directOverlay) ReportPeer(net.Addr, uint32, error) {}
+
 func (o *directOverlay) GetPeers() []net.Addr {
 	addrs := make([]net.Addr, 0, len(o.peers))
 	for addr := range o.peers {
@@ -989,3 +1095,59 @@ func TestRollDPoSConsensus(t *testing.T) {
 		}
 	})
 }
+
+func TestRollDPoSEndorsers(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	candidates := make([]string, 5)
+	for i := 0; i < len(candidates); i++ {
+		candidates[i] = testAddrs[i].RawAddress
+	}
+	seed := []byte{1, 2, 3}
+	blk := blockchain.NewBlock(0, 9, hash.ZeroHash32B, testutil.TimestampNow(), nil, nil, nil, nil)
+	blk.Header.Seed = seed
+	ctx := makeTestRollDPoSCtx(
+		testAddrs[0],
+		ctrl,
+		config.RollDPoS{
+			NumSubEpochs: 2,
+			NumDelegates: 4,
+		},
+		func(blockchain *mock_blockchain.MockBlockchain) {
+			// Block 9 is in the second epoch, whose delegates are picked from the candidates at height 8 with the seed
+			// carried by block 9
+			blockchain.EXPECT().CandidatesByHeight(uint64(8)).Return([]*state.Candidate{
+				{Address: candidates[0]},
+				{Address: candidates[1]},
+				{Address: candidates[2]},
+				{Address: candidates[3]},
+				{Address: candidates[4]},
+			}, nil).Times(2)
+			blockchain.EXPECT().TipHeight().Return(uint64(9)).Times(1)
+			blockchain.EXPECT().GetBlockByHeight(uint64(9)).Return(blk, nil).Times(1)
+			// The seed of the second epoch can't be derived before the first epoch is finished
+			blockchain.EXPECT().TipHeight().Return(uint64(7)).Times(2)
+		},
+		func(_ *mock_actpool.MockActPool) {},
+		func(_ *mock_network.MockOverlay) {},
+		clock.NewMock(),
+	)
+	r := &RollDPoS{ctx: ctx}
+
+	endorsers, quorum, err := r.Endorsers(9)
+	require.NoError(t, err)
+	expected := make([]string, len(candidates))
+	copy(expected, candidates)
+	crypto.SortCandidates(expected, 2, seed)
+	assert.Equal(t, expected[:4], endorsers)
+	assert.Equal(t, 3, quorum)
+
+	_, _, err = r.Endorsers(9)
+	assert.Error(t, err)
+
+	_, _, err = r.Endorsers(0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,279 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/beacon"
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/logger"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+)
+
+// epochStartHeight returns the height of the first block of the epoch
+func (ctx *rollDPoSCtx) epochStartHeight(epochNum uint64) uint64 {
+	return uint64(ctx.cfg.NumDelegates)*uint64(ctx.getNumSubEpochs())*(epochNum-1) + 1
+}
+
+// epochNumOf returns the number of the epoch which the block of the height belongs to, and the height must be positive
+func (ctx *rollDPoSCtx) epochNumOf(height uint64) uint64 {
+	return (height-1)/(uint64(ctx.cfg.NumDelegates)*uint64(ctx.getNumSubEpochs())) + 1
+}
+
+// epochSeed returns the seed of the epoch. Once the first block of the epoch is committed, the seed is the one carried
+// by the block, otherwise it's derived from the seed of the previous epoch and the DKG signature shares over it.
+func (ctx *rollDPoSCtx) epochSeed(epochNum uint64) ([]byte, error) {
+	if epochNum <= 1 {
+		return crypto.CryptoSeed, nil
+	}
+	height := ctx.epochStartHeight(epochNum)
+	if height <= ctx.chain.TipHeight() {
+		blk, err := ctx.chain.GetBlockByHeight(height)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error when getting the first block of epoch %d", epochNum)
+		}
+		if len(blk.Header.Seed) > 0 {
+			return blk.Header.Seed, nil
+		}
+	}
+	return ctx.deriveSeed(epochNum)
+}
+
+// epochDelegates returns the delegates of the epoch, which are picked from the candidates with the seed of the epoch
+func (ctx *rollDPoSCtx) epochDelegates(epochNum uint64) ([]string, error) {
+	candidates, err := ctx.epochCandidates(epochNum)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := ctx.epochSeed(epochNum)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when getting the seed to pick the delegates of epoch %d", epochNum)
+	}
+	crypto.SortCandidates(candidates, epochNum, seed)
+	return candidates[:ctx.cfg.NumDelegates], nil
+}
+
+// dkgPubKeys derives the DKG public keys of the delegates of the epoch from the secret blocks committed in its DKG
+// stage. The secret shares are carried by the blocks in plaintext, and only the dealers whose shares all match their
+// witnesses are qualified, so anyone could tell the key which each delegate should sign the seed with.
+func (ctx *rollDPoSCtx) dkgPubKeys(epochNum uint64) (map[string][]byte, error) {
+	pubkeys := make(map[string][]byte)
+	if !ctx.cfg.EnableDKG {
+		return pubkeys, nil
+	}
+	delegates, err := ctx.epochDelegates(epochNum)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when getting the DKG committee of epoch %d", epochNum)
+	}
+	if err := validateDKGCommittee(len(delegates)); err != nil {
+		return pubkeys, nil
+	}
+	dealt := make(map[string]map[string][]uint32)
+	start := ctx.epochStartHeight(epochNum)
+	for h := start; h < start+uint64(len(delegates)); h++ {
+		blk, err := ctx.chain.GetBlockByHeight(h)
+		if err != nil {
+			// The block of the height may not exist if the consensus wasn't reached
+			continue
+		}
+		if blk.SecretWitness == nil || len(verifySecretBlock(blk, delegates)) > 0 {
+			continue
+		}
+		dealer := blk.ProducerAddress()
+		secrets := make(map[string][]uint32, len(blk.SecretProposals))
+		for _, sp := range blk.SecretProposals {
+			if sp.SrcAddr() == dealer {
+				secrets[sp.DstAddr()] = sp.Secret()
+			}
+		}
+		dealt[dealer] = secrets
+	}
+	if len(dealt) == 0 {
+		return pubkeys, nil
+	}
+	for _, delegate := range delegates {
+		pubkey, _, err := dkgKeyPair(delegates, func(dealer string) ([]uint32, bool) {
+			secret, ok := dealt[dealer][delegate]
+			return secret, ok
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "error when deriving the DKG key of %s in epoch %d", delegate, epochNum)
+		}
+		pubkeys[delegate] = pubkey
+	}
+	return pubkeys, nil
+}
+
+// deriveSeed derives the seed of the epoch from the seed of the previous epoch and the DKG signature shares over it,
+// which the delegates publish in the blocks of the previous epoch. A share only counts if it's signed with the key dealt
+// to the producer of the block in the DKG of the previous epoch, and only the first valid share of each producer counts.
+func (ctx *rollDPoSCtx) deriveSeed(epochNum uint64) ([]byte, error) {
+	if ctx.epochStartHeight(epochNum)-1 > ctx.chain.TipHeight() {
+		return nil, errors.Errorf("epoch %d isn't finished to derive the seed of epoch %d", epochNum-1, epochNum)
+	}
+	prevSeed, err := ctx.epochSeed(epochNum - 1)
+	if err != nil {
+		return nil, err
+	}
+	pubkeys, err := ctx.dkgPubKeys(epochNum - 1)
+	if err != nil {
+		return nil, err
+	}
+	shares := make([]*beacon.Share, 0)
+	signed := make(map[string]bool)
+	for h := ctx.epochStartHeight(epochNum - 1); h < ctx.epochStartHeight(epochNum); h++ {
+		blk, err := ctx.chain.GetBlockByHeight(h)
+		if err != nil {
+			// The block of the height may not exist if the consensus wasn't reached
+			continue
+		}
+		producer := blk.ProducerAddress()
+		if signed[producer] || len(blk.Header.DKGBlockSig) == 0 {
+			continue
+		}
+		if !bytes.Equal(blk.Header.DKGID, iotxaddress.CreateID(producer)) ||
+			!bytes.Equal(blk.Header.DKGPubkey, pubkeys[producer]) {
+			logger.Warn().Uint64("height", h).Str("producer", producer).Msg("DKG key of the block isn't dealt to the producer")
+			continue
+		}
+		if err := verifyDKGSignature(blk, prevSeed); err != nil {
+			logger.Warn().Err(err).Uint64("height", h).Msg("invalid DKG signature over the seed")
+			continue
+		}
+		signed[producer] = true
+		shares = append(shares, &beacon.Share{
+			ID:        blk.Header.DKGID,
+			PublicKey: blk.Header.DKGPubkey,
+			Signature: blk.Header.DKGBlockSig,
+		})
+	}
+	seed, err := beacon.DeriveSeed(prevSeed, shares)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when deriving the seed of epoch %d", epochNum)
+	}
+	return seed, nil
+}
+
+// verifySeed verifies the seed carried by the first block of the epoch against the one derived from the block data,
+// and returns the seed of the epoch
+func (ctx *rollDPoSCtx) verifySeed(epochNum uint64) ([]byte, error) {
+	height := ctx.epochStartHeight(epochNum)
+	if height > ctx.chain.TipHeight() {
+		return nil, errors.Errorf("epoch %d hasn't started yet", epochNum)
+	}
+	blk, err := ctx.chain.GetBlockByHeight(height)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when getting the first block of epoch %d", epochNum)
+	}
+	seed, err := ctx.derivedSeed(epochNum)
+	if err != nil {
+		return nil, err
+	}
+	// The first block of the epoch may not carry the seed, if it's a dummy block
+	if len(blk.Header.Seed) > 0 && !bytes.Equal(blk.Header.Seed, seed) {
+		return nil, errors.Wrapf(
+			beacon.ErrInvalidSeed,
+			"block %d carries seed %x, but the one of epoch %d is %x",
+			height,
+			blk.Header.Seed,
+			epochNum,
+			seed,
+		)
+	}
+	return seed, nil
+}
+
+// derivedSeed returns the seed of the epoch derived from the block data, which is the genesis seed for the first epoch
+func (ctx *rollDPoSCtx) derivedSeed(epochNum uint64) ([]byte, error) {
+	if epochNum <= 1 {
+		return crypto.CryptoSeed, nil
+	}
+	return ctx.deriveSeed(epochNum)
+}
+
+// verifyBlockSeed checks that the first block of the epoch carries the seed derived from the block data before it, and
+// the others carry none. A dummy block carries no seed even if it's the first block of the epoch.
+func (ctx *rollDPoSCtx) verifyBlockSeed(blk *blockchain.Block) error {
+	height := blk.Height()
+	if height == 0 {
+		return nil
+	}
+	epochNum := ctx.epochNumOf(height)
+	if height != ctx.epochStartHeight(epochNum) || blk.IsDummyBlock() {
+		if len(blk.Header.Seed) > 0 {
+			return errors.Wrapf(beacon.ErrInvalidSeed, "block %d shouldn't carry the seed", height)
+		}
+		return nil
+	}
+	seed, err := ctx.derivedSeed(epochNum)
+	if err != nil {
+		return errors.Wrapf(err, "error when deriving the seed of epoch %d", epochNum)
+	}
+	if !bytes.Equal(blk.Header.Seed, seed) {
+		return errors.Wrapf(
+			beacon.ErrInvalidSeed,
+			"block %d carries seed %x, but the one of epoch %d is %x",
+			height,
+			blk.Header.Seed,
+			epochNum,
+			seed,
+		)
+	}
+	return nil
+}
+
+// seedValidator checks the seed carried by the block on top of the validation of the chain, so that the blocks synced
+// from the network are checked as well as the proposed ones
+type seedValidator struct {
+	blockchain.Validator
+	ctx *rollDPoSCtx
+}
+
+// Validate validates the block with the chain validator, and then the seed it carries
+func (v *seedValidator) Validate(blk *blockchain.Block, tipHeight uint64, tipHash hash.Hash32B, containCoinbase bool) error {
+	if err := v.Validator.Validate(blk, tipHeight, tipHash, containCoinbase); err != nil {
+		return err
+	}
+	return v.ctx.verifyBlockSeed(blk)
+}
+
+// validateBlockSeed checks that the first block of the epoch carries the seed of the epoch, and the others carry none
+func (ctx *rollDPoSCtx) validateBlockSeed(blk *blockchain.Block) error {
+	if blk.Height() != ctx.epoch.height {
+		if len(blk.Header.Seed) > 0 {
+			return errors.Wrapf(beacon.ErrInvalidSeed, "block %d isn't the first block of the epoch", blk.Height())
+		}
+		return nil
+	}
+	if !bytes.Equal(blk.Header.Seed, ctx.epoch.seed) {
+		return errors.Wrapf(
+			beacon.ErrInvalidSeed,
+			"block %d carries seed %x, but the one of the epoch is %x",
+			blk.Height(),
+			blk.Header.Seed,
+			ctx.epoch.seed,
+		)
+	}
+	return nil
+}
+
+// VerifySeed verifies the seed of the epoch from the block data, which is the seed the delegates of the epoch are
+// picked and ordered with, and returns the seed
+func (r *RollDPoS) VerifySeed(epochNum uint64) ([]byte, error) {
+	return r.ctx.verifySeed(epochNum)
+}
+
+// Validator returns the validator checking the seeds carried by the blocks on top of the current validator of the
+// chain, which is set to the chain
+func (r *RollDPoS) Validator() blockchain.Validator {
+	return &seedValidator{Validator: r.ctx.chain.Validator(), ctx: r.ctx}
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"testing"
+
+	"github.com/facebookgo/clock"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/beacon"
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/test/mock/mock_actpool"
+	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
+	"github.com/iotexproject/iotex-core/test/mock/mock_network"
+)
+
+func TestDeriveSeed(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const numDelegates = 21
+	cfg := config.Default.Consensus.RollDPoS
+	cfg.NumDelegates = numDelegates
+	cfg.NumSubEpochs = 1
+	cfg.EnableDKG = true
+
+	addrs := make(map[string]*iotxaddress.Address, numDelegates)
+	candidates := make([]*state.Candidate, numDelegates)
+	delegates := make([]string, numDelegates)
+	for i := range candidates {
+		addr := newTestAddr()
+		addrs[addr.RawAddress] = addr
+		candidates[i] = &state.Candidate{Address: addr.RawAddress}
+		delegates[i] = addr.RawAddress
+	}
+	crypto.SortCandidates(delegates, 1, crypto.CryptoSeed)
+
+	// The delegates deal the secrets in the DKG stage, and the last one deals a falsified share, so it's disqualified
+	secretBlks := make([]*blockchain.Block, numDelegates)
+	dealt := make(map[string][][]uint32, numDelegates)
+	for i, dealer := range delegates {
+		secretBlks[i], dealt[dealer] = newTestSecretBlock(t, addrs[dealer], delegates)
+	}
+	falsified, err := action.NewSecretProposal(1, delegates[numDelegates-1], delegates[0], []uint32{1, 2, 3, 4, 5})
+	require.NoError(err)
+	secretBlks[numDelegates-1].SecretProposals[0] = falsified
+	pks := make([][]byte, numDelegates)
+	sks := make([][]uint32, numDelegates)
+	for j := range delegates {
+		pks[j], sks[j], err = dkgKeyPair(delegates, func(dealer string) ([]uint32, bool) {
+			if dealer == delegates[numDelegates-1] {
+				return nil, false
+			}
+			return dealt[dealer][j], true
+		})
+		require.NoError(err)
+	}
+	fakePks, fakeSks := newTestDKGKeys(t, dkgIDs(delegates))
+
+	// The delegates sign the seed in the rest of the epoch. The key which isn't dealt to the producer, the ID of another
+	// delegate and the repeated share of a producer don't count.
+	dkgStart := uint64(1)
+	seedStart := dkgStart + numDelegates
+	seedEnd := seedStart + numDelegates
+	shares := make([]*beacon.Share, 0)
+	newDKGBlock := func(h uint64, i int, pk []byte, sk []uint32) *blockchain.Block {
+		blk := blockchain.NewBlock(config.Default.Chain.ID, h, hash.ZeroHash32B, 0, nil, nil, nil, nil)
+		blk.Header.DKGID = iotxaddress.CreateID(delegates[i])
+		blk.Header.DKGPubkey = pk
+		_, sig, err := crypto.BLS.Sign(sk, crypto.CryptoSeed)
+		require.NoError(err)
+		blk.Header.DKGBlockSig = sig
+		require.NoError(blk.SignBlock(addrs[delegates[i]]))
+		return blk
+	}
+	ctx := makeTestRollDPoSCtx(
+		addrs[delegates[0]],
+		ctrl,
+		cfg,
+		func(chain *mock_blockchain.MockBlockchain) {
+			chain.EXPECT().TipHeight().Return(seedEnd - 1).AnyTimes()
+			for i, blk := range secretBlks {
+				chain.EXPECT().GetBlockByHeight(dkgStart+uint64(i)).Return(blk, nil).AnyTimes()
+			}
+			for h := seedStart; h < seedEnd; h++ {
+				i := int(h - seedStart)
+				var blk *blockchain.Block
+				switch i {
+				case 1:
+					blk = newDKGBlock(h, i, fakePks[i], fakeSks[i])
+				case 2:
+					blk = newDKGBlock(h, 3, pks[3], sks[3])
+					require.NoError(blk.SignBlock(addrs[delegates[i]]))
+				case 4:
+					blk = newDKGBlock(h, 0, pks[0], sks[0])
+				case 5:
+					chain.EXPECT().GetBlockByHeight(h).Return(nil, errors.New("not found")).AnyTimes()
+					continue
+				default:
+					blk = newDKGBlock(h, i, pks[i], sks[i])
+					shares = append(shares, &beacon.Share{
+						ID:        blk.Header.DKGID,
+						PublicKey: blk.Header.DKGPubkey,
+						Signature: blk.Header.DKGBlockSig,
+					})
+				}
+				chain.EXPECT().GetBlockByHeight(h).Return(blk, nil).AnyTimes()
+			}
+		},
+		func(_ *mock_actpool.MockActPool) {},
+		func(_ *mock_network.MockOverlay) {},
+		clock.NewMock(),
+	)
+	ctx.candidatesByHeightFunc = func(uint64) ([]*state.Candidate, error) { return candidates, nil }
+
+	keys, err := ctx.dkgPubKeys(1)
+	require.NoError(err)
+	require.Equal(numDelegates, len(keys))
+	for j, delegate := range delegates {
+		require.Equal(pks[j], keys[delegate])
+	}
+
+	expected, err := beacon.DeriveSeed(crypto.CryptoSeed, shares)
+	require.NoError(err)
+	require.NotEqual(hash.Hash256b(crypto.CryptoSeed), expected)
+	seed, err := ctx.deriveSeed(2)
+	require.NoError(err)
+	require.Equal(expected, seed)
+
+	// The first block of the next epoch carries the seed, and the others carry none
+	blk := blockchain.NewBlock(config.Default.Chain.ID, seedEnd, hash.ZeroHash32B, 0, nil, nil, nil, nil)
+	require.True(blk.IsDummyBlock())
+	require.NoError(ctx.verifyBlockSeed(blk))
+	require.NoError(blk.SignBlock(addrs[delegates[0]]))
+	require.Equal(beacon.ErrInvalidSeed, errors.Cause(ctx.verifyBlockSeed(blk)))
+	blk.Header.Seed = hash.Hash256b(crypto.CryptoSeed)
+	require.Equal(beacon.ErrInvalidSeed, errors.Cause(ctx.verifyBlockSeed(blk)))
+	blk.Header.Seed = seed
+	require.NoError(ctx.verifyBlockSeed(blk))
+	blk = blockchain.NewBlock(config.Default.Chain.ID, seedEnd-1, hash.ZeroHash32B, 0, nil, nil, nil, nil)
+	blk.Header.Seed = seed
+	require.NoError(blk.SignBlock(addrs[delegates[0]]))
+	require.Equal(beacon.ErrInvalidSeed, errors.Cause(ctx.verifyBlockSeed(blk)))
+
+	// Without DKG, no key is dealt, so the seed is the hash of the previous one
+	ctx.cfg.EnableDKG = false
+	ctx.cfg.NumSubEpochs = 2
+	seed, err = ctx.deriveSeed(2)
+	require.NoError(err)
+	require.Equal(hash.Hash256b(crypto.CryptoSeed), seed)
+}
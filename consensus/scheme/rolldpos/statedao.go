@@ -0,0 +1,201 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"bytes"
+	"encoding/gob"
+
+	"github.com/boltdb/bolt"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+)
+
+const rollDPoSNS = "rolldpos"
+
+var (
+	epochKey  = []byte("epoch")
+	commitKey = []byte("commit")
+	roundKey  = []byte("round")
+
+	// ErrConflictingEndorse indicates that signing the endorse or the proposal conflicts with the ones signed before,
+	// which could get the node slashed
+	ErrConflictingEndorse = errors.New("conflicting with the endorses signed before")
+)
+
+// epochRecord is the persisted epoch which the node has entered as a delegate
+type epochRecord struct {
+	Num       uint64
+	Height    uint64
+	Delegates []string
+	Seed      []byte
+}
+
+// commitRecord is a persisted commit endorse which the node has signed
+type commitRecord struct {
+	Height   uint64
+	BlkHash  hash.Hash32B
+	Decision bool
+}
+
+// isTimeoutVote checks if the commit endorse votes for the dummy block of the height
+func (r *commitRecord) isTimeoutVote() bool {
+	return r.BlkHash == hash.ZeroHash32B && !r.Decision
+}
+
+// roundRecord is a persisted proposal, or proposal endorse, which the node has signed in a round. Proposer is the
+// proposer of the block in the round, which is the node itself for its own proposal.
+type roundRecord struct {
+	Height   uint64
+	Round    uint32
+	Proposer string
+	BlkHash  hash.Hash32B
+	Decision bool
+}
+
+// stateDAO persists the state of the consensus, so that a restarted delegate resumes the epoch with the same seed, and
+// doesn't sign a message conflicting with the ones signed before restarting
+type stateDAO struct {
+	kvstore db.KVStore
+}
+
+func newStateDAO(kvstore db.KVStore) *stateDAO {
+	return &stateDAO{kvstore: kvstore}
+}
+
+// getEpoch returns the last epoch which the node has entered, or nil if there is none
+func (dao *stateDAO) getEpoch() (*epochRecord, error) {
+	var epoch epochRecord
+	found, err := dao.get(epochKey, &epoch)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &epoch, nil
+}
+
+// putEpoch persists the epoch which the node enters
+func (dao *stateDAO) putEpoch(epoch *epochRecord) error {
+	return dao.put(epochKey, epoch)
+}
+
+// recordCommit persists the commit endorse before it is signed, unless it conflicts with the ones signed at the same
+// height. It conflicts if it reverses the decision on a block, or endorses a block while another one has been endorsed,
+// or votes for the dummy block while a block has been endorsed and vice versa.
+// The proposal endorses are not binding, so a new round at the same height could endorse another proposal. A commit
+// endorse of a height lower than the last signed one is rejected, because the ones signed at that height are not kept.
+func (dao *stateDAO) recordCommit(en *commitRecord) error {
+	var records []commitRecord
+	if _, err := dao.get(commitKey, &records); err != nil {
+		return err
+	}
+	if len(records) > 0 && en.Height < records[0].Height {
+		return errors.Wrapf(
+			ErrConflictingEndorse,
+			"height %d is lower than the last signed height %d",
+			en.Height,
+			records[0].Height,
+		)
+	}
+	if len(records) > 0 && en.Height > records[0].Height {
+		records = nil
+	}
+	for _, record := range records {
+		if record.BlkHash == en.BlkHash && record.Decision == en.Decision {
+			// Signing the same endorse again is safe
+			return nil
+		}
+		if record.BlkHash == en.BlkHash ||
+			(record.Decision && en.Decision) ||
+			(record.Decision && en.isTimeoutVote()) ||
+			(record.isTimeoutVote() && en.Decision) {
+			return errors.Wrapf(
+				ErrConflictingEndorse,
+				"block %x at height %d is endorsed with decision %t before",
+				record.BlkHash,
+				en.Height,
+				record.Decision,
+			)
+		}
+	}
+	return dao.put(commitKey, append(records, *en))
+}
+
+// recordRound persists the proposal or the proposal endorse before it is signed, unless it conflicts with the ones
+// signed at the same height. It conflicts if it goes back to an earlier round than the last signed one, or it signs
+// another block, or reverses the decision on the block, of the same proposer in the same round.
+func (dao *stateDAO) recordRound(en *roundRecord) error {
+	var records []roundRecord
+	if _, err := dao.get(roundKey, &records); err != nil {
+		return err
+	}
+	if len(records) > 0 && en.Height < records[0].Height {
+		return errors.Wrapf(
+			ErrConflictingEndorse,
+			"height %d is lower than the last signed height %d",
+			en.Height,
+			records[0].Height,
+		)
+	}
+	if len(records) > 0 && en.Height > records[0].Height {
+		records = nil
+	}
+	for _, record := range records {
+		if en.Round < record.Round {
+			return errors.Wrapf(
+				ErrConflictingEndorse,
+				"round %d is lower than the signed round %d at height %d",
+				en.Round,
+				record.Round,
+				en.Height,
+			)
+		}
+		if record.Round != en.Round || record.Proposer != en.Proposer {
+			continue
+		}
+		if record.BlkHash == en.BlkHash && record.Decision == en.Decision {
+			// Signing the same message again is safe
+			return nil
+		}
+		return errors.Wrapf(
+			ErrConflictingEndorse,
+			"block %x of %s in round %d at height %d is signed with decision %t before",
+			record.BlkHash,
+			record.Proposer,
+			en.Round,
+			en.Height,
+			record.Decision,
+		)
+	}
+	return dao.put(roundKey, append(records, *en))
+}
+
+func (dao *stateDAO) get(key []byte, v interface{}) (bool, error) {
+	value, err := dao.kvstore.Get(rollDPoSNS, key)
+	if cause := errors.Cause(err); cause == db.ErrNotExist || cause == bolt.ErrBucketNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "error when getting %s from the consensus state DB", key)
+	}
+	if err := gob.NewDecoder(bytes.NewBuffer(value)).Decode(v); err != nil {
+		return false, errors.Wrapf(err, "error when decoding %s", key)
+	}
+	return true, nil
+}
+
+func (dao *stateDAO) put(key []byte, v interface{}) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return errors.Wrapf(err, "error when encoding %s", key)
+	}
+	if err := dao.kvstore.Put(rollDPoSNS, key, buf.Bytes()); err != nil {
+		return errors.Wrapf(err, "error when putting %s into the consensus state DB", key)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestStateDAORecordCommit(t *testing.T) {
+	require := require.New(t)
+
+	dao := newStateDAO(db.NewMemKVStore())
+	blkA := hash.Hash32B{1}
+	blkB := hash.Hash32B{2}
+
+	require.NoError(dao.recordCommit(&commitRecord{Height: 5, BlkHash: blkA, Decision: true}))
+	// Signing the same endorse again is fine
+	require.NoError(dao.recordCommit(&commitRecord{Height: 5, BlkHash: blkA, Decision: true}))
+	// Endorsing another block, or reversing the decision, conflicts
+	err := dao.recordCommit(&commitRecord{Height: 5, BlkHash: blkB, Decision: true})
+	require.Equal(ErrConflictingEndorse, errors.Cause(err))
+	err = dao.recordCommit(&commitRecord{Height: 5, BlkHash: blkA, Decision: false})
+	require.Equal(ErrConflictingEndorse, errors.Cause(err))
+	// Rejecting another block doesn't
+	require.NoError(dao.recordCommit(&commitRecord{Height: 5, BlkHash: blkB, Decision: false}))
+	// A lower height conflicts, while a higher one starts over
+	err = dao.recordCommit(&commitRecord{Height: 4, BlkHash: blkB, Decision: true})
+	require.Equal(ErrConflictingEndorse, errors.Cause(err))
+	require.NoError(dao.recordCommit(&commitRecord{Height: 6, BlkHash: blkB, Decision: true}))
+	// Voting for the dummy block conflicts with endorsing a block, and vice versa
+	err = dao.recordCommit(&commitRecord{Height: 6, BlkHash: hash.ZeroHash32B, Decision: false})
+	require.Equal(ErrConflictingEndorse, errors.Cause(err))
+	require.NoError(dao.recordCommit(&commitRecord{Height: 7, BlkHash: hash.ZeroHash32B, Decision: false}))
+	err = dao.recordCommit(&commitRecord{Height: 7, BlkHash: blkA, Decision: true})
+	require.Equal(ErrConflictingEndorse, errors.Cause(err))
+	require.NoError(dao.recordCommit(&commitRecord{Height: 7, BlkHash: blkA, Decision: false}))
+}
+
+func TestStateDAORecordRound(t *testing.T) {
+	require := require.New(t)
+
+	dao := newStateDAO(db.NewMemKVStore())
+	blkA := hash.Hash32B{1}
+	blkB := hash.Hash32B{2}
+
+	require.NoError(dao.recordRound(&roundRecord{Height: 5, Round: 1, Proposer: "a", BlkHash: blkA, Decision: true}))
+	// Signing the same message again is fine
+	require.NoError(dao.recordRound(&roundRecord{Height: 5, Round: 1, Proposer: "a", BlkHash: blkA, Decision: true}))
+	// Signing another block of the proposer in the round, or reversing the decision, conflicts
+	err := dao.recordRound(&roundRecord{Height: 5, Round: 1, Proposer: "a", BlkHash: blkB, Decision: true})
+	require.Equal(ErrConflictingEndorse, errors.Cause(err))
+	err = dao.recordRound(&roundRecord{Height: 5, Round: 1, Proposer: "a", BlkHash: blkA, Decision: false})
+	require.Equal(ErrConflictingEndorse, errors.Cause(err))
+	// The block of another proposer, or of a later round, doesn't
+	require.NoError(dao.recordRound(&roundRecord{Height: 5, Round: 1, Proposer: "b", BlkHash: blkB, Decision: true}))
+	require.NoError(dao.recordRound(&roundRecord{Height: 5, Round: 2, Proposer: "a", BlkHash: blkB, Decision: true}))
+	// Going back to an earlier round, or a lower height, conflicts, while a higher height starts over
+	err = dao.recordRound(&roundRecord{Height: 5, Round: 1, Proposer: "c", BlkHash: blkB, Decision: true})
+	require.Equal(ErrConflictingEndorse, errors.Cause(err))
+	err = dao.recordRound(&roundRecord{Height: 4, Round: 3, Proposer: "a", BlkHash: blkB, Decision: true})
+	require.Equal(ErrConflictingEndorse, errors.Cause(err))
+	require.NoError(dao.recordRound(&roundRecord{Height: 6, Round: 0, Proposer: "a", BlkHash: blkB, Decision: true}))
+}
+
+func TestStateDAOAcrossRestarts(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	path := "/tmp/test-rolldpos-state.db"
+	testutil.CleanupPath(t, path)
+	defer testutil.CleanupPath(t, path)
+
+	kvstore := db.NewBoltDB(path, &config.Default.DB)
+	require.NoError(kvstore.Start(ctx))
+	dao := newStateDAO(kvstore)
+	epoch, err := dao.getEpoch()
+	require.NoError(err)
+	require.Nil(epoch)
+	epoch = &epochRecord{Num: 2, Height: 9, Delegates: []string{"a", "b"}, Seed: []byte{1, 2, 3}}
+	require.NoError(dao.putEpoch(epoch))
+	require.NoError(dao.recordCommit(&commitRecord{Height: 9, BlkHash: hash.Hash32B{1}, Decision: true}))
+	require.NoError(dao.recordRound(&roundRecord{Height: 9, Round: 1, Proposer: "a", BlkHash: hash.Hash32B{1}, Decision: true}))
+	require.NoError(kvstore.Stop(ctx))
+
+	kvstore = db.NewBoltDB(path, &config.Default.DB)
+	require.NoError(kvstore.Start(ctx))
+	defer func() {
+		require.NoError(kvstore.Stop(ctx))
+	}()
+	dao = newStateDAO(kvstore)
+	restored, err := dao.getEpoch()
+	require.NoError(err)
+	require.Equal(epoch, restored)
+	err = dao.recordCommit(&commitRecord{Height: 9, BlkHash: hash.Hash32B{2}, Decision: true})
+	require.Equal(ErrConflictingEndorse, errors.Cause(err))
+	err = dao.recordRound(&roundRecord{Height: 9, Round: 1, Proposer: "a", BlkHash: hash.Hash32B{2}, Decision: true})
+	require.Equal(ErrConflictingEndorse, errors.Cause(err))
+	err = dao.recordRound(&roundRecord{Height: 9, Round: 0, Proposer: "b", BlkHash: hash.Hash32B{2}, Decision: true})
+	require.Equal(ErrConflictingEndorse, errors.Cause(err))
+}
@@ -0,0 +1,259 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package sim
+
+import (
+	"math/big"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/logger"
+	"github.com/iotexproject/iotex-core/network"
+	"github.com/iotexproject/iotex-core/pkg/enc"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/proto"
+)
+
+// Byzantine is the set of the Byzantine strategies which a simulated node follows. The zero value is an honest node.
+type Byzantine struct {
+	// Equivocate makes the node propose a conflicting block at the same height to half of its peers
+	Equivocate bool
+	// WithholdEndorse makes the node never send its endorses
+	WithholdEndorse bool
+	// Delay delays all the messages sent by the node
+	Delay time.Duration
+	// EndorseInvalid makes the node endorse the proposed blocks without validating them
+	EndorseInvalid bool
+	// SpamHeights is the number of future heights, of which the node sends endorses along with each message
+	SpamHeights uint64
+}
+
+// byzVal is the validator of the byzantine node, which accepts any block
+type byzVal struct {
+	val blockchain.Validator
+}
+
+// Validate accepts the block without validating it
+func (v *byzVal) Validate(blk *blockchain.Block, tipHeight uint64, tipHash hash.Hash32B, containCoinbase bool) error {
+	return nil
+}
+
+// byzantineOverlay sits between the consensus and the P2P network of a simulated node, and turns the messages which the
+// consensus sends into the ones the Byzantine strategies of the node send
+type byzantineOverlay struct {
+	network.Overlay
+	bc        blockchain.Blockchain
+	addr      *iotxaddress.Address
+	validator blockchain.Validator
+	mutex     sync.RWMutex
+	byzantine Byzantine
+}
+
+func newByzantineOverlay(p2p network.Overlay, bc blockchain.Blockchain, addr *iotxaddress.Address) *byzantineOverlay {
+	return &byzantineOverlay{Overlay: p2p, bc: bc, addr: addr}
+}
+
+// setByzantine sets the Byzantine strategies which the node follows from now on
+func (o *byzantineOverlay) setByzantine(byzantine *Byzantine) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if byzantine.EndorseInvalid != o.byzantine.EndorseInvalid {
+		if byzantine.EndorseInvalid {
+			// The validator set by the consensus is restored once the node turns honest again
+			o.validator = o.bc.Validator()
+			o.bc.SetValidator(&byzVal{val: o.validator})
+		} else {
+			o.bc.SetValidator(o.validator)
+		}
+	}
+	o.byzantine = *byzantine
+}
+
+func (o *byzantineOverlay) getByzantine() Byzantine {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	return o.byzantine
+}
+
+// Broadcast broadcasts the messages which the Byzantine strategies send instead of the given one
+func (o *byzantineOverlay) Broadcast(chainID uint32, msg proto.Message) error {
+	byzantine := o.getByzantine()
+	if pbPropose, ok := msg.(*iproto.ProposePb); ok && byzantine.Equivocate {
+		if err := o.equivocate(chainID, pbPropose, byzantine.Delay); err != nil {
+			return err
+		}
+	} else if !o.withhold(msg, byzantine) {
+		o.send(func() error { return o.Overlay.Broadcast(chainID, msg) }, byzantine.Delay)
+	}
+	for _, spam := range o.spam(msg, byzantine) {
+		spam := spam
+		o.send(func() error { return o.Overlay.Broadcast(chainID, spam) }, byzantine.Delay)
+	}
+	return nil
+}
+
+// Tell tells the peer the messages which the Byzantine strategies send instead of the given one
+func (o *byzantineOverlay) Tell(chainID uint32, addr net.Addr, msg proto.Message) error {
+	byzantine := o.getByzantine()
+	if !o.withhold(msg, byzantine) {
+		o.send(func() error { return o.Overlay.Tell(chainID, addr, msg) }, byzantine.Delay)
+	}
+	for _, spam := range o.spam(msg, byzantine) {
+		spam := spam
+		o.send(func() error { return o.Overlay.Tell(chainID, addr, spam) }, byzantine.Delay)
+	}
+	return nil
+}
+
+// send sends the message now, or after the delay in the background
+func (o *byzantineOverlay) send(send func() error, delay time.Duration) {
+	if delay <= 0 {
+		if err := send(); err != nil {
+			logger.Error().Err(err).Msg("error when sending the message")
+		}
+		return
+	}
+	time.AfterFunc(delay, func() {
+		if err := send(); err != nil {
+			logger.Error().Err(err).Msg("error when sending the delayed message")
+		}
+	})
+}
+
+// withhold returns true if the message is an endorse which the node withholds
+func (o *byzantineOverlay) withhold(msg proto.Message, byzantine Byzantine) bool {
+	_, ok := msg.(*iproto.EndorsePb)
+	return ok && byzantine.WithholdEndorse
+}
+
+// equivocate tells the proposed block to half of the peers, and a conflicting block of the same height to the others
+func (o *byzantineOverlay) equivocate(chainID uint32, pbPropose *iproto.ProposePb, delay time.Duration) error {
+	conflicting, err := o.conflictingProposal(pbPropose)
+	if err != nil {
+		return errors.Wrap(err, "error when creating the conflicting proposal")
+	}
+	peers := o.Overlay.GetPeers()
+	if len(peers) == 0 {
+		o.send(func() error { return o.Overlay.Broadcast(chainID, pbPropose) }, delay)
+		o.send(func() error { return o.Overlay.Broadcast(chainID, conflicting) }, delay)
+		return nil
+	}
+	for i, peer := range peers {
+		peer := peer
+		msg := pbPropose
+		if i%2 == 1 {
+			msg = conflicting
+		}
+		o.send(func() error { return o.Overlay.Tell(chainID, peer, msg) }, delay)
+	}
+	return nil
+}
+
+// conflictingProposal creates a proposal of another block on the same parent, which carries an alternative valid set of
+// actions, so that it differs from the proposed one. If the proposed block carries any action, the conflicting one only
+// carries the coinbase transfer, otherwise it carries a zero-amount transfer from the node to itself. Either set is
+// valid on the parent regardless of the pending actions of the node, since no other action of the node is in the block.
+func (o *byzantineOverlay) conflictingProposal(pbPropose *iproto.ProposePb) (*iproto.ProposePb, error) {
+	proposed := &blockchain.Block{}
+	proposed.ConvertFromBlockPb(pbPropose.GetBlock())
+	if proposed.PrevHash() != o.bc.TipHash() {
+		return nil, errors.Errorf("the chain has moved on from the parent of block %d", proposed.Height())
+	}
+	var tsfs []*action.Transfer
+	if len(proposed.Votes)+len(proposed.Executions)+len(proposed.Actions) == 0 && !hasTransfer(proposed) {
+		nonce, err := o.bc.Nonce(o.addr.RawAddress)
+		if err != nil {
+			return nil, err
+		}
+		tsf, err := action.NewTransfer(nonce+1, big.NewInt(0), o.addr.RawAddress, o.addr.RawAddress, []byte{}, 0, big.NewInt(0))
+		if err != nil {
+			return nil, err
+		}
+		if err := action.Sign(tsf, o.addr.PrivateKey); err != nil {
+			return nil, err
+		}
+		tsfs = append(tsfs, tsf)
+	}
+	blk, err := o.bc.MintNewBlock(tsfs, nil, nil, nil, o.addr, "")
+	if err != nil {
+		return nil, err
+	}
+	if blk.PrevHash() != proposed.PrevHash() {
+		return nil, errors.Errorf("the chain has moved on from the parent of block %d", proposed.Height())
+	}
+	// The DKG signature is over the seed of the epoch, which is independent of the block content
+	blk.Header.DKGID = proposed.Header.DKGID
+	blk.Header.DKGPubkey = proposed.Header.DKGPubkey
+	blk.Header.DKGBlockSig = proposed.Header.DKGBlockSig
+	blk.Header.Seed = proposed.Header.Seed
+	if err := blk.SignBlock(o.addr); err != nil {
+		return nil, err
+	}
+	proposalHash := blake2b.Sum256(
+		action.ProposalByteStream(blk.Height(), pbPropose.GetRound(), blk.HashBlock(), pbPropose.GetProposerAddr()),
+	)
+	return &iproto.ProposePb{
+		Proposer:       o.addr.RawAddress,
+		Block:          blk.ConvertToBlockPb(),
+		Round:          pbPropose.GetRound(),
+		ProposerAddr:   pbPropose.GetProposerAddr(),
+		ProposerPubKey: o.addr.PublicKey[:],
+		Signature:      crypto.EC283.Sign(o.addr.PrivateKey, proposalHash[:]),
+	}, nil
+}
+
+// hasTransfer returns true if the block carries any transfer other than the coinbase one
+func hasTransfer(blk *blockchain.Block) bool {
+	for _, tsf := range blk.Transfers {
+		if !tsf.IsCoinbase() {
+			return true
+		}
+	}
+	return false
+}
+
+// spam returns the commit endorses of random blocks at the future heights of the message, signed by the node
+func (o *byzantineOverlay) spam(msg proto.Message, byzantine Byzantine) []proto.Message {
+	if byzantine.SpamHeights == 0 {
+		return nil
+	}
+	var height uint64
+	switch m := msg.(type) {
+	case *iproto.ProposePb:
+		height = m.GetBlock().GetHeader().GetHeight()
+	case *iproto.EndorsePb:
+		height = m.GetHeight()
+	default:
+		return nil
+	}
+	spams := make([]proto.Message, 0, byzantine.SpamHeights)
+	for h := height + 1; h <= height+byzantine.SpamHeights; h++ {
+		heightBytes := make([]byte, 8)
+		enc.MachineEndian.PutUint64(heightBytes, h)
+		blkHash := blake2b.Sum256(append(o.addr.PublicKey[:], heightBytes...))
+		endorseHash := blake2b.Sum256(action.EndorseByteStream(h, true, blkHash, true))
+		spams = append(spams, &iproto.EndorsePb{
+			Height:         h,
+			BlockHash:      blkHash[:],
+			Topic:          iproto.EndorsePb_COMMIT,
+			Endorser:       o.addr.RawAddress,
+			EndorserPubKey: o.addr.PublicKey[:],
+			Decision:       true,
+			Signature:      crypto.EC283.Sign(o.addr.PrivateKey, endorseHash[:]),
+		})
+	}
+	return spams
+}
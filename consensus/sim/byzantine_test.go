@@ -0,0 +1,118 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package sim
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/network/node"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/proto"
+	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
+	"github.com/iotexproject/iotex-core/test/mock/mock_network"
+	"github.com/iotexproject/iotex-core/test/testaddress"
+)
+
+func TestByzantineOverlay(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	addr := testaddress.Addrinfo["producer"]
+	chainID := config.Default.Chain.ID
+	bc := mock_blockchain.NewMockBlockchain(ctrl)
+	p2p := mock_network.NewMockOverlay(ctrl)
+	sent := make(chan proto.Message, 10)
+	p2p.EXPECT().Broadcast(chainID, gomock.Any()).Do(func(_ uint32, msg proto.Message) { sent <- msg }).
+		Return(nil).AnyTimes()
+	p2p.EXPECT().Tell(chainID, gomock.Any(), gomock.Any()).Do(func(_ uint32, _ net.Addr, msg proto.Message) {
+		sent <- msg
+	}).Return(nil).AnyTimes()
+	val := &byzVal{}
+	bc.EXPECT().Validator().Return(val).Times(1)
+	o := newByzantineOverlay(p2p, bc, addr)
+
+	blk := blockchain.NewBlock(chainID, 3, hash.ZeroHash32B, 0, nil, nil, nil, nil)
+	require.NoError(blk.SignBlock(addr))
+	pbPropose := &iproto.ProposePb{Proposer: addr.RawAddress, Block: blk.ConvertToBlockPb()}
+	pbEndorse := &iproto.EndorsePb{Height: 3, Topic: iproto.EndorsePb_COMMIT, Endorser: addr.RawAddress}
+
+	// The honest node sends the messages as they are
+	require.NoError(o.Broadcast(chainID, pbEndorse))
+	require.Equal(pbEndorse, <-sent)
+
+	// The endorses are withheld, and the endorses of the future heights are spammed along with each message
+	o.setByzantine(&Byzantine{WithholdEndorse: true, SpamHeights: 2})
+	require.NoError(o.Broadcast(chainID, pbPropose))
+	require.NoError(o.Tell(chainID, node.NewTCPNode("127.0.0.1:10000"), pbEndorse))
+	require.Equal(pbPropose, <-sent)
+	for i := 0; i < 4; i++ {
+		spam, ok := (<-sent).(*iproto.EndorsePb)
+		require.True(ok)
+		height := uint64(4 + i%2)
+		require.Equal(height, spam.Height)
+		var blkHash hash.Hash32B
+		copy(blkHash[:], spam.BlockHash)
+		endorseHash := blake2b.Sum256(action.EndorseByteStream(height, true, blkHash, true))
+		require.True(crypto.EC283.Verify(addr.PublicKey, endorseHash[:], spam.Signature))
+	}
+	require.Equal(0, len(sent))
+
+	// The messages are delayed
+	o.setByzantine(&Byzantine{Delay: 50 * time.Millisecond})
+	start := time.Now()
+	require.NoError(o.Broadcast(chainID, pbEndorse))
+	require.Equal(0, len(sent))
+	require.Equal(pbEndorse, <-sent)
+	require.True(time.Since(start) >= 50*time.Millisecond)
+
+	// The proposed blocks are endorsed without validation
+	bc.EXPECT().SetValidator(gomock.Any()).Do(func(v blockchain.Validator) {
+		_, ok := v.(*byzVal)
+		require.True(ok)
+		require.NotEqual(val, v)
+	}).Times(1)
+	o.setByzantine(&Byzantine{EndorseInvalid: true})
+	bc.EXPECT().SetValidator(val).Times(1)
+	o.setByzantine(&Byzantine{})
+
+	// The proposer sends conflicting blocks to the peers
+	conflicting := &blockchain.Block{}
+	bc.EXPECT().Nonce(addr.RawAddress).Return(uint64(1), nil).Times(1)
+	bc.EXPECT().MintNewBlock(gomock.Any(), nil, nil, nil, addr, "").Do(
+		func(tsf []*action.Transfer, _, _, _, _, _ interface{}) {
+			require.Equal(1, len(tsf))
+			require.Equal(uint64(2), tsf[0].Nonce())
+			*conflicting = *blockchain.NewBlock(chainID, 3, hash.ZeroHash32B, 0, tsf, nil, nil, nil)
+		},
+	).Return(conflicting, nil).Times(1)
+	p2p.EXPECT().GetPeers().Return([]net.Addr{
+		node.NewTCPNode("127.0.0.1:10000"),
+		node.NewTCPNode("127.0.0.1:10001"),
+	}).Times(1)
+	o.setByzantine(&Byzantine{Equivocate: true})
+	require.NoError(o.Broadcast(chainID, pbPropose))
+	require.Equal(pbPropose, <-sent)
+	pbConflicting, ok := (<-sent).(*iproto.ProposePb)
+	require.True(ok)
+	require.Equal(blk.Height(), pbConflicting.Block.Header.Height)
+	var conflictingBlk blockchain.Block
+	conflictingBlk.ConvertFromBlockPb(pbConflicting.Block)
+	require.NotEqual(blk.HashBlock(), conflictingBlk.HashBlock())
+	require.True(conflictingBlk.VerifySignature())
+}
@@ -40,14 +40,16 @@ type Sim interface {
 	SetStream(*pbsim.Simulator_PingServer)
 	SetDoneStream(chan bool)
 	SendUnsent()
+	SetByzantine(*Byzantine)
 }
 
 // consensus_sim struct with a stream parameter for writing to simulator stream
 type sim struct {
-	cfg    *config.Consensus
-	scheme scheme.Scheme
-	stream pbsim.Simulator_PingServer
-	unsent []*pbsim.Reply
+	cfg       *config.Consensus
+	scheme    scheme.Scheme
+	stream    pbsim.Simulator_PingServer
+	unsent    []*pbsim.Reply
+	byzantine *byzantineOverlay
 }
 
 // NewSim creates a consensus_sim struct
@@ -107,13 +109,15 @@ func NewSim(
 		}
 	*/
 
+	addr := consensus.GetAddr(cfg)
+	cs.byzantine = newByzantineOverlay(p2p, bc, addr)
 	var err error
 	cs.scheme, err = rolldpos.NewRollDPoSBuilder().
-		SetAddr(consensus.GetAddr(cfg)).
+		SetAddr(addr).
 		SetConfig(cfg.Consensus.RollDPoS).
 		SetBlockchain(bc).
 		SetActPool(ap).
-		SetP2P(p2p).
+		SetP2P(cs.byzantine).
 		Build()
 	if err != nil {
 		logger.Panic().Err(err).Msg("error when constructing RollDPoS")
@@ -177,17 +181,21 @@ func NewSimByzantine(
 		}
 	*/
 
+	addr := consensus.GetAddr(cfg)
+	cs.byzantine = newByzantineOverlay(p2p, bc, addr)
 	var err error
 	cs.scheme, err = rolldpos.NewRollDPoSBuilder().
-		SetAddr(consensus.GetAddr(cfg)).
+		SetAddr(addr).
 		SetConfig(cfg.Consensus.RollDPoS).
 		SetBlockchain(bc).
 		SetActPool(ap).
-		SetP2P(p2p).
+		SetP2P(cs.byzantine).
 		Build()
 	if err != nil {
 		logger.Panic().Err(err).Msg("error when constructing RollDPoS")
 	}
+	// The byzantine node endorses the proposed blocks without validating them, unless other strategies are selected
+	cs.byzantine.setByzantine(&Byzantine{EndorseInvalid: true})
 	cs.unsent = make([]*pbsim.Reply, 0)
 
 	return cs
@@ -274,6 +282,19 @@ func (c *sim) SetDoneStream(done chan bool) {
 	c.scheme.SetDoneStream(done)
 }
 
+// SetByzantine sets the Byzantine strategies which the node follows
+func (c *sim) SetByzantine(byzantine *Byzantine) {
+	logger.Info().
+		Bool("equivocate", byzantine.Equivocate).
+		Bool("withholdEndorse", byzantine.WithholdEndorse).
+		Dur("delay", byzantine.Delay).
+		Bool("endorseInvalid", byzantine.EndorseInvalid).
+		Uint64("spamHeights", byzantine.SpamHeights).
+		Msg("Set Byzantine strategies")
+
+	c.byzantine.setByzantine(byzantine)
+}
+
 // SeparateMsg separates a proto.Message into its msgType and msgBody
 func SeparateMsg(m proto.Message) (uint32, []byte) {
 	msgType, err := iproto.GetTypeFromProtoMsg(m)
@@ -17,6 +17,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 
@@ -27,7 +28,6 @@ import (
 	"github.com/iotexproject/iotex-core/crypto"
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/network"
-	"github.com/iotexproject/iotex-core/pkg/hash"
 	"github.com/iotexproject/iotex-core/pkg/keypair"
 	"github.com/iotexproject/iotex-core/proto"
 )
@@ -38,27 +38,12 @@ const (
 )
 
 // server is used to implement message.SimulatorServer.
-type (
-	server struct {
-		nodes []Sim // slice of Consensus objects
-	}
-	byzVal struct {
-		val blockchain.Validator
-	}
-)
+type server struct {
+	nodes []Sim // slice of Consensus objects
+}
 
 var cpuprofile = flag.String("cpuprofile", "", "write cpu profile to file")
 
-// Validate for the byzantine node uses the actual block validator and returns the opposite
-func (v *byzVal) Validate(blk *blockchain.Block, tipHeight uint64, tipHash hash.Hash32B, containCoinbase bool) error {
-	//err := v.val.Validate(blk, tipHeight, tipHash)
-	//if err != nil {
-	//	return nil
-	//}
-	//return errors.New("")
-	return nil
-}
-
 // Ping implements simulator.SimulatorServer
 func (s *server) Init(in *pb.InitRequest, stream pb.Simulator_InitServer) error {
 	nPlayers := in.NBF + in.NFS + in.NHonest
@@ -100,13 +85,10 @@ func (s *server) Init(in *pb.InitRequest, stream pb.Simulator_InitServer) error
 			logger.Panic().Err(err).Msg("error when starting blockchain")
 		}
 
-		if i >= int(in.NFS+in.NHonest) { // is byzantine node
-			val := bc.Validator()
-			byzVal := &byzVal{val: val}
-			bc.SetValidator(byzVal)
+		overlay, err := network.NewOverlay(&cfg.Network, cfg.Chain.ID)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Fail to create overlay")
 		}
-
-		overlay := network.NewOverlay(&cfg.Network)
 		ap, err := actpool.NewActPool(bc, cfg.ActPool)
 		if err != nil {
 			logger.Fatal().Err(err).Msg("Fail to create actpool")
@@ -186,6 +168,22 @@ func (s *server) Ping(in *pb.Request, stream pb.Simulator_PingServer) error {
 	return nil
 }
 
+// SetByzantine implements simulator.SimulatorServer
+func (s *server) SetByzantine(ctx context.Context, in *pb.ByzantineRequest) (*pb.Empty, error) {
+	if in.PlayerID < 0 || int(in.PlayerID) >= len(s.nodes) || s.nodes[in.PlayerID] == nil {
+		return nil, errors.Errorf("node %d is not running", in.PlayerID)
+	}
+	s.nodes[in.PlayerID].SetByzantine(&Byzantine{
+		Equivocate:      in.Equivocate,
+		WithholdEndorse: in.WithholdEndorse,
+		Delay:           time.Duration(in.Delay) * time.Millisecond,
+		EndorseInvalid:  in.EndorseInvalid,
+		SpamHeights:     in.SpamHeights,
+	})
+	fmt.Printf("Node %d follows Byzantine strategies %+v\n", in.PlayerID, in)
+	return &pb.Empty{}, nil
+}
+
 func (s *server) Exit(context context.Context, in *pb.Empty) (*pb.Empty, error) {
 	defer os.Exit(0)
 	defer pprof.StopCPUProfile()
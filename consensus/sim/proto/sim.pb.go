@@ -37,7 +37,7 @@ func (m *Request) Reset()         { *m = Request{} }
 func (m *Request) String() string { return proto.CompactTextString(m) }
 func (*Request) ProtoMessage()    {}
 func (*Request) Descriptor() ([]byte, []int) {
-	return fileDescriptor_sim_4f723b2e18ed9d42, []int{0}
+	return fileDescriptor_sim_a0d6dd789df55817, []int{0}
 }
 func (m *Request) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Request.Unmarshal(m, b)
@@ -92,7 +92,7 @@ func (m *InitRequest) Reset()         { *m = InitRequest{} }
 func (m *InitRequest) String() string { return proto.CompactTextString(m) }
 func (*InitRequest) ProtoMessage()    {}
 func (*InitRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_sim_4f723b2e18ed9d42, []int{1}
+	return fileDescriptor_sim_a0d6dd789df55817, []int{1}
 }
 func (m *InitRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_InitRequest.Unmarshal(m, b)
@@ -147,7 +147,7 @@ func (m *Reply) Reset()         { *m = Reply{} }
 func (m *Reply) String() string { return proto.CompactTextString(m) }
 func (*Reply) ProtoMessage()    {}
 func (*Reply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_sim_4f723b2e18ed9d42, []int{2}
+	return fileDescriptor_sim_a0d6dd789df55817, []int{2}
 }
 func (m *Reply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Reply.Unmarshal(m, b)
@@ -201,7 +201,7 @@ func (m *Proposal) Reset()         { *m = Proposal{} }
 func (m *Proposal) String() string { return proto.CompactTextString(m) }
 func (*Proposal) ProtoMessage()    {}
 func (*Proposal) Descriptor() ([]byte, []int) {
-	return fileDescriptor_sim_4f723b2e18ed9d42, []int{3}
+	return fileDescriptor_sim_a0d6dd789df55817, []int{3}
 }
 func (m *Proposal) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Proposal.Unmarshal(m, b)
@@ -253,7 +253,7 @@ func (m *Empty) Reset()         { *m = Empty{} }
 func (m *Empty) String() string { return proto.CompactTextString(m) }
 func (*Empty) ProtoMessage()    {}
 func (*Empty) Descriptor() ([]byte, []int) {
-	return fileDescriptor_sim_4f723b2e18ed9d42, []int{4}
+	return fileDescriptor_sim_a0d6dd789df55817, []int{4}
 }
 func (m *Empty) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Empty.Unmarshal(m, b)
@@ -273,12 +273,97 @@ func (m *Empty) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_Empty proto.InternalMessageInfo
 
+// The request message selecting the Byzantine strategies which a node follows
+type ByzantineRequest struct {
+	PlayerID int32 `protobuf:"varint,1,opt,name=playerID,proto3" json:"playerID,omitempty"`
+	// propose a conflicting block to half of the peers
+	Equivocate bool `protobuf:"varint,2,opt,name=equivocate,proto3" json:"equivocate,omitempty"`
+	// never send the endorses
+	WithholdEndorse bool `protobuf:"varint,3,opt,name=withholdEndorse,proto3" json:"withholdEndorse,omitempty"`
+	// delay all the messages sent by the node in milliseconds
+	Delay uint32 `protobuf:"varint,4,opt,name=delay,proto3" json:"delay,omitempty"`
+	// endorse the proposed blocks without validating them
+	EndorseInvalid bool `protobuf:"varint,5,opt,name=endorseInvalid,proto3" json:"endorseInvalid,omitempty"`
+	// number of future heights of which the node sends endorses along with each message
+	SpamHeights          uint64   `protobuf:"varint,6,opt,name=spamHeights,proto3" json:"spamHeights,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ByzantineRequest) Reset()         { *m = ByzantineRequest{} }
+func (m *ByzantineRequest) String() string { return proto.CompactTextString(m) }
+func (*ByzantineRequest) ProtoMessage()    {}
+func (*ByzantineRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_sim_a0d6dd789df55817, []int{5}
+}
+func (m *ByzantineRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ByzantineRequest.Unmarshal(m, b)
+}
+func (m *ByzantineRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ByzantineRequest.Marshal(b, m, deterministic)
+}
+func (dst *ByzantineRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ByzantineRequest.Merge(dst, src)
+}
+func (m *ByzantineRequest) XXX_Size() int {
+	return xxx_messageInfo_ByzantineRequest.Size(m)
+}
+func (m *ByzantineRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ByzantineRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ByzantineRequest proto.InternalMessageInfo
+
+func (m *ByzantineRequest) GetPlayerID() int32 {
+	if m != nil {
+		return m.PlayerID
+	}
+	return 0
+}
+
+func (m *ByzantineRequest) GetEquivocate() bool {
+	if m != nil {
+		return m.Equivocate
+	}
+	return false
+}
+
+func (m *ByzantineRequest) GetWithholdEndorse() bool {
+	if m != nil {
+		return m.WithholdEndorse
+	}
+	return false
+}
+
+func (m *ByzantineRequest) GetDelay() uint32 {
+	if m != nil {
+		return m.Delay
+	}
+	return 0
+}
+
+func (m *ByzantineRequest) GetEndorseInvalid() bool {
+	if m != nil {
+		return m.EndorseInvalid
+	}
+	return false
+}
+
+func (m *ByzantineRequest) GetSpamHeights() uint64 {
+	if m != nil {
+		return m.SpamHeights
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Request)(nil), "sim.Request")
 	proto.RegisterType((*InitRequest)(nil), "sim.InitRequest")
 	proto.RegisterType((*Reply)(nil), "sim.Reply")
 	proto.RegisterType((*Proposal)(nil), "sim.Proposal")
 	proto.RegisterType((*Empty)(nil), "sim.Empty")
+	proto.RegisterType((*ByzantineRequest)(nil), "sim.ByzantineRequest")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -296,6 +381,7 @@ type SimulatorClient interface {
 	Ping(ctx context.Context, in *Request, opts ...grpc.CallOption) (Simulator_PingClient, error)
 	Init(ctx context.Context, in *InitRequest, opts ...grpc.CallOption) (Simulator_InitClient, error)
 	Exit(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
+	SetByzantine(ctx context.Context, in *ByzantineRequest, opts ...grpc.CallOption) (*Empty, error)
 }
 
 type simulatorClient struct {
@@ -379,11 +465,21 @@ func (c *simulatorClient) Exit(ctx context.Context, in *Empty, opts ...grpc.Call
 	return out, nil
 }
 
+func (c *simulatorClient) SetByzantine(ctx context.Context, in *ByzantineRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/sim.Simulator/SetByzantine", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SimulatorServer is the server API for Simulator service.
 type SimulatorServer interface {
 	Ping(*Request, Simulator_PingServer) error
 	Init(*InitRequest, Simulator_InitServer) error
 	Exit(context.Context, *Empty) (*Empty, error)
+	SetByzantine(context.Context, *ByzantineRequest) (*Empty, error)
 }
 
 func RegisterSimulatorServer(s *grpc.Server, srv SimulatorServer) {
@@ -450,6 +546,24 @@ func _Simulator_Exit_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Simulator_SetByzantine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ByzantineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulatorServer).SetByzantine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sim.Simulator/SetByzantine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulatorServer).SetByzantine(ctx, req.(*ByzantineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Simulator_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "sim.Simulator",
 	HandlerType: (*SimulatorServer)(nil),
@@ -458,6 +572,10 @@ var _Simulator_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Exit",
 			Handler:    _Simulator_Exit_Handler,
 		},
+		{
+			MethodName: "SetByzantine",
+			Handler:    _Simulator_SetByzantine_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -474,27 +592,34 @@ var _Simulator_serviceDesc = grpc.ServiceDesc{
 	Metadata: "consensus/sim/proto/sim.proto",
 }
 
-func init() { proto.RegisterFile("consensus/sim/proto/sim.proto", fileDescriptor_sim_4f723b2e18ed9d42) }
-
-var fileDescriptor_sim_4f723b2e18ed9d42 = []byte{
-	// 296 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x92, 0x51, 0x4b, 0xc3, 0x30,
-	0x14, 0x85, 0x17, 0xb7, 0xba, 0xed, 0xce, 0xe1, 0x08, 0x3e, 0x94, 0x82, 0x50, 0x8a, 0x0f, 0x05,
-	0x61, 0x13, 0xfd, 0x07, 0xc3, 0x15, 0x87, 0x08, 0x23, 0xf3, 0x0f, 0x44, 0xb9, 0x96, 0x40, 0x9a,
-	0xc4, 0x26, 0x15, 0xfb, 0xe6, 0x4f, 0x97, 0xa6, 0xad, 0x14, 0x5f, 0xc5, 0xb7, 0x73, 0x3e, 0xc2,
-	0xb9, 0x37, 0x27, 0x81, 0xcb, 0x57, 0xad, 0x2c, 0x2a, 0x5b, 0xd9, 0x8d, 0x15, 0xc5, 0xc6, 0x94,
-	0xda, 0xe9, 0x46, 0xad, 0xbd, 0xa2, 0x63, 0x2b, 0x8a, 0x04, 0x61, 0xca, 0xf0, 0xbd, 0x42, 0xeb,
-	0x68, 0x04, 0x33, 0x23, 0x79, 0x8d, 0xe5, 0xfe, 0x3e, 0x24, 0x31, 0x49, 0x03, 0xf6, 0xe3, 0x69,
-	0x0a, 0xe7, 0x42, 0x39, 0x2c, 0x15, 0x97, 0x4f, 0x36, 0x7f, 0xae, 0x0d, 0x86, 0x27, 0x31, 0x49,
-	0x97, 0xec, 0x37, 0xa6, 0x17, 0x10, 0x7c, 0x70, 0x59, 0x61, 0x38, 0x8e, 0x49, 0x3a, 0x67, 0xad,
-	0x49, 0x1e, 0x61, 0xb1, 0x57, 0xc2, 0xf5, 0xa3, 0x42, 0x98, 0xaa, 0x07, 0xad, 0xd0, 0xba, 0x6e,
-	0x52, 0x6f, 0xe9, 0x0a, 0xc6, 0x2a, 0x3b, 0xfa, 0xf0, 0x80, 0x35, 0xd2, 0x93, 0x6d, 0xe6, 0xe3,
-	0x1a, 0xb2, 0xcd, 0x12, 0x01, 0x01, 0x43, 0x23, 0x6b, 0x1a, 0xc3, 0xa2, 0x40, 0x6b, 0x79, 0x8e,
-	0x7e, 0xa3, 0x36, 0x6a, 0x88, 0xfe, 0xbc, 0xf7, 0x1b, 0xcc, 0x0e, 0xa5, 0x36, 0xda, 0x72, 0xf9,
-	0xaf, 0xfd, 0x4c, 0x21, 0xd8, 0x15, 0xc6, 0xd5, 0xb7, 0x5f, 0x04, 0xe6, 0x47, 0x51, 0x54, 0x92,
-	0x3b, 0x5d, 0xd2, 0x2b, 0x98, 0x1c, 0x84, 0xca, 0xe9, 0xd9, 0xba, 0x79, 0xb6, 0xae, 0xbd, 0x08,
-	0x3a, 0x67, 0x64, 0x9d, 0x8c, 0x6e, 0x08, 0xbd, 0x86, 0x49, 0x53, 0x2e, 0x5d, 0x79, 0x3e, 0xe8,
-	0x39, 0x5a, 0x7a, 0xd2, 0xdf, 0xc0, 0x1f, 0x8e, 0x61, 0xb2, 0xfb, 0x14, 0x8e, 0xb6, 0x21, 0x7e,
-	0x68, 0x34, 0xd0, 0xc9, 0xe8, 0xe5, 0xd4, 0x7f, 0x8f, 0xbb, 0xef, 0x00, 0x00, 0x00, 0xff, 0xff,
-	0x77, 0x0f, 0xa4, 0xf5, 0x3f, 0x02, 0x00, 0x00,
+func init() { proto.RegisterFile("consensus/sim/proto/sim.proto", fileDescriptor_sim_a0d6dd789df55817) }
+
+var fileDescriptor_sim_a0d6dd789df55817 = []byte{
+	// 407 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x93, 0xe1, 0x8a, 0xd4, 0x30,
+	0x10, 0xc7, 0x2f, 0x6e, 0x7b, 0xbb, 0x37, 0x77, 0xa7, 0x4b, 0x50, 0x08, 0x05, 0xa5, 0x14, 0x91,
+	0x82, 0x70, 0x27, 0xde, 0x1b, 0x2c, 0xee, 0x72, 0x8b, 0x08, 0x47, 0xd6, 0x17, 0x88, 0xd7, 0xb1,
+	0x1b, 0x48, 0x93, 0x5c, 0x93, 0xae, 0xd6, 0xf7, 0xf2, 0x5d, 0x7c, 0x1c, 0x69, 0xda, 0x3d, 0x6a,
+	0x3f, 0x09, 0xe2, 0xb7, 0xf9, 0xff, 0x9a, 0xcc, 0xfc, 0x3b, 0x33, 0x81, 0x97, 0xf7, 0x46, 0x3b,
+	0xd4, 0xae, 0x71, 0xd7, 0x4e, 0x56, 0xd7, 0xb6, 0x36, 0xde, 0x74, 0xd1, 0x55, 0x88, 0xe8, 0xcc,
+	0xc9, 0x2a, 0x43, 0x98, 0x73, 0x7c, 0x68, 0xd0, 0x79, 0x9a, 0xc0, 0xc2, 0x2a, 0xd1, 0x62, 0xbd,
+	0xfd, 0xc0, 0x48, 0x4a, 0xf2, 0x98, 0x3f, 0x6a, 0x9a, 0xc3, 0x33, 0xa9, 0x3d, 0xd6, 0x5a, 0xa8,
+	0x4f, 0xae, 0xfc, 0xdc, 0x5a, 0x64, 0x4f, 0x52, 0x92, 0x5f, 0xf2, 0x29, 0xa6, 0xcf, 0x21, 0x3e,
+	0x08, 0xd5, 0x20, 0x9b, 0xa5, 0x24, 0x3f, 0xe3, 0xbd, 0xc8, 0x3e, 0xc2, 0xf9, 0x56, 0x4b, 0x7f,
+	0x2c, 0xc5, 0x60, 0xae, 0x6f, 0x8d, 0x46, 0xe7, 0x87, 0x4a, 0x47, 0x49, 0x97, 0x30, 0xd3, 0x9b,
+	0x5d, 0x48, 0x1e, 0xf3, 0x2e, 0x0c, 0x64, 0xb5, 0x61, 0xb3, 0x81, 0xac, 0x36, 0x99, 0x84, 0x98,
+	0xa3, 0x55, 0x2d, 0x4d, 0xe1, 0xbc, 0x42, 0xe7, 0x44, 0x89, 0xc1, 0x51, 0x9f, 0x6a, 0x8c, 0xfe,
+	0xd9, 0xf7, 0x57, 0x58, 0xdc, 0xd5, 0xc6, 0x1a, 0x27, 0xd4, 0x7f, 0xed, 0xcf, 0x1c, 0xe2, 0x75,
+	0x65, 0x7d, 0x9b, 0xfd, 0x22, 0xb0, 0x5c, 0xb5, 0x3f, 0x84, 0xf6, 0x52, 0xe3, 0xdf, 0x4c, 0xe6,
+	0x15, 0x00, 0x3e, 0x34, 0xf2, 0x60, 0xee, 0x85, 0xef, 0x8b, 0x2e, 0xf8, 0x88, 0x74, 0xce, 0xbe,
+	0x49, 0xbf, 0xdf, 0x1b, 0x55, 0xac, 0x75, 0x61, 0x6a, 0xd7, 0x57, 0x5e, 0xf0, 0x29, 0xee, 0x9c,
+	0x15, 0xa8, 0x44, 0xcb, 0xa2, 0xe0, 0xbc, 0x17, 0xf4, 0x0d, 0x3c, 0xc5, 0xfe, 0xc0, 0x56, 0x1f,
+	0x84, 0x92, 0x05, 0x8b, 0xc3, 0xf5, 0x09, 0xed, 0x66, 0xe1, 0xac, 0xa8, 0x6e, 0x51, 0x96, 0x7b,
+	0xef, 0xd8, 0x69, 0x4a, 0xf2, 0x88, 0x8f, 0xd1, 0xfb, 0x9f, 0x04, 0xce, 0x76, 0xb2, 0x6a, 0x94,
+	0xf0, 0xa6, 0xa6, 0xaf, 0x21, 0xba, 0x93, 0xba, 0xa4, 0x17, 0x57, 0xdd, 0x46, 0x0e, 0x7f, 0x9a,
+	0xc0, 0xa0, 0xac, 0x6a, 0xb3, 0x93, 0x77, 0x84, 0xbe, 0x85, 0xa8, 0xdb, 0x1b, 0xba, 0x0c, 0x7c,
+	0xb4, 0x42, 0xc9, 0x65, 0x20, 0xc7, 0xe1, 0x84, 0xc3, 0x29, 0x44, 0xeb, 0xef, 0xd2, 0xd3, 0x3e,
+	0x49, 0xe8, 0x67, 0x32, 0x8a, 0xb3, 0x13, 0x7a, 0x03, 0x17, 0x3b, 0xf4, 0x8f, 0xfd, 0xa5, 0x2f,
+	0xc2, 0xd7, 0x69, 0xbf, 0xff, 0xbc, 0xf4, 0xe5, 0x34, 0x3c, 0x97, 0x9b, 0xdf, 0x03, 0x00, 0xb6,
+	0xdd, 0x98, 0x84, 0x4f, 0x03, 0x00, 0x00,
 }
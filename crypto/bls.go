@@ -28,6 +28,8 @@ const (
 	sigSize     = 5 // number of uint32s in sig
 	privkeySize = 5
 	numnodes    = 21
+	// NumNodes is the number of nodes which DKG runs among
+	NumNodes = numnodes
 )
 
 var (
@@ -183,6 +185,80 @@ func (b *bls) VerifyAggregate(ids [][]uint8, pubkeys [][]byte, msg []byte, aggsi
 	return errors.Wrap(ErrInvalidSignature, "Error when verify aggregate signature")
 }
 
+// AggregateSignatures aggregates the signatures of the signers over the same message into a multi-signature, which is
+// verified against the aggregate of their public keys
+func (b *bls) AggregateSignatures(sigs [][]byte) ([]byte, error) {
+	if len(sigs) == 0 {
+		return []byte{}, errors.Wrap(ErrInvalidSignature, "no signature to aggregate")
+	}
+	var sum C.ec160_point_pro
+	for i, sig := range sigs {
+		sigSer, err := b.signatureDeserialization(sig)
+		if err != nil {
+			return []byte{}, err
+		}
+		var point C.ec160_point_aff
+		if ok := C.point_decompression_mnt(&sigSer[0], &point); ok != 1 {
+			return []byte{}, errors.Wrapf(ErrInvalidSignature, "signature %d is not a point on the curve", i)
+		}
+		if i == 0 {
+			C.affine_to_project_mnt(&point, &sum)
+			continue
+		}
+		var next C.ec160_point_pro
+		var l [5][5]C.uint32_t
+		C.mixed_addition_mnt(&sum, &point, &next, &l[0], C.curve_only)
+		sum = next
+	}
+	var aggsig C.ec160_point_aff
+	C.project_to_affine_mnt(&sum, &aggsig)
+	var aggsigSer [sigSize]C.uint32_t
+	C.point_compression_mnt(&aggsig, &aggsigSer[0])
+	return b.signatureSerialization(aggsigSer)
+}
+
+// AggregatePubKeys aggregates the public keys of the signers, which a multi-signature of them is verified against
+func (b *bls) AggregatePubKeys(pubkeys [][]byte) ([]byte, error) {
+	if len(pubkeys) == 0 {
+		return []byte{}, errors.Wrap(ErrInvalidKey, "no public key to aggregate")
+	}
+	var sum C.ec_point_pro_twist
+	for i, pubkey := range pubkeys {
+		pk, err := twistPointDeserialization(pubkey)
+		if err != nil {
+			return []byte{}, err
+		}
+		if ok := C.bls_pk_validation(&pk); ok != 1 {
+			return []byte{}, errors.Wrapf(ErrInvalidKey, "public key %d is invalid", i)
+		}
+		if i == 0 {
+			sum.X = pk.x
+			sum.Y = pk.y
+			C.setoneFp3(&sum.Z)
+			continue
+		}
+		var next C.ec_point_pro_twist
+		C.mixed_addition_twist(&sum, &pk, &next)
+		sum = next
+	}
+	var aggpk C.ec_point_aff_twist
+	C.project_to_affine_twist(&sum, &aggpk)
+	return twistPointSerialization(aggpk)
+}
+
+// VerifyMultiSig verifies the multi-signature of the signers over the message against their public keys. As the public
+// keys are summed up, each of them should have been proven to be owned by its signer, e.g., with a signature over it.
+func (b *bls) VerifyMultiSig(pubkeys [][]byte, msg []byte, aggsig []byte) error {
+	aggpk, err := b.AggregatePubKeys(pubkeys)
+	if err != nil {
+		return err
+	}
+	if err := b.Verify(aggpk, msg, aggsig); err != nil {
+		return errors.Wrap(ErrInvalidSignature, "Error when verify multi-signature")
+	}
+	return nil
+}
+
 func (b *bls) signatureSerialization(sigSer [sigSize]C.uint32_t) ([]byte, error) {
 	var sig [sigSize]uint32
 	for i, x := range sigSer {
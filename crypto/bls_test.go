@@ -98,3 +98,35 @@ func TestTBLS(t *testing.T) {
 		require.NoError(err)
 	}
 }
+
+func TestMultiSig(t *testing.T) {
+	require := require.New(t)
+
+	message := []byte("hello iotex message")
+	pkList := make([][]byte, 5)
+	sigList := make([][]byte, 5)
+	for i := range pkList {
+		sk := DKG.SkGeneration()
+		var err error
+		pkList[i], err = BLS.NewPubKey(sk)
+		require.NoError(err)
+		_, sigList[i], err = BLS.Sign(sk, message)
+		require.NoError(err)
+	}
+
+	aggsig, err := BLS.AggregateSignatures(sigList)
+	require.NoError(err)
+	require.NoError(BLS.VerifyMultiSig(pkList, message, aggsig))
+	// A single signature is a multi-signature of its signer
+	aggsig, err = BLS.AggregateSignatures(sigList[:1])
+	require.NoError(err)
+	require.Equal(sigList[0], aggsig)
+	require.NoError(BLS.VerifyMultiSig(pkList[:1], message, aggsig))
+
+	// The multi-signature doesn't verify against the public keys of the other signers
+	aggsig, err = BLS.AggregateSignatures(sigList[:3])
+	require.NoError(err)
+	require.Error(BLS.VerifyMultiSig(pkList[:4], message, aggsig))
+	require.Error(BLS.VerifyMultiSig(pkList[1:4], message, aggsig))
+	require.Error(BLS.VerifyMultiSig(pkList[:3], []byte("another message"), aggsig))
+}
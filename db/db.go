@@ -9,6 +9,7 @@ package db
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/boltdb/bolt"
 	"github.com/pkg/errors"
@@ -133,7 +134,12 @@ func (m *memKVStore) Commit(b KVStoreBatch) error {
 	return b.Clear()
 }
 
-const fileMode = 0600
+const (
+	fileMode = 0600
+	// openTimeout is how long to wait for the file lock of the BoltDB, which is held by another process opening the
+	// same file
+	openTimeout = 10 * time.Second
+)
 
 // boltDB is KVStore implementation based bolt DB
 type boltDB struct {
@@ -157,9 +163,9 @@ func (b *boltDB) Start(_ context.Context) error {
 		return nil
 	}
 
-	db, err := bolt.Open(b.path, fileMode, nil)
+	db, err := bolt.Open(b.path, fileMode, &bolt.Options{Timeout: openTimeout})
 	if err != nil {
-		return err
+		return errors.Wrapf(err, "error when opening %s", b.path)
 	}
 	b.db = db
 	return nil
@@ -26,41 +26,87 @@ import (
 type Subscriber interface {
 	HandleAction(*pb.ActionPb) error
 	HandleBlock(*pb.BlockPb) error
-	HandleBlockSync(*pb.BlockPb) error
+	HandleBlockSync(net.Addr, *pb.BlockPb) error
 	HandleSyncRequest(string, *pb.BlockSync) error
+	HandleChainTip(string, *pb.ChainTip) error
+	HandleHeaderSyncRequest(string, *pb.BlockHeaderSync) error
+	HandleBlockHeaders(net.Addr, *pb.BlockHeaderContainer) error
 	HandleBlockPropose(*pb.ProposePb) error
 	HandleEndorse(*pb.EndorsePb) error
 }
 
+// PeerReporter is notified of the errors which the subscribers return when handling the messages from peers, so that
+// the peers sending invalid messages could be penalized
+type PeerReporter interface {
+	ReportPeer(sender net.Addr, msgType uint32, err error)
+}
+
 // Dispatcher is used by peers, handles incoming block and header notifications and relays announcements of new blocks.
 type Dispatcher interface {
 	lifecycle.StartStopper
 
 	// AddSubscriber adds to dispatcher
 	AddSubscriber(uint32, Subscriber)
+	// RemoveSubscriber removes the subscriber of the given chain from dispatcher
+	RemoveSubscriber(uint32)
+	// AttachPeerReporter attaches the reporter of the peers sending invalid messages
+	AttachPeerReporter(PeerReporter)
 	// HandleBroadcast handles the incoming broadcast message. The transportation layer semantics is at least once.
-	// That said, the handler is likely to receive duplicate messages.
-	HandleBroadcast(uint32, proto.Message, chan bool)
+	// That said, the handler is likely to receive duplicate messages. The sender is the peer relaying the message, which
+	// is nil if the message is from the node itself.
+	HandleBroadcast(uint32, net.Addr, proto.Message, chan bool)
 	// HandleTell handles the incoming tell message. The transportation layer semantics is exact once. The sender is
 	// given for the sake of replying the message
 	HandleTell(uint32, net.Addr, proto.Message, chan bool)
 }
 
-var requestMtc = prometheus.NewCounterVec(
-	prometheus.CounterOpts{
-		Name: "iotex_dispatch_request",
-		Help: "Dispatcher request counter.",
-	},
-	[]string{"method", "succeed"},
+var (
+	requestMtc = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_dispatch_request",
+			Help: "Dispatcher request counter.",
+		},
+		[]string{"method", "succeed"},
+	)
+	eventMtc = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_dispatch_event",
+			Help: "Dispatcher event counter.",
+		},
+		[]string{"lane", "status"},
+	)
+	queueMtc = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iotex_dispatch_queue",
+			Help: "Dispatcher pending event gauge.",
+		},
+		[]string{"lane"},
+	)
 )
 
 func init() {
 	prometheus.MustRegister(requestMtc)
+	prometheus.MustRegister(eventMtc)
+	prometheus.MustRegister(queueMtc)
+}
+
+// consensusMsg packages a proto consensus message.
+type consensusMsg struct {
+	chainID uint32
+	sender  net.Addr
+	msg     proto.Message
+	msgType uint32
+	done    chan bool
+}
+
+func (m consensusMsg) ChainID() uint32 {
+	return m.chainID
 }
 
 // blockMsg packages a proto block message.
 type blockMsg struct {
 	chainID uint32
+	sender  net.Addr
 	block   *pb.BlockPb
 	blkType uint32
 	done    chan bool
@@ -82,9 +128,24 @@ func (m blockSyncMsg) ChainID() uint32 {
 	return m.chainID
 }
 
+// headerSyncMsg packages a proto message of header-first block sync, i.e., a chain tip, a header sync request or the
+// headers in response.
+type headerSyncMsg struct {
+	chainID uint32
+	sender  net.Addr
+	msg     proto.Message
+	msgType uint32
+	done    chan bool
+}
+
+func (m headerSyncMsg) ChainID() uint32 {
+	return m.chainID
+}
+
 // actionMsg packages a proto action message.
 type actionMsg struct {
 	chainID uint32
+	sender  net.Addr
 	action  *pb.ActionPb
 	done    chan bool
 }
@@ -93,17 +154,73 @@ func (m actionMsg) ChainID() uint32 {
 	return m.chainID
 }
 
-// IotxDispatcher is the request and event dispatcher for iotx node.
-type IotxDispatcher struct {
-	started        int32
-	shutdown       int32
-	eventChan      chan interface{}
-	eventAudit     map[uint32]int
-	eventAuditLock sync.RWMutex
-	wg             sync.WaitGroup
-	quit           chan struct{}
+// The message classes, each of which is queued in its own lane. The lanes are listed in the order of priority.
+const (
+	consensusLane = iota
+	blockLane
+	syncLane
+	actionLane
+	numLanes
+)
+
+// lane is a bounded queue of the messages of a class
+type lane struct {
+	name   string
+	events chan interface{}
+	// weight is the max number of events handled in a round, while the other lanes have pending events as well
+	weight int
+	// dropOldest makes the lane drop the oldest pending event to take a new one when the lane is full. Otherwise, the
+	// new event is dropped.
+	dropOldest bool
+	mu         sync.Mutex
+}
 
-	subscribers map[uint32]Subscriber
+func newLane(name string, size uint, weight uint, dropOldest bool) *lane {
+	if weight == 0 {
+		weight = 1
+	}
+	return &lane{
+		name:       name,
+		events:     make(chan interface{}, size),
+		weight:     int(weight),
+		dropOldest: dropOldest,
+	}
+}
+
+// enqueue adds the event to the lane, and returns false if an event is dropped because the lane is full
+func (l *lane) enqueue(event interface{}) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	select {
+	case l.events <- event:
+		return true
+	default:
+	}
+	if !l.dropOldest {
+		return false
+	}
+	// The handler is the only other party receiving from the channel, so that there's room after the receipt
+	select {
+	case <-l.events:
+	default:
+	}
+	l.events <- event
+	return false
+}
+
+// IotxDispatcher is the request and event dispatcher for iotx node. The incoming messages are queued in the lanes of
+// their classes, and handled in weighted rounds, so that a flood of one class, e.g., actions, doesn't hold up the
+// others, e.g., consensus messages.
+type IotxDispatcher struct {
+	started  int32
+	shutdown int32
+	lanes    [numLanes]*lane
+	wg       sync.WaitGroup
+	quit     chan struct{}
+
+	subscribers   map[uint32]Subscriber
+	subscribersMU sync.RWMutex
+	reporter      PeerReporter
 }
 
 // NewDispatcher creates a new Dispatcher
@@ -111,11 +228,22 @@ func NewDispatcher(
 	cfg *config.Config,
 ) (Dispatcher, error) {
 	d := &IotxDispatcher{
-		eventChan:   make(chan interface{}, cfg.Dispatcher.EventChanSize),
-		eventAudit:  make(map[uint32]int),
 		quit:        make(chan struct{}),
 		subscribers: make(map[uint32]Subscriber),
 	}
+	size := func(size uint) uint {
+		if size == 0 {
+			return cfg.Dispatcher.EventChanSize
+		}
+		return size
+	}
+	// Stale consensus messages and blocks are less useful than the new ones, while the dropped sync messages are
+	// requested again and the dropped actions are broadcast again
+	d.lanes[consensusLane] = newLane(
+		"consensus", size(cfg.Dispatcher.ConsensusChanSize), cfg.Dispatcher.ConsensusWeight, true)
+	d.lanes[blockLane] = newLane("block", size(cfg.Dispatcher.BlockChanSize), cfg.Dispatcher.BlockWeight, true)
+	d.lanes[syncLane] = newLane("sync", size(cfg.Dispatcher.SyncChanSize), cfg.Dispatcher.SyncWeight, false)
+	d.lanes[actionLane] = newLane("action", size(cfg.Dispatcher.ActionChanSize), cfg.Dispatcher.ActionWeight, false)
 	return d, nil
 }
 
@@ -124,9 +252,31 @@ func (d *IotxDispatcher) AddSubscriber(
 	chainID uint32,
 	subscriber Subscriber,
 ) {
+	d.subscribersMU.Lock()
+	defer d.subscribersMU.Unlock()
 	d.subscribers[chainID] = subscriber
 }
 
+// RemoveSubscriber removes the subscriber of the given chain from dispatcher
+func (d *IotxDispatcher) RemoveSubscriber(chainID uint32) {
+	d.subscribersMU.Lock()
+	defer d.subscribersMU.Unlock()
+	delete(d.subscribers, chainID)
+}
+
+// AttachPeerReporter attaches the reporter of the peers sending invalid messages
+func (d *IotxDispatcher) AttachPeerReporter(reporter PeerReporter) {
+	d.reporter = reporter
+}
+
+// subscriber returns the subscriber of the given chain
+func (d *IotxDispatcher) subscriber(chainID uint32) (Subscriber, bool) {
+	d.subscribersMU.RLock()
+	defer d.subscribersMU.RUnlock()
+	subscriber, ok := d.subscribers[chainID]
+	return subscriber, ok
+}
+
 // Start starts the dispatcher.
 func (d *IotxDispatcher) Start(ctx context.Context) error {
 	if atomic.AddInt32(&d.started, 1) != 1 {
@@ -150,58 +300,121 @@ func (d *IotxDispatcher) Stop(ctx context.Context) error {
 	return nil
 }
 
-// EventChan returns the event chan
-func (d *IotxDispatcher) EventChan() *chan interface{} {
-	return &d.eventChan
-}
-
-// EventAudit returns the event audit map
-func (d *IotxDispatcher) EventAudit() map[uint32]int {
-	d.eventAuditLock.RLock()
-	defer d.eventAuditLock.RUnlock()
-	snapshot := make(map[uint32]int)
-	for k, v := range d.eventAudit {
-		snapshot[k] = v
+// PendingEvents returns the numbers of the pending events in the lanes
+func (d *IotxDispatcher) PendingEvents() map[string]int {
+	pending := make(map[string]int)
+	for _, l := range d.lanes {
+		pending[l.name] = len(l.events)
 	}
-	return snapshot
+	return pending
 }
 
-// newsHandler is the main handler for handling all news from peers.
+// newsHandler is the main handler for handling all news from peers. In each round, it handles up to the weight of
+// events from each lane in the order of priority, and waits for a new event once all the lanes are empty.
 func (d *IotxDispatcher) newsHandler() {
-loop:
+	defer func() {
+		d.wg.Done()
+		logger.Info().Msg("News handler done")
+	}()
 	for {
 		select {
-		case m := <-d.eventChan:
-			switch msg := m.(type) {
-			case *actionMsg:
-				d.handleActionMsg(msg)
-			case *blockMsg:
-				d.handleBlockMsg(msg)
-			case *blockSyncMsg:
-				d.handleBlockSyncMsg(msg)
-
-			default:
-				logger.Warn().
-					Str("msg", msg.(string)).
-					Msg("Invalid message type in block handler")
+		case <-d.quit:
+			return
+		default:
+		}
+		handled := 0
+		for i, l := range d.lanes {
+			for n := 0; n < l.weight && d.handleNext(i); n++ {
+				handled++
 			}
-
+		}
+		if handled > 0 {
+			continue
+		}
+		select {
+		case event := <-d.lanes[consensusLane].events:
+			d.handleEvent(consensusLane, event)
+		case event := <-d.lanes[blockLane].events:
+			d.handleEvent(blockLane, event)
+		case event := <-d.lanes[syncLane].events:
+			d.handleEvent(syncLane, event)
+		case event := <-d.lanes[actionLane].events:
+			d.handleEvent(actionLane, event)
 		case <-d.quit:
-			break loop
+			return
 		}
 	}
+}
+
+// handleNext handles the next event in the given lane, and returns false if the lane is empty
+func (d *IotxDispatcher) handleNext(i int) bool {
+	select {
+	case event := <-d.lanes[i].events:
+		d.handleEvent(i, event)
+		return true
+	default:
+		return false
+	}
+}
+
+// handleEvent handles an event taken from the given lane
+func (d *IotxDispatcher) handleEvent(i int, event interface{}) {
+	l := d.lanes[i]
+	queueMtc.WithLabelValues(l.name).Set(float64(len(l.events)))
+	eventMtc.WithLabelValues(l.name, "handled").Inc()
+	switch msg := event.(type) {
+	case *consensusMsg:
+		d.handleConsensusMsg(msg)
+	case *actionMsg:
+		d.handleActionMsg(msg)
+	case *blockMsg:
+		d.handleBlockMsg(msg)
+	case *blockSyncMsg:
+		d.handleBlockSyncMsg(msg)
+	case *headerSyncMsg:
+		d.handleHeaderSyncMsg(msg)
+	default:
+		logger.Warn().
+			Str("lane", l.name).
+			Msg("Invalid message type in block handler")
+	}
+}
 
-	d.wg.Done()
-	logger.Info().Msg("News handler done")
+// handleConsensusMsg handles consensusMsg from all peers.
+func (d *IotxDispatcher) handleConsensusMsg(m *consensusMsg) {
+	if subscriber, ok := d.subscriber(m.ChainID()); ok {
+		switch m.msgType {
+		case pb.MsgProposeProtoMsgType:
+			if err := subscriber.HandleBlockPropose(m.msg.(*pb.ProposePb)); err != nil {
+				logger.Error().
+					Err(err).
+					Msg("failed to handle block propose")
+				d.reportPeer(m.sender, m.msgType, err)
+			}
+		case pb.MsgEndorseProtoMsgType:
+			if err := subscriber.HandleEndorse(m.msg.(*pb.EndorsePb)); err != nil {
+				logger.Error().
+					Err(err).
+					Msg("failed to handle endorse")
+				d.reportPeer(m.sender, m.msgType, err)
+			}
+		}
+	} else {
+		logger.Info().Uint32("ChainID", m.ChainID()).Msg("No subscriber specified in the dispatcher")
+	}
+	// signal to let caller know we are done
+	if m.done != nil {
+		m.done <- true
+	}
 }
 
 // handleActionMsg handles actionMsg from all peers.
 func (d *IotxDispatcher) handleActionMsg(m *actionMsg) {
-	d.updateEventAudit(pb.MsgActionType)
-	if subscriber, ok := d.subscribers[m.ChainID()]; ok {
+	if subscriber, ok := d.subscriber(m.ChainID()); ok {
 		if err := subscriber.HandleAction(m.action); err != nil {
 			requestMtc.WithLabelValues("AddAction", "false").Inc()
 			logger.Debug().Err(err)
+			d.reportPeer(m.sender, pb.MsgActionType, err)
 		}
 	} else {
 		logger.Info().Uint32("ChainID", m.ChainID()).Msg("No subscriber specified in the dispatcher")
@@ -214,16 +427,16 @@ func (d *IotxDispatcher) handleActionMsg(m *actionMsg) {
 
 // handleBlockMsg handles blockMsg from peers.
 func (d *IotxDispatcher) handleBlockMsg(m *blockMsg) {
-	if subscriber, ok := d.subscribers[m.ChainID()]; ok {
+	if subscriber, ok := d.subscriber(m.ChainID()); ok {
 		if m.blkType == pb.MsgBlockProtoMsgType {
-			d.updateEventAudit(pb.MsgBlockProtoMsgType)
 			if err := subscriber.HandleBlock(m.block); err != nil {
 				logger.Error().Err(err).Msg("Fail to handle the block")
+				d.reportPeer(m.sender, m.blkType, err)
 			}
 		} else if m.blkType == pb.MsgBlockSyncDataType {
-			d.updateEventAudit(pb.MsgBlockSyncDataType)
-			if err := subscriber.HandleBlockSync(m.block); err != nil {
+			if err := subscriber.HandleBlockSync(m.sender, m.block); err != nil {
 				logger.Error().Err(err).Msg("Fail to sync the block")
+				d.reportPeer(m.sender, m.blkType, err)
 			}
 		}
 	} else {
@@ -244,8 +457,7 @@ func (d *IotxDispatcher) handleBlockSyncMsg(m *blockSyncMsg) {
 		Uint64("end", m.sync.End).
 		Msg("receive blockSyncMsg")
 
-	d.updateEventAudit(pb.MsgBlockSyncReqType)
-	if subscriber, ok := d.subscribers[m.ChainID()]; ok {
+	if subscriber, ok := d.subscriber(m.ChainID()); ok {
 		// dispatch to block sync
 		if err := subscriber.HandleSyncRequest(m.sender, m.sync); err != nil {
 			logger.Error().Err(err)
@@ -259,26 +471,63 @@ func (d *IotxDispatcher) handleBlockSyncMsg(m *blockSyncMsg) {
 	}
 }
 
+// handleHeaderSyncMsg handles the messages of header-first block sync from peers.
+func (d *IotxDispatcher) handleHeaderSyncMsg(m *headerSyncMsg) {
+	if subscriber, ok := d.subscriber(m.ChainID()); ok {
+		var err error
+		sender := m.sender.String()
+		switch m.msgType {
+		case pb.MsgChainTipType:
+			err = subscriber.HandleChainTip(sender, m.msg.(*pb.ChainTip))
+		case pb.MsgBlockHeaderSyncReqType:
+			err = subscriber.HandleHeaderSyncRequest(sender, m.msg.(*pb.BlockHeaderSync))
+		case pb.MsgBlockHeaderSyncDataType:
+			err = subscriber.HandleBlockHeaders(m.sender, m.msg.(*pb.BlockHeaderContainer))
+		}
+		if err != nil {
+			logger.Error().Err(err).Uint32("msgType", m.msgType).Msg("Fail to handle the header sync message")
+			d.reportPeer(m.sender, m.msgType, err)
+		}
+	} else {
+		logger.Info().Uint32("ChainID", m.ChainID()).Msg("No subscriber specified in the dispatcher")
+	}
+	// signal to let caller know we are done
+	if m.done != nil {
+		m.done <- true
+	}
+}
+
+// dispatchConsensus adds the passed consensus message to the news handling queue.
+func (d *IotxDispatcher) dispatchConsensus(chainID uint32, sender net.Addr, msgType uint32, msg proto.Message, done chan bool) {
+	if atomic.LoadInt32(&d.shutdown) != 0 {
+		if done != nil {
+			close(done)
+		}
+		return
+	}
+	d.enqueueEvent(consensusLane, &consensusMsg{chainID, sender, msg, msgType, done})
+}
+
 // dispatchAction adds the passed action message to the news handling queue.
-func (d *IotxDispatcher) dispatchAction(chainID uint32, msg proto.Message, done chan bool) {
+func (d *IotxDispatcher) dispatchAction(chainID uint32, sender net.Addr, msg proto.Message, done chan bool) {
 	if atomic.LoadInt32(&d.shutdown) != 0 {
 		if done != nil {
 			close(done)
 		}
 		return
 	}
-	d.enqueueEvent(&actionMsg{chainID, (msg).(*pb.ActionPb), done})
+	d.enqueueEvent(actionLane, &actionMsg{chainID, sender, (msg).(*pb.ActionPb), done})
 }
 
 // dispatchBlockCommit adds the passed block message to the news handling queue.
-func (d *IotxDispatcher) dispatchBlockCommit(chainID uint32, msg proto.Message, done chan bool) {
+func (d *IotxDispatcher) dispatchBlockCommit(chainID uint32, sender net.Addr, msg proto.Message, done chan bool) {
 	if atomic.LoadInt32(&d.shutdown) != 0 {
 		if done != nil {
 			close(done)
 		}
 		return
 	}
-	d.enqueueEvent(&blockMsg{chainID, (msg).(*pb.BlockPb), pb.MsgBlockProtoMsgType, done})
+	d.enqueueEvent(blockLane, &blockMsg{chainID, sender, (msg).(*pb.BlockPb), pb.MsgBlockProtoMsgType, done})
 }
 
 // dispatchBlockSyncReq adds the passed block sync request to the news handling queue.
@@ -289,11 +538,11 @@ func (d *IotxDispatcher) dispatchBlockSyncReq(chainID uint32, sender string, msg
 		}
 		return
 	}
-	d.enqueueEvent(&blockSyncMsg{chainID, sender, (msg).(*pb.BlockSync), done})
+	d.enqueueEvent(syncLane, &blockSyncMsg{chainID, sender, (msg).(*pb.BlockSync), done})
 }
 
 // dispatchBlockSyncData handles block sync data
-func (d *IotxDispatcher) dispatchBlockSyncData(chainID uint32, msg proto.Message, done chan bool) {
+func (d *IotxDispatcher) dispatchBlockSyncData(chainID uint32, sender net.Addr, msg proto.Message, done chan bool) {
 	if atomic.LoadInt32(&d.shutdown) != 0 {
 		if done != nil {
 			close(done)
@@ -301,19 +550,44 @@ func (d *IotxDispatcher) dispatchBlockSyncData(chainID uint32, msg proto.Message
 		return
 	}
 	data := (msg).(*pb.BlockContainer)
-	d.enqueueEvent(&blockMsg{chainID, data.Block, pb.MsgBlockSyncDataType, done})
+	if len(data.Blocks) == 0 {
+		d.enqueueEvent(syncLane, &blockMsg{chainID, sender, data.Block, pb.MsgBlockSyncDataType, done})
+		return
+	}
+	// A batch is handled block by block, and the caller is signaled once the last block is handled
+	blocks := data.Blocks
+	if data.Block != nil {
+		blocks = append([]*pb.BlockPb{data.Block}, blocks...)
+	}
+	for i, blk := range blocks {
+		var blkDone chan bool
+		if i == len(blocks)-1 {
+			blkDone = done
+		}
+		d.enqueueEvent(syncLane, &blockMsg{chainID, sender, blk, pb.MsgBlockSyncDataType, blkDone})
+	}
+}
+
+// dispatchHeaderSync adds the passed message of header-first block sync to the news handling queue.
+func (d *IotxDispatcher) dispatchHeaderSync(chainID uint32, sender net.Addr, msgType uint32, msg proto.Message, done chan bool) {
+	if atomic.LoadInt32(&d.shutdown) != 0 {
+		if done != nil {
+			close(done)
+		}
+		return
+	}
+	d.enqueueEvent(syncLane, &headerSyncMsg{chainID, sender, msg, msgType, done})
 }
 
 // HandleBroadcast handles incoming broadcast message
-func (d *IotxDispatcher) HandleBroadcast(chainID uint32, message proto.Message, done chan bool) {
+func (d *IotxDispatcher) HandleBroadcast(chainID uint32, sender net.Addr, message proto.Message, done chan bool) {
 	msgType, err := pb.GetTypeFromProtoMsg(message)
 	if err != nil {
 		logger.Warn().
 			Str("error", err.Error()).
 			Msg("unexpected message handled by HandleBroadcast")
 	}
-	subscriber, ok := d.subscribers[chainID]
-	if !ok {
+	if _, ok := d.subscriber(chainID); !ok {
 		logger.Warn().
 			Uint32("chainID", chainID).
 			Msg("chainID has not been registered in dispatcher")
@@ -321,30 +595,12 @@ func (d *IotxDispatcher) HandleBroadcast(chainID uint32, message proto.Message,
 	}
 
 	switch msgType {
-	case pb.MsgProposeProtoMsgType:
-		err := subscriber.HandleBlockPropose(message.(*pb.ProposePb))
-		if err != nil {
-			logger.Error().
-				Err(err).
-				Msg("failed to handle block propose")
-		}
-		if done != nil {
-			done <- true
-		}
-	case pb.MsgEndorseProtoMsgType:
-		err := subscriber.HandleEndorse(message.(*pb.EndorsePb))
-		if err != nil {
-			logger.Error().
-				Err(err).
-				Msg("failed to handle endorse")
-		}
-		if done != nil {
-			done <- true
-		}
+	case pb.MsgProposeProtoMsgType, pb.MsgEndorseProtoMsgType:
+		d.dispatchConsensus(chainID, sender, msgType, message, done)
 	case pb.MsgActionType:
-		d.dispatchAction(chainID, message, done)
+		d.dispatchAction(chainID, sender, message, done)
 	case pb.MsgBlockProtoMsgType:
-		d.dispatchBlockCommit(chainID, message, done)
+		d.dispatchBlockCommit(chainID, sender, message, done)
 	default:
 		logger.Warn().
 			Uint32("msgType", msgType).
@@ -364,7 +620,12 @@ func (d *IotxDispatcher) HandleTell(chainID uint32, sender net.Addr, message pro
 	case pb.MsgBlockSyncReqType:
 		d.dispatchBlockSyncReq(chainID, sender.String(), message, done)
 	case pb.MsgBlockSyncDataType:
-		d.dispatchBlockSyncData(chainID, message, done)
+		d.dispatchBlockSyncData(chainID, sender, message, done)
+	case pb.MsgChainTipType, pb.MsgBlockHeaderSyncReqType, pb.MsgBlockHeaderSyncDataType:
+		d.dispatchHeaderSync(chainID, sender, msgType, message, done)
+	case pb.MsgEndorseProtoMsgType:
+		// The endorses are sent to the proposer to aggregate
+		d.dispatchConsensus(chainID, sender, msgType, message, done)
 	default:
 		logger.Warn().
 			Uint32("msgType", msgType).
@@ -372,18 +633,19 @@ func (d *IotxDispatcher) HandleTell(chainID uint32, sender net.Addr, message pro
 	}
 }
 
-func (d *IotxDispatcher) enqueueEvent(event interface{}) {
-	go func() {
-		if len(d.eventChan) == cap(d.eventChan) {
-			logger.Warn().Msg("dispatcher event chan is full, drop an event")
-			return
-		}
-		d.eventChan <- event
-	}()
+func (d *IotxDispatcher) enqueueEvent(i int, event interface{}) {
+	l := d.lanes[i]
+	if !l.enqueue(event) {
+		eventMtc.WithLabelValues(l.name, "dropped").Inc()
+		logger.Warn().Str("lane", l.name).Msg("dispatcher lane is full, drop an event")
+	}
+	queueMtc.WithLabelValues(l.name).Set(float64(len(l.events)))
 }
 
-func (d *IotxDispatcher) updateEventAudit(t uint32) {
-	d.eventAuditLock.Lock()
-	defer d.eventAuditLock.Unlock()
-	d.eventAudit[t]++
+// reportPeer reports the error of handling the message from the sender, unless the message is from the node itself
+func (d *IotxDispatcher) reportPeer(sender net.Addr, msgType uint32, err error) {
+	if sender == nil || d.reporter == nil {
+		return
+	}
+	d.reporter.ReportPeer(sender, msgType, err)
 }
@@ -8,11 +8,15 @@ package dispatcher
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/network/node"
@@ -68,7 +72,7 @@ func TestHandleBroadcast(t *testing.T) {
 	done := make(chan bool, 1000)
 	for i := 0; i < 100; i++ {
 		for _, msg := range msgs {
-			d.HandleBroadcast(config.Default.Chain.ID, msg, done)
+			d.HandleBroadcast(config.Default.Chain.ID, node.NewTCPNode("192.168.0.0:10000"), msg, done)
 		}
 	}
 }
@@ -89,6 +93,128 @@ func TestHandleTell(t *testing.T) {
 	}
 }
 
+func TestReportPeer(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	cfg := &config.Config{
+		Consensus:  config.Consensus{Scheme: config.NOOPScheme},
+		Dispatcher: config.Dispatcher{EventChanSize: 1024},
+	}
+	d, err := NewDispatcher(cfg)
+	require.NoError(err)
+	d.AddSubscriber(config.Default.Chain.ID, &FailingSubscriber{})
+	reporter := &DummyReporter{}
+	d.AttachPeerReporter(reporter)
+	require.NoError(d.Start(ctx))
+	defer func() {
+		require.NoError(d.Stop(ctx))
+	}()
+
+	sender := node.NewTCPNode("192.168.0.0:10000")
+	done := make(chan bool, 1)
+	d.HandleBroadcast(config.Default.Chain.ID, sender, &pb.ActionPb{}, done)
+	<-done
+	d.HandleTell(config.Default.Chain.ID, sender, &pb.BlockContainer{Block: &pb.BlockPb{}}, done)
+	<-done
+	d.HandleTell(config.Default.Chain.ID, sender, &pb.BlockHeaderContainer{}, done)
+	<-done
+	d.HandleBroadcast(config.Default.Chain.ID, sender, &pb.EndorsePb{}, done)
+	<-done
+	// Messages from the node itself are not reported
+	d.HandleBroadcast(config.Default.Chain.ID, nil, &pb.BlockPb{}, done)
+	<-done
+
+	require.Equal(
+		[]uint32{pb.MsgActionType, pb.MsgBlockSyncDataType, pb.MsgBlockHeaderSyncDataType, pb.MsgEndorseProtoMsgType},
+		reporter.msgTypes,
+	)
+	for _, sender := range reporter.senders {
+		require.Equal("192.168.0.0:10000", sender)
+	}
+}
+
+func TestLanes(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	cfg := &config.Config{
+		Consensus: config.Consensus{Scheme: config.NOOPScheme},
+		Dispatcher: config.Dispatcher{
+			EventChanSize:     1024,
+			ConsensusChanSize: 2,
+			ActionChanSize:    2,
+		},
+	}
+	d, err := NewDispatcher(cfg)
+	require.NoError(err)
+	subscriber := &RecordingSubscriber{}
+	d.AddSubscriber(config.Default.Chain.ID, subscriber)
+
+	// The full action lane drops the new actions, while the full consensus lane drops the old messages
+	done := make(chan bool, 6)
+	for i := uint64(1); i <= 3; i++ {
+		d.HandleBroadcast(config.Default.Chain.ID, nil, &pb.ActionPb{Nonce: i}, done)
+		d.HandleBroadcast(config.Default.Chain.ID, nil, &pb.EndorsePb{Height: i}, done)
+	}
+	require.NoError(d.Start(ctx))
+	defer func() {
+		require.NoError(d.Stop(ctx))
+	}()
+	for i := 0; i < 4; i++ {
+		<-done
+	}
+	// Consensus messages are handled first in each round
+	require.Equal([]string{"endorse 2", "action 1", "endorse 3", "action 2"}, subscriber.handled)
+}
+
+type RecordingSubscriber struct {
+	DummySubscriber
+	handled []string
+}
+
+func (s *RecordingSubscriber) HandleAction(action *pb.ActionPb) error {
+	s.handled = append(s.handled, fmt.Sprintf("action %d", action.Nonce))
+	return nil
+}
+
+func (s *RecordingSubscriber) HandleEndorse(endorse *pb.EndorsePb) error {
+	s.handled = append(s.handled, fmt.Sprintf("endorse %d", endorse.Height))
+	return nil
+}
+
+type DummyReporter struct {
+	senders  []string
+	msgTypes []uint32
+}
+
+func (r *DummyReporter) ReportPeer(sender net.Addr, msgType uint32, _ error) {
+	r.senders = append(r.senders, sender.String())
+	r.msgTypes = append(r.msgTypes, msgType)
+}
+
+type FailingSubscriber struct {
+	DummySubscriber
+}
+
+func (s *FailingSubscriber) HandleBlock(*pb.BlockPb) error {
+	return errors.New("invalid block")
+}
+
+func (s *FailingSubscriber) HandleBlockSync(net.Addr, *pb.BlockPb) error {
+	return errors.New("invalid block")
+}
+
+func (s *FailingSubscriber) HandleBlockHeaders(net.Addr, *pb.BlockHeaderContainer) error {
+	return errors.New("invalid headers")
+}
+
+func (s *FailingSubscriber) HandleAction(*pb.ActionPb) error {
+	return errors.New("invalid action")
+}
+
+func (s *FailingSubscriber) HandleEndorse(*pb.EndorsePb) error {
+	return errors.New("invalid endorse")
+}
+
 type DummySubscriber struct {
 }
 
@@ -96,7 +222,7 @@ func (s *DummySubscriber) HandleBlock(*pb.BlockPb) error {
 	return nil
 }
 
-func (s *DummySubscriber) HandleBlockSync(*pb.BlockPb) error {
+func (s *DummySubscriber) HandleBlockSync(net.Addr, *pb.BlockPb) error {
 	return nil
 }
 
@@ -104,6 +230,18 @@ func (s *DummySubscriber) HandleSyncRequest(string, *pb.BlockSync) error {
 	return nil
 }
 
+func (s *DummySubscriber) HandleChainTip(string, *pb.ChainTip) error {
+	return nil
+}
+
+func (s *DummySubscriber) HandleHeaderSyncRequest(string, *pb.BlockHeaderSync) error {
+	return nil
+}
+
+func (s *DummySubscriber) HandleBlockHeaders(net.Addr, *pb.BlockHeaderContainer) error {
+	return nil
+}
+
 func (s *DummySubscriber) HandleAction(*pb.ActionPb) error {
 	return nil
 }
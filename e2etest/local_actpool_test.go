@@ -57,7 +57,8 @@ func TestLocalActPool(t *testing.T) {
 
 	// create client
 	cfg.Network.BootstrapNodes = []string{svr.P2P().Self().String()}
-	cli := network.NewOverlay(&cfg.Network)
+	cli, err := network.NewOverlay(&cfg.Network, cfg.Chain.ID)
+	require.NoError(err)
 	require.NotNil(cli)
 	require.NoError(cli.Start(ctx))
 
@@ -132,7 +133,8 @@ func TestPressureActPool(t *testing.T) {
 
 	// create client
 	cfg.Network.BootstrapNodes = []string{svr.P2P().Self().String()}
-	cli := network.NewOverlay(&cfg.Network)
+	cli, err := network.NewOverlay(&cfg.Network, cfg.Chain.ID)
+	require.NoError(err)
 	require.NotNil(cli)
 	require.Nil(cli.Start(ctx))
 
@@ -58,7 +58,8 @@ func TestLocalCommit(t *testing.T) {
 
 	// create client
 	cfg.Network.BootstrapNodes = []string{svr.P2P().Self().String()}
-	p := network.NewOverlay(&cfg.Network)
+	p, err := network.NewOverlay(&cfg.Network, cfg.Chain.ID)
+	require.NoError(err)
 	require.NotNil(p)
 	require.NoError(p.Start(ctx))
 
@@ -439,7 +440,8 @@ func TestVoteLocalCommit(t *testing.T) {
 	require.NotNil(svr.ChainService(chainID).ActionPool())
 
 	cfg.Network.BootstrapNodes = []string{svr.P2P().Self().String()}
-	p := network.NewOverlay(&cfg.Network)
+	p, err := network.NewOverlay(&cfg.Network, cfg.Chain.ID)
+	require.NoError(err)
 	require.NotNil(p)
 	require.NoError(p.Start(ctx))
 
@@ -699,7 +701,8 @@ func TestDummyBlockReplacement(t *testing.T) {
 
 	// create client
 	cfg.Network.BootstrapNodes = []string{svr.P2P().Self().String()}
-	p := network.NewOverlay(&cfg.Network)
+	p, err := network.NewOverlay(&cfg.Network, cfg.Chain.ID)
+	require.NoError(err)
 	require.NotNil(p)
 	require.NoError(p.Start(ctx))
 
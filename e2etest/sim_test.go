@@ -0,0 +1,124 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package e2etest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/clock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/chainservice"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/dispatcher"
+	"github.com/iotexproject/iotex-core/network/simnet"
+	ta "github.com/iotexproject/iotex-core/test/testaddress"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+// simNode is a chain service on a simulated overlay, with the real dispatcher in between
+type simNode struct {
+	p2p *simnet.Overlay
+	dp  dispatcher.Dispatcher
+	cs  *chainservice.ChainService
+}
+
+func newSimNode(t *testing.T, sb *simnet.Switchboard, addr string, cfg *config.Config) *simNode {
+	require := require.New(t)
+
+	p2p := sb.NewOverlay(addr)
+	dp, err := dispatcher.NewDispatcher(cfg)
+	require.NoError(err)
+	cs, err := chainservice.New(cfg, p2p, dp, chainservice.WithTesting())
+	require.NoError(err)
+	dp.AddSubscriber(cs.ChainID(), cs)
+	dp.AttachPeerReporter(p2p)
+	p2p.AttachDispatcher(dp)
+
+	ctx := context.Background()
+	require.NoError(dp.Start(ctx))
+	require.NoError(p2p.Start(ctx))
+	require.NoError(cs.Start(ctx))
+	return &simNode{p2p: p2p, dp: dp, cs: cs}
+}
+
+func (n *simNode) stop(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, n.cs.Stop(ctx))
+	require.NoError(t, n.p2p.Stop(ctx))
+	require.NoError(t, n.dp.Stop(ctx))
+}
+
+func addTestingEmptyBlock(bc blockchain.Blockchain) (*blockchain.Block, error) {
+	blk, err := bc.MintNewBlock(nil, nil, nil, nil, ta.Addrinfo["producer"], "")
+	if err != nil {
+		return nil, err
+	}
+	if err := bc.ValidateBlock(blk, true); err != nil {
+		return nil, err
+	}
+	return blk, bc.CommitBlock(blk)
+}
+
+func TestSimSync(t *testing.T) {
+	require := require.New(t)
+
+	cfg, err := newTestConfig()
+	require.NoError(err)
+	cfg.BlockSync.Interval = 10 * time.Millisecond
+
+	// The messages between the nodes are only delivered as the mock clock advances, so that the order they arrive in
+	// doesn't depend on the scheduling of the nodes
+	clk := clock.NewMock()
+	sb := simnet.NewSwitchboard(clk, 0)
+	sb.DefaultLink = simnet.Link{Latency: 50 * time.Millisecond}
+	tipOf := func(n *simNode, height uint64) testutil.CheckCondition {
+		return func() (bool, error) {
+			clk.Add(sb.DefaultLink.Latency)
+			return n.cs.Blockchain().TipHeight() == height, nil
+		}
+	}
+
+	svr := newSimNode(t, sb, "svr", cfg)
+	defer svr.stop(t)
+	require.NoError(addTestingTsfBlocks(svr.cs.Blockchain()))
+	require.Equal(uint64(5), svr.cs.Blockchain().TipHeight())
+
+	// The new node catches up with the tip the server advertises
+	cli := newSimNode(t, sb, "cli", cfg)
+	defer cli.stop(t)
+	require.NoError(testutil.WaitUntil(10*time.Millisecond, 5*time.Second, tipOf(cli, 5)))
+	for h := uint64(1); h <= 5; h++ {
+		expected, err := svr.cs.Blockchain().GetHashByHeight(h)
+		require.NoError(err)
+		synced, err := cli.cs.Blockchain().GetHashByHeight(h)
+		require.NoError(err)
+		require.Equal(expected, synced)
+	}
+
+	// The block broadcast while the node is cut off is missed, and synced once the network heals
+	sb.Partition([]string{"cli"})
+	blk, err := addTestingEmptyBlock(svr.cs.Blockchain())
+	require.NoError(err)
+	require.NoError(svr.p2p.Broadcast(cfg.Chain.ID, blk.ConvertToBlockPb()))
+	clk.Add(time.Second)
+	require.Equal(uint64(5), cli.cs.Blockchain().TipHeight())
+	sb.Heal()
+	require.NoError(testutil.WaitUntil(10*time.Millisecond, 5*time.Second, tipOf(cli, 6)))
+	synced, err := cli.cs.Blockchain().GetHashByHeight(6)
+	require.NoError(err)
+	require.Equal(blk.HashBlock(), synced)
+
+	// The blocks broadcast afterwards reach the node through the dispatcher as well
+	blk, err = addTestingEmptyBlock(svr.cs.Blockchain())
+	require.NoError(err)
+	require.NoError(svr.p2p.Broadcast(cfg.Chain.ID, blk.ConvertToBlockPb()))
+	require.NoError(testutil.WaitUntil(10*time.Millisecond, 5*time.Second, tipOf(cli, 7)))
+}
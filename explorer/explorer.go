@@ -16,6 +16,7 @@ import (
 	"github.com/iotexproject/iotex-core/actpool"
 	"github.com/iotexproject/iotex-core/blockchain"
 	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/blocksync"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/consensus"
 	"github.com/iotexproject/iotex-core/dispatcher"
@@ -61,6 +62,7 @@ type Service struct {
 	dp  dispatcher.Dispatcher
 	ap  actpool.ActPool
 	p2p network.Overlay
+	bs  blocksync.BlockSync
 	cfg config.Explorer
 }
 
@@ -895,7 +897,7 @@ func (exp *Service) SendTransfer(tsfJSON explorer.SendTransferRequest) (resp exp
 		return explorer.SendTransferResponse{}, err
 	}
 	// send to actpool via dispatcher
-	exp.dp.HandleBroadcast(exp.bc.ChainID(), actPb, nil)
+	exp.dp.HandleBroadcast(exp.bc.ChainID(), nil, actPb, nil)
 
 	tsf := &action.Transfer{}
 	tsf.ConvertFromActionPb(actPb)
@@ -942,7 +944,7 @@ func (exp *Service) SendVote(voteJSON explorer.SendVoteRequest) (resp explorer.S
 		return explorer.SendVoteResponse{}, err
 	}
 	// send to actpool via dispatcher
-	exp.dp.HandleBroadcast(exp.bc.ChainID(), actPb, nil)
+	exp.dp.HandleBroadcast(exp.bc.ChainID(), nil, actPb, nil)
 
 	v := &action.Vote{}
 	v.ConvertFromActionPb(actPb)
@@ -956,6 +958,7 @@ func (exp *Service) GetPeers() (explorer.GetPeersResponse, error) {
 	for _, p := range exp.p2p.GetPeers() {
 		peers = append(peers, explorer.Node{
 			Address: p.String(),
+			Score:   int64(exp.p2p.PeerScore(p)),
 		})
 	}
 	return explorer.GetPeersResponse{
@@ -1009,7 +1012,7 @@ func (exp *Service) SendSmartContract(execution explorer.Execution) (resp explor
 		return explorer.SendSmartContractResponse{}, err
 	}
 	// send to actpool via dispatcher
-	exp.dp.HandleBroadcast(exp.bc.ChainID(), actPb, nil)
+	exp.dp.HandleBroadcast(exp.bc.ChainID(), nil, actPb, nil)
 
 	sc := &action.Execution{}
 	sc.ConvertFromActionPb(actPb)
@@ -1076,6 +1079,32 @@ func (exp *Service) GetBlockOrActionByHash(hashStr string) (explorer.GetBlkOrAct
 	return explorer.GetBlkOrActResponse{}, nil
 }
 
+// GetSyncStatus gets the status of the block sync
+func (exp *Service) GetSyncStatus() (explorer.SyncStatus, error) {
+	if exp.bs == nil {
+		return explorer.SyncStatus{}, errors.New("block sync is not available")
+	}
+	status := exp.bs.SyncStatus()
+	peers := make([]explorer.SyncPeer, 0, len(status.Peers))
+	for _, p := range status.Peers {
+		peers = append(peers, explorer.SyncPeer{
+			Address:         p.Address,
+			TipHeight:       int64(p.TipHeight),
+			PendingRequests: int64(p.PendingRequests),
+			Failures:        int64(p.Failures),
+		})
+	}
+	return explorer.SyncStatus{
+		Synced:          status.Synced,
+		TipHeight:       int64(status.TipHeight),
+		TargetHeight:    int64(status.TargetHeight),
+		ConfirmedHeight: int64(status.ConfirmedHeight),
+		BlocksPerSecond: status.BlocksPerSecond,
+		EtaSeconds:      int64(status.ETA.Seconds()),
+		Peers:           peers,
+	}, nil
+}
+
 // getTransfer takes in a blockchain and transferHash and returns an Explorer Transfer
 func getTransfer(bc blockchain.Blockchain, ap actpool.ActPool, transferHash hash.Hash32B) (explorer.Transfer, error) {
 	explorerTransfer := explorer.Transfer{}
@@ -13,6 +13,7 @@ import (
 	"math/big"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/pkg/errors"
@@ -22,6 +23,7 @@ import (
 	"github.com/iotexproject/iotex-core/actpool"
 	"github.com/iotexproject/iotex-core/blockchain"
 	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/blocksync"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/consensus/scheme"
 	"github.com/iotexproject/iotex-core/explorer/idl/explorer"
@@ -29,6 +31,7 @@ import (
 	"github.com/iotexproject/iotex-core/pkg/keypair"
 	"github.com/iotexproject/iotex-core/state"
 	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
+	"github.com/iotexproject/iotex-core/test/mock/mock_blocksync"
 	"github.com/iotexproject/iotex-core/test/mock/mock_consensus"
 	"github.com/iotexproject/iotex-core/test/mock/mock_dispatcher"
 	"github.com/iotexproject/iotex-core/test/mock/mock_network"
@@ -528,7 +531,7 @@ func TestService_SendTransfer(t *testing.T) {
 	require.NotNil(err)
 
 	chain.EXPECT().ChainID().Return(uint32(1)).Times(2)
-	mDp.EXPECT().HandleBroadcast(gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	mDp.EXPECT().HandleBroadcast(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
 	p2p.EXPECT().Broadcast(gomock.Any(), gomock.Any()).Times(1)
 
 	r := explorer.SendTransferRequest{
@@ -563,7 +566,7 @@ func TestService_SendVote(t *testing.T) {
 	require.NotNil(err)
 
 	chain.EXPECT().ChainID().Return(uint32(1)).Times(2)
-	mDp.EXPECT().HandleBroadcast(gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	mDp.EXPECT().HandleBroadcast(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
 	p2p.EXPECT().Broadcast(gomock.Any(), gomock.Any()).Times(1)
 
 	r := explorer.SendVoteRequest{
@@ -599,7 +602,7 @@ func TestService_SendSmartContract(t *testing.T) {
 	explorerExecution.Signature = hex.EncodeToString(execution.Signature())
 
 	chain.EXPECT().ChainID().Return(uint32(1)).Times(2)
-	mDp.EXPECT().HandleBroadcast(gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	mDp.EXPECT().HandleBroadcast(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
 	p2p.EXPECT().Broadcast(gomock.Any(), gomock.Any()).Times(1)
 
 	response, err := svc.SendSmartContract(explorerExecution)
@@ -622,6 +625,7 @@ func TestServiceGetPeers(t *testing.T) {
 		&node.Node{Addr: "127.0.0.1:10003"},
 		&node.Node{Addr: "127.0.0.1:10004"},
 	})
+	p2p.EXPECT().PeerScore(gomock.Any()).Return(-10).Times(3)
 	p2p.EXPECT().Self().Return(&node.Node{Addr: "127.0.0.1:10001"})
 
 	response, err := svc.GetPeers()
@@ -629,6 +633,35 @@ func TestServiceGetPeers(t *testing.T) {
 	require.Equal("127.0.0.1:10001", response.Self.Address)
 	require.Len(response.Peers, 3)
 	require.Equal("127.0.0.1:10003", response.Peers[1].Address)
+	require.Equal(int64(-10), response.Peers[1].Score)
+}
+
+func TestServiceGetSyncStatus(t *testing.T) {
+	require := require.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	bs := mock_blocksync.NewMockBlockSync(ctrl)
+	svc := Service{bs: bs}
+
+	bs.EXPECT().SyncStatus().Return(blocksync.SyncStatus{
+		TipHeight:       10,
+		TargetHeight:    30,
+		ConfirmedHeight: 12,
+		BlocksPerSecond: 2,
+		ETA:             10 * time.Second,
+		Peers:           []blocksync.PeerSyncStatus{{Address: "127.0.0.1:10002", TipHeight: 30, Failures: 1}},
+	})
+
+	response, err := svc.GetSyncStatus()
+	require.Nil(err)
+	require.False(response.Synced)
+	require.Equal(int64(30), response.TargetHeight)
+	require.Equal(int64(12), response.ConfirmedHeight)
+	require.Equal(int64(10), response.EtaSeconds)
+	require.Len(response.Peers, 1)
+	require.Equal(int64(1), response.Peers[0].Failures)
 }
 
 func TestTransferPayloadBytesLimit(t *testing.T) {
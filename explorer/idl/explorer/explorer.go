@@ -9,8 +9,8 @@ import (
 )
 
 const BarristerVersion string = "0.1.6"
-const BarristerChecksum string = "97e0f2efe11dee3190b33a6c0cb7fda8"
-const BarristerDateGenerated int64 = 1538418246052000000
+const BarristerChecksum string = "15763c087b96a87eaf499b0835638c1e"
+const BarristerDateGenerated int64 = 1792382224772000000
 
 type CoinStatistic struct {
 	Height     int64 `json:"height"`
@@ -179,6 +179,7 @@ type SendVoteResponse struct {
 
 type Node struct {
 	Address string `json:"address"`
+	Score   int64  `json:"score"`
 }
 
 type GetPeersResponse struct {
@@ -197,6 +198,23 @@ type GetBlkOrActResponse struct {
 	Execution *Execution `json:"execution,omitempty"`
 }
 
+type SyncPeer struct {
+	Address         string `json:"address"`
+	TipHeight       int64  `json:"tipHeight"`
+	PendingRequests int64  `json:"pendingRequests"`
+	Failures        int64  `json:"failures"`
+}
+
+type SyncStatus struct {
+	Synced          bool       `json:"synced"`
+	TipHeight       int64      `json:"tipHeight"`
+	TargetHeight    int64      `json:"targetHeight"`
+	ConfirmedHeight int64      `json:"confirmedHeight"`
+	BlocksPerSecond float64    `json:"blocksPerSecond"`
+	EtaSeconds      int64      `json:"etaSeconds"`
+	Peers           []SyncPeer `json:"peers"`
+}
+
 type Explorer interface {
 	GetBlockchainHeight() (int64, error)
 	GetAddressBalance(address string) (int64, error)
@@ -229,6 +247,7 @@ type Explorer interface {
 	GetReceiptByExecutionID(id string) (Receipt, error)
 	ReadExecutionState(request Execution) (string, error)
 	GetBlockOrActionByHash(hashStr string) (GetBlkOrActResponse, error)
+	GetSyncStatus() (SyncStatus, error)
 }
 
 func NewExplorerProxy(c barrister.Client) Explorer {
@@ -798,6 +817,24 @@ func (_p ExplorerProxy) GetBlockOrActionByHash(hashStr string) (GetBlkOrActRespo
 	return GetBlkOrActResponse{}, _err
 }
 
+func (_p ExplorerProxy) GetSyncStatus() (SyncStatus, error) {
+	_res, _err := _p.client.Call("Explorer.getSyncStatus")
+	if _err == nil {
+		_retType := _p.idl.Method("Explorer.getSyncStatus").Returns
+		_res, _err = barrister.Convert(_p.idl, &_retType, reflect.TypeOf(SyncStatus{}), _res, "")
+	}
+	if _err == nil {
+		_cast, _ok := _res.(SyncStatus)
+		if !_ok {
+			_t := reflect.TypeOf(_res)
+			_msg := fmt.Sprintf("Explorer.getSyncStatus returned invalid type: %v", _t)
+			return SyncStatus{}, &barrister.JsonRpcError{Code: -32000, Message: _msg}
+		}
+		return _cast, nil
+	}
+	return SyncStatus{}, _err
+}
+
 func NewJSONServer(idl *barrister.Idl, forceASCII bool, explorer Explorer) barrister.Server {
 	return NewServer(idl, &barrister.JsonSerializer{forceASCII}, explorer)
 }
@@ -1910,6 +1947,13 @@ var IdlJsonRaw = `[
                 "optional": false,
                 "is_array": false,
                 "comment": ""
+            },
+            {
+                "name": "score",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
             }
         ],
         "values": null,
@@ -2009,6 +2053,111 @@ var IdlJsonRaw = `[
         "date_generated": 0,
         "checksum": ""
     },
+    {
+        "type": "struct",
+        "name": "SyncPeer",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
+            {
+                "name": "address",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "tipHeight",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "pendingRequests",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "failures",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "struct",
+        "name": "SyncStatus",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
+            {
+                "name": "synced",
+                "type": "bool",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "tipHeight",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "targetHeight",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "confirmedHeight",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "blocksPerSecond",
+                "type": "float",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "etaSeconds",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "peers",
+                "type": "SyncPeer",
+                "optional": false,
+                "is_array": true,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
     {
         "type": "interface",
         "name": "Explorer",
@@ -2779,6 +2928,18 @@ var IdlJsonRaw = `[
                     "is_array": false,
                     "comment": ""
                 }
+            },
+            {
+                "name": "getSyncStatus",
+                "comment": "get the status of the block sync",
+                "params": [],
+                "returns": {
+                    "name": "",
+                    "type": "SyncStatus",
+                    "optional": false,
+                    "is_array": false,
+                    "comment": ""
+                }
             }
         ],
         "barrister_version": "",
@@ -2795,7 +2956,7 @@ var IdlJsonRaw = `[
         "values": null,
         "functions": null,
         "barrister_version": "0.1.6",
-        "date_generated": 1538418246052,
-        "checksum": "97e0f2efe11dee3190b33a6c0cb7fda8"
+        "date_generated": 1792382224772,
+        "checksum": "15763c087b96a87eaf499b0835638c1e"
     }
 ]`
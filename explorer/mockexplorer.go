@@ -228,6 +228,11 @@ func (exp *MockExplorer) GetBlockOrActionByHash(hash string) (explorer.GetBlkOrA
 	return explorer.GetBlkOrActResponse{}, nil
 }
 
+// GetSyncStatus returns a synced status
+func (exp *MockExplorer) GetSyncStatus() (explorer.SyncStatus, error) {
+	return explorer.SyncStatus{Synced: true}, nil
+}
+
 func randInt64() int64 {
 	rand.Seed(time.Now().UnixNano())
 	amount := int64(0)
@@ -7,6 +7,7 @@
 package explorer
 
 import (
+	"encoding/json"
 	"net"
 	"net/http"
 	"strconv"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/iotexproject/iotex-core/actpool"
 	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blocksync"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/consensus"
 	"github.com/iotexproject/iotex-core/dispatcher"
@@ -42,6 +44,7 @@ func NewServer(
 	dispatcher dispatcher.Dispatcher,
 	actPool actpool.ActPool,
 	p2p network.Overlay,
+	bs blocksync.BlockSync,
 ) *Server {
 	return &Server{
 		cfg: cfg,
@@ -51,6 +54,7 @@ func NewServer(
 			dp:  dispatcher,
 			ap:  actPool,
 			p2p: p2p,
+			bs:  bs,
 			cfg: cfg,
 		},
 	}
@@ -72,7 +76,10 @@ func (s *Server) Start(_ context.Context) error {
 		idl := barrister.MustParseIdlJson([]byte(explorer.IdlJsonRaw))
 		s.jrpcSvr = explorer.NewJSONServer(idl, true, s.exp)
 		s.jrpcSvr.AddFilter(logFilter{})
-		s.httpSvr = http.Server{Handler: &s.jrpcSvr}
+		mux := http.NewServeMux()
+		mux.HandleFunc("/sync", s.serveSyncStatus)
+		mux.Handle("/", &s.jrpcSvr)
+		s.httpSvr = http.Server{Handler: mux}
 		listener, err := net.Listen("tcp", ":"+portStr)
 		if err != nil {
 			logger.Panic().Err(err).Msg("error when creating network listener")
@@ -111,6 +118,23 @@ func (s *Server) Port() int {
 // Explorer returns explorer interface.
 func (s *Server) Explorer() explorer.Explorer { return s.exp }
 
+// serveSyncStatus writes the status of the block sync, with status code 503 if the node is not synced, so that the load
+// balancers could route the requests to the synced nodes only
+func (s *Server) serveSyncStatus(w http.ResponseWriter, _ *http.Request) {
+	status, err := s.exp.GetSyncStatus()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if !status.Synced {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		logger.Error().Err(err).Msg("error when writing sync status")
+	}
+}
+
 // logFilter example of Filter implementation
 type logFilter struct{}
 
@@ -32,4 +32,8 @@ func TestServer(t *testing.T) {
 	} else {
 		require.Equal("200 OK", resp.Status)
 	}
+	// The mock explorer is synced
+	resp, err = client.Get("http://127.0.0.1:14004/sync")
+	require.NoError(err)
+	require.Equal(http.StatusOK, resp.StatusCode)
 }
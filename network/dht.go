@@ -0,0 +1,510 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package network
+
+import (
+	"bytes"
+	"context"
+	"math/bits"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/facebookgo/clock"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/logger"
+	pb "github.com/iotexproject/iotex-core/network/proto"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/lifecycle"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+)
+
+var _ lifecycle.StartStopper = (*DHT)(nil)
+
+// keyBits is the number of bits of a DHT key, which is also the number of buckets of a routing table
+const keyBits = hash.HashSize * 8
+
+// distance returns the XOR distance between two keys
+func distance(a, b hash.Hash32B) hash.Hash32B {
+	var d hash.Hash32B
+	for i := range d {
+		d[i] = a[i] ^ b[i]
+	}
+	return d
+}
+
+// commonPrefixLen returns the number of leading bits shared by two keys
+func commonPrefixLen(a, b hash.Hash32B) int {
+	d := distance(a, b)
+	for i, x := range d {
+		if x != 0 {
+			return i*8 + bits.LeadingZeros8(x)
+		}
+	}
+	return keyBits
+}
+
+// sortByDistance sorts the contacts by the distance between their keys and the target, closest first
+func sortByDistance(contacts []*contact, target hash.Hash32B) {
+	sort.Slice(contacts, func(i, j int) bool {
+		di := distance(contacts[i].id, target)
+		dj := distance(contacts[j].id, target)
+		return bytes.Compare(di[:], dj[:]) < 0
+	})
+}
+
+// contact is an entry of the routing table. The key of a contact is its node ID, which it has proven to own by a
+// handshake at the address.
+type contact struct {
+	id   hash.Hash32B
+	addr string
+}
+
+// RoutingTable is a Kademlia routing table. The contacts are kept in the bucket indexed by the length of the common
+// prefix between their keys and the one of this node, and each bucket holds at most k contacts ordered from the
+// least recently seen to the most recently seen.
+type RoutingTable struct {
+	self      hash.Hash32B
+	k         int
+	clk       clock.Clock
+	mu        sync.RWMutex
+	buckets   [keyBits][]*contact
+	refreshed [keyBits]time.Time
+}
+
+// NewRoutingTable creates an instance of RoutingTable
+func NewRoutingTable(self hash.Hash32B, k int, clk clock.Clock) *RoutingTable {
+	if k <= 0 {
+		k = 1
+	}
+	return &RoutingTable{self: self, k: k, clk: clk}
+}
+
+// Self returns the key of this node
+func (t *RoutingTable) Self() hash.Hash32B {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.self
+}
+
+// SetSelf changes the key of this node, and redistributes the existing contacts into the buckets
+func (t *RoutingTable) SetSelf(self hash.Hash32B) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var contacts []*contact
+	for i, b := range t.buckets {
+		contacts = append(contacts, b...)
+		t.buckets[i] = nil
+	}
+	t.self = self
+	for _, c := range contacts {
+		t.add(c)
+	}
+}
+
+// Add adds the node of the given ID at the given address into the routing table, or marks it as the most recently seen
+// if it is already there. A new contact is dropped if its bucket is full. It returns true if the contact is in the
+// routing table after that.
+func (t *RoutingTable) Add(id hash.Hash32B, addr string) bool {
+	added, _ := t.addOrLRU(id, addr)
+	return added
+}
+
+// addOrLRU adds the contact like Add. If the bucket is full, it returns the least recently seen contact of the bucket,
+// which the new contact may replace if the old one turns out to be offline.
+func (t *RoutingTable) addOrLRU(id hash.Hash32B, addr string) (bool, *contact) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.add(&contact{id: id, addr: addr})
+}
+
+func (t *RoutingTable) add(c *contact) (bool, *contact) {
+	i := commonPrefixLen(t.self, c.id)
+	if i == keyBits {
+		return false, nil
+	}
+	b := t.buckets[i]
+	for j, existing := range b {
+		if existing.id == c.id {
+			b = append(b[:j], b[j+1:]...)
+			break
+		}
+	}
+	if len(b) >= t.k {
+		return false, b[0]
+	}
+	t.buckets[i] = append(b, c)
+	t.refreshed[i] = t.clk.Now()
+	return true, nil
+}
+
+// replace replaces the least recently seen contact returned by addOrLRU with the new one, if the old one is still in
+// the routing table. It returns true if the new contact is added.
+func (t *RoutingTable) replace(old *contact, id hash.Hash32B, addr string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.remove(old.id) {
+		return false
+	}
+	added, _ := t.add(&contact{id: id, addr: addr})
+	return added
+}
+
+// Remove removes the node of the given ID from the routing table
+func (t *RoutingTable) Remove(id hash.Hash32B) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.remove(id)
+}
+
+func (t *RoutingTable) remove(id hash.Hash32B) bool {
+	i := commonPrefixLen(t.self, id)
+	if i == keyBits {
+		return false
+	}
+	b := t.buckets[i]
+	for j, c := range b {
+		if c.id == id {
+			t.buckets[i] = append(b[:j], b[j+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of contacts in the routing table
+func (t *RoutingTable) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	n := 0
+	for _, b := range t.buckets {
+		n += len(b)
+	}
+	return n
+}
+
+// Closest returns the addresses of at most n contacts closest to the target
+func (t *RoutingTable) Closest(target hash.Hash32B, n int) []string {
+	contacts := t.closest(target, n)
+	addrs := make([]string, 0, len(contacts))
+	for _, c := range contacts {
+		addrs = append(addrs, c.addr)
+	}
+	return addrs
+}
+
+func (t *RoutingTable) closest(target hash.Hash32B, n int) []*contact {
+	t.mu.RLock()
+	var contacts []*contact
+	for _, b := range t.buckets {
+		contacts = append(contacts, b...)
+	}
+	t.mu.RUnlock()
+
+	sortByDistance(contacts, target)
+	if len(contacts) > n {
+		contacts = contacts[:n]
+	}
+	return contacts
+}
+
+// Sample returns the addresses of at most n contacts, which are not skipped by the given function. The contacts are
+// picked from the buckets in a round-robin way, so that they spread over the key space rather than concentrate around
+// this node, and the most recently seen contacts of each bucket are picked first.
+func (t *RoutingTable) Sample(n int, skip func(string) bool) []string {
+	t.mu.RLock()
+	var groups [][]string
+	for _, b := range t.buckets {
+		var g []string
+		for i := len(b) - 1; i >= 0; i-- {
+			if !skip(b[i].addr) {
+				g = append(g, b[i].addr)
+			}
+		}
+		if len(g) > 0 {
+			groups = append(groups, g)
+		}
+	}
+	t.mu.RUnlock()
+
+	rand.Shuffle(len(groups), func(i, j int) { groups[i], groups[j] = groups[j], groups[i] })
+	var addrs []string
+	for round := 0; len(addrs) < n; round++ {
+		picked := false
+		for _, g := range groups {
+			if round < len(g) && len(addrs) < n {
+				addrs = append(addrs, g[round])
+				picked = true
+			}
+		}
+		if !picked {
+			break
+		}
+	}
+	return addrs
+}
+
+// staleBuckets returns the indexes of the buckets which have not been updated within the given interval. The buckets
+// beyond the deepest non-empty one are skipped, because it is unlikely to find any node sharing such a long prefix.
+func (t *RoutingTable) staleBuckets(interval time.Duration) []int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	deepest := -1
+	for i, b := range t.buckets {
+		if len(b) > 0 {
+			deepest = i
+		}
+	}
+	now := t.clk.Now()
+	var stale []int
+	for i := 0; i <= deepest && i < keyBits; i++ {
+		if now.Sub(t.refreshed[i]) >= interval {
+			stale = append(stale, i)
+			t.refreshed[i] = now
+		}
+	}
+	return stale
+}
+
+// randomID returns a random key which falls into the bucket of the given index
+func (t *RoutingTable) randomID(i int) hash.Hash32B {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var id hash.Hash32B
+	rand.Read(id[:])
+	for b := 0; b < i; b++ {
+		mask := byte(0x80) >> uint(b%8)
+		id[b/8] = id[b/8]&^mask | t.self[b/8]&mask
+	}
+	mask := byte(0x80) >> uint(i%8)
+	id[i/8] = id[i/8]&^mask | ^t.self[i/8]&mask
+	return id
+}
+
+// DHT discovers the nodes in the P2P network with Kademlia-style FIND_NODE lookups over the RPC transport. It is
+// only enabled with peer discovery and a positive bucket size. The contacts are keyed by their node IDs, and a node
+// only gets into the routing table after it answers a handshake from this node at its address.
+type DHT struct {
+	Overlay *IotxOverlay
+	Table   *RoutingTable
+
+	// pinging holds the IDs of the least recently seen contacts being pinged before being replaced
+	pinging sync.Map
+}
+
+// NewDHT creates an instance of DHT. The key of this node is set to the node ID once started.
+func NewDHT(o *IotxOverlay) *DHT {
+	return &DHT{
+		Overlay: o,
+		Table:   NewRoutingTable(hash.ZeroHash32B, o.Config.DHTBucketSize, clock.New()),
+	}
+}
+
+// Start sets the key of this node, and joins the DHT in the background
+func (d *DHT) Start(_ context.Context) error {
+	d.Table.SetSelf(d.Overlay.Identity.ID)
+	go d.Bootstrap()
+	return nil
+}
+
+// Stop does nothing
+func (d *DHT) Stop(_ context.Context) error { return nil }
+
+// Bootstrap seeds the routing table with the bootstrap nodes answering the handshakes, and looks up this node itself
+// to learn about the nodes close to it
+func (d *DHT) Bootstrap() {
+	for _, addr := range d.Overlay.Config.BootstrapNodes {
+		if addr == d.Overlay.RPC.String() {
+			continue
+		}
+		p, done, err := d.dial(addr, hash.ZeroHash32B)
+		if err != nil {
+			logger.Debug().Err(err).Str("dst", addr).Msg("failed to handshake with the bootstrap node")
+			continue
+		}
+		d.add(p.ID, addr)
+		done()
+	}
+	d.Lookup(d.Table.Self())
+}
+
+// Refresh looks up a random key in each bucket which has not been updated within the refresh interval. It
+// bootstraps again if all the contacts are gone.
+func (d *DHT) Refresh() {
+	if d.Table.Len() == 0 {
+		d.Bootstrap()
+		return
+	}
+	for _, i := range d.Table.staleBuckets(d.Overlay.Config.DHTRefreshInterval) {
+		d.Lookup(d.Table.randomID(i))
+	}
+}
+
+// Lookup iteratively queries the closest nodes known so far for the nodes closer to the target, until the closest
+// ones have all been queried. It returns the addresses of at most k nodes closest to the target. The node IDs in the
+// responses are only claimed, so that a node must prove its ID by a handshake before being queried. The nodes
+// answering the queries are added into the routing table, and the ones failing to are removed.
+func (d *DHT) Lookup(target hash.Hash32B) []string {
+	k := d.Table.k
+	alpha := d.Overlay.Config.DHTLookupConcurrency
+	if alpha <= 0 {
+		alpha = 1
+	}
+	shortlist := d.Table.closest(target, k)
+	seen := map[hash.Hash32B]bool{d.Table.Self(): true}
+	for _, c := range shortlist {
+		seen[c.id] = true
+	}
+	queried := make(map[hash.Hash32B]bool)
+	type reply struct {
+		c     *contact
+		found []*contact
+		err   error
+	}
+	for {
+		var batch []*contact
+		for _, c := range shortlist {
+			if len(batch) >= alpha {
+				break
+			}
+			if !queried[c.id] {
+				batch = append(batch, c)
+			}
+		}
+		if len(batch) == 0 {
+			break
+		}
+		replies := make(chan reply, len(batch))
+		for _, c := range batch {
+			queried[c.id] = true
+			go func(c *contact) {
+				found, err := d.findNode(c, target)
+				replies <- reply{c: c, found: found, err: err}
+			}(c)
+		}
+		failed := make(map[hash.Hash32B]bool)
+		for range batch {
+			r := <-replies
+			if r.err != nil {
+				logger.Debug().Err(r.err).Str("dst", r.c.addr).Msg("failed to find node")
+				d.Table.Remove(r.c.id)
+				failed[r.c.id] = true
+				continue
+			}
+			d.add(r.c.id, r.c.addr)
+			for _, c := range r.found {
+				if seen[c.id] || d.isBanned(c) {
+					continue
+				}
+				seen[c.id] = true
+				shortlist = append(shortlist, c)
+			}
+		}
+		alive := shortlist[:0]
+		for _, c := range shortlist {
+			if !failed[c.id] {
+				alive = append(alive, c)
+			}
+		}
+		shortlist = alive
+		sortByDistance(shortlist, target)
+		if len(shortlist) > k {
+			shortlist = shortlist[:k]
+		}
+	}
+	addrs := make([]string, 0, len(shortlist))
+	for _, c := range shortlist {
+		addrs = append(addrs, c.addr)
+	}
+	return addrs
+}
+
+// isBanned returns true if the node of the contact is banned by either its ID or its address
+func (d *DHT) isBanned(c *contact) bool {
+	return d.Overlay.Reputation.IsBanned(idKey(c.id)) || d.Overlay.Reputation.IsBanned(d.Overlay.peerKey(c.addr))
+}
+
+// add adds the node which has proven its ID at the address into the routing table. If the bucket is full, the least
+// recently seen contact of the bucket is pinged in the background. It is kept if it answers, as the nodes that have
+// stayed online for long are more likely to remain online, and otherwise replaced with the new one.
+func (d *DHT) add(id hash.Hash32B, addr string) {
+	added, lru := d.Table.addOrLRU(id, addr)
+	if added || lru == nil {
+		return
+	}
+	if _, loaded := d.pinging.LoadOrStore(lru.id, true); loaded {
+		return
+	}
+	go func() {
+		defer d.pinging.Delete(lru.id)
+		p, done, err := d.dial(lru.addr, lru.id)
+		if err != nil {
+			logger.Debug().Err(err).Str("dst", lru.addr).Msg("replace the unresponsive contact")
+			d.Table.replace(lru, id, addr)
+			return
+		}
+		done()
+		d.Table.Add(p.ID, lru.addr)
+	}()
+}
+
+// dial connects to the node at the given address, and makes it prove the ownership of the given node ID by a
+// handshake, unless it is already a peer of the ID. A zero ID accepts whatever the node proves. The returned function
+// must be called to close the temporary connection.
+func (d *DHT) dial(addr string, id hash.Hash32B) (*Peer, func(), error) {
+	p, done, err := d.Overlay.PM.peerOrDial(addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.ID == hash.ZeroHash32B {
+		// The ID is checked by the handshake over the temporary connection
+		p.ID = id
+		err = d.Overlay.handshake(p, rand.Uint64())
+	} else if id != hash.ZeroHash32B && p.ID != id {
+		err = errors.Wrapf(ErrNodeIDMismatch, "peer at %s", addr)
+	}
+	if err != nil {
+		done()
+		return nil, nil, err
+	}
+	return p, done, nil
+}
+
+// findNode asks the node of the contact for the nodes closest to the target. A temporary connection is made if the
+// node is not a peer.
+func (d *DHT) findNode(c *contact, target hash.Hash32B) ([]*contact, error) {
+	p, done, err := d.dial(c.addr, c.id)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	res, err := p.FindNode(&pb.FindNodeReq{Target: target[:], Addr: d.Overlay.RPC.String(), Count: uint32(d.Table.k)})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Id) != len(res.Addr) {
+		return nil, errors.Wrapf(ErrInvalidMsg, "%d node IDs for %d addresses", len(res.Id), len(res.Addr))
+	}
+	found := make([]*contact, 0, len(res.Addr))
+	for i, addr := range res.Addr {
+		if len(res.Id[i]) != hash.HashSize {
+			return nil, errors.Wrapf(ErrInvalidMsg, "node ID of %d bytes", len(res.Id[i]))
+		}
+		found = append(found, &contact{id: byteutil.BytesTo32B(res.Id[i]), addr: addr})
+	}
+	return found, nil
+}
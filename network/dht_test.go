@@ -0,0 +1,211 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package network
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/clock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+
+	pb "github.com/iotexproject/iotex-core/network/proto"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+// testKey returns a DHT key derived from the given string
+func testKey(s string) hash.Hash32B {
+	return byteutil.BytesTo32B(hash.Hash256b([]byte(s)))
+}
+
+// testKeysInBucket returns n keys falling into the bucket of the given index
+func testKeysInBucket(self hash.Hash32B, i int, n int) []hash.Hash32B {
+	var keys []hash.Hash32B
+	for j := 0; len(keys) < n; j++ {
+		if key := testKey(fmt.Sprintf("node%d", j)); commonPrefixLen(self, key) == i {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func TestRoutingTable(t *testing.T) {
+	require := require.New(t)
+
+	self := testKey("self")
+	table := NewRoutingTable(self, 2, clock.NewMock())
+	require.False(table.Add(self, "127.0.0.1:4689"))
+	require.Equal(0, table.Len())
+
+	// Pick the keys falling into the first bucket, which covers half of the key space
+	ids := testKeysInBucket(self, 0, 3)
+	addrs := []string{"127.0.0.1:10000", "127.0.0.1:10001", "127.0.0.1:10002"}
+	require.True(table.Add(ids[0], addrs[0]))
+	require.True(table.Add(ids[1], addrs[1]))
+	// The known contact is refreshed as the most recently seen one
+	require.True(table.Add(ids[0], addrs[0]))
+	// The bucket is full, so that the new contact is dropped, and the least recently seen one is returned
+	added, lru := table.addOrLRU(ids[2], addrs[2])
+	require.False(added)
+	require.Equal(ids[1], lru.id)
+	require.Equal(2, table.Len())
+	require.True(table.replace(lru, ids[2], addrs[2]))
+	require.False(table.replace(lru, ids[1], addrs[1]))
+	require.Equal(2, table.Len())
+	require.Equal([]string{addrs[2]}, table.Closest(ids[2], 1))
+
+	// The contact of a known ID moves to the new address
+	require.True(table.Add(ids[0], "127.0.0.1:20000"))
+	require.Equal([]string{"127.0.0.1:20000"}, table.Closest(ids[0], 1))
+	table.Remove(ids[0])
+	require.Equal(1, table.Len())
+	require.True(table.Add(ids[0], addrs[0]))
+
+	// Contacts are redistributed after changing the node ID
+	table.SetSelf(ids[0])
+	require.Equal(1, table.Len())
+	require.Equal([]string{addrs[2]}, table.Closest(ids[2], 10))
+}
+
+func TestRoutingTable_Closest(t *testing.T) {
+	require := require.New(t)
+
+	self := testKey("self")
+	table := NewRoutingTable(self, 50, clock.NewMock())
+	keys := make(map[string]hash.Hash32B)
+	for i := 0; i < 50; i++ {
+		addr := fmt.Sprintf("127.0.0.1:%d", 10000+i)
+		keys[addr] = testKey(addr)
+		table.Add(keys[addr], addr)
+	}
+	target := keys["127.0.0.1:10025"]
+	closest := table.Closest(target, 5)
+	require.Equal(5, len(closest))
+	require.Equal("127.0.0.1:10025", closest[0])
+	for i := 1; i < len(closest); i++ {
+		d1 := distance(keys[closest[i-1]], target)
+		d2 := distance(keys[closest[i]], target)
+		require.True(bytes.Compare(d1[:], d2[:]) < 0)
+	}
+
+	sample := table.Sample(10, func(addr string) bool { return addr == "127.0.0.1:10025" })
+	require.Equal(10, len(sample))
+	require.NotContains(sample, "127.0.0.1:10025")
+	require.Equal(table.Len()-1, len(table.Sample(100, func(addr string) bool { return addr == "127.0.0.1:10025" })))
+}
+
+func TestRoutingTable_Refresh(t *testing.T) {
+	require := require.New(t)
+
+	self := testKey("self")
+	clk := clock.NewMock()
+	table := NewRoutingTable(self, 16, clk)
+	for _, i := range []int{0, 1, 7, 8, 100, 255} {
+		require.Equal(i, commonPrefixLen(self, table.randomID(i)))
+	}
+
+	require.Equal(0, len(table.staleBuckets(time.Minute)))
+	table.Add(testKeysInBucket(self, 2, 1)[0], "127.0.0.1:10000")
+	// Only the empty buckets up to the deepest non-empty one are stale
+	require.Equal([]int{0, 1}, table.staleBuckets(time.Minute))
+	require.Equal(0, len(table.staleBuckets(time.Minute)))
+	clk.Add(time.Minute)
+	require.Equal([]int{0, 1, 2}, table.staleBuckets(time.Minute))
+}
+
+func newTestDHTOverlay(t *testing.T) *IotxOverlay {
+	cfg := LoadTestConfig("", true)
+	cfg.BootstrapNodes = nil
+	o := newTestHandshakeOverlay(t, cfg)
+	o.DHT = NewDHT(o)
+	o.DHT.Table.SetSelf(o.Identity.ID)
+	return o
+}
+
+func TestDHTLookup(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	var overlays []*IotxOverlay
+	for i := 0; i < 5; i++ {
+		o := newTestDHTOverlay(t)
+		defer func() {
+			require.NoError(o.RPC.Stop(ctx))
+		}()
+		overlays = append(overlays, o)
+	}
+	// Every node only knows the previous one
+	for i := 1; i < len(overlays); i++ {
+		overlays[i].DHT.Table.Add(overlays[i-1].Identity.ID, overlays[i-1].RPC.String())
+	}
+
+	last := overlays[len(overlays)-1]
+	found := last.DHT.Lookup(overlays[0].Identity.ID)
+	require.Equal(len(overlays)-1, len(found))
+	require.Equal(overlays[0].RPC.String(), found[0])
+	require.Equal(len(overlays)-1, last.DHT.Table.Len())
+	// The requester doesn't get into the routing tables of the queried nodes without answering a handshake
+	require.Equal(0, overlays[0].DHT.Table.Len())
+
+	// A target which is not a DHT key is rejected
+	p := NewTCPPeer(overlays[0].RPC.String())
+	require.NoError(p.Connect(overlays[0].Config))
+	defer func() {
+		require.NoError(p.Close())
+	}()
+	_, err := p.FindNode(&pb.FindNodeReq{Target: []byte{1, 2, 3}})
+	require.Error(err)
+
+	// Unreachable nodes are removed from the routing table
+	unreachable := testKey("unreachable")
+	overlays[0].DHT.Table.Add(unreachable, "127.0.0.1:1")
+	overlays[0].DHT.Lookup(unreachable)
+	require.NotContains(overlays[0].DHT.Table.Closest(unreachable, 100), "127.0.0.1:1")
+
+	// The node failing to prove the claimed ID at the address is removed as well
+	forged := testKey("forged")
+	overlays[0].DHT.Table.Add(forged, overlays[1].RPC.String())
+	overlays[0].DHT.Lookup(forged)
+	require.Equal(0, overlays[0].DHT.Table.Len())
+}
+
+func TestDHTReplaceUnresponsiveContact(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	o1 := newTestDHTOverlay(t)
+	o2 := newTestDHTOverlay(t)
+	defer func() {
+		require.NoError(o1.RPC.Stop(ctx))
+		require.NoError(o2.RPC.Stop(ctx))
+	}()
+	d := o1.DHT
+	d.Table = NewRoutingTable(o1.Identity.ID, 1, clock.New())
+	i := commonPrefixLen(o1.Identity.ID, o2.Identity.ID)
+	keys := testKeysInBucket(o1.Identity.ID, i, 2)
+	pinged := func() (bool, error) {
+		return LenSyncMap(&d.pinging) == 0, nil
+	}
+
+	// The least recently seen contact answering the ping is kept
+	require.True(d.Table.Add(o2.Identity.ID, o2.RPC.String()))
+	d.add(keys[0], "127.0.0.1:2")
+	require.NoError(testutil.WaitUntil(10*time.Millisecond, 2*time.Second, pinged))
+	require.Equal([]string{o2.RPC.String()}, d.Table.Closest(o2.Identity.ID, 10))
+
+	// The unresponsive one is replaced with the new contact
+	d.Table.Remove(o2.Identity.ID)
+	require.True(d.Table.Add(keys[0], "127.0.0.1:1"))
+	d.add(keys[1], "127.0.0.1:2")
+	require.NoError(testutil.WaitUntil(10*time.Millisecond, 2*time.Second, pinged))
+	require.Equal([]string{"127.0.0.1:2"}, d.Table.Closest(keys[1], 10))
+}
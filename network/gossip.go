@@ -7,35 +7,52 @@
 package network
 
 import (
+	"bytes"
 	"context"
 	"sync"
 	"time"
 
 	"encoding/hex"
 
+	"github.com/pkg/errors"
+
 	"github.com/iotexproject/iotex-core/dispatcher"
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/network/proto"
+	"github.com/iotexproject/iotex-core/pkg/hash"
 	"github.com/iotexproject/iotex-core/pkg/lifecycle"
 	"github.com/iotexproject/iotex-core/pkg/routine"
 	"github.com/iotexproject/iotex-core/proto"
 )
 
-// Gossip relays messages in the IotxOverlay (at least once semantics)
+// Gossip relays messages in the IotxOverlay (at least once semantics). The messages larger than the announce threshold
+// are relayed by announcing their checksums, and the receivers fetch the bodies they haven't seen from the announcers.
 type Gossip struct {
-	Overlay     *IotxOverlay
-	Dispatcher  dispatcher.Dispatcher
-	MsgLogs     *sync.Map
+	Overlay    *IotxOverlay
+	Dispatcher dispatcher.Dispatcher
+	MsgLogs    *sync.Map
+	// Bodies keeps the announced messages to serve the fetch requests, which are keyed by the hex encoded checksums
+	Bodies      *sync.Map
 	CleanerTask *routine.RecurringTask
 
+	// fetching marks the checksums of the messages being fetched, so that a message is fetched once at a time
+	fetching  *sync.Map
 	lifecycle lifecycle.Lifecycle
 }
 
+// announcedMsg is a message body kept for the peers to fetch
+type announcedMsg struct {
+	msgBody []byte
+	time    time.Time
+}
+
 // NewGossip generates a Gossip instance
 func NewGossip(o *IotxOverlay) *Gossip {
 	g := &Gossip{
-		Overlay: o,
-		MsgLogs: &sync.Map{},
+		Overlay:  o,
+		MsgLogs:  &sync.Map{},
+		Bodies:   &sync.Map{},
+		fetching: &sync.Map{},
 	}
 	cleaner := NewMsgLogsCleaner(g)
 	g.CleanerTask = routine.NewRecurringTask(cleaner.Clean, o.Config.MsgLogsCleaningInterval)
@@ -54,14 +71,15 @@ func (g *Gossip) AttachDispatcher(dispatcher dispatcher.Dispatcher) {
 	g.Dispatcher = dispatcher
 }
 
-// OnReceivingMsg listens to and handles the incoming broadcast message
-func (g *Gossip) OnReceivingMsg(msg *network.BroadcastReq) error {
+// OnReceivingMsg listens to and handles the incoming broadcast message. The key is the reputation key of the connection
+// the message arrives on.
+func (g *Gossip) OnReceivingMsg(msg *network.BroadcastReq, key string) error {
 	checksumStr := hex.EncodeToString(msg.MsgChecksum)
 	if _, loaded := g.MsgLogs.LoadOrStore(checksumStr, time.Now()); loaded {
 		return nil
 	}
 	// Call dispatch to notify that a new message comes in
-	if err := g.processMsg(msg.ChainId, msg.MsgType, msg.MsgBody); err != nil {
+	if err := g.processMsg(msg.ChainId, msg.Addr, key, msg.MsgType, msg.MsgBody); err != nil {
 		return err
 	}
 	// If other nodes use a crazy TTL, truncate it to the local configured value
@@ -82,18 +100,80 @@ func (g *Gossip) OnReceivingMsg(msg *network.BroadcastReq) error {
 	return nil
 }
 
-func (g *Gossip) processMsg(chainID uint32, msgType uint32, msgBody []byte) error {
+// OnReceivingAnnounce fetches the body of the announced message from the announcer if the message hasn't been seen, and
+// then handles it as a broadcast message. The key is the reputation key of the connection the announcement arrives on.
+func (g *Gossip) OnReceivingAnnounce(msg *network.AnnounceReq, key string) error {
+	checksumStr := hex.EncodeToString(msg.MsgChecksum)
+	if _, ok := g.MsgLogs.Load(checksumStr); ok {
+		return nil
+	}
+	if _, loaded := g.fetching.LoadOrStore(checksumStr, true); loaded {
+		return nil
+	}
+	go func() {
+		defer g.fetching.Delete(checksumStr)
+		if err := g.fetchMsg(msg, key); err != nil {
+			logger.Debug().
+				Err(err).
+				Str("src", msg.Addr).
+				Uint32("msg-type", msg.MsgType).
+				Str("msg-checksum", checksumStr).
+				Msg("failed to fetch an announced message")
+		}
+	}()
+	return nil
+}
+
+// fetchMsg fetches the body of the announced message from the peer the announcement arrives from, and checks it against
+// the announced checksum. The address in the announcement isn't dialed, so that the announcer can't direct the fetch to
+// another node, and a body not matching the checksum is always attributed to the peer serving it.
+func (g *Gossip) fetchMsg(msg *network.AnnounceReq, key string) error {
+	p := g.Overlay.PM.peerByKey(key)
+	if p == nil {
+		return errors.Wrapf(ErrInvalidMsg, "announcer %s is not a peer", key)
+	}
+	res, err := p.Fetch(&network.FetchReq{MsgChecksum: msg.MsgChecksum, Addr: g.Overlay.RPC.String()})
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(hash.Hash256b(res.MsgBody), msg.MsgChecksum) {
+		g.Overlay.penalize(g.Overlay.keyOf(p), MismatchedFetchResponse)
+		return errors.Wrap(ErrInvalidMsg, "fetched message body doesn't match the announced checksum")
+	}
+	return g.OnReceivingMsg(&network.BroadcastReq{
+		ChainId:     msg.ChainId,
+		MsgType:     msg.MsgType,
+		MsgBody:     res.MsgBody,
+		MsgChecksum: msg.MsgChecksum,
+		Ttl:         msg.Ttl,
+		Addr:        msg.Addr,
+	}, key)
+}
+
+// announcedBody returns the body of an announced message
+func (g *Gossip) announcedBody(msgChecksum []byte) ([]byte, bool) {
+	value, ok := g.Bodies.Load(hex.EncodeToString(msgChecksum))
+	if !ok {
+		return nil, false
+	}
+	return value.(*announcedMsg).msgBody, true
+}
+
+func (g *Gossip) processMsg(chainID uint32, addr string, key string, msgType uint32, msgBody []byte) error {
 	protoMsg, err := iproto.TypifyProtoMsg(msgType, msgBody)
 	if err != nil {
 		return err
 	}
 	if g.Dispatcher != nil {
-		g.Dispatcher.HandleBroadcast(chainID, protoMsg, nil)
+		g.Dispatcher.HandleBroadcast(chainID, newRemoteSender(addr, key), protoMsg, nil)
 	}
 	return nil
 }
 
 func (g *Gossip) relayMsg(chainID uint32, msgType uint32, msgBody []byte, msgChecksum []byte, ttl int32) error {
+	if threshold := g.Overlay.Config.GossipAnnounceThreshold; threshold > 0 && len(msgBody) > threshold {
+		return g.announceMsg(chainID, msgType, msgBody, msgChecksum, ttl)
+	}
 	// Send the message to all neighbors
 	g.Overlay.PM.Peers.Range(func(_, value interface{}) bool {
 		go func() {
@@ -109,6 +189,7 @@ func (g *Gossip) relayMsg(chainID uint32, msgType uint32, msgBody []byte, msgChe
 					MsgBody:     msgBody,
 					MsgChecksum: msgChecksum,
 					Ttl:         ttl,
+					Addr:        g.Overlay.RPC.String(),
 				},
 			)
 			if err != nil {
@@ -126,6 +207,40 @@ func (g *Gossip) relayMsg(chainID uint32, msgType uint32, msgBody []byte, msgChe
 	return nil
 }
 
+// announceMsg keeps the message body for the peers to fetch, and announces its checksum to all neighbors
+func (g *Gossip) announceMsg(chainID uint32, msgType uint32, msgBody []byte, msgChecksum []byte, ttl int32) error {
+	g.Bodies.Store(hex.EncodeToString(msgChecksum), &announcedMsg{msgBody: msgBody, time: time.Now()})
+	g.Overlay.PM.Peers.Range(func(_, value interface{}) bool {
+		go func() {
+			peer, ok := value.(*Peer)
+			if !ok {
+				logger.Error().Msg("value is not an instance of Peer")
+				return
+			}
+			_, err := peer.Announce(
+				&network.AnnounceReq{
+					ChainId:     chainID,
+					MsgType:     msgType,
+					MsgChecksum: msgChecksum,
+					Ttl:         ttl,
+					Addr:        g.Overlay.RPC.String(),
+				},
+			)
+			if err != nil {
+				logger.Error().
+					Err(err).
+					Str("dst", peer.String()).
+					Uint32("msg-type", msgType).
+					Str("msg-checksum", hex.EncodeToString(msgChecksum)).
+					Int32("ttl", ttl).
+					Msg("failed to announce a message")
+			}
+		}()
+		return true
+	})
+	return nil
+}
+
 // MsgLogsCleaner periodically refreshes the recent received message log
 type MsgLogsCleaner struct {
 	G *Gossip
@@ -137,7 +252,7 @@ func NewMsgLogsCleaner(g *Gossip) *MsgLogsCleaner {
 	return c
 }
 
-// Clean cleans logs
+// Clean cleans logs and the announced message bodies
 func (c *MsgLogsCleaner) Clean() {
 	var keys []string
 	c.G.MsgLogs.Range(func(key, value interface{}) bool {
@@ -149,4 +264,14 @@ func (c *MsgLogsCleaner) Clean() {
 	for _, key := range keys {
 		c.G.MsgLogs.Delete(key)
 	}
+	keys = nil
+	c.G.Bodies.Range(func(key, value interface{}) bool {
+		if time.Since(value.(*announcedMsg).time) > c.G.Overlay.Config.GossipBodyRetention {
+			keys = append(keys, key.(string))
+		}
+		return true
+	})
+	for _, key := range keys {
+		c.G.Bodies.Delete(key)
+	}
 }
@@ -0,0 +1,102 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package network
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	pb "github.com/iotexproject/iotex-core/network/proto"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/proto"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func newTestGossipOverlay(t *testing.T, cfg *config.Network) *IotxOverlay {
+	o := newTestHandshakeOverlay(t, cfg)
+	o.Gossip = NewGossip(o)
+	o.Gossip.AttachDispatcher(&MockDispatcher{})
+	return o
+}
+
+func TestGossipAnnounceAndFetch(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	cfg1 := LoadTestConfig("", true)
+	cfg1.GossipAnnounceThreshold = 64
+	o1 := newTestGossipOverlay(t, cfg1)
+	o2 := newTestGossipOverlay(t, LoadTestConfig("", true))
+	defer func() {
+		require.NoError(o1.RPC.Stop(ctx))
+		require.NoError(o2.RPC.Stop(ctx))
+	}()
+	o1.PM.AddPeer(o2.RPC.String())
+	require.NotNil(o1.PM.peerByAddr(o2.RPC.String()))
+
+	received := func(msg proto.Message) func() (bool, error) {
+		body, err := proto.Marshal(msg)
+		require.NoError(err)
+		return func() (bool, error) {
+			_, ok := o2.Gossip.MsgLogs.Load(hex.EncodeToString(hash.Hash256b(body)))
+			return ok, nil
+		}
+	}
+
+	// Small messages are pushed in full
+	small := &iproto.ActionPb{Nonce: 1}
+	require.NoError(o1.Broadcast(1, small))
+	require.NoError(testutil.WaitUntil(10*time.Millisecond, 2*time.Second, received(small)))
+	require.Equal(uint(0), LenSyncMap(o1.Gossip.Bodies))
+
+	// Large messages are announced, and the receiver fetches the bodies
+	large := &iproto.ActionPb{Nonce: 2, Signature: make([]byte, 128)}
+	require.NoError(o1.Broadcast(1, large))
+	require.NoError(testutil.WaitUntil(10*time.Millisecond, 2*time.Second, received(large)))
+	require.Equal(uint(1), LenSyncMap(o1.Gossip.Bodies))
+
+	// A body not matching the announced checksum is rejected, and the announcer is penalized
+	key := idKey(o1.Identity.ID)
+	checksum := hash.Hash256b([]byte("checksum"))
+	o1.Gossip.Bodies.Store(hex.EncodeToString(checksum), &announcedMsg{msgBody: []byte("body"), time: time.Now()})
+	err := o2.Gossip.fetchMsg(&pb.AnnounceReq{
+		MsgType:     iproto.MsgActionType,
+		MsgChecksum: checksum,
+		Ttl:         1,
+		Addr:        o1.RPC.String(),
+	}, key)
+	require.Error(err)
+	require.Equal(-penalties[MismatchedFetchResponse], o2.Reputation.Score(key))
+
+	// Unknown bodies cannot be fetched
+	require.Error(o2.Gossip.fetchMsg(&pb.AnnounceReq{
+		MsgChecksum: hash.Hash256b([]byte("unknown")),
+		Addr:        o1.RPC.String(),
+	}, key))
+
+	// The bodies are only fetched from the peer the announcement arrives from, whatever address it claims
+	o3 := newTestGossipOverlay(t, LoadTestConfig("", true))
+	defer func() {
+		require.NoError(o3.RPC.Stop(ctx))
+	}()
+	body, err := proto.Marshal(large)
+	require.NoError(err)
+	err = o3.Gossip.fetchMsg(&pb.AnnounceReq{
+		MsgType:     iproto.MsgActionType,
+		MsgChecksum: hash.Hash256b(body),
+		Ttl:         1,
+		Addr:        o1.RPC.String(),
+	}, o1.RPC.String())
+	require.Equal(ErrInvalidMsg, errors.Cause(err))
+}
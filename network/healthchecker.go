@@ -27,9 +27,9 @@ func NewHealthChecker(o *IotxOverlay) *HealthChecker {
 // Check checks peer health
 func (hc *HealthChecker) Check() {
 	addrs := []string{}
-	hc.Overlay.PM.Peers.Range(func(key, value interface{}) bool {
+	hc.Overlay.PM.Peers.Range(func(_, value interface{}) bool {
 		if time.Since(value.(*Peer).LastResTime) > hc.SilentInterval {
-			addrs = append(addrs, key.(string))
+			addrs = append(addrs, value.(*Peer).String())
 		}
 		return true
 	})
@@ -0,0 +1,297 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package network
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/crypto"
+	pb "github.com/iotexproject/iotex-core/network/proto"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/keypair"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/pkg/util/fileutil"
+	"github.com/iotexproject/iotex-core/pkg/version"
+)
+
+var (
+	// ErrChainIDMismatch indicates that the peer serves another chain
+	ErrChainIDMismatch = errors.New("chain ID mismatch")
+	// ErrVersionMismatch indicates that the peer speaks another protocol version
+	ErrVersionMismatch = errors.New("protocol version mismatch")
+	// ErrNodeIDMismatch indicates that the peer at an address is not the node known before
+	ErrNodeIDMismatch = errors.New("node ID mismatch")
+	// ErrNodeNotAllowed indicates that the peer is not in the node allowlist
+	ErrNodeNotAllowed = errors.New("node is not allowed")
+)
+
+// Identity is the key pair of a node. The hash of the public key is the node ID, which identifies the node in the P2P
+// network regardless of its address.
+type Identity struct {
+	PubKey keypair.PublicKey
+	PriKey keypair.PrivateKey
+	ID     hash.Hash32B
+}
+
+// NewIdentity loads the key pair of the node from the file at the given path. A new key pair is generated and saved
+// to the file if it doesn't exist yet. With an empty path, the key pair only lives in memory.
+func NewIdentity(path string) (*Identity, error) {
+	if path != "" && fileutil.FileExists(path) {
+		return loadIdentity(path)
+	}
+	pk, sk, err := crypto.EC283.NewKeyPair()
+	if err != nil {
+		return nil, errors.Wrap(err, "error when generating the node key pair")
+	}
+	id := &Identity{PubKey: pk, PriKey: sk, ID: NodeID(pk)}
+	if path == "" {
+		return id, nil
+	}
+	content := fmt.Sprintf("%s\n%s\n", keypair.EncodePublicKey(pk), keypair.EncodePrivateKey(sk))
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		return nil, errors.Wrapf(err, "error when saving the node key pair to %s", path)
+	}
+	return id, nil
+}
+
+// loadIdentity reads the key pair from a file consisting of the hex encoded public key and private key in two lines
+func loadIdentity(path string) (*Identity, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when reading the node key pair from %s", path)
+	}
+	lines := strings.Fields(string(content))
+	if len(lines) != 2 {
+		return nil, errors.Errorf("node key file %s should contain a public key and a private key", path)
+	}
+	pk, err := keypair.DecodePublicKey(lines[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when decoding the public key in %s", path)
+	}
+	sk, err := keypair.DecodePrivateKey(lines[1])
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when decoding the private key in %s", path)
+	}
+	derived, err := crypto.EC283.NewPubKey(sk)
+	if err != nil || derived != pk {
+		return nil, errors.Errorf("node key file %s contains a mismatched key pair", path)
+	}
+	return &Identity{PubKey: pk, PriKey: sk, ID: NodeID(pk)}, nil
+}
+
+// NodeID returns the ID of the node owning the given public key
+func NodeID(pk keypair.PublicKey) hash.Hash32B {
+	return byteutil.BytesTo32B(hash.Hash256b(pk[:]))
+}
+
+// handshakeHash returns the hash signed in a ping or pong
+func handshakeHash(nonce uint64, addr string, chainID uint32, version uint32) []byte {
+	b := byteutil.Uint64ToBytes(nonce)
+	b = append(b, byteutil.Uint32ToBytes(chainID)...)
+	b = append(b, byteutil.Uint32ToBytes(version)...)
+	b = append(b, []byte(addr)...)
+	return hash.Hash256b(b)
+}
+
+// challengeHash returns the hash signed in the answer to a challenge. Signing the ID of the node issuing the challenge
+// keeps the answer from being relayed to another node.
+func challengeHash(challenge uint64, issuer hash.Hash32B, chainID uint32) []byte {
+	b := byteutil.Uint64ToBytes(challenge)
+	b = append(b, byteutil.Uint32ToBytes(chainID)...)
+	b = append(b, issuer[:]...)
+	return hash.Hash256b(b)
+}
+
+// newPing creates a ping signed by the node at the given address
+func newPing(identity *Identity, chainID uint32, nonce uint64, addr string) *pb.Ping {
+	return &pb.Ping{
+		Nonce:     nonce,
+		Addr:      addr,
+		ChainId:   chainID,
+		Version:   version.ProtocolVersion,
+		PubKey:    identity.PubKey[:],
+		Signature: crypto.EC283.Sign(identity.PriKey, handshakeHash(nonce, addr, chainID, version.ProtocolVersion)),
+	}
+}
+
+// newPong creates a pong acknowledging the nonce of a ping. Signing the nonce chosen by the pinger proves that the
+// node owns the key at the moment.
+func newPong(identity *Identity, chainID uint32, nonce uint64) *pb.Pong {
+	return &pb.Pong{
+		AckNonce:  nonce,
+		ChainId:   chainID,
+		Version:   version.ProtocolVersion,
+		PubKey:    identity.PubKey[:],
+		Signature: crypto.EC283.Sign(identity.PriKey, handshakeHash(nonce, "", chainID, version.ProtocolVersion)),
+	}
+}
+
+// verifyPing checks the handshake in a ping, and returns the ID of the pinging node
+func (o *IotxOverlay) verifyPing(ping *pb.Ping) (hash.Hash32B, error) {
+	return o.verifyHandshake(ping.ChainId, ping.Version, ping.PubKey, ping.Signature,
+		handshakeHash(ping.Nonce, ping.Addr, ping.ChainId, ping.Version))
+}
+
+// verifyChallenge checks the answer to the challenge in a verified ping
+func (o *IotxOverlay) verifyChallenge(ping *pb.Ping) error {
+	pk, err := keypair.BytesToPublicKey(ping.PubKey)
+	if err != nil {
+		return errors.Wrap(ErrBadSignature, err.Error())
+	}
+	if !crypto.EC283.Verify(pk, challengeHash(ping.Challenge, o.Identity.ID, o.ChainID), ping.ChallengeSignature) {
+		return errors.Wrap(ErrBadSignature, "failed to verify the challenge signature")
+	}
+	return nil
+}
+
+// verifyPong checks the handshake in a pong acknowledging the given nonce, and returns the ID of the ponging node
+func (o *IotxOverlay) verifyPong(nonce uint64, pong *pb.Pong) (hash.Hash32B, error) {
+	if pong.AckNonce != nonce {
+		return hash.ZeroHash32B, errors.Errorf("pong carries an unmatched nonce %d instead of %d", pong.AckNonce, nonce)
+	}
+	return o.verifyHandshake(pong.ChainId, pong.Version, pong.PubKey, pong.Signature,
+		handshakeHash(pong.AckNonce, "", pong.ChainId, pong.Version))
+}
+
+func (o *IotxOverlay) verifyHandshake(
+	chainID uint32,
+	ver uint32,
+	pubKey []byte,
+	signature []byte,
+	digest []byte,
+) (hash.Hash32B, error) {
+	var id hash.Hash32B
+	if chainID != o.ChainID {
+		return id, errors.Wrapf(ErrChainIDMismatch, "peer serves chain %d instead of %d", chainID, o.ChainID)
+	}
+	if ver != version.ProtocolVersion {
+		return id, errors.Wrapf(ErrVersionMismatch, "peer speaks version %d instead of %d", ver, version.ProtocolVersion)
+	}
+	pk, err := keypair.BytesToPublicKey(pubKey)
+	if err != nil {
+		return id, errors.Wrap(ErrBadSignature, err.Error())
+	}
+	if !crypto.EC283.Verify(pk, digest, signature) {
+		return id, errors.Wrap(ErrBadSignature, "failed to verify the handshake signature")
+	}
+	id = NodeID(pk)
+	if !o.isAllowed(id) {
+		return id, errors.Wrapf(ErrNodeNotAllowed, "node %s", hex.EncodeToString(id[:]))
+	}
+	return id, nil
+}
+
+// isAllowed returns true if the node is in the allowlist, or there's no allowlist
+func (o *IotxOverlay) isAllowed(id hash.Hash32B) bool {
+	if len(o.Config.NodeAllowlist) == 0 {
+		return true
+	}
+	idStr := hex.EncodeToString(id[:])
+	for _, allowed := range o.Config.NodeAllowlist {
+		if allowed == idStr {
+			return true
+		}
+	}
+	return false
+}
+
+// handshake pings the peer, and checks the handshake in its pong. The node ID of the peer is set once it proves the
+// ownership of the key, and it must stay the same afterwards. The challenge in the pong is answered by another ping
+// over the same connection, so that the peer attributes the messages from this node over the connection to its ID.
+func (o *IotxOverlay) handshake(p *Peer, nonce uint64) error {
+	pong, err := p.Ping(newPing(o.Identity, o.ChainID, nonce, o.RPC.String()))
+	if err != nil {
+		return err
+	}
+	if pong == nil {
+		return errors.New("nil pong")
+	}
+	id, err := o.verifyPong(nonce, pong)
+	if err != nil {
+		return err
+	}
+	if p.ID == hash.ZeroHash32B {
+		p.ID = id
+	} else if p.ID != id {
+		return errors.Wrapf(ErrNodeIDMismatch, "peer at %s", p.String())
+	}
+	if pong.Challenge == 0 {
+		return nil
+	}
+	ping := newPing(o.Identity, o.ChainID, rand.Uint64(), o.RPC.String())
+	ping.Challenge = pong.Challenge
+	ping.ChallengeSignature = crypto.EC283.Sign(o.Identity.PriKey, challengeHash(pong.Challenge, id, o.ChainID))
+	_, err = p.Ping(ping)
+	return err
+}
+
+// connIDs binds the inbound connections to the node IDs of the remote nodes. A connection is bound once the remote
+// node answers the challenge issued over it, until the connection ends.
+type connIDs struct {
+	mu         sync.Mutex
+	challenges map[string]uint64
+	ids        map[string]hash.Hash32B
+}
+
+func newConnIDs() *connIDs {
+	return &connIDs{
+		challenges: make(map[string]uint64),
+		ids:        make(map[string]hash.Hash32B),
+	}
+}
+
+// challenge issues a new challenge for the connection from the given remote address
+func (c *connIDs) challenge(conn string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	challenge := rand.Uint64()
+	for challenge == 0 {
+		challenge = rand.Uint64()
+	}
+	c.challenges[conn] = challenge
+	return challenge
+}
+
+// bind binds the connection to the node ID if the challenge is the one issued for it. A challenge can only be answered
+// once.
+func (c *connIDs) bind(conn string, challenge uint64, id hash.Hash32B) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if issued, ok := c.challenges[conn]; !ok || issued != challenge {
+		return false
+	}
+	delete(c.challenges, conn)
+	c.ids[conn] = id
+	return true
+}
+
+// id returns the node ID which the connection is bound to
+func (c *connIDs) id(conn string) (hash.Hash32B, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id, ok := c.ids[conn]
+	return id, ok
+}
+
+// remove forgets the connection once it ends
+func (c *connIDs) remove(conn string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.challenges, conn)
+	delete(c.ids, conn)
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package network
+
+import (
+	"context"
+	"encoding/hex"
+	"io/ioutil"
+	"testing"
+
+	"github.com/facebookgo/clock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+const testNodeKeyPath = "node.key.test"
+
+func newTestIdentity(t *testing.T) *Identity {
+	identity, err := NewIdentity("")
+	require.NoError(t, err)
+	return identity
+}
+
+func newTestHandshakeOverlay(t *testing.T, cfg *config.Network) *IotxOverlay {
+	o := &IotxOverlay{Config: cfg, Reputation: NewReputation(cfg, clock.New()), Identity: newTestIdentity(t)}
+	o.PM = NewPeerManager(o, 5, 5)
+	o.RPC = NewRPCServer(o)
+	require.NoError(t, o.RPC.Start(context.Background()))
+	return o
+}
+
+func TestIdentity(t *testing.T) {
+	require := require.New(t)
+	testutil.CleanupPath(t, testNodeKeyPath)
+	defer testutil.CleanupPath(t, testNodeKeyPath)
+
+	// The key pair is generated and saved on the first start, and loaded afterwards
+	identity, err := NewIdentity(testNodeKeyPath)
+	require.NoError(err)
+	require.Equal(NodeID(identity.PubKey), identity.ID)
+	loaded, err := NewIdentity(testNodeKeyPath)
+	require.NoError(err)
+	require.Equal(identity, loaded)
+
+	// A key file with a mismatched key pair is rejected
+	other := newTestIdentity(t)
+	content := hex.EncodeToString(other.PubKey[:]) + "\n" + hex.EncodeToString(identity.PriKey[:]) + "\n"
+	require.NoError(ioutil.WriteFile(testNodeKeyPath, []byte(content), 0600))
+	_, err = NewIdentity(testNodeKeyPath)
+	require.Error(err)
+}
+
+func TestHandshake(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	o1 := newTestHandshakeOverlay(t, LoadTestConfig("", true))
+	o2 := newTestHandshakeOverlay(t, LoadTestConfig("", true))
+	o3 := newTestHandshakeOverlay(t, LoadTestConfig("", true))
+	o3.ChainID = 2
+	cfg4 := LoadTestConfig("", true)
+	cfg4.NodeAllowlist = []string{hex.EncodeToString(o1.Identity.ID[:])}
+	o4 := newTestHandshakeOverlay(t, cfg4)
+	defer func() {
+		for _, o := range []*IotxOverlay{o1, o2, o3, o4} {
+			require.NoError(o.RPC.Stop(ctx))
+		}
+	}()
+
+	// Peers are keyed by the node IDs proven in the handshake
+	o1.PM.AddPeer(o2.RPC.String())
+	value, ok := o1.PM.Peers.Load(o2.Identity.ID)
+	require.True(ok)
+	require.Equal(o2.RPC.String(), value.(*Peer).String())
+	value, ok = o2.PM.Peers.Load(o1.Identity.ID)
+	require.True(ok)
+	require.Equal(o1.RPC.String(), value.(*Peer).String())
+
+	// The connection the handshake is made over is bound to the node ID of the client
+	bound := false
+	o2.RPC.conns.mu.Lock()
+	for _, id := range o2.RPC.conns.ids {
+		bound = bound || id == o1.Identity.ID
+	}
+	o2.RPC.conns.mu.Unlock()
+	require.True(bound)
+
+	// Nodes serving another chain are rejected
+	o1.PM.AddPeer(o3.RPC.String())
+	require.Nil(o1.PM.peerByAddr(o3.RPC.String()))
+	require.Equal(uint(0), LenSyncMap(o3.PM.Peers))
+
+	// Nodes out of the allowlist are rejected
+	o2.PM.AddPeer(o4.RPC.String())
+	require.Nil(o2.PM.peerByAddr(o4.RPC.String()))
+	o1.PM.AddPeer(o4.RPC.String())
+	require.NotNil(o1.PM.peerByAddr(o4.RPC.String()))
+
+	// The node answering at a known address must keep the same node ID
+	p := o1.PM.peerByAddr(o2.RPC.String())
+	require.NoError(o1.handshake(p, 1))
+	p.ID = o3.Identity.ID
+	err := o1.handshake(p, 2)
+	require.Equal(ErrNodeIDMismatch, errors.Cause(err))
+}
@@ -12,6 +12,7 @@ import (
 	"net"
 	"time"
 
+	"github.com/facebookgo/clock"
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 
@@ -36,6 +37,10 @@ type Overlay interface {
 	Tell(uint32, net.Addr, proto.Message) error
 	Self() net.Addr
 	GetPeers() []net.Addr
+	// PeerScore returns the reputation score of the peer
+	PeerScore(net.Addr) int
+	// ReportPeer penalizes the peer if the error of handling its message of the given type is caused by its misbehavior
+	ReportPeer(net.Addr, uint32, error)
 }
 
 // IotxOverlay is the implementation
@@ -46,13 +51,25 @@ type IotxOverlay struct {
 	Tasks      []*routine.RecurringTask
 	Config     *config.Network
 	Dispatcher dispatcher.Dispatcher
+	Reputation *Reputation
+	Identity   *Identity
+	// ChainID is the ID of the root chain, which the peers must agree on in the handshake
+	ChainID uint32
+	// DHT is nil if peer discovery or the DHT is disabled
+	DHT *DHT
 
 	lifecycle lifecycle.Lifecycle
 }
 
-// NewOverlay creates an instance of IotxOverlay
-func NewOverlay(config *config.Network) *IotxOverlay {
-	o := &IotxOverlay{Config: config}
+// NewOverlay creates an instance of IotxOverlay serving the root chain of the given ID
+func NewOverlay(config *config.Network, chainID uint32) (*IotxOverlay, error) {
+	o := &IotxOverlay{Config: config, ChainID: chainID}
+	identity, err := NewIdentity(config.NodeKeyPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the node key pair")
+	}
+	o.Identity = identity
+	o.Reputation = NewReputation(config, clock.New())
 	o.RPC = NewRPCServer(o)
 	o.PM = NewPeerManager(o, config.NumPeersLowerBound, config.NumPeersUpperBound)
 	o.Gossip = NewGossip(o)
@@ -61,11 +78,14 @@ func NewOverlay(config *config.Network) *IotxOverlay {
 	o.addPingTask()
 	o.addHealthCheckTask()
 	if config.PeerDiscovery {
+		if config.DHTBucketSize > 0 {
+			o.addDHT()
+		}
 		o.addPeerMaintainer()
-	} else {
-		o.addConfigBasedPeerMaintainer()
+	} else if err := o.addConfigBasedPeerMaintainer(); err != nil {
+		return nil, err
 	}
-	return o
+	return o, nil
 }
 
 // Start starts IotxOverlay and it's sub-models.
@@ -78,6 +98,94 @@ func (o *IotxOverlay) Stop(ctx context.Context) error { return o.lifecycle.OnSto
 func (o *IotxOverlay) AttachDispatcher(dispatcher dispatcher.Dispatcher) {
 	o.Dispatcher = dispatcher
 	o.Gossip.AttachDispatcher(dispatcher)
+	dispatcher.AttachPeerReporter(o)
+}
+
+// ReportPeer penalizes the peer if the error of handling its message is caused by its misbehavior. The misbehavior is
+// reported against the connection the message arrives on, or the peer connected at the address. An address which is
+// neither is only what some message claims, so it isn't penalized.
+func (o *IotxOverlay) ReportPeer(sender net.Addr, msgType uint32, err error) {
+	m, ok := misbehaviorOf(msgType, err)
+	if !ok {
+		return
+	}
+	if s, ok := sender.(*remoteSender); ok {
+		o.penalize(s.key, m)
+		return
+	}
+	if p := o.PM.peerByAddr(sender.String()); p != nil {
+		o.penalize(o.keyOf(p), m)
+		return
+	}
+	logger.Debug().
+		Str("sender", sender.String()).
+		Str("misbehavior", m.String()).
+		Msg("Skip penalizing the misbehavior of an unauthenticated sender")
+}
+
+// PeerScore returns the reputation score of the peer
+func (o *IotxOverlay) PeerScore(peer net.Addr) int {
+	if p := o.PM.peerByAddr(peer.String()); p != nil {
+		return o.Reputation.Score(o.keyOf(p))
+	}
+	return o.Reputation.Score(o.peerKey(peer.String()))
+}
+
+// keyOf returns the key of the peer in the reputation, which is its node ID once it has proven the ID
+func (o *IotxOverlay) keyOf(p *Peer) string {
+	if p.ID == hash.ZeroHash32B {
+		return o.peerKey(p.String())
+	}
+	return idKey(p.ID)
+}
+
+// idKey returns the key of the node of the given ID in the reputation
+func idKey(id hash.Hash32B) string {
+	return hex.EncodeToString(id[:])
+}
+
+// peerKey returns the key of the node at the given address in the reputation, if the node hasn't proven its ID. The key
+// is the host of the address, so that a banned node can't come back from another port, unless multiple connections per
+// host are allowed.
+func (o *IotxOverlay) peerKey(addr string) string {
+	if o.Config.AllowMultiConnsPerHost {
+		return addr
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// penalize lowers the reputation score of the peer, and disconnects all the peers of the key if it gets banned
+func (o *IotxOverlay) penalize(key string, m Misbehavior) {
+	if !o.Reputation.Penalize(key, m) {
+		return
+	}
+	logger.Warn().
+		Str("peer", key).
+		Str("misbehavior", m.String()).
+		Dur("duration", o.Config.PeerBanDuration).
+		Msg("Ban peer for misbehaviors")
+	o.PM.Peers.Range(func(_, value interface{}) bool {
+		if p := value.(*Peer); o.keyOf(p) == key || o.peerKey(p.String()) == key {
+			o.PM.RemovePeer(p.String())
+		}
+		return true
+	})
+}
+
+// remoteSender is the sender of an inbound message. Its address is the one the sender claims to listen at, which the
+// replies are sent to, while the key is the reputation key of the connection the message actually arrived on, which
+// the misbehaviors are reported against.
+type remoteSender struct {
+	*node.Node
+	key string
+}
+
+func newRemoteSender(addr string, key string) *remoteSender {
+	return &remoteSender{Node: node.NewTCPNode(addr), key: key}
 }
 
 func (o *IotxOverlay) addPingTask() {
@@ -94,6 +202,14 @@ func (o *IotxOverlay) addHealthCheckTask() {
 	o.Tasks = append(o.Tasks, hcTask)
 }
 
+func (o *IotxOverlay) addDHT() {
+	o.DHT = NewDHT(o)
+	o.lifecycle.Add(o.DHT)
+	dhtTask := routine.NewRecurringTask(o.DHT.Refresh, o.Config.DHTRefreshInterval)
+	o.lifecycle.Add(dhtTask)
+	o.Tasks = append(o.Tasks, dhtTask)
+}
+
 func (o *IotxOverlay) addPeerMaintainer() {
 	pm := NewPeerMaintainer(o)
 	pmTask := routine.NewRecurringTask(pm.Update, o.Config.PeerMaintainerInterval)
@@ -101,15 +217,16 @@ func (o *IotxOverlay) addPeerMaintainer() {
 	o.Tasks = append(o.Tasks, pmTask)
 }
 
-func (o *IotxOverlay) addConfigBasedPeerMaintainer() {
+func (o *IotxOverlay) addConfigBasedPeerMaintainer() error {
 	topology, err := NewTopology(o.Config.TopologyPath)
 	if err != nil {
-		logger.Fatal().Err(err).Msg("Fail to load topology")
+		return errors.Wrap(err, "failed to load the topology")
 	}
 	cbpm := NewConfigBasedPeerMaintainer(o, topology)
 	cbpmTask := routine.NewRecurringTask(cbpm.Update, o.Config.PeerMaintainerInterval)
 	o.lifecycle.Add(cbpmTask)
 	o.Tasks = append(o.Tasks, cbpmTask)
+	return nil
 }
 
 // Broadcast lets the caller to broadcast the message to all nodes in the P2P network
@@ -61,11 +61,20 @@ func LoadTestConfig(addr string, allowMultiConnsPerHost bool) *config.Network {
 			MaxMsgSize:              1024 * 1024 * 10,
 			PeerDiscovery:           true,
 			TTL:                     3,
+			DHTBucketSize:           16,
+			DHTLookupConcurrency:    3,
+			DHTRefreshInterval:      time.Minute,
 		},
 	}
 	return &config.Network
 }
 
+func newTestOverlay(t require.TestingT, cfg *config.Network) *IotxOverlay {
+	o, err := NewOverlay(cfg, config.Default.Chain.ID)
+	require.NoError(t, err)
+	return o
+}
+
 func LoadTestConfigWithTLSEnabled(addr string, allowMultiConnsPerHost bool) *config.Network {
 	cfg := LoadTestConfig(addr, allowMultiConnsPerHost)
 	cfg.TLSEnabled = true
@@ -80,6 +89,10 @@ type MockDispatcher struct {
 
 func (d *MockDispatcher) AddSubscriber(uint32, dispatcher.Subscriber) {}
 
+func (d *MockDispatcher) RemoveSubscriber(uint32) {}
+
+func (d *MockDispatcher) AttachPeerReporter(dispatcher.PeerReporter) {}
+
 func (d *MockDispatcher) Start(_ context.Context) error {
 	return nil
 }
@@ -88,7 +101,7 @@ func (d *MockDispatcher) Stop(_ context.Context) error {
 	return nil
 }
 
-func (d *MockDispatcher) HandleBroadcast(uint32, proto.Message, chan bool) {
+func (d *MockDispatcher) HandleBroadcast(uint32, net.Addr, proto.Message, chan bool) {
 }
 
 func (d *MockDispatcher) HandleTell(uint32, net.Addr, proto.Message, chan bool) {
@@ -101,7 +114,7 @@ type MockDispatcher1 struct {
 
 func (d1 *MockDispatcher1) AddSubscriber(uint32, dispatcher.Subscriber) {}
 
-func (d1 *MockDispatcher1) HandleBroadcast(uint32, proto.Message, chan bool) {
+func (d1 *MockDispatcher1) HandleBroadcast(uint32, net.Addr, proto.Message, chan bool) {
 	d1.Count++
 }
 
@@ -124,7 +137,7 @@ func TestOverlay(t *testing.T) {
 		} else {
 			config = LoadTestConfig("", true)
 		}
-		node := NewOverlay(config)
+		node := newTestOverlay(t, config)
 		node.AttachDispatcher(dp)
 		err := node.Start(ctx)
 		assert.NoError(t, err)
@@ -186,12 +199,12 @@ func TestTell(t *testing.T) {
 	dp1 := &MockDispatcher2{T: t}
 	addr1 := randomAddress()
 	addr2 := randomAddress()
-	p1 := NewOverlay(LoadTestConfig(addr1, true))
+	p1 := newTestOverlay(t, LoadTestConfig(addr1, true))
 	p1.AttachDispatcher(dp1)
 	err := p1.Start(ctx)
 	assert.NoError(t, err)
 	dp2 := &MockDispatcher2{T: t}
-	p2 := NewOverlay(LoadTestConfig(addr2, true))
+	p2 := newTestOverlay(t, LoadTestConfig(addr2, true))
 	p2.AttachDispatcher(dp2)
 	err = p2.Start(ctx)
 	assert.NoError(t, err)
@@ -228,17 +241,17 @@ func TestOneConnPerHost(t *testing.T) {
 	addr1 := randomAddress()
 	addr2 := randomAddress()
 	addr3 := randomAddress()
-	p1 := NewOverlay(LoadTestConfig(addr1, false))
+	p1 := newTestOverlay(t, LoadTestConfig(addr1, false))
 	p1.AttachDispatcher(dp1)
 	err := p1.Start(ctx)
 	require.Nil(t, err)
 	dp2 := &MockDispatcher2{T: t}
-	p2 := NewOverlay(LoadTestConfig(addr2, false))
+	p2 := newTestOverlay(t, LoadTestConfig(addr2, false))
 	p2.AttachDispatcher(dp2)
 	err = p2.Start(ctx)
 	assert.NoError(t, err)
 	dp3 := &MockDispatcher2{T: t}
-	p3 := NewOverlay(LoadTestConfig(addr3, false))
+	p3 := newTestOverlay(t, LoadTestConfig(addr3, false))
 	p3.AttachDispatcher(dp3)
 	err = p3.Start(ctx)
 	assert.NoError(t, err)
@@ -294,7 +307,7 @@ func TestConfigBasedTopology(t *testing.T) {
 		config.PeerDiscovery = false
 		config.TopologyPath = path
 		dp := &MockDispatcher{}
-		node := NewOverlay(config)
+		node := newTestOverlay(t, config)
 		node.AttachDispatcher(dp)
 		err = node.Start(ctx)
 		assert.NoError(t, err)
@@ -317,8 +330,8 @@ func TestConfigBasedTopology(t *testing.T) {
 				return false, nil
 			}
 			addrs := make([]string, 0)
-			node.PM.Peers.Range(func(key, value interface{}) bool {
-				addrs = append(addrs, key.(string))
+			node.PM.Peers.Range(func(_, value interface{}) bool {
+				addrs = append(addrs, value.(*Peer).String())
 				return true
 			})
 			sort.Strings(addrs)
@@ -349,7 +362,7 @@ func TestRandomizePeerList(t *testing.T) {
 		cfg.NumPeersLowerBound = 4
 		cfg.NumPeersUpperBound = 4
 		cfg.PeerForceDisconnectionRoundInterval = 1
-		node := NewOverlay(cfg)
+		node := newTestOverlay(t, cfg)
 		node.AttachDispatcher(dp)
 		require.NoError(t, node.Start(ctx))
 		nodes = append(nodes, node)
@@ -387,7 +400,7 @@ func (d3 *MockDispatcher3) HandleTell(uint32, net.Addr, proto.Message, chan bool
 	d3.C <- true
 }
 
-func (d3 *MockDispatcher3) HandleBroadcast(uint32, proto.Message, chan bool) {
+func (d3 *MockDispatcher3) HandleBroadcast(uint32, net.Addr, proto.Message, chan bool) {
 	d3.C <- true
 }
 
@@ -403,13 +416,13 @@ func runBenchmarkOp(tell bool, size int, parallel bool, tls bool, b *testing.B)
 	}
 	c1 := make(chan bool)
 	d1 := &MockDispatcher3{C: c1}
-	p1 := NewOverlay(cfg1)
+	p1 := newTestOverlay(b, cfg1)
 	p1.AttachDispatcher(d1)
 	err := p1.Start(ctx)
 	assert.NoError(b, err)
 	c2 := make(chan bool)
 	d2 := &MockDispatcher3{C: c2}
-	p2 := NewOverlay(cfg2)
+	p2 := newTestOverlay(b, cfg2)
 	p2.AttachDispatcher(d2)
 	err = p2.Start(ctx)
 	assert.NoError(b, err)
@@ -17,6 +17,7 @@ import (
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/network/node"
 	pb "github.com/iotexproject/iotex-core/network/proto"
+	"github.com/iotexproject/iotex-core/pkg/hash"
 	"github.com/iotexproject/iotex-core/proto"
 )
 
@@ -45,6 +46,8 @@ func init() {
 // Peer represents a node in the peer-to-peer networks
 type Peer struct {
 	node.Node
+	// ID is the node ID of the peer, which is set once the peer passes the handshake
+	ID          hash.Hash32B
 	Client      pb.PeerClient
 	Conn        *grpc.ClientConn
 	Ctx         context.Context
@@ -154,6 +157,42 @@ func (p *Peer) Tell(req *pb.TellReq) (*pb.TellRes, error) {
 	return res, err
 }
 
+// FindNode implements the client side RPC
+func (p *Peer) FindNode(req *pb.FindNodeReq) (*pb.FindNodeRes, error) {
+	succeed := "false"
+	res, err := p.Client.FindNode(p.Ctx, req)
+	if err == nil {
+		succeed = "true"
+		p.updateLastResTime()
+	}
+	cRequestMtc.WithLabelValues("FindNode", succeed).Inc()
+	return res, err
+}
+
+// Announce implements the client side RPC
+func (p *Peer) Announce(req *pb.AnnounceReq) (*pb.AnnounceRes, error) {
+	succeed := "false"
+	res, err := p.Client.Announce(p.Ctx, req)
+	if err == nil {
+		succeed = "true"
+		p.updateLastResTime()
+	}
+	cRequestMtc.WithLabelValues("Announce", succeed).Inc()
+	return res, err
+}
+
+// Fetch implements the client side RPC
+func (p *Peer) Fetch(req *pb.FetchReq) (*pb.FetchRes, error) {
+	succeed := "false"
+	res, err := p.Client.Fetch(p.Ctx, req)
+	if err == nil {
+		succeed = "true"
+		p.updateLastResTime()
+	}
+	cRequestMtc.WithLabelValues("Fetch", succeed).Inc()
+	return res, err
+}
+
 // Update the last time when successfully getting an response from the peer
 func (p *Peer) updateLastResTime() {
 	p.LastResTime = time.Now()
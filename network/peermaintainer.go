@@ -25,8 +25,9 @@ func NewPeerMaintainer(o *IotxOverlay) *PeerMaintainer {
 	return &PeerMaintainer{Overlay: o}
 }
 
-// Update maintains peer connection. Current strategy is to get the (upper_bound - count) peer addresses from one of the
-// current peer if the count is lower than the lower bound
+// Update maintains peer connection. Current strategy is to draw the (upper_bound - count) peer addresses from the DHT
+// routing table, or to get them from one of the current peer if the routing table is not available, if the count is
+// lower than the lower bound
 func (pm *PeerMaintainer) Update() {
 	defer func() {
 		pm.round++
@@ -39,7 +40,14 @@ func (pm *PeerMaintainer) Update() {
 
 	count := LenSyncMap(pm.Overlay.PM.Peers)
 	cConnMtc.WithLabelValues().Set(float64(count))
-	if count == 0 {
+	if count < pm.Overlay.PM.NumPeersLowerBound && pm.Overlay.DHT != nil && pm.Overlay.DHT.Table.Len() > 0 {
+		addrs := pm.Overlay.DHT.Table.Sample(int(pm.Overlay.PM.NumPeersUpperBound-count), func(addr string) bool {
+			return pm.Overlay.PM.peerByAddr(addr) != nil
+		})
+		for _, addr := range addrs {
+			pm.Overlay.PM.AddPeer(addr)
+		}
+	} else if count == 0 {
 		// TODO: Now we simply read the bootstrap nodes from the config. This needs to be changed in the future
 		bns1 := pm.Overlay.Config.BootstrapNodes
 		bns2 := make([]string, len(bns1))
@@ -109,8 +117,8 @@ func (cbpm *ConfigBasedPeerMaintainer) Update() {
 	for _, addr := range cbpm.Addrs {
 		addrs[addr.String()] = false
 	}
-	cbpm.Overlay.PM.Peers.Range(func(key, value interface{}) bool {
-		addrs[key.(string)] = true
+	cbpm.Overlay.PM.Peers.Range(func(_, value interface{}) bool {
+		addrs[value.(*Peer).String()] = true
 		return true
 	})
 	for addr, ok := range addrs {
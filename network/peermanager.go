@@ -7,13 +7,15 @@
 package network
 
 import (
+	"math/rand"
 	"net"
 	"sync"
 
 	"github.com/iotexproject/iotex-core/logger"
+	"github.com/iotexproject/iotex-core/pkg/hash"
 )
 
-// PeerManager represents the outgoing neighbor list
+// PeerManager represents the outgoing neighbor list, which is keyed by the node IDs of the peers
 // TODO: We should decouple peer address and peer. Node can know more nodes than it connects to
 type PeerManager struct {
 	// TODO: Need to revisit sync.Map: https://github.com/golang/go/issues/24112
@@ -33,26 +35,74 @@ func NewPeerManager(o *IotxOverlay, lb uint, ub uint) *PeerManager {
 	}
 }
 
-// AddPeer adds a new peer
+// AddPeer connects to the node at the given address, and adds it as a new peer once it passes the handshake
 func (pm *PeerManager) AddPeer(addr string) {
+	if !pm.canAdd(addr) {
+		return
+	}
+	p := NewTCPPeer(addr)
+	if err := p.Connect(pm.Overlay.Config); err != nil {
+		logger.Error().
+			Str("dst", addr).
+			Msg("failed to establish an outgoing connection")
+		return
+	}
+	if err := pm.Overlay.handshake(p, rand.Uint64()); err != nil {
+		logger.Error().
+			Err(err).
+			Str("dst", addr).
+			Msg("failed to handshake with the node")
+		pm.closePeer(p)
+		return
+	}
+	if pm.Overlay.DHT != nil {
+		pm.Overlay.DHT.add(p.ID, addr)
+	}
+	pm.storePeer(p)
+}
+
+// addPingingPeer adds the node which has pinged this node with a valid handshake as a new peer. The address that the
+// node claims is not proven yet, and gets verified by the next ping from this node.
+func (pm *PeerManager) addPingingPeer(addr string, id hash.Hash32B) {
+	if !pm.canAdd(addr) {
+		return
+	}
+	p := NewTCPPeer(addr)
+	p.ID = id
+	if err := p.Connect(pm.Overlay.Config); err != nil {
+		logger.Error().
+			Str("dst", addr).
+			Msg("failed to establish an outgoing connection")
+		return
+	}
+	pm.storePeer(p)
+}
+
+// canAdd checks if the node at the given address can be added as a new peer
+func (pm *PeerManager) canAdd(addr string) bool {
 	if LenSyncMap(pm.Peers) >= pm.NumPeersUpperBound {
 		logger.Debug().
 			Uint("peers", pm.NumPeersUpperBound).
 			Msg("Node already reached the max number of peers")
-		return
+		return false
 	}
 	if pm.Overlay.RPC.String() == addr {
 		logger.Debug().
 			Str("dst", addr).
 			Msg("Node at address is the current node")
-		return
+		return false
+	}
+	if pm.Overlay.Reputation.IsBanned(pm.Overlay.peerKey(addr)) {
+		logger.Debug().
+			Str("dst", addr).
+			Msg("Node at address is banned")
+		return false
 	}
-	_, ok := pm.Peers.Load(addr)
-	if ok {
+	if pm.peerByAddr(addr) != nil {
 		logger.Debug().
 			Str("dst", addr).
 			Msg("Node at address is already the peer")
-		return
+		return false
 	}
 	if !pm.Overlay.Config.AllowMultiConnsPerHost {
 		nHost, _, err := net.SplitHostPort(addr)
@@ -60,7 +110,7 @@ func (pm *PeerManager) AddPeer(addr string) {
 			logger.Error().
 				Str("dst", addr).
 				Msg("Node address is invalid")
-			return
+			return false
 		}
 		found := false
 		pm.Peers.Range(func(key, value interface{}) bool {
@@ -82,36 +132,58 @@ func (pm *PeerManager) AddPeer(addr string) {
 			logger.Debug().
 				Str("dst-host", nHost).
 				Msg("Another node on the same Host is already the peer")
-			return
+			return false
 		}
 	}
-	p := NewTCPPeer(addr)
-	err := p.Connect(pm.Overlay.Config)
-	if err != nil {
-		logger.Error().
-			Str("dst", addr).
-			Msg("failed to establish an outgoing connection")
+	return true
+}
+
+// storePeer stores the connected peer by its node ID, unless it is the current node, a banned node or already a peer
+// at another address
+func (pm *PeerManager) storePeer(p *Peer) {
+	if p.ID == pm.Overlay.Identity.ID {
+		logger.Debug().
+			Str("dst", p.String()).
+			Msg("Node at address is the current node")
+		pm.closePeer(p)
+		return
+	}
+	if pm.Overlay.Reputation.IsBanned(idKey(p.ID)) {
+		logger.Debug().
+			Str("dst", p.String()).
+			Msg("Node is banned")
+		pm.closePeer(p)
+		return
+	}
+	if _, loaded := pm.Peers.LoadOrStore(p.ID, p); loaded {
+		logger.Debug().
+			Str("dst", p.String()).
+			Msg("Node is already the peer at another address")
+		pm.closePeer(p)
+		return
 	}
-	pm.Peers.Store(addr, p)
 	logger.Debug().
-		Str("dst", addr).
+		Str("dst", p.String()).
 		Msg("establish an outgoing connection")
 }
 
-// RemovePeer removes an existing peer
+// RemovePeer removes an existing peer at the given address
 func (pm *PeerManager) RemovePeer(addr string) {
-	p, found := pm.Peers.Load(addr)
-	if !found {
+	p := pm.peerByAddr(addr)
+	if p == nil {
 		logger.Debug().
 			Str("dst", addr).
 			Msg("Node at address is not a peer")
 		return
 	}
-	pm.Peers.Delete(p.(*Peer).String())
-	err := p.(*Peer).Close()
-	if err != nil {
+	pm.Peers.Delete(p.ID)
+	pm.closePeer(p)
+}
+
+func (pm *PeerManager) closePeer(p *Peer) {
+	if err := p.Close(); err != nil {
 		logger.Error().
-			Str("dst", addr).
+			Str("dst", p.String()).
 			Msg("failed to terminate an outgoing connection")
 	}
 }
@@ -124,7 +196,7 @@ func (pm *PeerManager) RemoveLRUPeer() {
 		lastResTime := value.(*Peer).LastResTime.Unix()
 		if minLastResTime == 0 || lastResTime < minLastResTime {
 			minLastResTime = lastResTime
-			addr = key.(string)
+			addr = value.(*Peer).String()
 		}
 		return true
 	})
@@ -135,18 +207,56 @@ func (pm *PeerManager) RemoveLRUPeer() {
 
 // GetOrAddPeer gets a peer. If it is still not in the neighbor list, it will be added first.
 func (pm *PeerManager) GetOrAddPeer(addr string) *Peer {
-	peer, ok := pm.Peers.Load(addr)
-	if ok {
-		return peer.(*Peer)
+	if peer := pm.peerByAddr(addr); peer != nil {
+		return peer
 	}
 	if LenSyncMap(pm.Peers) >= pm.NumPeersUpperBound {
 		pm.RemoveLRUPeer()
 	}
 	// TODO: there could be race condition that another peer is added first
 	pm.AddPeer(addr)
-	peer, ok = pm.Peers.Load(addr)
-	if ok {
-		return peer.(*Peer)
+	return pm.peerByAddr(addr)
+}
+
+// peerByAddr returns the peer at the given address, or nil if there's no such peer
+func (pm *PeerManager) peerByAddr(addr string) *Peer {
+	var peer *Peer
+	pm.Peers.Range(func(_, value interface{}) bool {
+		if value.(*Peer).String() == addr {
+			peer = value.(*Peer)
+			return false
+		}
+		return true
+	})
+	return peer
+}
+
+// peerByKey returns the peer of the given reputation key, or nil if there's no such peer
+func (pm *PeerManager) peerByKey(key string) *Peer {
+	var peer *Peer
+	pm.Peers.Range(func(_, value interface{}) bool {
+		if pm.Overlay.keyOf(value.(*Peer)) == key {
+			peer = value.(*Peer)
+			return false
+		}
+		return true
+	})
+	return peer
+}
+
+// peerOrDial returns the peer at the given address. If the node is not a peer, a temporary connection is made, which
+// the caller must close by the returned function.
+func (pm *PeerManager) peerOrDial(addr string) (*Peer, func(), error) {
+	if p := pm.peerByAddr(addr); p != nil {
+		return p, func() {}, nil
+	}
+	p := NewTCPPeer(addr)
+	if err := p.Connect(pm.Overlay.Config); err != nil {
+		return nil, nil, err
 	}
-	return nil
+	return p, func() {
+		if err := p.Close(); err != nil {
+			logger.Error().Err(err).Str("dst", addr).Msg("failed to close the temporary connection")
+		}
+	}, nil
 }
@@ -9,8 +9,9 @@ package network
 import (
 	"math/rand"
 
+	"github.com/pkg/errors"
+
 	"github.com/iotexproject/iotex-core/logger"
-	pb "github.com/iotexproject/iotex-core/network/proto"
 )
 
 // Pinger is the recurring logic to constantly check if the node can talk to its peers
@@ -23,7 +24,7 @@ func NewPinger(o *IotxOverlay) *Pinger {
 	return &Pinger{Overlay: o}
 }
 
-// Ping pings the neighbor peers
+// Ping pings the neighbor peers, and checks their handshakes. A peer is removed if it is not the node it claimed to be.
 func (h *Pinger) Ping() {
 	h.Overlay.PM.Peers.Range(func(_, value interface{}) bool {
 		go func() {
@@ -33,21 +34,24 @@ func (h *Pinger) Ping() {
 				logger.Error().Msg("value is not an instance of Peer")
 				return
 			}
-			pong, err := p.Ping(&pb.Ping{Nonce: n, Addr: h.Overlay.RPC.String()})
-			if err != nil {
-				logger.Error().Err(err).Str("dst", p.String()).Msg("error when getting pong")
-				return
-			}
-			if pong == nil {
-				logger.Error().Str("dst", p.String()).Msg("nil pong")
+			err := h.Overlay.handshake(p, n)
+			if err == nil {
+				// The peer has proven its ID at the address
+				if h.Overlay.DHT != nil {
+					h.Overlay.DHT.add(p.ID, p.String())
+				}
 				return
 			}
-			if pong.AckNonce != n {
-				logger.Error().
-					Str("dst", p.String()).
-					Uint64("out-nonce", n).
-					Uint64("in-nonce", pong.AckNonce).
-					Msg("pong carries an unmatched nonce")
+			logger.Error().Err(err).Str("dst", p.String()).Msg("error when handshaking with the peer")
+			switch errors.Cause(err) {
+			case ErrBadSignature:
+				h.Overlay.penalize(h.Overlay.keyOf(p), BadSignature)
+				h.Overlay.PM.RemovePeer(p.String())
+			case ErrChainIDMismatch, ErrVersionMismatch, ErrNodeIDMismatch, ErrNodeNotAllowed:
+				// The peer is not the node it claimed to be, or not the one this node could talk to
+				h.Overlay.PM.RemovePeer(p.String())
+			default:
+				h.Overlay.penalize(h.Overlay.keyOf(p), UnresponsivePing)
 			}
 		}()
 		return true
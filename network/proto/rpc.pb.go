@@ -27,7 +27,18 @@ type Ping struct {
 	Nonce uint64 `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
 	// Every one who participates into the network needs to tell others its address
 	// TODO: Seperate it as a standalone protocol
-	Addr                 string   `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	Addr    string `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	ChainId uint32 `protobuf:"varint,3,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	Version uint32 `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	// The public key of the node, whose hash is the node ID
+	PubKey []byte `protobuf:"bytes,5,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	// The signature over the other fields, which proves the ownership of the public key
+	Signature []byte `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+	// The challenge in the previous pong over the same connection, which binds the connection to the node ID once
+	// answered
+	Challenge uint64 `protobuf:"varint,7,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	// The signature over the challenge and the node ID of the receiver
+	ChallengeSignature   []byte   `protobuf:"bytes,8,opt,name=challenge_signature,json=challengeSignature,proto3" json:"challenge_signature,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -37,7 +48,7 @@ func (m *Ping) Reset()         { *m = Ping{} }
 func (m *Ping) String() string { return proto.CompactTextString(m) }
 func (*Ping) ProtoMessage()    {}
 func (*Ping) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rpc_c5f1a61bd6a5b846, []int{0}
+	return fileDescriptor_rpc_f0628d57765e3128, []int{0}
 }
 func (m *Ping) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Ping.Unmarshal(m, b)
@@ -71,8 +82,57 @@ func (m *Ping) GetAddr() string {
 	return ""
 }
 
+func (m *Ping) GetChainId() uint32 {
+	if m != nil {
+		return m.ChainId
+	}
+	return 0
+}
+
+func (m *Ping) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Ping) GetPubKey() []byte {
+	if m != nil {
+		return m.PubKey
+	}
+	return nil
+}
+
+func (m *Ping) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *Ping) GetChallenge() uint64 {
+	if m != nil {
+		return m.Challenge
+	}
+	return 0
+}
+
+func (m *Ping) GetChallengeSignature() []byte {
+	if m != nil {
+		return m.ChallengeSignature
+	}
+	return nil
+}
+
 type Pong struct {
-	AckNonce             uint64   `protobuf:"varint,1,opt,name=ack_nonce,json=ackNonce,proto3" json:"ack_nonce,omitempty"`
+	AckNonce  uint64 `protobuf:"varint,1,opt,name=ack_nonce,json=ackNonce,proto3" json:"ack_nonce,omitempty"`
+	ChainId   uint32 `protobuf:"varint,2,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	Version   uint32 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	PubKey    []byte `protobuf:"bytes,4,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	Signature []byte `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	// A random nonce issued for the connection, which the pinger answers in the next ping to bind the connection to its
+	// node ID
+	Challenge            uint64   `protobuf:"varint,6,opt,name=challenge,proto3" json:"challenge,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -82,7 +142,7 @@ func (m *Pong) Reset()         { *m = Pong{} }
 func (m *Pong) String() string { return proto.CompactTextString(m) }
 func (*Pong) ProtoMessage()    {}
 func (*Pong) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rpc_c5f1a61bd6a5b846, []int{1}
+	return fileDescriptor_rpc_f0628d57765e3128, []int{1}
 }
 func (m *Pong) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Pong.Unmarshal(m, b)
@@ -109,6 +169,41 @@ func (m *Pong) GetAckNonce() uint64 {
 	return 0
 }
 
+func (m *Pong) GetChainId() uint32 {
+	if m != nil {
+		return m.ChainId
+	}
+	return 0
+}
+
+func (m *Pong) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Pong) GetPubKey() []byte {
+	if m != nil {
+		return m.PubKey
+	}
+	return nil
+}
+
+func (m *Pong) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *Pong) GetChallenge() uint64 {
+	if m != nil {
+		return m.Challenge
+	}
+	return 0
+}
+
 type GetPeersReq struct {
 	Count                uint32   `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -120,7 +215,7 @@ func (m *GetPeersReq) Reset()         { *m = GetPeersReq{} }
 func (m *GetPeersReq) String() string { return proto.CompactTextString(m) }
 func (*GetPeersReq) ProtoMessage()    {}
 func (*GetPeersReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rpc_c5f1a61bd6a5b846, []int{2}
+	return fileDescriptor_rpc_f0628d57765e3128, []int{2}
 }
 func (m *GetPeersReq) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetPeersReq.Unmarshal(m, b)
@@ -158,7 +253,7 @@ func (m *GetPeersRes) Reset()         { *m = GetPeersRes{} }
 func (m *GetPeersRes) String() string { return proto.CompactTextString(m) }
 func (*GetPeersRes) ProtoMessage()    {}
 func (*GetPeersRes) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rpc_c5f1a61bd6a5b846, []int{3}
+	return fileDescriptor_rpc_f0628d57765e3128, []int{3}
 }
 func (m *GetPeersRes) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetPeersRes.Unmarshal(m, b)
@@ -186,12 +281,14 @@ func (m *GetPeersRes) GetAddr() []string {
 }
 
 type BroadcastReq struct {
-	Header               uint32   `protobuf:"varint,1,opt,name=header,proto3" json:"header,omitempty"`
-	ChainId              uint32   `protobuf:"varint,2,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
-	MsgType              uint32   `protobuf:"varint,3,opt,name=msg_type,json=msgType,proto3" json:"msg_type,omitempty"`
-	MsgBody              []byte   `protobuf:"bytes,4,opt,name=msg_body,json=msgBody,proto3" json:"msg_body,omitempty"`
-	MsgChecksum          []byte   `protobuf:"bytes,5,opt,name=msg_checksum,json=msgChecksum,proto3" json:"msg_checksum,omitempty"`
-	Ttl                  int32    `protobuf:"varint,6,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	Header      uint32 `protobuf:"varint,1,opt,name=header,proto3" json:"header,omitempty"`
+	ChainId     uint32 `protobuf:"varint,2,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	MsgType     uint32 `protobuf:"varint,3,opt,name=msg_type,json=msgType,proto3" json:"msg_type,omitempty"`
+	MsgBody     []byte `protobuf:"bytes,4,opt,name=msg_body,json=msgBody,proto3" json:"msg_body,omitempty"`
+	MsgChecksum []byte `protobuf:"bytes,5,opt,name=msg_checksum,json=msgChecksum,proto3" json:"msg_checksum,omitempty"`
+	Ttl         int32  `protobuf:"varint,6,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// The address of the peer relaying the message, which is accountable for its validity
+	Addr                 string   `protobuf:"bytes,7,opt,name=addr,proto3" json:"addr,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -201,7 +298,7 @@ func (m *BroadcastReq) Reset()         { *m = BroadcastReq{} }
 func (m *BroadcastReq) String() string { return proto.CompactTextString(m) }
 func (*BroadcastReq) ProtoMessage()    {}
 func (*BroadcastReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rpc_c5f1a61bd6a5b846, []int{4}
+	return fileDescriptor_rpc_f0628d57765e3128, []int{4}
 }
 func (m *BroadcastReq) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BroadcastReq.Unmarshal(m, b)
@@ -263,6 +360,13 @@ func (m *BroadcastReq) GetTtl() int32 {
 	return 0
 }
 
+func (m *BroadcastReq) GetAddr() string {
+	if m != nil {
+		return m.Addr
+	}
+	return ""
+}
+
 type BroadcastRes struct {
 	Header               uint32   `protobuf:"varint,1,opt,name=header,proto3" json:"header,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -274,7 +378,7 @@ func (m *BroadcastRes) Reset()         { *m = BroadcastRes{} }
 func (m *BroadcastRes) String() string { return proto.CompactTextString(m) }
 func (*BroadcastRes) ProtoMessage()    {}
 func (*BroadcastRes) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rpc_c5f1a61bd6a5b846, []int{5}
+	return fileDescriptor_rpc_f0628d57765e3128, []int{5}
 }
 func (m *BroadcastRes) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BroadcastRes.Unmarshal(m, b)
@@ -316,7 +420,7 @@ func (m *TellReq) Reset()         { *m = TellReq{} }
 func (m *TellReq) String() string { return proto.CompactTextString(m) }
 func (*TellReq) ProtoMessage()    {}
 func (*TellReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rpc_c5f1a61bd6a5b846, []int{6}
+	return fileDescriptor_rpc_f0628d57765e3128, []int{6}
 }
 func (m *TellReq) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TellReq.Unmarshal(m, b)
@@ -382,7 +486,7 @@ func (m *TellRes) Reset()         { *m = TellRes{} }
 func (m *TellRes) String() string { return proto.CompactTextString(m) }
 func (*TellRes) ProtoMessage()    {}
 func (*TellRes) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rpc_c5f1a61bd6a5b846, []int{7}
+	return fileDescriptor_rpc_f0628d57765e3128, []int{7}
 }
 func (m *TellRes) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TellRes.Unmarshal(m, b)
@@ -409,6 +513,313 @@ func (m *TellRes) GetHeader() uint32 {
 	return 0
 }
 
+type FindNodeReq struct {
+	// The DHT key to look up
+	Target []byte `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	// The address of the requesting node, which is excluded from the response
+	Addr                 string   `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	Count                uint32   `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FindNodeReq) Reset()         { *m = FindNodeReq{} }
+func (m *FindNodeReq) String() string { return proto.CompactTextString(m) }
+func (*FindNodeReq) ProtoMessage()    {}
+func (*FindNodeReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rpc_f0628d57765e3128, []int{8}
+}
+func (m *FindNodeReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FindNodeReq.Unmarshal(m, b)
+}
+func (m *FindNodeReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FindNodeReq.Marshal(b, m, deterministic)
+}
+func (dst *FindNodeReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FindNodeReq.Merge(dst, src)
+}
+func (m *FindNodeReq) XXX_Size() int {
+	return xxx_messageInfo_FindNodeReq.Size(m)
+}
+func (m *FindNodeReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_FindNodeReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FindNodeReq proto.InternalMessageInfo
+
+func (m *FindNodeReq) GetTarget() []byte {
+	if m != nil {
+		return m.Target
+	}
+	return nil
+}
+
+func (m *FindNodeReq) GetAddr() string {
+	if m != nil {
+		return m.Addr
+	}
+	return ""
+}
+
+func (m *FindNodeReq) GetCount() uint32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type FindNodeRes struct {
+	Addr []string `protobuf:"bytes,1,rep,name=addr,proto3" json:"addr,omitempty"`
+	// The node IDs claimed by the found nodes, which are verified by the handshakes before adding them into the
+	// routing table
+	Id                   [][]byte `protobuf:"bytes,2,rep,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FindNodeRes) Reset()         { *m = FindNodeRes{} }
+func (m *FindNodeRes) String() string { return proto.CompactTextString(m) }
+func (*FindNodeRes) ProtoMessage()    {}
+func (*FindNodeRes) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rpc_f0628d57765e3128, []int{9}
+}
+func (m *FindNodeRes) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FindNodeRes.Unmarshal(m, b)
+}
+func (m *FindNodeRes) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FindNodeRes.Marshal(b, m, deterministic)
+}
+func (dst *FindNodeRes) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FindNodeRes.Merge(dst, src)
+}
+func (m *FindNodeRes) XXX_Size() int {
+	return xxx_messageInfo_FindNodeRes.Size(m)
+}
+func (m *FindNodeRes) XXX_DiscardUnknown() {
+	xxx_messageInfo_FindNodeRes.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FindNodeRes proto.InternalMessageInfo
+
+func (m *FindNodeRes) GetAddr() []string {
+	if m != nil {
+		return m.Addr
+	}
+	return nil
+}
+
+func (m *FindNodeRes) GetId() [][]byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+type AnnounceReq struct {
+	Header  uint32 `protobuf:"varint,1,opt,name=header,proto3" json:"header,omitempty"`
+	ChainId uint32 `protobuf:"varint,2,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	MsgType uint32 `protobuf:"varint,3,opt,name=msg_type,json=msgType,proto3" json:"msg_type,omitempty"`
+	// The checksum of the message body, which the receiver fetches from the announcer if it hasn't seen the message
+	MsgChecksum []byte `protobuf:"bytes,4,opt,name=msg_checksum,json=msgChecksum,proto3" json:"msg_checksum,omitempty"`
+	Ttl         int32  `protobuf:"varint,5,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// The address of the announcing peer, which serves the message body
+	Addr                 string   `protobuf:"bytes,6,opt,name=addr,proto3" json:"addr,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AnnounceReq) Reset()         { *m = AnnounceReq{} }
+func (m *AnnounceReq) String() string { return proto.CompactTextString(m) }
+func (*AnnounceReq) ProtoMessage()    {}
+func (*AnnounceReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rpc_f0628d57765e3128, []int{10}
+}
+func (m *AnnounceReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AnnounceReq.Unmarshal(m, b)
+}
+func (m *AnnounceReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AnnounceReq.Marshal(b, m, deterministic)
+}
+func (dst *AnnounceReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AnnounceReq.Merge(dst, src)
+}
+func (m *AnnounceReq) XXX_Size() int {
+	return xxx_messageInfo_AnnounceReq.Size(m)
+}
+func (m *AnnounceReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_AnnounceReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AnnounceReq proto.InternalMessageInfo
+
+func (m *AnnounceReq) GetHeader() uint32 {
+	if m != nil {
+		return m.Header
+	}
+	return 0
+}
+
+func (m *AnnounceReq) GetChainId() uint32 {
+	if m != nil {
+		return m.ChainId
+	}
+	return 0
+}
+
+func (m *AnnounceReq) GetMsgType() uint32 {
+	if m != nil {
+		return m.MsgType
+	}
+	return 0
+}
+
+func (m *AnnounceReq) GetMsgChecksum() []byte {
+	if m != nil {
+		return m.MsgChecksum
+	}
+	return nil
+}
+
+func (m *AnnounceReq) GetTtl() int32 {
+	if m != nil {
+		return m.Ttl
+	}
+	return 0
+}
+
+func (m *AnnounceReq) GetAddr() string {
+	if m != nil {
+		return m.Addr
+	}
+	return ""
+}
+
+type AnnounceRes struct {
+	Header               uint32   `protobuf:"varint,1,opt,name=header,proto3" json:"header,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AnnounceRes) Reset()         { *m = AnnounceRes{} }
+func (m *AnnounceRes) String() string { return proto.CompactTextString(m) }
+func (*AnnounceRes) ProtoMessage()    {}
+func (*AnnounceRes) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rpc_f0628d57765e3128, []int{11}
+}
+func (m *AnnounceRes) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AnnounceRes.Unmarshal(m, b)
+}
+func (m *AnnounceRes) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AnnounceRes.Marshal(b, m, deterministic)
+}
+func (dst *AnnounceRes) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AnnounceRes.Merge(dst, src)
+}
+func (m *AnnounceRes) XXX_Size() int {
+	return xxx_messageInfo_AnnounceRes.Size(m)
+}
+func (m *AnnounceRes) XXX_DiscardUnknown() {
+	xxx_messageInfo_AnnounceRes.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AnnounceRes proto.InternalMessageInfo
+
+func (m *AnnounceRes) GetHeader() uint32 {
+	if m != nil {
+		return m.Header
+	}
+	return 0
+}
+
+type FetchReq struct {
+	MsgChecksum []byte `protobuf:"bytes,1,opt,name=msg_checksum,json=msgChecksum,proto3" json:"msg_checksum,omitempty"`
+	// The address of the fetching peer
+	Addr                 string   `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FetchReq) Reset()         { *m = FetchReq{} }
+func (m *FetchReq) String() string { return proto.CompactTextString(m) }
+func (*FetchReq) ProtoMessage()    {}
+func (*FetchReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rpc_f0628d57765e3128, []int{12}
+}
+func (m *FetchReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FetchReq.Unmarshal(m, b)
+}
+func (m *FetchReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FetchReq.Marshal(b, m, deterministic)
+}
+func (dst *FetchReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FetchReq.Merge(dst, src)
+}
+func (m *FetchReq) XXX_Size() int {
+	return xxx_messageInfo_FetchReq.Size(m)
+}
+func (m *FetchReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_FetchReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FetchReq proto.InternalMessageInfo
+
+func (m *FetchReq) GetMsgChecksum() []byte {
+	if m != nil {
+		return m.MsgChecksum
+	}
+	return nil
+}
+
+func (m *FetchReq) GetAddr() string {
+	if m != nil {
+		return m.Addr
+	}
+	return ""
+}
+
+type FetchRes struct {
+	MsgBody              []byte   `protobuf:"bytes,1,opt,name=msg_body,json=msgBody,proto3" json:"msg_body,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FetchRes) Reset()         { *m = FetchRes{} }
+func (m *FetchRes) String() string { return proto.CompactTextString(m) }
+func (*FetchRes) ProtoMessage()    {}
+func (*FetchRes) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rpc_f0628d57765e3128, []int{13}
+}
+func (m *FetchRes) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FetchRes.Unmarshal(m, b)
+}
+func (m *FetchRes) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FetchRes.Marshal(b, m, deterministic)
+}
+func (dst *FetchRes) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FetchRes.Merge(dst, src)
+}
+func (m *FetchRes) XXX_Size() int {
+	return xxx_messageInfo_FetchRes.Size(m)
+}
+func (m *FetchRes) XXX_DiscardUnknown() {
+	xxx_messageInfo_FetchRes.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FetchRes proto.InternalMessageInfo
+
+func (m *FetchRes) GetMsgBody() []byte {
+	if m != nil {
+		return m.MsgBody
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Ping)(nil), "network.Ping")
 	proto.RegisterType((*Pong)(nil), "network.Pong")
@@ -418,6 +829,12 @@ func init() {
 	proto.RegisterType((*BroadcastRes)(nil), "network.BroadcastRes")
 	proto.RegisterType((*TellReq)(nil), "network.TellReq")
 	proto.RegisterType((*TellRes)(nil), "network.TellRes")
+	proto.RegisterType((*FindNodeReq)(nil), "network.FindNodeReq")
+	proto.RegisterType((*FindNodeRes)(nil), "network.FindNodeRes")
+	proto.RegisterType((*AnnounceReq)(nil), "network.AnnounceReq")
+	proto.RegisterType((*AnnounceRes)(nil), "network.AnnounceRes")
+	proto.RegisterType((*FetchReq)(nil), "network.FetchReq")
+	proto.RegisterType((*FetchRes)(nil), "network.FetchRes")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -436,6 +853,9 @@ type PeerClient interface {
 	GetPeers(ctx context.Context, in *GetPeersReq, opts ...grpc.CallOption) (*GetPeersRes, error)
 	Broadcast(ctx context.Context, in *BroadcastReq, opts ...grpc.CallOption) (*BroadcastRes, error)
 	Tell(ctx context.Context, in *TellReq, opts ...grpc.CallOption) (*TellRes, error)
+	FindNode(ctx context.Context, in *FindNodeReq, opts ...grpc.CallOption) (*FindNodeRes, error)
+	Announce(ctx context.Context, in *AnnounceReq, opts ...grpc.CallOption) (*AnnounceRes, error)
+	Fetch(ctx context.Context, in *FetchReq, opts ...grpc.CallOption) (*FetchRes, error)
 }
 
 type peerClient struct {
@@ -482,12 +902,42 @@ func (c *peerClient) Tell(ctx context.Context, in *TellReq, opts ...grpc.CallOpt
 	return out, nil
 }
 
+func (c *peerClient) FindNode(ctx context.Context, in *FindNodeReq, opts ...grpc.CallOption) (*FindNodeRes, error) {
+	out := new(FindNodeRes)
+	err := c.cc.Invoke(ctx, "/network.Peer/findNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerClient) Announce(ctx context.Context, in *AnnounceReq, opts ...grpc.CallOption) (*AnnounceRes, error) {
+	out := new(AnnounceRes)
+	err := c.cc.Invoke(ctx, "/network.Peer/announce", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerClient) Fetch(ctx context.Context, in *FetchReq, opts ...grpc.CallOption) (*FetchRes, error) {
+	out := new(FetchRes)
+	err := c.cc.Invoke(ctx, "/network.Peer/fetch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PeerServer is the server API for Peer service.
 type PeerServer interface {
 	Ping(context.Context, *Ping) (*Pong, error)
 	GetPeers(context.Context, *GetPeersReq) (*GetPeersRes, error)
 	Broadcast(context.Context, *BroadcastReq) (*BroadcastRes, error)
 	Tell(context.Context, *TellReq) (*TellRes, error)
+	FindNode(context.Context, *FindNodeReq) (*FindNodeRes, error)
+	Announce(context.Context, *AnnounceReq) (*AnnounceRes, error)
+	Fetch(context.Context, *FetchReq) (*FetchRes, error)
 }
 
 func RegisterPeerServer(s *grpc.Server, srv PeerServer) {
@@ -566,6 +1016,60 @@ func _Peer_Tell_Handler(srv interface{}, ctx context.Context, dec func(interface
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Peer_FindNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindNodeReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServer).FindNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/network.Peer/FindNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServer).FindNode(ctx, req.(*FindNodeReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Peer_Announce_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnnounceReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServer).Announce(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/network.Peer/Announce",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServer).Announce(ctx, req.(*AnnounceReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Peer_Fetch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServer).Fetch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/network.Peer/Fetch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServer).Fetch(ctx, req.(*FetchReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Peer_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "network.Peer",
 	HandlerType: (*PeerServer)(nil),
@@ -586,38 +1090,66 @@ var _Peer_serviceDesc = grpc.ServiceDesc{
 			MethodName: "tell",
 			Handler:    _Peer_Tell_Handler,
 		},
+		{
+			MethodName: "findNode",
+			Handler:    _Peer_FindNode_Handler,
+		},
+		{
+			MethodName: "announce",
+			Handler:    _Peer_Announce_Handler,
+		},
+		{
+			MethodName: "fetch",
+			Handler:    _Peer_Fetch_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "network/proto/rpc.proto",
 }
 
-func init() { proto.RegisterFile("network/proto/rpc.proto", fileDescriptor_rpc_c5f1a61bd6a5b846) }
-
-var fileDescriptor_rpc_c5f1a61bd6a5b846 = []byte{
-	// 399 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x53, 0xcd, 0x8e, 0xd3, 0x30,
-	0x10, 0xae, 0x89, 0xd3, 0x9f, 0xd9, 0x56, 0x5a, 0x59, 0x0b, 0x84, 0x70, 0x49, 0xbd, 0xd2, 0x2a,
-	0x07, 0xd4, 0x45, 0x70, 0x41, 0xe2, 0x56, 0x0e, 0x88, 0x0b, 0xaa, 0xa2, 0xde, 0xab, 0xd4, 0xb6,
-	0xd2, 0x2a, 0x89, 0x1d, 0x62, 0x57, 0x28, 0x2f, 0xc0, 0xd3, 0xf0, 0x36, 0xbc, 0x10, 0xb2, 0x9b,
-	0x46, 0x29, 0x6a, 0xb8, 0xcd, 0x37, 0xdf, 0x78, 0x3c, 0xdf, 0xf8, 0x33, 0xbc, 0x96, 0xc2, 0xfc,
-	0x54, 0x75, 0xfe, 0x5c, 0xd5, 0xca, 0xa8, 0xe7, 0xba, 0x62, 0x2b, 0x17, 0x91, 0x49, 0x4b, 0xd0,
-	0xf7, 0x80, 0x37, 0x47, 0x99, 0x91, 0x07, 0xf0, 0xa5, 0x92, 0x4c, 0x04, 0x28, 0x42, 0x31, 0x4e,
-	0xce, 0x80, 0x10, 0xc0, 0x29, 0xe7, 0x75, 0xf0, 0x22, 0x42, 0xf1, 0x2c, 0x71, 0x31, 0x7d, 0x04,
-	0xbc, 0x51, 0x32, 0x23, 0x6f, 0x61, 0x96, 0xb2, 0x7c, 0xd7, 0x3f, 0x35, 0x4d, 0x59, 0xfe, 0xdd,
-	0x62, 0xfa, 0x08, 0x77, 0x5f, 0x85, 0xd9, 0x08, 0x51, 0xeb, 0x44, 0xfc, 0xb0, 0xdd, 0x99, 0x3a,
-	0x49, 0xe3, 0xea, 0x16, 0xc9, 0x19, 0xd0, 0x65, 0xbf, 0x48, 0x77, 0x97, 0xa1, 0xc8, 0xeb, 0x2e,
-	0xfb, 0x8d, 0x60, 0xbe, 0xae, 0x55, 0xca, 0x59, 0xaa, 0x8d, 0xed, 0xf4, 0x0a, 0xc6, 0x07, 0x91,
-	0x72, 0x51, 0xb7, 0xad, 0x5a, 0x44, 0xde, 0xc0, 0x94, 0x1d, 0xd2, 0xa3, 0xdc, 0x1d, 0xb9, 0x9b,
-	0x76, 0x91, 0x4c, 0x1c, 0xfe, 0xc6, 0x2d, 0x55, 0xea, 0x6c, 0x67, 0x9a, 0x4a, 0x04, 0xde, 0x99,
-	0x2a, 0x75, 0xb6, 0x6d, 0x2a, 0x71, 0xa1, 0xf6, 0x8a, 0x37, 0x01, 0x8e, 0x50, 0x3c, 0x77, 0xd4,
-	0x5a, 0xf1, 0x86, 0x2c, 0x61, 0x6e, 0x29, 0x76, 0x10, 0x2c, 0xd7, 0xa7, 0x32, 0xf0, 0x1d, 0x7d,
-	0x57, 0xea, 0xec, 0x4b, 0x9b, 0x22, 0xf7, 0xe0, 0x19, 0x53, 0x04, 0xe3, 0x08, 0xc5, 0x7e, 0x62,
-	0x43, 0xfa, 0x74, 0x35, 0xad, 0x1e, 0x9a, 0x96, 0xfe, 0x42, 0x30, 0xd9, 0x8a, 0xa2, 0xf8, 0x9f,
-	0xa2, 0x1b, 0xbb, 0xbf, 0x52, 0xe9, 0x0d, 0xab, 0xc4, 0xc3, 0x2a, 0xfd, 0x2b, 0x95, 0x74, 0x79,
-	0x99, 0x63, 0x70, 0xd6, 0x0f, 0x7f, 0x10, 0x60, 0xfb, 0x46, 0xe4, 0x09, 0x70, 0x65, 0xad, 0xb2,
-	0x58, 0xb5, 0xe6, 0x59, 0x59, 0xe7, 0x84, 0x3d, 0xa8, 0x64, 0x46, 0x47, 0xe4, 0x13, 0x4c, 0xb3,
-	0xf6, 0x59, 0xc9, 0x43, 0x47, 0xf6, 0xec, 0x10, 0xde, 0xca, 0x6a, 0x3a, 0x22, 0x9f, 0x61, 0xb6,
-	0xbf, 0xac, 0x8f, 0xbc, 0xec, 0x8a, 0xfa, 0x06, 0x08, 0x6f, 0xa6, 0xed, 0xe1, 0x77, 0x80, 0x8d,
-	0x28, 0x0a, 0x72, 0xdf, 0x15, 0xb4, 0x1b, 0x0e, 0xff, 0xcd, 0x68, 0x3a, 0xda, 0x8f, 0xdd, 0x3f,
-	0xf8, 0xf8, 0x37, 0x00, 0x00, 0xff, 0xff, 0xbf, 0xf4, 0x7b, 0x4a, 0x22, 0x03, 0x00, 0x00,
+func init() { proto.RegisterFile("network/proto/rpc.proto", fileDescriptor_rpc_f0628d57765e3128) }
+
+var fileDescriptor_rpc_f0628d57765e3128 = []byte{
+	// 643 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x95, 0x41, 0x6f, 0xd3, 0x30,
+	0x14, 0xc7, 0x97, 0x36, 0x69, 0xda, 0xd7, 0x0e, 0x0d, 0x33, 0x58, 0x16, 0x38, 0x74, 0x46, 0x9b,
+	0x7a, 0x40, 0xab, 0x80, 0x0b, 0x12, 0xa7, 0x0d, 0x69, 0x08, 0x21, 0x8d, 0x29, 0xec, 0x5e, 0xa5,
+	0x89, 0x97, 0x46, 0x4d, 0xed, 0x10, 0x3b, 0xa0, 0x7e, 0x01, 0x3e, 0x09, 0x57, 0xbe, 0x03, 0xdf,
+	0x87, 0x2f, 0xc0, 0x11, 0x39, 0x75, 0x52, 0xb7, 0x4b, 0x2a, 0x21, 0x71, 0xf3, 0x7b, 0xcf, 0x7e,
+	0x79, 0x3f, 0xff, 0xdf, 0x73, 0xe0, 0x88, 0x12, 0xf1, 0x8d, 0x65, 0xf3, 0x71, 0x9a, 0x31, 0xc1,
+	0xc6, 0x59, 0x1a, 0x9c, 0x17, 0x2b, 0x64, 0xab, 0x00, 0xfe, 0x6d, 0x80, 0x79, 0x13, 0xd3, 0x08,
+	0x1d, 0x82, 0x45, 0x19, 0x0d, 0x88, 0x63, 0x0c, 0x8d, 0x91, 0xe9, 0xad, 0x0c, 0x84, 0xc0, 0xf4,
+	0xc3, 0x30, 0x73, 0x5a, 0x43, 0x63, 0xd4, 0xf3, 0x8a, 0x35, 0x3a, 0x86, 0x6e, 0x30, 0xf3, 0x63,
+	0x3a, 0x89, 0x43, 0xa7, 0x3d, 0x34, 0x46, 0xfb, 0x9e, 0x5d, 0xd8, 0x1f, 0x42, 0xe4, 0x80, 0xfd,
+	0x95, 0x64, 0x3c, 0x66, 0xd4, 0x31, 0x57, 0x11, 0x65, 0xa2, 0x23, 0xb0, 0xd3, 0x7c, 0x3a, 0x99,
+	0x93, 0xa5, 0x63, 0x0d, 0x8d, 0xd1, 0xc0, 0xeb, 0xa4, 0xf9, 0xf4, 0x23, 0x59, 0xa2, 0x67, 0xd0,
+	0xe3, 0x71, 0x44, 0x7d, 0x91, 0x67, 0xc4, 0xe9, 0x14, 0xa1, 0xb5, 0x43, 0x46, 0x83, 0x99, 0x9f,
+	0x24, 0x84, 0x46, 0xc4, 0xb1, 0x8b, 0xca, 0xd6, 0x0e, 0x34, 0x86, 0x47, 0x95, 0x31, 0x59, 0x67,
+	0xe9, 0x16, 0x59, 0x50, 0x15, 0xfa, 0x5c, 0x46, 0xf0, 0x4f, 0x49, 0xcb, 0x68, 0x84, 0x9e, 0x42,
+	0xcf, 0x0f, 0xe6, 0x13, 0x9d, 0xb8, 0xeb, 0x07, 0xf3, 0xeb, 0x02, 0x5a, 0x07, 0x6c, 0x35, 0x02,
+	0xb6, 0x1b, 0x01, 0xcd, 0x66, 0x40, 0x6b, 0x27, 0x60, 0x67, 0x0b, 0x10, 0x3f, 0x87, 0xfe, 0x7b,
+	0x22, 0x6e, 0x08, 0xc9, 0xb8, 0x47, 0xbe, 0x48, 0x8d, 0x02, 0x96, 0x53, 0x51, 0x54, 0xbc, 0xef,
+	0xad, 0x0c, 0x7c, 0xa2, 0x6f, 0xe2, 0x95, 0x64, 0xc6, 0xb0, 0x5d, 0x4a, 0x86, 0x7f, 0x19, 0x30,
+	0xb8, 0xcc, 0x98, 0x1f, 0x06, 0x3e, 0x17, 0x32, 0xd3, 0x13, 0xe8, 0xcc, 0x88, 0x1f, 0x92, 0x4c,
+	0xa5, 0x52, 0xd6, 0x2e, 0xf4, 0x63, 0xe8, 0x2e, 0x78, 0x34, 0x11, 0xcb, 0x94, 0x94, 0xec, 0x0b,
+	0x1e, 0xdd, 0x2e, 0x53, 0x52, 0x86, 0xa6, 0x2c, 0x2c, 0xe1, 0x65, 0xe8, 0x92, 0x85, 0x4b, 0x74,
+	0x02, 0x03, 0x19, 0x0a, 0x66, 0x24, 0x98, 0xf3, 0x7c, 0xa1, 0x2e, 0xa0, 0xbf, 0xe0, 0xd1, 0x3b,
+	0xe5, 0x42, 0x07, 0xd0, 0x16, 0x22, 0x29, 0xe0, 0x2d, 0x4f, 0x2e, 0x2b, 0x04, 0x7b, 0xdd, 0x75,
+	0xf8, 0x6c, 0x83, 0x80, 0x37, 0x11, 0xe0, 0xef, 0x06, 0xd8, 0xb7, 0x24, 0x49, 0x76, 0x51, 0xfe,
+	0x63, 0x57, 0xeb, 0xe4, 0x66, 0x33, 0xb9, 0xb5, 0x41, 0x8e, 0x4f, 0xca, 0x3a, 0x9a, 0x6b, 0xfd,
+	0x04, 0xfd, 0xab, 0x98, 0x86, 0xd7, 0x2c, 0x24, 0xaa, 0x5c, 0xe1, 0x67, 0x11, 0x59, 0xe9, 0x3b,
+	0xf0, 0x94, 0x55, 0x5b, 0x6e, 0xd5, 0x0a, 0x6d, 0xbd, 0x15, 0x5e, 0xea, 0x09, 0x6b, 0x5b, 0x01,
+	0x3d, 0x80, 0x56, 0xa1, 0x6d, 0x7b, 0x34, 0xf0, 0x5a, 0x71, 0x88, 0x7f, 0x18, 0xd0, 0xbf, 0xa0,
+	0x94, 0xe5, 0x34, 0x20, 0xff, 0xbf, 0x33, 0xb6, 0xe5, 0x37, 0x1b, 0xe5, 0xb7, 0xee, 0xcb, 0xdf,
+	0xd1, 0xe4, 0x3f, 0xd5, 0xab, 0x6c, 0xbe, 0xd1, 0x0b, 0xe8, 0x5e, 0x11, 0x11, 0xcc, 0x24, 0xc9,
+	0xf6, 0xb7, 0x8d, 0xfb, 0xdf, 0xae, 0xb9, 0x59, 0x7c, 0x5a, 0xa5, 0xe0, 0x1b, 0xf2, 0x1a, 0x1b,
+	0xf2, 0xbe, 0xfa, 0xd3, 0x02, 0x53, 0xce, 0x1c, 0x3a, 0x03, 0x33, 0x95, 0x0f, 0xe8, 0xfe, 0xb9,
+	0x7a, 0x53, 0xcf, 0xe5, 0x7b, 0xea, 0x6a, 0x26, 0xa3, 0x11, 0xde, 0x43, 0x6f, 0xa0, 0x1b, 0xa9,
+	0x31, 0x45, 0x87, 0x55, 0x50, 0x1b, 0x6f, 0xb7, 0xce, 0xcb, 0xf1, 0x1e, 0x7a, 0x0b, 0xbd, 0x69,
+	0xd9, 0xfa, 0xe8, 0x71, 0xb5, 0x49, 0x1f, 0x68, 0xb7, 0xd6, 0x2d, 0x0f, 0xbf, 0x00, 0x53, 0x90,
+	0x24, 0x41, 0x07, 0xd5, 0x06, 0x35, 0x1d, 0xee, 0xb6, 0x87, 0xaf, 0x8a, 0xbc, 0x53, 0x0d, 0xa4,
+	0x15, 0xa9, 0x35, 0xa9, 0x5b, 0xe7, 0x55, 0x27, 0x7d, 0x25, 0x90, 0x76, 0x52, 0xeb, 0x2c, 0xb7,
+	0xce, 0x2b, 0x4f, 0x8e, 0xc1, 0xba, 0x93, 0x17, 0x8e, 0x1e, 0xae, 0x53, 0x2b, 0x0d, 0xdd, 0x7b,
+	0x2e, 0x8e, 0xf7, 0xa6, 0x9d, 0xe2, 0x1f, 0xf6, 0xfa, 0xef, 0x00, 0x4b, 0x8f, 0x14, 0x2f, 0xde,
+	0x06, 0x00, 0x00,
 }
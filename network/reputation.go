@@ -0,0 +1,233 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package network
+
+import (
+	"sync"
+	"time"
+
+	"github.com/facebookgo/clock"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/proto"
+)
+
+var (
+	// ErrInvalidMsg indicates that a message from a peer is invalid regardless of the state of the receiver, which is
+	// counted as a misbehavior of the peer
+	ErrInvalidMsg = errors.New("invalid message")
+	// ErrBadSignature indicates that a message from a peer carries a bad signature
+	ErrBadSignature = errors.New("bad signature")
+	// ErrUselessMsg indicates that a message from a peer brings nothing new to the receiver. It isn't counted as a
+	// misbehavior, because the same blocks and messages are likely to reach the receiver from several honest peers.
+	ErrUselessMsg = errors.New("useless message")
+	// ErrPeerBanned indicates that the peer is banned for its misbehaviors
+	ErrPeerBanned = errors.New("peer is banned")
+)
+
+var (
+	misbehaviorMtc = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_network_peer_misbehavior",
+			Help: "Peer misbehavior counter.",
+		},
+		[]string{"misbehavior"},
+	)
+	banMtc = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_network_peer_ban",
+			Help: "Peer ban counter.",
+		},
+		[]string{},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(misbehaviorMtc)
+	prometheus.MustRegister(banMtc)
+}
+
+// Misbehavior is a kind of misbehavior which lowers the reputation score of a peer
+type Misbehavior int
+
+const (
+	// InvalidAction means that the peer sent an action which is invalid by itself
+	InvalidAction Misbehavior = iota
+	// InvalidBlock means that the peer sent a block which is invalid by itself
+	InvalidBlock
+	// BadSignature means that the peer sent an action, a block or an endorsement with a bad signature
+	BadSignature
+	// OversizedMsg means that the peer sent a message larger than the max message size
+	OversizedMsg
+	// UnresponsivePing means that the peer failed to answer a ping
+	UnresponsivePing
+	// MismatchedFetchResponse means that the peer served a message body not matching the checksum it announced
+	MismatchedFetchResponse
+	// InvalidConsensusMsg means that the peer sent a proposal or an endorsement which is invalid by itself
+	InvalidConsensusMsg
+)
+
+// penalties are the scores deducted from a peer for each kind of misbehavior
+var penalties = map[Misbehavior]int{
+	InvalidAction:           10,
+	InvalidBlock:            50,
+	BadSignature:            50,
+	OversizedMsg:            20,
+	UnresponsivePing:        2,
+	MismatchedFetchResponse: 20,
+	InvalidConsensusMsg:     20,
+}
+
+// String returns the name of the misbehavior
+func (m Misbehavior) String() string {
+	switch m {
+	case InvalidAction:
+		return "invalid_action"
+	case InvalidBlock:
+		return "invalid_block"
+	case BadSignature:
+		return "bad_signature"
+	case OversizedMsg:
+		return "oversized_msg"
+	case UnresponsivePing:
+		return "unresponsive_ping"
+	case MismatchedFetchResponse:
+		return "mismatched_fetch_response"
+	case InvalidConsensusMsg:
+		return "invalid_consensus_msg"
+	default:
+		return "unknown"
+	}
+}
+
+// misbehaviorOf tells the misbehavior of the peer, if the given error returned from handling the message of the given
+// type is caused by the peer
+func misbehaviorOf(msgType uint32, err error) (Misbehavior, bool) {
+	switch errors.Cause(err) {
+	case ErrBadSignature:
+		return BadSignature, true
+	case ErrInvalidMsg:
+		switch msgType {
+		case iproto.MsgActionType:
+			return InvalidAction, true
+		case iproto.MsgBlockProtoMsgType, iproto.MsgBlockSyncDataType, iproto.MsgBlockHeaderSyncDataType:
+			return InvalidBlock, true
+		case iproto.MsgProposeProtoMsgType, iproto.MsgEndorseProtoMsgType:
+			return InvalidConsensusMsg, true
+		}
+	}
+	return 0, false
+}
+
+// peerScore is the reputation score of a peer. The score starts from 0, goes down with misbehaviors and recovers by 1
+// every recovery interval until 0.
+type peerScore struct {
+	score      int
+	lastUpdate time.Time
+}
+
+// Reputation keeps the reputation scores of the peers keyed by their peer keys (see IotxOverlay.peerKey), and bans the
+// peers whose scores drop below the threshold for a while
+type Reputation struct {
+	cfg    *config.Network
+	clk    clock.Clock
+	mu     sync.Mutex
+	scores map[string]*peerScore
+	bans   map[string]time.Time
+}
+
+// NewReputation creates an instance of Reputation
+func NewReputation(cfg *config.Network, clk clock.Clock) *Reputation {
+	return &Reputation{
+		cfg:    cfg,
+		clk:    clk,
+		scores: make(map[string]*peerScore),
+		bans:   make(map[string]time.Time),
+	}
+}
+
+// Penalize lowers the score of the peer of the given key for the misbehavior, and returns true if the peer gets
+// banned because of it
+func (r *Reputation) Penalize(key string, m Misbehavior) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	misbehaviorMtc.WithLabelValues(m.String()).Inc()
+	if r.isBanned(key) {
+		return false
+	}
+	s := r.score(key)
+	s.score -= penalties[m]
+	if r.cfg.PeerBanDuration <= 0 || s.score >= r.cfg.PeerBanThreshold {
+		return false
+	}
+	// The peer starts over with a clean score after the ban expires
+	delete(r.scores, key)
+	r.bans[key] = r.clk.Now().Add(r.cfg.PeerBanDuration)
+	banMtc.WithLabelValues().Inc()
+	return true
+}
+
+// Score returns the current score of the peer of the given key
+func (r *Reputation) Score(key string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.scores[key]
+	if !ok {
+		return 0
+	}
+	r.recover(s)
+	return s.score
+}
+
+// IsBanned returns true if the peer of the given key is currently banned
+func (r *Reputation) IsBanned(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.isBanned(key)
+}
+
+func (r *Reputation) isBanned(key string) bool {
+	until, ok := r.bans[key]
+	if !ok {
+		return false
+	}
+	if r.clk.Now().Before(until) {
+		return true
+	}
+	delete(r.bans, key)
+	return false
+}
+
+// score returns the recovered score of the peer, which is created if not existing
+func (r *Reputation) score(key string) *peerScore {
+	s, ok := r.scores[key]
+	if !ok {
+		s = &peerScore{lastUpdate: r.clk.Now()}
+		r.scores[key] = s
+		return s
+	}
+	r.recover(s)
+	return s
+}
+
+func (r *Reputation) recover(s *peerScore) {
+	now := r.clk.Now()
+	if r.cfg.PeerScoreRecoveryInterval <= 0 || s.score >= 0 {
+		s.lastUpdate = now
+		return
+	}
+	points := int(now.Sub(s.lastUpdate) / r.cfg.PeerScoreRecoveryInterval)
+	s.lastUpdate = s.lastUpdate.Add(time.Duration(points) * r.cfg.PeerScoreRecoveryInterval)
+	if s.score += points; s.score > 0 {
+		s.score = 0
+	}
+}
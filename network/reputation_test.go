@@ -0,0 +1,90 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/facebookgo/clock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/proto"
+)
+
+func TestReputation(t *testing.T) {
+	require := require.New(t)
+
+	cfg := config.Default.Network
+	cfg.PeerBanThreshold = -100
+	cfg.PeerBanDuration = 10 * time.Minute
+	cfg.PeerScoreRecoveryInterval = time.Minute
+	clk := clock.NewMock()
+	r := NewReputation(&cfg, clk)
+	addr := "127.0.0.1:10001"
+
+	require.Equal(0, r.Score(addr))
+	require.False(r.Penalize(addr, InvalidAction))
+	require.Equal(-10, r.Score(addr))
+
+	// Score recovers over time until 0
+	clk.Add(5 * time.Minute)
+	require.Equal(-5, r.Score(addr))
+	clk.Add(time.Hour)
+	require.Equal(0, r.Score(addr))
+
+	// Peer gets banned once the score drops below the threshold
+	require.False(r.Penalize(addr, BadSignature))
+	require.False(r.Penalize(addr, InvalidBlock))
+	require.False(r.IsBanned(addr))
+	require.True(r.Penalize(addr, InvalidAction))
+	require.True(r.IsBanned(addr))
+	require.False(r.IsBanned("127.0.0.1:10002"))
+	// Peer already banned is not banned again
+	require.False(r.Penalize(addr, InvalidAction))
+
+	// Ban expires after the duration, and the peer starts over with a clean score
+	clk.Add(10 * time.Minute)
+	require.False(r.IsBanned(addr))
+	require.Equal(0, r.Score(addr))
+
+	// Zero ban duration disables banning
+	cfg.PeerBanDuration = 0
+	for i := 0; i < 10; i++ {
+		require.False(r.Penalize(addr, BadSignature))
+	}
+	require.False(r.IsBanned(addr))
+}
+
+func TestMisbehaviorOf(t *testing.T) {
+	require := require.New(t)
+
+	m, ok := misbehaviorOf(iproto.MsgActionType, errors.Wrap(ErrBadSignature, "action"))
+	require.True(ok)
+	require.Equal(BadSignature, m)
+	m, ok = misbehaviorOf(iproto.MsgActionType, errors.Wrap(ErrInvalidMsg, "action"))
+	require.True(ok)
+	require.Equal(InvalidAction, m)
+	m, ok = misbehaviorOf(iproto.MsgBlockSyncDataType, errors.Wrap(ErrInvalidMsg, "block"))
+	require.True(ok)
+	require.Equal(InvalidBlock, m)
+	m, ok = misbehaviorOf(iproto.MsgBlockHeaderSyncDataType, errors.Wrap(ErrInvalidMsg, "header"))
+	require.True(ok)
+	require.Equal(InvalidBlock, m)
+	m, ok = misbehaviorOf(iproto.MsgEndorseProtoMsgType, errors.Wrap(ErrInvalidMsg, "endorse"))
+	require.True(ok)
+	require.Equal(InvalidConsensusMsg, m)
+
+	// Errors depending on the state of the receiver are not counted as misbehaviors, nor are the blocks already known,
+	// which honest peers could serve as well
+	_, ok = misbehaviorOf(iproto.MsgBlockSyncDataType, errors.Wrap(ErrUselessMsg, "block"))
+	require.False(ok)
+	_, ok = misbehaviorOf(iproto.MsgActionType, errors.New("nonce too low"))
+	require.False(ok)
+}
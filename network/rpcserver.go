@@ -13,16 +13,19 @@ import (
 	"sync"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/stats"
 
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/network/node"
 	pb "github.com/iotexproject/iotex-core/network/proto"
 	"github.com/iotexproject/iotex-core/pkg/counter"
+	"github.com/iotexproject/iotex-core/pkg/hash"
 	"github.com/iotexproject/iotex-core/pkg/lifecycle"
 	"github.com/iotexproject/iotex-core/proto"
 )
@@ -54,6 +57,7 @@ type RPCServer struct {
 	counters    *sync.Map
 	rateLimit   uint64
 	lastReqTime time.Time
+	conns       *connIDs
 }
 
 // NewRPCServer creates an instance of RPCServer
@@ -68,6 +72,7 @@ func NewRPCServer(o *IotxOverlay) *RPCServer {
 		listenPort: listenPort,
 		rateLimit:  o.Config.RateLimitPerSec * uint64(o.Config.RateLimitWindowSize) / uint64(time.Second),
 		counters:   &sync.Map{},
+		conns:      newConnIDs(),
 	}
 }
 
@@ -82,8 +87,36 @@ func (s *RPCServer) Ping(ctx context.Context, ping *pb.Ping) (*pb.Pong, error) {
 		return nil, fmt.Errorf("sended requests too frequently")
 	}
 	sRequestMtc.WithLabelValues("Ping", "false").Inc()
-	s.Overlay.PM.AddPeer(ping.Addr)
-	return &pb.Pong{AckNonce: ping.Nonce}, nil
+	key, err := s.senderKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.Overlay.Reputation.IsBanned(key) {
+		return nil, ErrPeerBanned
+	}
+	id, err := s.Overlay.verifyPing(ping)
+	if err == nil && ping.Challenge != 0 {
+		err = s.Overlay.verifyChallenge(ping)
+	}
+	if err != nil {
+		if errors.Cause(err) == ErrBadSignature {
+			s.Overlay.penalize(key, BadSignature)
+		}
+		return nil, err
+	}
+	conn, err := s.getClientAddr(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ping.Challenge != 0 && !s.conns.bind(conn, ping.Challenge, id) {
+		return nil, errors.Wrap(ErrInvalidMsg, "challenge is not issued for the connection")
+	}
+	// The address isn't proven yet, so that the node gets into the routing table only after it answers a handshake
+	// from this node at the address
+	s.Overlay.PM.addPingingPeer(ping.Addr, id)
+	pong := newPong(s.Overlay.Identity, s.Overlay.ChainID, ping.Nonce)
+	pong.Challenge = s.conns.challenge(conn)
+	return pong, nil
 }
 
 // GetPeers implements the server side RPC logic
@@ -124,8 +157,15 @@ func (s *RPCServer) Broadcast(ctx context.Context, req *pb.BroadcastReq) (*pb.Br
 		return nil, fmt.Errorf("sended requests too frequently")
 	}
 	sRequestMtc.WithLabelValues("Broadcast", "false").Inc()
+	key, err := s.senderKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkMsg(key, req.MsgBody); err != nil {
+		return nil, err
+	}
 
-	err = s.Overlay.Gossip.OnReceivingMsg(req)
+	err = s.Overlay.Gossip.OnReceivingMsg(req, key)
 	if err == nil {
 		return &pb.BroadcastRes{Header: iproto.MagicBroadcastMsgHeader}, nil
 	}
@@ -143,17 +183,122 @@ func (s *RPCServer) Tell(ctx context.Context, req *pb.TellReq) (*pb.TellRes, err
 		return nil, fmt.Errorf("sended requests too frequently")
 	}
 	sRequestMtc.WithLabelValues("Tell", "false").Inc()
+	key, err := s.senderKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkMsg(key, req.MsgBody); err != nil {
+		return nil, err
+	}
 
 	protoMsg, err := iproto.TypifyProtoMsg(req.MsgType, req.MsgBody)
 	if err != nil {
 		return nil, err
 	}
 	if s.Overlay.Dispatcher != nil {
-		s.Overlay.Dispatcher.HandleTell(req.ChainId, node.NewTCPNode(req.Addr), protoMsg, nil)
+		s.Overlay.Dispatcher.HandleTell(req.ChainId, newRemoteSender(req.Addr, key), protoMsg, nil)
 	}
 	return &pb.TellRes{Header: iproto.MagicBroadcastMsgHeader}, nil
 }
 
+// Announce implements the server side RPC logic
+func (s *RPCServer) Announce(ctx context.Context, req *pb.AnnounceReq) (*pb.AnnounceRes, error) {
+	drop, err := s.shouldDropRequest(ctx)
+	s.updateLastResTime()
+	if err != nil {
+		return nil, err
+	}
+	if drop {
+		return nil, fmt.Errorf("sended requests too frequently")
+	}
+	sRequestMtc.WithLabelValues("Announce", "false").Inc()
+	key, err := s.senderKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.Overlay.Reputation.IsBanned(key) {
+		return nil, ErrPeerBanned
+	}
+	if req.Addr == "" {
+		return nil, errors.Wrap(ErrInvalidMsg, "announcement without the announcer address")
+	}
+	if len(req.MsgChecksum) != hash.HashSize {
+		return nil, errors.Wrapf(ErrInvalidMsg, "checksum of %d bytes is invalid", len(req.MsgChecksum))
+	}
+
+	if err := s.Overlay.Gossip.OnReceivingAnnounce(req, key); err != nil {
+		return nil, err
+	}
+	return &pb.AnnounceRes{Header: iproto.MagicBroadcastMsgHeader}, nil
+}
+
+// Fetch implements the server side RPC logic
+func (s *RPCServer) Fetch(ctx context.Context, req *pb.FetchReq) (*pb.FetchRes, error) {
+	drop, err := s.shouldDropRequest(ctx)
+	s.updateLastResTime()
+	if err != nil {
+		return nil, err
+	}
+	if drop {
+		return nil, fmt.Errorf("sended requests too frequently")
+	}
+	sRequestMtc.WithLabelValues("Fetch", "false").Inc()
+	key, err := s.senderKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.Overlay.Reputation.IsBanned(key) {
+		return nil, ErrPeerBanned
+	}
+
+	body, ok := s.Overlay.Gossip.announcedBody(req.MsgChecksum)
+	if !ok {
+		return nil, errors.Errorf("message %x is not found", req.MsgChecksum)
+	}
+	return &pb.FetchRes{MsgBody: body}, nil
+}
+
+// FindNode implements the server side RPC logic
+func (s *RPCServer) FindNode(ctx context.Context, req *pb.FindNodeReq) (*pb.FindNodeRes, error) {
+	drop, err := s.shouldDropRequest(ctx)
+	s.updateLastResTime()
+	if err != nil {
+		return nil, err
+	}
+	if drop {
+		return nil, fmt.Errorf("sended requests too frequently")
+	}
+	sRequestMtc.WithLabelValues("FindNode", "false").Inc()
+	if s.Overlay.DHT == nil {
+		return nil, fmt.Errorf("DHT is disabled")
+	}
+	key, err := s.senderKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.Overlay.Reputation.IsBanned(key) {
+		return nil, ErrPeerBanned
+	}
+	if len(req.Target) != hash.HashSize {
+		return nil, errors.Wrapf(ErrInvalidMsg, "target of %d bytes is not a DHT key", len(req.Target))
+	}
+
+	var target hash.Hash32B
+	copy(target[:], req.Target)
+	count := int(req.Count)
+	if count <= 0 || count > s.Overlay.DHT.Table.k {
+		count = s.Overlay.DHT.Table.k
+	}
+	res := &pb.FindNodeRes{}
+	for _, c := range s.Overlay.DHT.Table.closest(target, count+1) {
+		if c.addr != req.Addr && len(res.Addr) < count {
+			res.Addr = append(res.Addr, c.addr)
+			res.Id = append(res.Id, c.id[:])
+		}
+	}
+	return res, nil
+}
+
 // Start starts the rpc server
 func (s *RPCServer) Start(_ context.Context) error {
 	lis, err := net.Listen(s.Network(), s.listenPort)
@@ -175,11 +320,13 @@ func (s *RPCServer) Start(_ context.Context) error {
 		}
 		s.Server = grpc.NewServer(
 			grpc.Creds(creds),
+			grpc.StatsHandler(&connHandler{conns: s.conns}),
 			grpc.KeepaliveEnforcementPolicy(s.Overlay.Config.KLPolicy),
 			grpc.KeepaliveParams(s.Overlay.Config.KLServerParams),
 			grpc.MaxRecvMsgSize(s.Overlay.Config.MaxMsgSize))
 	} else {
 		s.Server = grpc.NewServer(
+			grpc.StatsHandler(&connHandler{conns: s.conns}),
 			grpc.KeepaliveEnforcementPolicy(s.Overlay.Config.KLPolicy),
 			grpc.KeepaliveParams(s.Overlay.Config.KLServerParams),
 			grpc.MaxRecvMsgSize(1024*1024*10))
@@ -232,6 +379,32 @@ func (s *RPCServer) shouldDropRequest(ctx context.Context) (bool, error) {
 	return false, nil
 }
 
+// checkMsg rejects the message from a banned peer, and penalizes the peer sending an oversized message
+func (s *RPCServer) checkMsg(key string, msgBody []byte) error {
+	if s.Overlay.Reputation.IsBanned(key) {
+		return ErrPeerBanned
+	}
+	if maxSize := s.Overlay.Config.MaxMsgSize; maxSize > 0 && len(msgBody) > maxSize {
+		s.Overlay.penalize(key, OversizedMsg)
+		return errors.Wrapf(ErrInvalidMsg, "message of %d bytes exceeds the max size %d", len(msgBody), maxSize)
+	}
+	return nil
+}
+
+// senderKey returns the reputation key of the connection the request arrives on, which is the node ID of the sender if
+// the connection is bound to it, or the peer key of the remote address otherwise. Unlike the address in the request,
+// it can't be forged by the sender, so it is what the reputation of the sender is kept by.
+func (s *RPCServer) senderKey(ctx context.Context) (string, error) {
+	addr, err := s.getClientAddr(ctx)
+	if err != nil {
+		return "", err
+	}
+	if id, ok := s.conns.id(addr); ok {
+		return idKey(id), nil
+	}
+	return s.Overlay.peerKey(addr), nil
+}
+
 func (s *RPCServer) getClientAddr(ctx context.Context) (string, error) {
 	p, ok := peer.FromContext(ctx)
 	if !ok {
@@ -247,3 +420,31 @@ func (s *RPCServer) getClientAddr(ctx context.Context) (string, error) {
 func (s *RPCServer) updateLastResTime() {
 	s.lastReqTime = time.Now()
 }
+
+type connTagKey struct{}
+
+// connHandler forgets the node ID bound to an inbound connection once the connection ends
+type connHandler struct {
+	conns *connIDs
+}
+
+// TagRPC implements stats.Handler
+func (h *connHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context { return ctx }
+
+// HandleRPC implements stats.Handler
+func (h *connHandler) HandleRPC(context.Context, stats.RPCStats) {}
+
+// TagConn implements stats.Handler
+func (h *connHandler) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	return context.WithValue(ctx, connTagKey{}, info.RemoteAddr.String())
+}
+
+// HandleConn implements stats.Handler
+func (h *connHandler) HandleConn(ctx context.Context, s stats.ConnStats) {
+	if _, ok := s.(*stats.ConnEnd); !ok {
+		return
+	}
+	if conn, ok := ctx.Value(connTagKey{}).(string); ok {
+		h.conns.remove(conn)
+	}
+}
@@ -7,17 +7,22 @@
 package network
 
 import (
+	"net"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/facebookgo/clock"
 	"github.com/golang/mock/gomock"
 	"github.com/golang/protobuf/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/net/context"
 
+	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/network/node"
 	pb "github.com/iotexproject/iotex-core/network/proto"
+	"github.com/iotexproject/iotex-core/pkg/hash"
 	"github.com/iotexproject/iotex-core/proto"
 	"github.com/iotexproject/iotex-core/test/mock/mock_dispatcher"
 )
@@ -25,7 +30,7 @@ import (
 func TestRpcPingPong(t *testing.T) {
 	ctx := context.Background()
 	config := LoadTestConfig("", true)
-	o := &IotxOverlay{Config: config}
+	o := &IotxOverlay{Config: config, Reputation: NewReputation(config, clock.New()), Identity: newTestIdentity(t)}
 	o.PM = NewPeerManager(o, 1, 1)
 	s := NewRPCServer(o)
 	o.RPC = s
@@ -42,23 +47,50 @@ func TestRpcPingPong(t *testing.T) {
 		assert.NoError(t, err)
 	}()
 
-	pong, err := p.Ping(&pb.Ping{Nonce: uint64(4689), Addr: "127.0.0.1:10001"})
+	identity := newTestIdentity(t)
+	pong, err := p.Ping(newPing(identity, o.ChainID, uint64(4689), "127.0.0.1:10001"))
 	assert.Nil(t, err)
 	assert.NotNil(t, pong)
 	assert.Equal(t, uint64(4689), pong.AckNonce)
-	value, ok := o.PM.Peers.Load("127.0.0.1:10001")
+	id, err := o.verifyPong(uint64(4689), pong)
+	assert.NoError(t, err)
+	assert.Equal(t, o.Identity.ID, id)
+	value, ok := o.PM.Peers.Load(identity.ID)
 	assert.True(t, ok)
 	assert.NotNil(t, value)
 	assert.True(t, "127.0.0.1:10001" == value.(*Peer).String())
+
+	// Pings without a valid handshake are rejected
+	ping := newPing(identity, o.ChainID, uint64(4690), "127.0.0.1:10002")
+	ping.Nonce++
+	_, err = p.Ping(ping)
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), ErrBadSignature.Error()))
+	_, err = p.Ping(newPing(identity, o.ChainID+1, uint64(4690), "127.0.0.1:10002"))
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), ErrChainIDMismatch.Error()))
+	assert.Nil(t, o.PM.peerByAddr("127.0.0.1:10002"))
+
+	// Only the challenge issued over the connection binds it
+	ping = newPing(identity, o.ChainID, uint64(4691), "127.0.0.1:10001")
+	ping.Challenge = pong.Challenge + 1
+	ping.ChallengeSignature = crypto.EC283.Sign(identity.PriKey, challengeHash(ping.Challenge, o.Identity.ID, o.ChainID))
+	_, err = p.Ping(ping)
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), ErrInvalidMsg.Error()))
+	ping.Challenge = pong.Challenge
+	ping.ChallengeSignature = crypto.EC283.Sign(identity.PriKey, challengeHash(ping.Challenge, o.Identity.ID, o.ChainID))
+	_, err = p.Ping(ping)
+	assert.NoError(t, err)
 }
 
 func TestGetPeers(t *testing.T) {
 	ctx := context.Background()
 	config := LoadTestConfig("", true)
-	o := &IotxOverlay{Config: config}
+	o := &IotxOverlay{Config: config, Reputation: NewReputation(config, clock.New())}
 	o.PM = NewPeerManager(o, 0, 0)
-	o.PM.Peers.Store("127.0.0.1:10001", NewTCPPeer("127.0.0.1:10001"))
-	o.PM.Peers.Store("127.0.0.1:10002", NewTCPPeer("127.0.0.1:10002"))
+	o.PM.Peers.Store(hash.Hash32B{1}, NewTCPPeer("127.0.0.1:10001"))
+	o.PM.Peers.Store(hash.Hash32B{2}, NewTCPPeer("127.0.0.1:10002"))
 	s := NewRPCServer(o)
 	o.RPC = s
 	err := s.Start(ctx)
@@ -101,7 +133,7 @@ func TestGetPeers(t *testing.T) {
 func TestBroadcast(t *testing.T) {
 	ctx := context.Background()
 	config := LoadTestConfig("", true)
-	o := &IotxOverlay{Config: config}
+	o := &IotxOverlay{Config: config, Reputation: NewReputation(config, clock.New())}
 	o.PM = NewPeerManager(o, 0, 0)
 	o.Gossip = NewGossip(o)
 	s := NewRPCServer(o)
@@ -136,7 +168,7 @@ func TestRPCTell(t *testing.T) {
 	dp.EXPECT().HandleTell(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
 
 	config := LoadTestConfig("", true)
-	o := &IotxOverlay{Dispatcher: dp, Config: config}
+	o := &IotxOverlay{Dispatcher: dp, Config: config, Reputation: NewReputation(config, clock.New())}
 	s := NewRPCServer(o)
 	o.RPC = s
 	err := s.Start(ctx)
@@ -163,6 +195,57 @@ func TestRPCTell(t *testing.T) {
 	assert.Equal(t, iproto.MagicBroadcastMsgHeader, res.Header)
 }
 
+func TestRPCForgedSenderAddr(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	mctrl := gomock.NewController(t)
+	defer mctrl.Finish()
+	dp := mock_dispatcher.NewMockDispatcher(mctrl)
+
+	config := LoadTestConfig("", false)
+	config.MaxMsgSize = 64
+	o := &IotxOverlay{Dispatcher: dp, Config: config, Reputation: NewReputation(config, clock.New())}
+	o.PM = NewPeerManager(o, 0, 0)
+	s := NewRPCServer(o)
+	o.RPC = s
+	require.NoError(s.Start(ctx))
+	p := NewPeer(s.Network(), s.String())
+	require.NoError(p.Connect(config))
+	defer func() {
+		require.NoError(p.Close())
+		require.NoError(s.Stop(ctx))
+	}()
+
+	// The message is dispatched with the claimed address for the replies, but reported against the connection
+	victim := "10.0.0.1:4689"
+	dp.EXPECT().HandleTell(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(_ uint32, sender net.Addr, _ proto.Message, _ chan bool) {
+			require.Equal(victim, sender.String())
+			o.ReportPeer(sender, iproto.MsgActionType, ErrInvalidMsg)
+		}).Times(1)
+	b, err := proto.Marshal(&iproto.ActionPb{})
+	require.NoError(err)
+	_, err = p.Tell(&pb.TellReq{Header: iproto.MagicBroadcastMsgHeader,
+		Addr:    victim,
+		MsgType: iproto.MsgActionType,
+		MsgBody: b})
+	require.NoError(err)
+	require.Equal(0, o.PeerScore(node.NewTCPNode(victim)))
+	require.Equal(-penalties[InvalidAction], o.Reputation.Score("127.0.0.1"))
+
+	// The sender without an address can't evade the penalty for the oversized message
+	_, err = p.Tell(&pb.TellReq{Header: iproto.MagicBroadcastMsgHeader,
+		MsgType: iproto.MsgActionType,
+		MsgBody: make([]byte, 65)})
+	require.Error(err)
+	require.Equal(-penalties[InvalidAction]-penalties[OversizedMsg], o.Reputation.Score("127.0.0.1"))
+	require.Equal(0, o.PeerScore(node.NewTCPNode(victim)))
+
+	// The address which is not a peer is only claimed, so it isn't penalized
+	o.ReportPeer(node.NewTCPNode(victim), iproto.MsgActionType, ErrInvalidMsg)
+	require.Equal(0, o.PeerScore(node.NewTCPNode(victim)))
+}
+
 func TestRateLimit(t *testing.T) {
 	ctx := context.Background()
 	mctrl := gomock.NewController(t)
@@ -173,7 +256,7 @@ func TestRateLimit(t *testing.T) {
 	config.RateLimitEnabled = true
 	config.RateLimitPerSec = 5
 	config.RateLimitWindowSize = time.Second
-	o := &IotxOverlay{Dispatcher: dp, Config: config}
+	o := &IotxOverlay{Dispatcher: dp, Config: config, Reputation: NewReputation(config, clock.New())}
 	s := NewRPCServer(o)
 	o.RPC = s
 	err := s.Start(ctx)
@@ -217,7 +300,7 @@ func TestSecureRpcPingPong(t *testing.T) {
 	config.CACrtPath = "../test/assets/ssl/iotex.io.crt"
 	config.PeerCrtPath = "../test/assets/ssl/127.0.0.1.crt"
 	config.PeerKeyPath = "../test/assets/ssl/127.0.0.1.key"
-	o := &IotxOverlay{Config: config}
+	o := &IotxOverlay{Config: config, Reputation: NewReputation(config, clock.New()), Identity: newTestIdentity(t)}
 	o.PM = NewPeerManager(o, 1, 1)
 	s := NewRPCServer(o)
 	o.RPC = s
@@ -234,11 +317,12 @@ func TestSecureRpcPingPong(t *testing.T) {
 		assert.NoError(t, err)
 	}()
 
-	pong, err := p.Ping(&pb.Ping{Nonce: uint64(4689), Addr: "127.0.0.1:10001"})
+	identity := newTestIdentity(t)
+	pong, err := p.Ping(newPing(identity, o.ChainID, uint64(4689), "127.0.0.1:10001"))
 	assert.Nil(t, err)
 	assert.NotNil(t, pong)
 	assert.Equal(t, uint64(4689), pong.AckNonce)
-	value, ok := o.PM.Peers.Load("127.0.0.1:10001")
+	value, ok := o.PM.Peers.Load(identity.ID)
 	assert.True(t, ok)
 	assert.NotNil(t, value)
 	assert.True(t, "127.0.0.1:10001" == value.(*Peer).String())
@@ -253,7 +337,7 @@ func TestKeepaliveParams(t *testing.T) {
 	config.KLServerParams.Time = 50 * time.Second
 	config.KLClientParams.Timeout = 20 * time.Millisecond
 	config.KLPolicy.MinTime = 20 * time.Millisecond
-	o := &IotxOverlay{Config: config}
+	o := &IotxOverlay{Config: config, Reputation: NewReputation(config, clock.New()), Identity: newTestIdentity(t)}
 	o.PM = NewPeerManager(o, 1, 1)
 	s := NewRPCServer(o)
 	o.RPC = s
@@ -270,13 +354,14 @@ func TestKeepaliveParams(t *testing.T) {
 		assert.NoError(t, err)
 	}()
 
+	identity := newTestIdentity(t)
 	for i := 0; i < 5; i++ {
 		time.Sleep(100 * time.Millisecond)
-		pong, err := p.Ping(&pb.Ping{Nonce: uint64(4689), Addr: "127.0.0.1:10001"})
+		pong, err := p.Ping(newPing(identity, o.ChainID, uint64(4689), "127.0.0.1:10001"))
 		assert.Nil(t, err)
 		assert.NotNil(t, pong)
 		assert.Equal(t, uint64(4689), pong.AckNonce)
-		value, ok := o.PM.Peers.Load("127.0.0.1:10001")
+		value, ok := o.PM.Peers.Load(identity.ID)
 		assert.True(t, ok)
 		assert.NotNil(t, value)
 		assert.True(t, "127.0.0.1:10001" == value.(*Peer).String())
@@ -0,0 +1,357 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package simnet
+
+import (
+	"context"
+	"encoding/hex"
+	"math/rand"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/facebookgo/clock"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/dispatcher"
+	"github.com/iotexproject/iotex-core/logger"
+	"github.com/iotexproject/iotex-core/network"
+	"github.com/iotexproject/iotex-core/network/node"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/proto"
+)
+
+// Link describes the quality of the connection from a node to another
+type Link struct {
+	// Latency is the base delay of delivering a message
+	Latency time.Duration
+	// Jitter is the max random delay added to the latency
+	Jitter time.Duration
+	// DropRate is the probability of losing a message
+	DropRate float64
+	// Bandwidth is the max number of bytes sent over the link per second. A zero bandwidth is unlimited.
+	Bandwidth int
+}
+
+// delivery is a message in flight
+type delivery struct {
+	at      time.Time
+	seq     uint64
+	from    *Overlay
+	to      *Overlay
+	tell    bool
+	chainID uint32
+	msgType uint32
+	msgBody []byte
+	ttl     int32
+}
+
+// Switchboard connects the simulated overlays in memory. The messages between them are delivered on the clock of the
+// switchboard, subject to the links between the nodes and the partitions of the network. Driven by a mock clock with a
+// fixed seed, a simulation is reproducible.
+type Switchboard struct {
+	// DefaultLink applies to the pairs of nodes without their own links
+	DefaultLink Link
+	// TTL is the number of hops a broadcast message travels
+	TTL int32
+
+	clk       clock.Clock
+	mu        sync.Mutex
+	rand      *rand.Rand
+	overlays  map[string]*Overlay
+	addrs     []string
+	links     map[[2]string]Link
+	busyUntil map[[2]string]time.Time
+	groups    map[string]int
+	queue     []*delivery
+	seq       uint64
+}
+
+// NewSwitchboard creates a switchboard on the given clock, whose random drops and jitters are drawn from the seed
+func NewSwitchboard(clk clock.Clock, seed int64) *Switchboard {
+	return &Switchboard{
+		TTL:       3,
+		clk:       clk,
+		rand:      rand.New(rand.NewSource(seed)),
+		overlays:  make(map[string]*Overlay),
+		links:     make(map[[2]string]Link),
+		busyUntil: make(map[[2]string]time.Time),
+	}
+}
+
+// NewOverlay creates a simulated overlay of the node at the given address, which is connected to all the other nodes
+// on the switchboard
+func (sb *Switchboard) NewOverlay(addr string) *Overlay {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	o := &Overlay{sb: sb, addr: addr, seen: make(map[string]bool)}
+	if _, ok := sb.overlays[addr]; !ok {
+		sb.addrs = append(sb.addrs, addr)
+	}
+	sb.overlays[addr] = o
+	return o
+}
+
+// SetLink sets the link from a node to another, which overrides the default link
+func (sb *Switchboard) SetLink(from string, to string, link Link) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	sb.links[[2]string{from, to}] = link
+}
+
+// Partition splits the network into the given groups of nodes, which can't reach each other. The nodes not in any group
+// make up another group.
+func (sb *Switchboard) Partition(groups ...[]string) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	sb.groups = make(map[string]int)
+	for i, group := range groups {
+		for _, addr := range group {
+			sb.groups[addr] = i + 1
+		}
+	}
+}
+
+// Heal removes the partitions of the network
+func (sb *Switchboard) Heal() {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	sb.groups = nil
+}
+
+// At runs the function once the given duration elapses on the clock of the switchboard, e.g., to partition the network
+// at some point of a simulation
+func (sb *Switchboard) At(d time.Duration, f func()) {
+	sb.clk.AfterFunc(d, f)
+}
+
+// reachable returns true if the nodes are in the same partition. It must be called with the lock held.
+func (sb *Switchboard) reachable(from string, to string) bool {
+	return sb.groups[from] == sb.groups[to]
+}
+
+// send puts a message in flight from a node to another
+func (sb *Switchboard) send(from *Overlay, to *Overlay, tell bool, chainID uint32, msgType uint32, msgBody []byte, ttl int32) {
+	sb.mu.Lock()
+	key := [2]string{from.addr, to.addr}
+	link, ok := sb.links[key]
+	if !ok {
+		link = sb.DefaultLink
+	}
+	if link.DropRate > 0 && sb.rand.Float64() < link.DropRate {
+		sb.mu.Unlock()
+		return
+	}
+	now := sb.clk.Now()
+	sent := now
+	if link.Bandwidth > 0 {
+		if busyUntil := sb.busyUntil[key]; busyUntil.After(sent) {
+			sent = busyUntil
+		}
+		sent = sent.Add(time.Duration(len(msgBody)) * time.Second / time.Duration(link.Bandwidth))
+		sb.busyUntil[key] = sent
+	}
+	at := sent.Add(link.Latency)
+	if link.Jitter > 0 {
+		at = at.Add(time.Duration(sb.rand.Int63n(int64(link.Jitter) + 1)))
+	}
+	sb.seq++
+	sb.queue = append(sb.queue, &delivery{
+		at:      at,
+		seq:     sb.seq,
+		from:    from,
+		to:      to,
+		tell:    tell,
+		chainID: chainID,
+		msgType: msgType,
+		msgBody: msgBody,
+		ttl:     ttl,
+	})
+	sb.mu.Unlock()
+	sb.clk.AfterFunc(at.Sub(now), sb.flush)
+}
+
+// flush delivers the messages due by now in the order of their delivery time and sending order, so that the order
+// doesn't depend on the order the clock fires the timers
+func (sb *Switchboard) flush() {
+	for {
+		sb.mu.Lock()
+		now := sb.clk.Now()
+		sort.SliceStable(sb.queue, func(i, j int) bool {
+			if sb.queue[i].at.Equal(sb.queue[j].at) {
+				return sb.queue[i].seq < sb.queue[j].seq
+			}
+			return sb.queue[i].at.Before(sb.queue[j].at)
+		})
+		if len(sb.queue) == 0 || sb.queue[0].at.After(now) {
+			sb.mu.Unlock()
+			return
+		}
+		d := sb.queue[0]
+		sb.queue = sb.queue[1:]
+		sb.mu.Unlock()
+		sb.deliver(d)
+	}
+}
+
+// deliver hands the message over to the dispatcher of the receiver, unless the receiver is stopped or the network is
+// partitioned in the meantime
+func (sb *Switchboard) deliver(d *delivery) {
+	sb.mu.Lock()
+	if !d.to.started || !sb.reachable(d.from.addr, d.to.addr) {
+		sb.mu.Unlock()
+		return
+	}
+	if !d.tell {
+		checksum := hex.EncodeToString(hash.Hash256b(d.msgBody))
+		if d.to.seen[checksum] {
+			sb.mu.Unlock()
+			return
+		}
+		d.to.seen[checksum] = true
+	}
+	dp := d.to.dispatcher
+	sb.mu.Unlock()
+
+	msg, err := iproto.TypifyProtoMsg(d.msgType, d.msgBody)
+	if err != nil {
+		logger.Error().Err(err).Uint32("msg-type", d.msgType).Msg("failed to typify a simulated message")
+		return
+	}
+	if d.tell {
+		if dp != nil {
+			dp.HandleTell(d.chainID, d.from.Self(), msg, nil)
+		}
+		return
+	}
+	if dp != nil {
+		dp.HandleBroadcast(d.chainID, d.from.Self(), msg, nil)
+	}
+	if d.ttl-1 > 0 {
+		d.to.relay(d.chainID, d.msgType, d.msgBody, d.ttl-1)
+	}
+}
+
+// Overlay is a simulated P2P network node, which exchanges the messages through a switchboard in memory
+type Overlay struct {
+	sb         *Switchboard
+	addr       string
+	dispatcher dispatcher.Dispatcher
+	started    bool
+	seen       map[string]bool
+}
+
+var _ network.Overlay = (*Overlay)(nil)
+
+// AttachDispatcher attaches to a Dispatcher instance
+func (o *Overlay) AttachDispatcher(dispatcher dispatcher.Dispatcher) {
+	o.sb.mu.Lock()
+	defer o.sb.mu.Unlock()
+	o.dispatcher = dispatcher
+}
+
+// Start connects the node to the switchboard
+func (o *Overlay) Start(_ context.Context) error {
+	o.sb.mu.Lock()
+	defer o.sb.mu.Unlock()
+	o.started = true
+	return nil
+}
+
+// Stop disconnects the node from the switchboard, which drops the messages to the node until it starts again
+func (o *Overlay) Stop(_ context.Context) error {
+	o.sb.mu.Lock()
+	defer o.sb.mu.Unlock()
+	o.started = false
+	return nil
+}
+
+// Broadcast sends the message to all reachable nodes, which relay it further until the TTL runs out
+func (o *Overlay) Broadcast(chainID uint32, msg proto.Message) error {
+	msgType, msgBody, err := marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal msg when broadcast")
+	}
+	o.sb.mu.Lock()
+	o.seen[hex.EncodeToString(hash.Hash256b(msgBody))] = true
+	ttl := o.sb.TTL
+	o.sb.mu.Unlock()
+	o.relay(chainID, msgType, msgBody, ttl)
+	return nil
+}
+
+// Tell sends the message to the given node
+func (o *Overlay) Tell(chainID uint32, addr net.Addr, msg proto.Message) error {
+	o.sb.mu.Lock()
+	to, ok := o.sb.overlays[addr.String()]
+	o.sb.mu.Unlock()
+	if !ok {
+		return network.ErrPeerNotFound
+	}
+	msgType, msgBody, err := marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal msg when tell msg")
+	}
+	o.sb.send(o, to, true, chainID, msgType, msgBody, 0)
+	return nil
+}
+
+// Self returns the address of the node
+func (o *Overlay) Self() net.Addr {
+	return node.NewTCPNode(o.addr)
+}
+
+// GetPeers returns the running nodes in the same partition
+func (o *Overlay) GetPeers() []net.Addr {
+	var peers []net.Addr
+	for _, p := range o.peers() {
+		peers = append(peers, p.Self())
+	}
+	return peers
+}
+
+// PeerScore returns 0, as the simulated nodes don't keep the reputation of the peers
+func (o *Overlay) PeerScore(net.Addr) int {
+	return 0
+}
+
+// ReportPeer does nothing, as the simulated nodes don't keep the reputation of the peers
+func (o *Overlay) ReportPeer(net.Addr, uint32, error) {}
+
+// peers returns the other running nodes in the same partition, in the order they join the switchboard
+func (o *Overlay) peers() []*Overlay {
+	o.sb.mu.Lock()
+	defer o.sb.mu.Unlock()
+	var peers []*Overlay
+	for _, addr := range o.sb.addrs {
+		p := o.sb.overlays[addr]
+		if p != o && p.started && o.sb.reachable(o.addr, addr) {
+			peers = append(peers, p)
+		}
+	}
+	return peers
+}
+
+func (o *Overlay) relay(chainID uint32, msgType uint32, msgBody []byte, ttl int32) {
+	for _, p := range o.peers() {
+		o.sb.send(o, p, false, chainID, msgType, msgBody, ttl)
+	}
+}
+
+func marshal(msg proto.Message) (uint32, []byte, error) {
+	msgType, err := iproto.GetTypeFromProtoMsg(msg)
+	if err != nil {
+		return 0, nil, err
+	}
+	msgBody, err := proto.Marshal(msg)
+	if err != nil {
+		return 0, nil, err
+	}
+	return msgType, msgBody, nil
+}
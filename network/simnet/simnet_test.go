@@ -0,0 +1,169 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package simnet
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/clock"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/dispatcher"
+	"github.com/iotexproject/iotex-core/network"
+	"github.com/iotexproject/iotex-core/network/node"
+	"github.com/iotexproject/iotex-core/proto"
+)
+
+func newTestNetwork(t *testing.T, clk clock.Clock, addrs ...string) (*Switchboard, []*Overlay, []*recordingDispatcher) {
+	sb := NewSwitchboard(clk, 0)
+	var overlays []*Overlay
+	var dispatchers []*recordingDispatcher
+	for _, addr := range addrs {
+		o := sb.NewOverlay(addr)
+		dp := &recordingDispatcher{}
+		o.AttachDispatcher(dp)
+		require.NoError(t, o.Start(context.Background()))
+		overlays = append(overlays, o)
+		dispatchers = append(dispatchers, dp)
+	}
+	return sb, overlays, dispatchers
+}
+
+func TestLatencyAndBandwidth(t *testing.T) {
+	require := require.New(t)
+
+	clk := clock.NewMock()
+	sb, overlays, dps := newTestNetwork(t, clk, "a", "b", "c")
+	sb.DefaultLink = Link{Latency: 100 * time.Millisecond}
+
+	require.NoError(overlays[0].Broadcast(1, &iproto.ActionPb{Nonce: 1}))
+	clk.Add(99 * time.Millisecond)
+	require.Equal(0, dps[1].len())
+	clk.Add(time.Millisecond)
+	require.Equal([]uint64{1}, dps[1].nonces())
+	require.Equal([]uint64{1}, dps[2].nonces())
+	// The relays are deduplicated
+	clk.Add(time.Second)
+	require.Equal(1, dps[1].len())
+	require.Equal(0, dps[0].len())
+
+	// Messages queue up on the link with a bandwidth cap
+	msg := &iproto.ActionPb{Signature: make([]byte, 1000)}
+	size := proto.Size(msg)
+	sb.SetLink("a", "b", Link{Bandwidth: size})
+	require.NoError(overlays[0].Tell(1, node.NewTCPNode("b"), msg))
+	require.NoError(overlays[0].Tell(1, node.NewTCPNode("b"), msg))
+	clk.Add(time.Second)
+	require.Equal(2, dps[1].len())
+	clk.Add(time.Second)
+	require.Equal(3, dps[1].len())
+	// Unknown nodes can't be told
+	require.Equal(network.ErrPeerNotFound, overlays[0].Tell(1, node.NewTCPNode("d"), msg))
+}
+
+func TestDropAndPartition(t *testing.T) {
+	require := require.New(t)
+
+	clk := clock.NewMock()
+	sb, overlays, dps := newTestNetwork(t, clk, "a", "b", "c")
+
+	// Every message on a lossy link is dropped, but the broadcast still reaches the node through a relay
+	sb.SetLink("a", "b", Link{DropRate: 1})
+	require.NoError(overlays[0].Broadcast(1, &iproto.ActionPb{Nonce: 1}))
+	clk.Add(time.Second)
+	require.Equal([]uint64{1}, dps[1].nonces())
+	require.NoError(overlays[0].Tell(1, node.NewTCPNode("b"), &iproto.ActionPb{Nonce: 2}))
+	clk.Add(time.Second)
+	require.Equal([]uint64{1}, dps[1].nonces())
+
+	// Nodes in different partitions can't reach each other until the network heals
+	sb.At(time.Second, func() { sb.Partition([]string{"a"}) })
+	sb.At(2*time.Second, sb.Heal)
+	clk.Add(time.Second)
+	require.Equal(0, len(overlays[0].GetPeers()))
+	require.Equal(1, len(overlays[1].GetPeers()))
+	require.NoError(overlays[0].Broadcast(1, &iproto.ActionPb{Nonce: 3}))
+	clk.Add(500 * time.Millisecond)
+	require.Equal([]uint64{1}, dps[2].nonces())
+	clk.Add(500 * time.Millisecond)
+	require.Equal(2, len(overlays[0].GetPeers()))
+	require.NoError(overlays[0].Broadcast(1, &iproto.ActionPb{Nonce: 4}))
+	clk.Add(time.Millisecond)
+	require.Equal([]uint64{1, 4}, dps[2].nonces())
+
+	// Stopped nodes don't receive messages
+	require.NoError(overlays[2].Stop(context.Background()))
+	require.NoError(overlays[0].Tell(1, node.NewTCPNode("c"), &iproto.ActionPb{Nonce: 5}))
+	clk.Add(time.Millisecond)
+	require.Equal([]uint64{1, 4}, dps[2].nonces())
+}
+
+func TestReproducibility(t *testing.T) {
+	require := require.New(t)
+
+	run := func() []uint64 {
+		clk := clock.NewMock()
+		sb, overlays, dps := newTestNetwork(t, clk, "a", "b")
+		sb.DefaultLink = Link{Latency: 10 * time.Millisecond, Jitter: 100 * time.Millisecond, DropRate: 0.2}
+		for i := uint64(0); i < 50; i++ {
+			require.NoError(overlays[0].Tell(1, node.NewTCPNode("b"), &iproto.ActionPb{Nonce: i}))
+		}
+		clk.Add(time.Second)
+		return dps[1].nonces()
+	}
+	received := run()
+	require.True(len(received) > 0 && len(received) < 50)
+	require.Equal(received, run())
+}
+
+type recordingDispatcher struct {
+	mu   sync.Mutex
+	msgs []proto.Message
+}
+
+func (d *recordingDispatcher) Start(context.Context) error { return nil }
+
+func (d *recordingDispatcher) Stop(context.Context) error { return nil }
+
+func (d *recordingDispatcher) AddSubscriber(uint32, dispatcher.Subscriber) {}
+
+func (d *recordingDispatcher) RemoveSubscriber(uint32) {}
+
+func (d *recordingDispatcher) AttachPeerReporter(dispatcher.PeerReporter) {}
+
+func (d *recordingDispatcher) HandleBroadcast(_ uint32, _ net.Addr, msg proto.Message, _ chan bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.msgs = append(d.msgs, msg)
+}
+
+func (d *recordingDispatcher) HandleTell(_ uint32, _ net.Addr, msg proto.Message, _ chan bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.msgs = append(d.msgs, msg)
+}
+
+func (d *recordingDispatcher) len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.msgs)
+}
+
+func (d *recordingDispatcher) nonces() []uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var nonces []uint64
+	for _, msg := range d.msgs {
+		nonces = append(nonces, msg.(*iproto.ActionPb).Nonce)
+	}
+	return nonces
+}
@@ -38,7 +38,7 @@ func (x EndorsePb_EndorsementTopic) String() string {
 	return proto.EnumName(EndorsePb_EndorsementTopic_name, int32(x))
 }
 func (EndorsePb_EndorsementTopic) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_blockchain_f3f6779518493ae1, []int{17, 0}
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{23, 0}
 }
 
 type TransferPb struct {
@@ -58,7 +58,7 @@ func (m *TransferPb) Reset()         { *m = TransferPb{} }
 func (m *TransferPb) String() string { return proto.CompactTextString(m) }
 func (*TransferPb) ProtoMessage()    {}
 func (*TransferPb) Descriptor() ([]byte, []int) {
-	return fileDescriptor_blockchain_f3f6779518493ae1, []int{0}
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{0}
 }
 func (m *TransferPb) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TransferPb.Unmarshal(m, b)
@@ -134,7 +134,7 @@ func (m *VotePb) Reset()         { *m = VotePb{} }
 func (m *VotePb) String() string { return proto.CompactTextString(m) }
 func (*VotePb) ProtoMessage()    {}
 func (*VotePb) Descriptor() ([]byte, []int) {
-	return fileDescriptor_blockchain_f3f6779518493ae1, []int{1}
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{1}
 }
 func (m *VotePb) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_VotePb.Unmarshal(m, b)
@@ -197,7 +197,7 @@ func (m *ExecutionPb) Reset()         { *m = ExecutionPb{} }
 func (m *ExecutionPb) String() string { return proto.CompactTextString(m) }
 func (*ExecutionPb) ProtoMessage()    {}
 func (*ExecutionPb) Descriptor() ([]byte, []int) {
-	return fileDescriptor_blockchain_f3f6779518493ae1, []int{2}
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{2}
 }
 func (m *ExecutionPb) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ExecutionPb.Unmarshal(m, b)
@@ -265,7 +265,7 @@ func (m *SecretProposalPb) Reset()         { *m = SecretProposalPb{} }
 func (m *SecretProposalPb) String() string { return proto.CompactTextString(m) }
 func (*SecretProposalPb) ProtoMessage()    {}
 func (*SecretProposalPb) Descriptor() ([]byte, []int) {
-	return fileDescriptor_blockchain_f3f6779518493ae1, []int{3}
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{3}
 }
 func (m *SecretProposalPb) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SecretProposalPb.Unmarshal(m, b)
@@ -318,7 +318,7 @@ func (m *SecretWitnessPb) Reset()         { *m = SecretWitnessPb{} }
 func (m *SecretWitnessPb) String() string { return proto.CompactTextString(m) }
 func (*SecretWitnessPb) ProtoMessage()    {}
 func (*SecretWitnessPb) Descriptor() ([]byte, []int) {
-	return fileDescriptor_blockchain_f3f6779518493ae1, []int{4}
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{4}
 }
 func (m *SecretWitnessPb) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SecretWitnessPb.Unmarshal(m, b)
@@ -369,7 +369,7 @@ func (m *LogPb) Reset()         { *m = LogPb{} }
 func (m *LogPb) String() string { return proto.CompactTextString(m) }
 func (*LogPb) ProtoMessage()    {}
 func (*LogPb) Descriptor() ([]byte, []int) {
-	return fileDescriptor_blockchain_f3f6779518493ae1, []int{5}
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{5}
 }
 func (m *LogPb) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_LogPb.Unmarshal(m, b)
@@ -454,7 +454,7 @@ func (m *ReceiptPb) Reset()         { *m = ReceiptPb{} }
 func (m *ReceiptPb) String() string { return proto.CompactTextString(m) }
 func (*ReceiptPb) ProtoMessage()    {}
 func (*ReceiptPb) Descriptor() ([]byte, []int) {
-	return fileDescriptor_blockchain_f3f6779518493ae1, []int{6}
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{6}
 }
 func (m *ReceiptPb) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ReceiptPb.Unmarshal(m, b)
@@ -534,7 +534,7 @@ func (m *StartSubChainPb) Reset()         { *m = StartSubChainPb{} }
 func (m *StartSubChainPb) String() string { return proto.CompactTextString(m) }
 func (*StartSubChainPb) ProtoMessage()    {}
 func (*StartSubChainPb) Descriptor() ([]byte, []int) {
-	return fileDescriptor_blockchain_f3f6779518493ae1, []int{7}
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{7}
 }
 func (m *StartSubChainPb) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StartSubChainPb.Unmarshal(m, b)
@@ -618,7 +618,7 @@ func (m *StopSubChainPb) Reset()         { *m = StopSubChainPb{} }
 func (m *StopSubChainPb) String() string { return proto.CompactTextString(m) }
 func (*StopSubChainPb) ProtoMessage()    {}
 func (*StopSubChainPb) Descriptor() ([]byte, []int) {
-	return fileDescriptor_blockchain_f3f6779518493ae1, []int{8}
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{8}
 }
 func (m *StopSubChainPb) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StopSubChainPb.Unmarshal(m, b)
@@ -691,7 +691,7 @@ func (m *PutBlockPb) Reset()         { *m = PutBlockPb{} }
 func (m *PutBlockPb) String() string { return proto.CompactTextString(m) }
 func (*PutBlockPb) ProtoMessage()    {}
 func (*PutBlockPb) Descriptor() ([]byte, []int) {
-	return fileDescriptor_blockchain_f3f6779518493ae1, []int{9}
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{9}
 }
 func (m *PutBlockPb) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PutBlockPb.Unmarshal(m, b)
@@ -767,6 +767,156 @@ func (m *PutBlockPb) GetEndorsorSignatures() [][]byte {
 	return nil
 }
 
+// evidence of two conflicting commit endorses signed by the offender at the same height
+type DoubleSignEvidencePb struct {
+	Reporter           string     `protobuf:"bytes,1,opt,name=reporter,proto3" json:"reporter,omitempty"`
+	ReporterPubKey     []byte     `protobuf:"bytes,2,opt,name=reporterPubKey,proto3" json:"reporterPubKey,omitempty"`
+	Offender           string     `protobuf:"bytes,3,opt,name=offender,proto3" json:"offender,omitempty"`
+	Endorse            *EndorsePb `protobuf:"bytes,4,opt,name=endorse,proto3" json:"endorse,omitempty"`
+	ConflictingEndorse *EndorsePb `protobuf:"bytes,5,opt,name=conflictingEndorse,proto3" json:"conflictingEndorse,omitempty"`
+	// the conflicting proposals, which the evidence carries instead of the endorses
+	Proposal             *SignedProposalPb `protobuf:"bytes,6,opt,name=proposal,proto3" json:"proposal,omitempty"`
+	ConflictingProposal  *SignedProposalPb `protobuf:"bytes,7,opt,name=conflictingProposal,proto3" json:"conflictingProposal,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *DoubleSignEvidencePb) Reset()         { *m = DoubleSignEvidencePb{} }
+func (m *DoubleSignEvidencePb) String() string { return proto.CompactTextString(m) }
+func (*DoubleSignEvidencePb) ProtoMessage()    {}
+func (*DoubleSignEvidencePb) Descriptor() ([]byte, []int) {
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{10}
+}
+func (m *DoubleSignEvidencePb) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DoubleSignEvidencePb.Unmarshal(m, b)
+}
+func (m *DoubleSignEvidencePb) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DoubleSignEvidencePb.Marshal(b, m, deterministic)
+}
+func (dst *DoubleSignEvidencePb) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DoubleSignEvidencePb.Merge(dst, src)
+}
+func (m *DoubleSignEvidencePb) XXX_Size() int {
+	return xxx_messageInfo_DoubleSignEvidencePb.Size(m)
+}
+func (m *DoubleSignEvidencePb) XXX_DiscardUnknown() {
+	xxx_messageInfo_DoubleSignEvidencePb.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DoubleSignEvidencePb proto.InternalMessageInfo
+
+func (m *DoubleSignEvidencePb) GetReporter() string {
+	if m != nil {
+		return m.Reporter
+	}
+	return ""
+}
+
+func (m *DoubleSignEvidencePb) GetReporterPubKey() []byte {
+	if m != nil {
+		return m.ReporterPubKey
+	}
+	return nil
+}
+
+func (m *DoubleSignEvidencePb) GetOffender() string {
+	if m != nil {
+		return m.Offender
+	}
+	return ""
+}
+
+func (m *DoubleSignEvidencePb) GetEndorse() *EndorsePb {
+	if m != nil {
+		return m.Endorse
+	}
+	return nil
+}
+
+func (m *DoubleSignEvidencePb) GetConflictingEndorse() *EndorsePb {
+	if m != nil {
+		return m.ConflictingEndorse
+	}
+	return nil
+}
+
+func (m *DoubleSignEvidencePb) GetProposal() *SignedProposalPb {
+	if m != nil {
+		return m.Proposal
+	}
+	return nil
+}
+
+func (m *DoubleSignEvidencePb) GetConflictingProposal() *SignedProposalPb {
+	if m != nil {
+		return m.ConflictingProposal
+	}
+	return nil
+}
+
+type SignerVotePb struct {
+	Voter                string   `protobuf:"bytes,1,opt,name=voter,proto3" json:"voter,omitempty"`
+	VoterPubKey          []byte   `protobuf:"bytes,2,opt,name=voterPubKey,proto3" json:"voterPubKey,omitempty"`
+	Candidate            string   `protobuf:"bytes,3,opt,name=candidate,proto3" json:"candidate,omitempty"`
+	Add                  bool     `protobuf:"varint,4,opt,name=add,proto3" json:"add,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SignerVotePb) Reset()         { *m = SignerVotePb{} }
+func (m *SignerVotePb) String() string { return proto.CompactTextString(m) }
+func (*SignerVotePb) ProtoMessage()    {}
+func (*SignerVotePb) Descriptor() ([]byte, []int) {
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{11}
+}
+func (m *SignerVotePb) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignerVotePb.Unmarshal(m, b)
+}
+func (m *SignerVotePb) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignerVotePb.Marshal(b, m, deterministic)
+}
+func (dst *SignerVotePb) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignerVotePb.Merge(dst, src)
+}
+func (m *SignerVotePb) XXX_Size() int {
+	return xxx_messageInfo_SignerVotePb.Size(m)
+}
+func (m *SignerVotePb) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignerVotePb.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignerVotePb proto.InternalMessageInfo
+
+func (m *SignerVotePb) GetVoter() string {
+	if m != nil {
+		return m.Voter
+	}
+	return ""
+}
+
+func (m *SignerVotePb) GetVoterPubKey() []byte {
+	if m != nil {
+		return m.VoterPubKey
+	}
+	return nil
+}
+
+func (m *SignerVotePb) GetCandidate() string {
+	if m != nil {
+		return m.Candidate
+	}
+	return ""
+}
+
+func (m *SignerVotePb) GetAdd() bool {
+	if m != nil {
+		return m.Add
+	}
+	return false
+}
+
 type ActionPb struct {
 	Version   uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
 	Nonce     uint64 `protobuf:"varint,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
@@ -782,6 +932,8 @@ type ActionPb struct {
 	//	*ActionPb_StartSubChain
 	//	*ActionPb_StopSubChain
 	//	*ActionPb_PutBlock
+	//	*ActionPb_DoubleSignEvidence
+	//	*ActionPb_SignerVote
 	Action               isActionPb_Action `protobuf_oneof:"action"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
 	XXX_unrecognized     []byte            `json:"-"`
@@ -792,7 +944,7 @@ func (m *ActionPb) Reset()         { *m = ActionPb{} }
 func (m *ActionPb) String() string { return proto.CompactTextString(m) }
 func (*ActionPb) ProtoMessage()    {}
 func (*ActionPb) Descriptor() ([]byte, []int) {
-	return fileDescriptor_blockchain_f3f6779518493ae1, []int{10}
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{12}
 }
 func (m *ActionPb) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ActionPb.Unmarshal(m, b)
@@ -883,6 +1035,14 @@ type ActionPb_PutBlock struct {
 	PutBlock *PutBlockPb `protobuf:"bytes,17,opt,name=putBlock,proto3,oneof"`
 }
 
+type ActionPb_DoubleSignEvidence struct {
+	DoubleSignEvidence *DoubleSignEvidencePb `protobuf:"bytes,18,opt,name=doubleSignEvidence,proto3,oneof"`
+}
+
+type ActionPb_SignerVote struct {
+	SignerVote *SignerVotePb `protobuf:"bytes,19,opt,name=signerVote,proto3,oneof"`
+}
+
 func (*ActionPb_Transfer) isActionPb_Action() {}
 
 func (*ActionPb_Vote) isActionPb_Action() {}
@@ -899,6 +1059,10 @@ func (*ActionPb_StopSubChain) isActionPb_Action() {}
 
 func (*ActionPb_PutBlock) isActionPb_Action() {}
 
+func (*ActionPb_DoubleSignEvidence) isActionPb_Action() {}
+
+func (*ActionPb_SignerVote) isActionPb_Action() {}
+
 func (m *ActionPb) GetAction() isActionPb_Action {
 	if m != nil {
 		return m.Action
@@ -962,6 +1126,20 @@ func (m *ActionPb) GetPutBlock() *PutBlockPb {
 	return nil
 }
 
+func (m *ActionPb) GetDoubleSignEvidence() *DoubleSignEvidencePb {
+	if x, ok := m.GetAction().(*ActionPb_DoubleSignEvidence); ok {
+		return x.DoubleSignEvidence
+	}
+	return nil
+}
+
+func (m *ActionPb) GetSignerVote() *SignerVotePb {
+	if x, ok := m.GetAction().(*ActionPb_SignerVote); ok {
+		return x.SignerVote
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*ActionPb) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _ActionPb_OneofMarshaler, _ActionPb_OneofUnmarshaler, _ActionPb_OneofSizer, []interface{}{
@@ -973,6 +1151,8 @@ func (*ActionPb) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) erro
 		(*ActionPb_StartSubChain)(nil),
 		(*ActionPb_StopSubChain)(nil),
 		(*ActionPb_PutBlock)(nil),
+		(*ActionPb_DoubleSignEvidence)(nil),
+		(*ActionPb_SignerVote)(nil),
 	}
 }
 
@@ -1020,6 +1200,16 @@ func _ActionPb_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.PutBlock); err != nil {
 			return err
 		}
+	case *ActionPb_DoubleSignEvidence:
+		b.EncodeVarint(18<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.DoubleSignEvidence); err != nil {
+			return err
+		}
+	case *ActionPb_SignerVote:
+		b.EncodeVarint(19<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.SignerVote); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("ActionPb.Action has unexpected type %T", x)
@@ -1094,6 +1284,22 @@ func _ActionPb_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffe
 		err := b.DecodeMessage(msg)
 		m.Action = &ActionPb_PutBlock{msg}
 		return true, err
+	case 18: // action.doubleSignEvidence
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(DoubleSignEvidencePb)
+		err := b.DecodeMessage(msg)
+		m.Action = &ActionPb_DoubleSignEvidence{msg}
+		return true, err
+	case 19: // action.signerVote
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(SignerVotePb)
+		err := b.DecodeMessage(msg)
+		m.Action = &ActionPb_SignerVote{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -1143,6 +1349,16 @@ func _ActionPb_OneofSizer(msg proto.Message) (n int) {
 		n += 2 // tag and wire
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *ActionPb_DoubleSignEvidence:
+		s := proto.Size(x.DoubleSignEvidence)
+		n += 2 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *ActionPb_SignerVote:
+		s := proto.Size(x.SignerVote)
+		n += 2 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -1152,20 +1368,22 @@ func _ActionPb_OneofSizer(msg proto.Message) (n int) {
 
 // header of a block
 type BlockHeaderPb struct {
-	Version              uint32   `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
-	ChainID              uint32   `protobuf:"varint,2,opt,name=chainID,proto3" json:"chainID,omitempty"`
-	Height               uint64   `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
-	Timestamp            uint64   `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	PrevBlockHash        []byte   `protobuf:"bytes,5,opt,name=prevBlockHash,proto3" json:"prevBlockHash,omitempty"`
-	TxRoot               []byte   `protobuf:"bytes,6,opt,name=txRoot,proto3" json:"txRoot,omitempty"`
-	StateRoot            []byte   `protobuf:"bytes,7,opt,name=stateRoot,proto3" json:"stateRoot,omitempty"`
-	ReceiptRoot          []byte   `protobuf:"bytes,8,opt,name=receiptRoot,proto3" json:"receiptRoot,omitempty"`
-	Reserved             []byte   `protobuf:"bytes,9,opt,name=reserved,proto3" json:"reserved,omitempty"`
-	Signature            []byte   `protobuf:"bytes,10,opt,name=signature,proto3" json:"signature,omitempty"`
-	Pubkey               []byte   `protobuf:"bytes,11,opt,name=pubkey,proto3" json:"pubkey,omitempty"`
-	DkgID                []byte   `protobuf:"bytes,12,opt,name=dkgID,proto3" json:"dkgID,omitempty"`
-	DkgPubkey            []byte   `protobuf:"bytes,13,opt,name=dkgPubkey,proto3" json:"dkgPubkey,omitempty"`
-	DkgSignature         []byte   `protobuf:"bytes,14,opt,name=dkgSignature,proto3" json:"dkgSignature,omitempty"`
+	Version       uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	ChainID       uint32 `protobuf:"varint,2,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	Height        uint64 `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	Timestamp     uint64 `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	PrevBlockHash []byte `protobuf:"bytes,5,opt,name=prevBlockHash,proto3" json:"prevBlockHash,omitempty"`
+	TxRoot        []byte `protobuf:"bytes,6,opt,name=txRoot,proto3" json:"txRoot,omitempty"`
+	StateRoot     []byte `protobuf:"bytes,7,opt,name=stateRoot,proto3" json:"stateRoot,omitempty"`
+	ReceiptRoot   []byte `protobuf:"bytes,8,opt,name=receiptRoot,proto3" json:"receiptRoot,omitempty"`
+	Reserved      []byte `protobuf:"bytes,9,opt,name=reserved,proto3" json:"reserved,omitempty"`
+	Signature     []byte `protobuf:"bytes,10,opt,name=signature,proto3" json:"signature,omitempty"`
+	Pubkey        []byte `protobuf:"bytes,11,opt,name=pubkey,proto3" json:"pubkey,omitempty"`
+	DkgID         []byte `protobuf:"bytes,12,opt,name=dkgID,proto3" json:"dkgID,omitempty"`
+	DkgPubkey     []byte `protobuf:"bytes,13,opt,name=dkgPubkey,proto3" json:"dkgPubkey,omitempty"`
+	DkgSignature  []byte `protobuf:"bytes,14,opt,name=dkgSignature,proto3" json:"dkgSignature,omitempty"`
+	// random seed of the epoch, which is carried by the first block of the epoch
+	Seed                 []byte   `protobuf:"bytes,15,opt,name=seed,proto3" json:"seed,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1175,7 +1393,7 @@ func (m *BlockHeaderPb) Reset()         { *m = BlockHeaderPb{} }
 func (m *BlockHeaderPb) String() string { return proto.CompactTextString(m) }
 func (*BlockHeaderPb) ProtoMessage()    {}
 func (*BlockHeaderPb) Descriptor() ([]byte, []int) {
-	return fileDescriptor_blockchain_f3f6779518493ae1, []int{11}
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{13}
 }
 func (m *BlockHeaderPb) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BlockHeaderPb.Unmarshal(m, b)
@@ -1293,21 +1511,32 @@ func (m *BlockHeaderPb) GetDkgSignature() []byte {
 	return nil
 }
 
+func (m *BlockHeaderPb) GetSeed() []byte {
+	if m != nil {
+		return m.Seed
+	}
+	return nil
+}
+
 // block consists of header followed by transactions
 // hash of current block can be computed from header hence not stored
 type BlockPb struct {
-	Header               *BlockHeaderPb `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
-	Actions              []*ActionPb    `protobuf:"bytes,2,rep,name=actions,proto3" json:"actions,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
-	XXX_unrecognized     []byte         `json:"-"`
-	XXX_sizecache        int32          `json:"-"`
+	Header  *BlockHeaderPb `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Actions []*ActionPb    `protobuf:"bytes,2,rep,name=actions,proto3" json:"actions,omitempty"`
+	// commit endorsements of the block, which are not part of the block hash
+	Endorsements []*EndorsePb `protobuf:"bytes,3,rep,name=endorsements,proto3" json:"endorsements,omitempty"`
+	// BLS aggregate of the commit endorsements of the block, which is not part of the block hash
+	AggregateEndorsement *EndorsePb `protobuf:"bytes,4,opt,name=aggregateEndorsement,proto3" json:"aggregateEndorsement,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
 func (m *BlockPb) Reset()         { *m = BlockPb{} }
 func (m *BlockPb) String() string { return proto.CompactTextString(m) }
 func (*BlockPb) ProtoMessage()    {}
 func (*BlockPb) Descriptor() ([]byte, []int) {
-	return fileDescriptor_blockchain_f3f6779518493ae1, []int{12}
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{14}
 }
 func (m *BlockPb) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BlockPb.Unmarshal(m, b)
@@ -1341,6 +1570,20 @@ func (m *BlockPb) GetActions() []*ActionPb {
 	return nil
 }
 
+func (m *BlockPb) GetEndorsements() []*EndorsePb {
+	if m != nil {
+		return m.Endorsements
+	}
+	return nil
+}
+
+func (m *BlockPb) GetAggregateEndorsement() *EndorsePb {
+	if m != nil {
+		return m.AggregateEndorsement
+	}
+	return nil
+}
+
 // index of block raw data file
 type BlockIndex struct {
 	Start                uint64   `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
@@ -1355,7 +1598,7 @@ func (m *BlockIndex) Reset()         { *m = BlockIndex{} }
 func (m *BlockIndex) String() string { return proto.CompactTextString(m) }
 func (*BlockIndex) ProtoMessage()    {}
 func (*BlockIndex) Descriptor() ([]byte, []int) {
-	return fileDescriptor_blockchain_f3f6779518493ae1, []int{13}
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{15}
 }
 func (m *BlockIndex) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BlockIndex.Unmarshal(m, b)
@@ -1408,7 +1651,7 @@ func (m *BlockSync) Reset()         { *m = BlockSync{} }
 func (m *BlockSync) String() string { return proto.CompactTextString(m) }
 func (*BlockSync) ProtoMessage()    {}
 func (*BlockSync) Descriptor() ([]byte, []int) {
-	return fileDescriptor_blockchain_f3f6779518493ae1, []int{14}
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{16}
 }
 func (m *BlockSync) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BlockSync.Unmarshal(m, b)
@@ -1445,17 +1688,19 @@ func (m *BlockSync) GetEnd() uint64 {
 // block container
 // used to send old/existing blocks in block sync
 type BlockContainer struct {
-	Block                *BlockPb `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Block *BlockPb `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	// A batch of consecutive blocks in a block sync response
+	Blocks               []*BlockPb `protobuf:"bytes,2,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
 func (m *BlockContainer) Reset()         { *m = BlockContainer{} }
 func (m *BlockContainer) String() string { return proto.CompactTextString(m) }
 func (*BlockContainer) ProtoMessage()    {}
 func (*BlockContainer) Descriptor() ([]byte, []int) {
-	return fileDescriptor_blockchain_f3f6779518493ae1, []int{15}
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{17}
 }
 func (m *BlockContainer) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BlockContainer.Unmarshal(m, b)
@@ -1482,10 +1727,213 @@ func (m *BlockContainer) GetBlock() *BlockPb {
 	return nil
 }
 
+func (m *BlockContainer) GetBlocks() []*BlockPb {
+	if m != nil {
+		return m.Blocks
+	}
+	return nil
+}
+
+// tip of the chain which a node advertises to its peers
+type ChainTip struct {
+	Height               uint64   `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Hash                 []byte   `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChainTip) Reset()         { *m = ChainTip{} }
+func (m *ChainTip) String() string { return proto.CompactTextString(m) }
+func (*ChainTip) ProtoMessage()    {}
+func (*ChainTip) Descriptor() ([]byte, []int) {
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{18}
+}
+func (m *ChainTip) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChainTip.Unmarshal(m, b)
+}
+func (m *ChainTip) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChainTip.Marshal(b, m, deterministic)
+}
+func (dst *ChainTip) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChainTip.Merge(dst, src)
+}
+func (m *ChainTip) XXX_Size() int {
+	return xxx_messageInfo_ChainTip.Size(m)
+}
+func (m *ChainTip) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChainTip.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChainTip proto.InternalMessageInfo
+
+func (m *ChainTip) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *ChainTip) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+// request of the headers of a range of blocks
+type BlockHeaderSync struct {
+	Start                uint64   `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	End                  uint64   `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockHeaderSync) Reset()         { *m = BlockHeaderSync{} }
+func (m *BlockHeaderSync) String() string { return proto.CompactTextString(m) }
+func (*BlockHeaderSync) ProtoMessage()    {}
+func (*BlockHeaderSync) Descriptor() ([]byte, []int) {
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{19}
+}
+func (m *BlockHeaderSync) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockHeaderSync.Unmarshal(m, b)
+}
+func (m *BlockHeaderSync) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockHeaderSync.Marshal(b, m, deterministic)
+}
+func (dst *BlockHeaderSync) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockHeaderSync.Merge(dst, src)
+}
+func (m *BlockHeaderSync) XXX_Size() int {
+	return xxx_messageInfo_BlockHeaderSync.Size(m)
+}
+func (m *BlockHeaderSync) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockHeaderSync.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockHeaderSync proto.InternalMessageInfo
+
+func (m *BlockHeaderSync) GetStart() uint64 {
+	if m != nil {
+		return m.Start
+	}
+	return 0
+}
+
+func (m *BlockHeaderSync) GetEnd() uint64 {
+	if m != nil {
+		return m.End
+	}
+	return 0
+}
+
+// header container
+// used to send the headers of consecutive blocks in response to a BlockHeaderSync
+type BlockHeaderContainer struct {
+	Headers []*BlockHeaderPb `protobuf:"bytes,1,rep,name=headers,proto3" json:"headers,omitempty"`
+	// commit certificates of the headers in the same order
+	Certificates         []*BlockCertificatePb `protobuf:"bytes,2,rep,name=certificates,proto3" json:"certificates,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *BlockHeaderContainer) Reset()         { *m = BlockHeaderContainer{} }
+func (m *BlockHeaderContainer) String() string { return proto.CompactTextString(m) }
+func (*BlockHeaderContainer) ProtoMessage()    {}
+func (*BlockHeaderContainer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{20}
+}
+func (m *BlockHeaderContainer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockHeaderContainer.Unmarshal(m, b)
+}
+func (m *BlockHeaderContainer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockHeaderContainer.Marshal(b, m, deterministic)
+}
+func (dst *BlockHeaderContainer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockHeaderContainer.Merge(dst, src)
+}
+func (m *BlockHeaderContainer) XXX_Size() int {
+	return xxx_messageInfo_BlockHeaderContainer.Size(m)
+}
+func (m *BlockHeaderContainer) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockHeaderContainer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockHeaderContainer proto.InternalMessageInfo
+
+func (m *BlockHeaderContainer) GetHeaders() []*BlockHeaderPb {
+	if m != nil {
+		return m.Headers
+	}
+	return nil
+}
+
+func (m *BlockHeaderContainer) GetCertificates() []*BlockCertificatePb {
+	if m != nil {
+		return m.Certificates
+	}
+	return nil
+}
+
+// commit certificate of a block header
+type BlockCertificatePb struct {
+	Endorsements         []*EndorsePb `protobuf:"bytes,1,rep,name=endorsements,proto3" json:"endorsements,omitempty"`
+	AggregateEndorsement *EndorsePb   `protobuf:"bytes,2,opt,name=aggregateEndorsement,proto3" json:"aggregateEndorsement,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *BlockCertificatePb) Reset()         { *m = BlockCertificatePb{} }
+func (m *BlockCertificatePb) String() string { return proto.CompactTextString(m) }
+func (*BlockCertificatePb) ProtoMessage()    {}
+func (*BlockCertificatePb) Descriptor() ([]byte, []int) {
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{21}
+}
+func (m *BlockCertificatePb) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockCertificatePb.Unmarshal(m, b)
+}
+func (m *BlockCertificatePb) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockCertificatePb.Marshal(b, m, deterministic)
+}
+func (dst *BlockCertificatePb) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockCertificatePb.Merge(dst, src)
+}
+func (m *BlockCertificatePb) XXX_Size() int {
+	return xxx_messageInfo_BlockCertificatePb.Size(m)
+}
+func (m *BlockCertificatePb) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockCertificatePb.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockCertificatePb proto.InternalMessageInfo
+
+func (m *BlockCertificatePb) GetEndorsements() []*EndorsePb {
+	if m != nil {
+		return m.Endorsements
+	}
+	return nil
+}
+
+func (m *BlockCertificatePb) GetAggregateEndorsement() *EndorsePb {
+	if m != nil {
+		return m.AggregateEndorsement
+	}
+	return nil
+}
+
 // corresponding to pre-prepare pharse in view change protocol
 type ProposePb struct {
-	Proposer             string   `protobuf:"bytes,1,opt,name=proposer,proto3" json:"proposer,omitempty"`
-	Block                *BlockPb `protobuf:"bytes,2,opt,name=block,proto3" json:"block,omitempty"`
+	Proposer     string   `protobuf:"bytes,1,opt,name=proposer,proto3" json:"proposer,omitempty"`
+	Block        *BlockPb `protobuf:"bytes,2,opt,name=block,proto3" json:"block,omitempty"`
+	Round        uint32   `protobuf:"varint,3,opt,name=round,proto3" json:"round,omitempty"`
+	ProposerAddr string   `protobuf:"bytes,4,opt,name=proposerAddr,proto3" json:"proposerAddr,omitempty"`
+	// the proposer signs the proposal of the block in the round with its public key
+	ProposerPubKey       []byte   `protobuf:"bytes,5,opt,name=proposerPubKey,proto3" json:"proposerPubKey,omitempty"`
+	Signature            []byte   `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+	ProposerAddr         string   `protobuf:"bytes,7,opt,name=proposerAddr,proto3" json:"proposerAddr,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1495,7 +1943,7 @@ func (m *ProposePb) Reset()         { *m = ProposePb{} }
 func (m *ProposePb) String() string { return proto.CompactTextString(m) }
 func (*ProposePb) ProtoMessage()    {}
 func (*ProposePb) Descriptor() ([]byte, []int) {
-	return fileDescriptor_blockchain_f3f6779518493ae1, []int{16}
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{22}
 }
 func (m *ProposePb) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ProposePb.Unmarshal(m, b)
@@ -1529,6 +1977,34 @@ func (m *ProposePb) GetBlock() *BlockPb {
 	return nil
 }
 
+func (m *ProposePb) GetRound() uint32 {
+	if m != nil {
+		return m.Round
+	}
+	return 0
+}
+
+func (m *ProposePb) GetProposerAddr() string {
+	if m != nil {
+		return m.ProposerAddr
+	}
+	return ""
+}
+
+func (m *ProposePb) GetProposerPubKey() []byte {
+	if m != nil {
+		return m.ProposerPubKey
+	}
+	return nil
+}
+
+func (m *ProposePb) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
 // corresponding to prepare and pre-prepare phase in view change protocol
 type EndorsePb struct {
 	Height               uint64                     `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
@@ -1538,6 +2014,10 @@ type EndorsePb struct {
 	EndorserPubKey       []byte                     `protobuf:"bytes,5,opt,name=endorserPubKey,proto3" json:"endorserPubKey,omitempty"`
 	Decision             bool                       `protobuf:"varint,6,opt,name=decision,proto3" json:"decision,omitempty"`
 	Signature            []byte                     `protobuf:"bytes,7,opt,name=signature,proto3" json:"signature,omitempty"`
+	BlsSignature         []byte                     `protobuf:"bytes,8,opt,name=blsSignature,proto3" json:"blsSignature,omitempty"`
+	Bitmap               []byte                     `protobuf:"bytes,9,opt,name=bitmap,proto3" json:"bitmap,omitempty"`
+	AggregateSignature   []byte                     `protobuf:"bytes,10,opt,name=aggregateSignature,proto3" json:"aggregateSignature,omitempty"`
+	Round                uint32                     `protobuf:"varint,11,opt,name=round,proto3" json:"round,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
 	XXX_unrecognized     []byte                     `json:"-"`
 	XXX_sizecache        int32                      `json:"-"`
@@ -1547,7 +2027,7 @@ func (m *EndorsePb) Reset()         { *m = EndorsePb{} }
 func (m *EndorsePb) String() string { return proto.CompactTextString(m) }
 func (*EndorsePb) ProtoMessage()    {}
 func (*EndorsePb) Descriptor() ([]byte, []int) {
-	return fileDescriptor_blockchain_f3f6779518493ae1, []int{17}
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{23}
 }
 func (m *EndorsePb) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_EndorsePb.Unmarshal(m, b)
@@ -1616,6 +2096,121 @@ func (m *EndorsePb) GetSignature() []byte {
 	return nil
 }
 
+func (m *EndorsePb) GetBlsSignature() []byte {
+	if m != nil {
+		return m.BlsSignature
+	}
+	return nil
+}
+
+func (m *EndorsePb) GetBitmap() []byte {
+	if m != nil {
+		return m.Bitmap
+	}
+	return nil
+}
+
+func (m *EndorsePb) GetAggregateSignature() []byte {
+	if m != nil {
+		return m.AggregateSignature
+	}
+	return nil
+}
+
+func (m *EndorsePb) GetRound() uint32 {
+	if m != nil {
+		return m.Round
+	}
+	return 0
+}
+
+// proposal of a block in a round signed by the proposer, which is carried by the double sign evidence
+type SignedProposalPb struct {
+	Height               uint64   `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Round                uint32   `protobuf:"varint,2,opt,name=round,proto3" json:"round,omitempty"`
+	BlockHash            []byte   `protobuf:"bytes,3,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
+	Proposer             string   `protobuf:"bytes,4,opt,name=proposer,proto3" json:"proposer,omitempty"`
+	ProposerPubKey       []byte   `protobuf:"bytes,5,opt,name=proposerPubKey,proto3" json:"proposerPubKey,omitempty"`
+	Signature            []byte   `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+	ProposerAddr         string   `protobuf:"bytes,7,opt,name=proposerAddr,proto3" json:"proposerAddr,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SignedProposalPb) Reset()         { *m = SignedProposalPb{} }
+func (m *SignedProposalPb) String() string { return proto.CompactTextString(m) }
+func (*SignedProposalPb) ProtoMessage()    {}
+func (*SignedProposalPb) Descriptor() ([]byte, []int) {
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{24}
+}
+func (m *SignedProposalPb) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignedProposalPb.Unmarshal(m, b)
+}
+func (m *SignedProposalPb) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignedProposalPb.Marshal(b, m, deterministic)
+}
+func (dst *SignedProposalPb) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignedProposalPb.Merge(dst, src)
+}
+func (m *SignedProposalPb) XXX_Size() int {
+	return xxx_messageInfo_SignedProposalPb.Size(m)
+}
+func (m *SignedProposalPb) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignedProposalPb.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignedProposalPb proto.InternalMessageInfo
+
+func (m *SignedProposalPb) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *SignedProposalPb) GetRound() uint32 {
+	if m != nil {
+		return m.Round
+	}
+	return 0
+}
+
+func (m *SignedProposalPb) GetBlockHash() []byte {
+	if m != nil {
+		return m.BlockHash
+	}
+	return nil
+}
+
+func (m *SignedProposalPb) GetProposer() string {
+	if m != nil {
+		return m.Proposer
+	}
+	return ""
+}
+
+func (m *SignedProposalPb) GetProposerPubKey() []byte {
+	if m != nil {
+		return m.ProposerPubKey
+	}
+	return nil
+}
+
+func (m *SignedProposalPb) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *SignedProposalPb) GetProposerAddr() string {
+	if m != nil {
+		return m.ProposerAddr
+	}
+	return ""
+}
+
 // Candidates and list of candidates
 type Candidate struct {
 	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
@@ -1632,7 +2227,7 @@ func (m *Candidate) Reset()         { *m = Candidate{} }
 func (m *Candidate) String() string { return proto.CompactTextString(m) }
 func (*Candidate) ProtoMessage()    {}
 func (*Candidate) Descriptor() ([]byte, []int) {
-	return fileDescriptor_blockchain_f3f6779518493ae1, []int{18}
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{25}
 }
 func (m *Candidate) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Candidate.Unmarshal(m, b)
@@ -1698,7 +2293,7 @@ func (m *CandidateList) Reset()         { *m = CandidateList{} }
 func (m *CandidateList) String() string { return proto.CompactTextString(m) }
 func (*CandidateList) ProtoMessage()    {}
 func (*CandidateList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_blockchain_f3f6779518493ae1, []int{19}
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{26}
 }
 func (m *CandidateList) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CandidateList.Unmarshal(m, b)
@@ -1739,7 +2334,7 @@ func (m *TestPayload) Reset()         { *m = TestPayload{} }
 func (m *TestPayload) String() string { return proto.CompactTextString(m) }
 func (*TestPayload) ProtoMessage()    {}
 func (*TestPayload) Descriptor() ([]byte, []int) {
-	return fileDescriptor_blockchain_f3f6779518493ae1, []int{20}
+	return fileDescriptor_blockchain_2d2fd2925c4a5efb, []int{27}
 }
 func (m *TestPayload) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TestPayload.Unmarshal(m, b)
@@ -1777,115 +2372,150 @@ func init() {
 	proto.RegisterType((*StartSubChainPb)(nil), "iproto.StartSubChainPb")
 	proto.RegisterType((*StopSubChainPb)(nil), "iproto.StopSubChainPb")
 	proto.RegisterType((*PutBlockPb)(nil), "iproto.PutBlockPb")
+	proto.RegisterType((*DoubleSignEvidencePb)(nil), "iproto.DoubleSignEvidencePb")
+	proto.RegisterType((*SignerVotePb)(nil), "iproto.SignerVotePb")
 	proto.RegisterType((*ActionPb)(nil), "iproto.ActionPb")
 	proto.RegisterType((*BlockHeaderPb)(nil), "iproto.BlockHeaderPb")
 	proto.RegisterType((*BlockPb)(nil), "iproto.BlockPb")
 	proto.RegisterType((*BlockIndex)(nil), "iproto.BlockIndex")
 	proto.RegisterType((*BlockSync)(nil), "iproto.BlockSync")
 	proto.RegisterType((*BlockContainer)(nil), "iproto.BlockContainer")
+	proto.RegisterType((*ChainTip)(nil), "iproto.ChainTip")
+	proto.RegisterType((*BlockHeaderSync)(nil), "iproto.BlockHeaderSync")
+	proto.RegisterType((*BlockHeaderContainer)(nil), "iproto.BlockHeaderContainer")
+	proto.RegisterType((*BlockCertificatePb)(nil), "iproto.BlockCertificatePb")
 	proto.RegisterType((*ProposePb)(nil), "iproto.ProposePb")
 	proto.RegisterType((*EndorsePb)(nil), "iproto.EndorsePb")
+	proto.RegisterType((*SignedProposalPb)(nil), "iproto.SignedProposalPb")
 	proto.RegisterType((*Candidate)(nil), "iproto.Candidate")
 	proto.RegisterType((*CandidateList)(nil), "iproto.CandidateList")
 	proto.RegisterType((*TestPayload)(nil), "iproto.TestPayload")
 	proto.RegisterEnum("iproto.EndorsePb_EndorsementTopic", EndorsePb_EndorsementTopic_name, EndorsePb_EndorsementTopic_value)
 }
 
-func init() { proto.RegisterFile("blockchain.proto", fileDescriptor_blockchain_f3f6779518493ae1) }
-
-var fileDescriptor_blockchain_f3f6779518493ae1 = []byte{
-	// 1487 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x57, 0x4b, 0x6f, 0x1c, 0xc5,
-	0x13, 0xf7, 0xec, 0xae, 0xf7, 0x51, 0xfb, 0xf0, 0xa6, 0xff, 0xf9, 0x9b, 0x21, 0x42, 0xd1, 0x32,
-	0x0a, 0xd1, 0x2a, 0x0a, 0x16, 0x38, 0x07, 0x38, 0x20, 0xa1, 0xd8, 0x89, 0xb4, 0x16, 0x4e, 0xbc,
-	0x6a, 0x9b, 0x70, 0x84, 0x79, 0xb4, 0xd7, 0x23, 0xef, 0x4e, 0x8f, 0xa6, 0x7b, 0x1c, 0xfb, 0x4b,
-	0x70, 0xe7, 0x02, 0xe2, 0x94, 0x3b, 0x27, 0x2e, 0xdc, 0xf9, 0x12, 0x7c, 0x17, 0xd4, 0xd5, 0xdd,
-	0xf3, 0x8a, 0x63, 0x71, 0xf2, 0xd6, 0xaf, 0xab, 0x6b, 0xea, 0xf5, 0xeb, 0x2a, 0xc3, 0x34, 0x58,
-	0xf3, 0xf0, 0x32, 0xbc, 0xf0, 0xe3, 0x64, 0x2f, 0xcd, 0xb8, 0xe4, 0xa4, 0x1b, 0xe3, 0x5f, 0xef,
-	0x4f, 0x07, 0xe0, 0x2c, 0xf3, 0x13, 0x71, 0xce, 0xb2, 0x65, 0x40, 0x76, 0xa1, 0xeb, 0x6f, 0x78,
-	0x9e, 0x48, 0xd7, 0x99, 0x39, 0xf3, 0x11, 0x35, 0x92, 0xc2, 0x05, 0x4b, 0x22, 0x96, 0xb9, 0xad,
-	0x99, 0x33, 0x1f, 0x50, 0x23, 0x91, 0x4f, 0x60, 0x90, 0xb1, 0x30, 0x4e, 0x63, 0x96, 0x48, 0xb7,
-	0x8d, 0x47, 0x25, 0x40, 0x5c, 0xe8, 0xa5, 0xfe, 0xcd, 0x9a, 0xfb, 0x91, 0xdb, 0x41, 0x73, 0x56,
-	0x24, 0x1e, 0x8c, 0xb4, 0x85, 0x65, 0x1e, 0x7c, 0xc7, 0x6e, 0xdc, 0x6d, 0x3c, 0xae, 0x61, 0xe4,
-	0x21, 0x40, 0x2c, 0x0e, 0x79, 0x9c, 0x04, 0xbe, 0x60, 0x6e, 0x77, 0xe6, 0xcc, 0xfb, 0xb4, 0x82,
-	0x78, 0x3f, 0x3b, 0xd0, 0x7d, 0xc3, 0x25, 0x5b, 0x06, 0xca, 0x0d, 0x19, 0x6f, 0x98, 0x90, 0xfe,
-	0x26, 0x45, 0xcf, 0x3b, 0xb4, 0x04, 0x94, 0x21, 0xc1, 0xd6, 0xe7, 0xcb, 0x3c, 0xb8, 0x64, 0x37,
-	0x18, 0xc0, 0x88, 0x56, 0x10, 0xe5, 0xcc, 0x15, 0x97, 0x2c, 0x7b, 0x1e, 0x45, 0x19, 0x13, 0xc2,
-	0xc4, 0x51, 0xc3, 0xac, 0x0e, 0xb3, 0x3a, 0x9d, 0x52, 0xc7, 0x62, 0xde, 0x2f, 0x0e, 0x0c, 0x5f,
-	0x5e, 0xb3, 0x30, 0x97, 0x31, 0x4f, 0xee, 0x48, 0xe6, 0x03, 0xe8, 0x33, 0x54, 0xe3, 0x36, 0x9d,
-	0x85, 0xac, 0xce, 0x42, 0x9e, 0xc8, 0xcc, 0x0f, 0x6d, 0x3e, 0x0b, 0x99, 0x3c, 0x86, 0x89, 0xd5,
-	0x33, 0x69, 0xd3, 0x59, 0x6d, 0xa0, 0x84, 0x40, 0x27, 0xf2, 0xa5, 0x6f, 0x92, 0x8a, 0xbf, 0xbd,
-	0x9f, 0x60, 0x7a, 0xca, 0xc2, 0x8c, 0xc9, 0x65, 0xc6, 0x53, 0x2e, 0xfc, 0xb5, 0xf6, 0xcf, 0x14,
-	0xd5, 0xf9, 0x70, 0x51, 0x5b, 0xcd, 0xa2, 0xe2, 0x2d, 0x65, 0xc9, 0x6d, 0xcf, 0xda, 0xf3, 0x31,
-	0x35, 0x92, 0x77, 0x08, 0x3b, 0xfa, 0x0b, 0x3f, 0xc4, 0x32, 0x61, 0x42, 0xdc, 0xf1, 0x01, 0x17,
-	0x7a, 0x6f, 0xb5, 0x92, 0xdb, 0x9a, 0xb5, 0x55, 0x5f, 0x18, 0xd1, 0xfb, 0xcb, 0x81, 0xed, 0x63,
-	0xbe, 0x5a, 0x06, 0x4a, 0xc7, 0x37, 0xb9, 0xd6, 0x97, 0xad, 0xa8, 0xac, 0x4a, 0x9e, 0xc6, 0xa1,
-	0xbd, 0x6c, 0xa4, 0x22, 0xec, 0x76, 0x19, 0x36, 0x99, 0xc1, 0x10, 0x5b, 0xff, 0x75, 0xbe, 0x09,
-	0x58, 0x86, 0xf9, 0xea, 0xd0, 0x2a, 0xa4, 0xbe, 0x23, 0xaf, 0x93, 0x85, 0x2f, 0x2e, 0x4c, 0xbe,
-	0xac, 0xa8, 0xd2, 0x80, 0x8a, 0x78, 0xd6, 0xc5, 0xb3, 0x12, 0x20, 0xf7, 0x61, 0x3b, 0x4e, 0x22,
-	0x76, 0xed, 0xf6, 0x66, 0xce, 0x7c, 0x4c, 0xb5, 0xe0, 0xfd, 0xed, 0xc0, 0x80, 0xb2, 0x90, 0xc5,
-	0xa9, 0x5c, 0x06, 0xea, 0xeb, 0x19, 0x93, 0x79, 0x96, 0xbc, 0xf1, 0xd7, 0x39, 0x33, 0x5d, 0x50,
-	0x85, 0x30, 0x43, 0xd2, 0x97, 0xb9, 0xc0, 0x3c, 0x77, 0xa8, 0x91, 0x54, 0x2c, 0x17, 0xea, 0xb3,
-	0x26, 0x16, 0xf5, 0x5b, 0x59, 0x5b, 0xf9, 0xe2, 0x90, 0x27, 0x22, 0xdf, 0xb0, 0xc8, 0xc6, 0x52,
-	0x81, 0xc8, 0x1c, 0x76, 0x6c, 0xb3, 0xd8, 0x3e, 0xdd, 0xc6, 0xdc, 0x35, 0x61, 0xf2, 0x29, 0x74,
-	0xd6, 0x7c, 0x25, 0xdc, 0xee, 0xac, 0x3d, 0x1f, 0xee, 0x8f, 0xf7, 0xf4, 0x6b, 0xb0, 0x87, 0xa9,
-	0xa7, 0x78, 0xe4, 0xfd, 0xd6, 0x82, 0x9d, 0x53, 0xe9, 0x67, 0xf2, 0x34, 0x0f, 0x0e, 0xd5, 0xcb,
-	0xa1, 0x8b, 0x82, 0x8f, 0xc8, 0xd1, 0x0b, 0x0c, 0x66, 0x4c, 0xad, 0xa8, 0x3e, 0x2d, 0x58, 0x98,
-	0x67, 0xb1, 0xbc, 0x79, 0xc1, 0x52, 0x2e, 0x62, 0x69, 0x88, 0xd6, 0x84, 0xc9, 0x13, 0x98, 0xf2,
-	0x94, 0x65, 0xbe, 0x22, 0x89, 0x55, 0xd5, 0x61, 0xbe, 0x87, 0xab, 0x90, 0x85, 0x72, 0x61, 0xc1,
-	0xe2, 0xd5, 0x85, 0xb4, 0x21, 0x57, 0x20, 0xb2, 0x07, 0x24, 0xf5, 0x33, 0x96, 0x18, 0xf9, 0xe4,
-	0xfc, 0x5c, 0x30, 0x89, 0x51, 0x77, 0xe8, 0x2d, 0x27, 0x8a, 0xc7, 0xfc, 0x6d, 0x52, 0x72, 0xbd,
-	0xab, 0x79, 0x5c, 0xc5, 0x14, 0xcf, 0x50, 0x5e, 0xe6, 0xc1, 0x3a, 0x0e, 0x15, 0xcf, 0x7a, 0x9a,
-	0x67, 0x75, 0xd4, 0xfb, 0xc3, 0x81, 0xc9, 0xa9, 0xe4, 0xe9, 0x7f, 0x4a, 0x90, 0x7a, 0x84, 0x24,
-	0x4f, 0x4d, 0x24, 0xba, 0xda, 0x15, 0x44, 0xf5, 0x13, 0x9a, 0x37, 0xac, 0xd7, 0xc2, 0x2d, 0xae,
-	0x74, 0x6e, 0x73, 0x05, 0xd3, 0x6f, 0xbc, 0x68, 0x54, 0xbe, 0x01, 0x7b, 0xbf, 0xb6, 0x00, 0x96,
-	0xb9, 0x3c, 0x50, 0x8d, 0x7c, 0xa7, 0xc3, 0xbb, 0xd0, 0xbd, 0xa8, 0x3a, 0x6b, 0xa4, 0x5b, 0x5b,
-	0xf3, 0x21, 0x80, 0x1f, 0xaa, 0xc2, 0x51, 0xce, 0xa5, 0x71, 0xb1, 0x82, 0x28, 0x2a, 0xa9, 0xc6,
-	0x66, 0x78, 0xac, 0x69, 0x56, 0x02, 0xe4, 0x29, 0xdc, 0x4b, 0x33, 0x1e, 0xe5, 0x61, 0x35, 0x4e,
-	0x4d, 0xb8, 0xf7, 0x0f, 0x54, 0xc5, 0x59, 0x12, 0xf1, 0x4c, 0xf0, 0x12, 0x14, 0x6e, 0x0f, 0x9f,
-	0x82, 0x5b, 0x4e, 0xaa, 0xfa, 0xa7, 0xf1, 0x2a, 0xf1, 0x65, 0x9e, 0x31, 0xe1, 0xf6, 0xeb, 0xfa,
-	0xe5, 0x89, 0xf7, 0x4f, 0x07, 0xfa, 0xcf, 0x43, 0xf3, 0x84, 0xbb, 0xd0, 0xbb, 0x62, 0x99, 0x88,
-	0x79, 0x62, 0xd3, 0x63, 0x44, 0x55, 0xaf, 0x84, 0x27, 0x21, 0x33, 0xd9, 0xd1, 0x82, 0x7a, 0xbe,
-	0x57, 0xbe, 0x38, 0x8e, 0x37, 0xa6, 0xa9, 0x3b, 0xb4, 0x90, 0xcd, 0xd9, 0x32, 0x8b, 0x43, 0x66,
-	0x52, 0x54, 0xc8, 0x98, 0x20, 0xeb, 0x42, 0x91, 0x20, 0x0b, 0x90, 0x2f, 0xa0, 0x2f, 0xcd, 0x8c,
-	0x76, 0x61, 0xe6, 0xcc, 0x87, 0xfb, 0xc4, 0x32, 0xb6, 0x9c, 0xdd, 0x8b, 0x2d, 0x5a, 0x68, 0x91,
-	0x47, 0xd0, 0x51, 0xa3, 0xc9, 0x1d, 0xa2, 0xf6, 0xc4, 0x6a, 0xeb, 0x71, 0xb9, 0xd8, 0xa2, 0x78,
-	0x4a, 0x9e, 0xc1, 0x80, 0xd9, 0x79, 0xe5, 0x8e, 0x50, 0xf5, 0x7f, 0x56, 0xb5, 0x32, 0xc8, 0x16,
-	0x5b, 0xb4, 0xd4, 0x23, 0x07, 0x30, 0x11, 0xb5, 0x49, 0xe2, 0x8e, 0xf1, 0xa6, 0x6b, 0x6f, 0x36,
-	0xe7, 0xcc, 0x62, 0x8b, 0x36, 0x6e, 0x90, 0x6f, 0x61, 0x2c, 0xaa, 0xb3, 0xc2, 0x9d, 0xa0, 0x89,
-	0x8f, 0xea, 0x26, 0x8a, 0x41, 0xb2, 0xd8, 0xa2, 0x75, 0x7d, 0x34, 0x50, 0x7d, 0x9b, 0xdc, 0x9d,
-	0x86, 0x81, 0xfa, 0xc3, 0x85, 0x06, 0xaa, 0x10, 0xf9, 0x06, 0x46, 0xa2, 0x42, 0x5d, 0x77, 0x8a,
-	0xf7, 0x77, 0xcb, 0xfb, 0x55, 0x5a, 0x2f, 0xb6, 0x68, 0x4d, 0x5b, 0x15, 0x24, 0x35, 0x1c, 0x72,
-	0xef, 0xd5, 0x0b, 0x52, 0x72, 0x4b, 0x15, 0xc4, 0x6a, 0x1d, 0xf4, 0xa1, 0xab, 0xf9, 0xe0, 0xfd,
-	0xde, 0x86, 0x31, 0x62, 0x0b, 0xe6, 0x47, 0xb8, 0x74, 0x7d, 0xb8, 0xc9, 0x2a, 0xec, 0x6c, 0x7d,
-	0x88, 0x9d, 0xed, 0x1a, 0x3b, 0x6b, 0x9b, 0x50, 0xa7, 0xb9, 0x09, 0x3d, 0x82, 0x71, 0x9a, 0xb1,
-	0xab, 0x83, 0x62, 0xac, 0xe9, 0x56, 0xab, 0x83, 0x38, 0x60, 0xaf, 0x91, 0xaa, 0x9a, 0x84, 0x46,
-	0xaa, 0xb3, 0xb8, 0xd7, 0x64, 0x31, 0x0e, 0x3b, 0x9c, 0x7c, 0x78, 0xde, 0xb7, 0xc3, 0xae, 0x80,
-	0x14, 0x01, 0x32, 0x26, 0x58, 0x76, 0xc5, 0x22, 0x77, 0xa0, 0x09, 0x60, 0xe5, 0x3a, 0x01, 0xa0,
-	0x49, 0x80, 0x5d, 0xe8, 0xa6, 0x7a, 0x7b, 0x1b, 0x6a, 0x8f, 0xb4, 0xa4, 0x48, 0x18, 0x5d, 0xae,
-	0x8e, 0x5e, 0x60, 0xf3, 0x8e, 0xa8, 0x16, 0x94, 0xad, 0xe8, 0x72, 0x65, 0xd6, 0xbd, 0xb1, 0xb6,
-	0x55, 0x00, 0x6a, 0x02, 0x44, 0x97, 0xab, 0x82, 0xf0, 0xd8, 0x7a, 0x23, 0x5a, 0xc3, 0xbc, 0x08,
-	0x7a, 0xf6, 0x81, 0xfc, 0x5c, 0x25, 0xda, 0xb7, 0x3b, 0xcc, 0x70, 0xff, 0xff, 0xb6, 0xd0, 0xb5,
-	0x1a, 0x52, 0xa3, 0x44, 0x9e, 0x40, 0x4f, 0xd7, 0x59, 0x6f, 0x27, 0xc3, 0xfd, 0xa9, 0xd5, 0xb7,
-	0x6f, 0x0a, 0xb5, 0x0a, 0xde, 0x31, 0x00, 0x1a, 0x39, 0x52, 0xab, 0x83, 0x8a, 0x05, 0x5b, 0xd4,
-	0xec, 0xaf, 0x5a, 0x20, 0x53, 0x68, 0xb3, 0x24, 0x32, 0x8f, 0x8c, 0xfa, 0xa9, 0x72, 0xc1, 0xf5,
-	0x94, 0x33, 0xfb, 0x97, 0x96, 0xbc, 0x67, 0x30, 0x40, 0x6b, 0xa7, 0x37, 0x49, 0x58, 0x1a, 0x6b,
-	0xdd, 0x62, 0xac, 0x5d, 0x18, 0xf3, 0xbe, 0x82, 0x09, 0x5e, 0x3a, 0xe4, 0x89, 0xf4, 0x63, 0x35,
-	0x71, 0x3e, 0x83, 0x6d, 0x5c, 0x72, 0x4c, 0xb8, 0x3b, 0xb5, 0x70, 0x97, 0x01, 0xd5, 0xa7, 0xde,
-	0x6b, 0x18, 0x68, 0x36, 0xab, 0xf5, 0xfb, 0x01, 0xf4, 0x53, 0x2d, 0xd8, 0x4d, 0xaf, 0x90, 0x4b,
-	0x7b, 0xad, 0x3b, 0xed, 0xbd, 0x6b, 0xc1, 0xe0, 0x25, 0x3e, 0xc6, 0x4c, 0x2f, 0x8e, 0xa6, 0xbb,
-	0x9d, 0x66, 0x77, 0x97, 0x2b, 0x59, 0xab, 0xb9, 0x92, 0x7d, 0x0d, 0xdb, 0xb8, 0x0a, 0x62, 0x80,
-	0x93, 0x7d, 0xaf, 0x78, 0xca, 0xac, 0x5d, 0xfb, 0x6b, 0xc3, 0x12, 0x79, 0xa6, 0x34, 0xa9, 0xbe,
-	0x80, 0x1b, 0xb9, 0x3e, 0xca, 0xcc, 0x66, 0x5f, 0xc8, 0xb8, 0x75, 0x9b, 0xdf, 0xb5, 0x7f, 0x56,
-	0x1a, 0xa8, 0xb2, 0x11, 0xb1, 0x30, 0x46, 0x1a, 0xeb, 0x7f, 0x56, 0x0a, 0xb9, 0xde, 0xdd, 0xbd,
-	0x46, 0x77, 0x7b, 0x4f, 0x61, 0xda, 0x74, 0x8c, 0x8c, 0xa0, 0xbf, 0xa4, 0x27, 0xcb, 0x93, 0xd3,
-	0xe7, 0xc7, 0xd3, 0x2d, 0x02, 0xd0, 0x3d, 0x3c, 0x79, 0xf5, 0xea, 0xe8, 0x6c, 0xea, 0x78, 0xef,
-	0x1c, 0x18, 0x1c, 0xfa, 0x49, 0x14, 0x47, 0xbe, 0x64, 0x77, 0xac, 0xc9, 0xf7, 0x61, 0x5b, 0x3d,
-	0xf2, 0xc2, 0xe4, 0x49, 0x0b, 0x86, 0x49, 0x2a, 0x8a, 0x76, 0xc1, 0x24, 0xe5, 0xfd, 0x63, 0x98,
-	0x84, 0x19, 0xc3, 0xe5, 0xab, 0xb6, 0x6c, 0x35, 0x50, 0xb5, 0xbd, 0xad, 0x7d, 0x21, 0xbf, 0x4f,
-	0xd5, 0xd7, 0x8d, 0xa6, 0xde, 0xb6, 0xde, 0xc3, 0xbd, 0x03, 0x18, 0x17, 0x8e, 0x1e, 0xc7, 0x42,
-	0x92, 0x2f, 0x01, 0x42, 0x0b, 0x28, 0x7f, 0x15, 0x3f, 0xee, 0xd9, 0x2a, 0x15, 0xaa, 0xb4, 0xa2,
-	0xe4, 0xcd, 0x61, 0x78, 0xc6, 0x84, 0x5c, 0x9a, 0xff, 0x1b, 0x3f, 0x86, 0xfe, 0x46, 0xac, 0x7e,
-	0x0c, 0x78, 0x74, 0x63, 0xd6, 0xe9, 0xde, 0x46, 0xac, 0x0e, 0x78, 0x74, 0x13, 0x74, 0xd1, 0xcc,
-	0xb3, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff, 0x5a, 0xf2, 0x22, 0xac, 0xec, 0x0e, 0x00, 0x00,
+func init() { proto.RegisterFile("blockchain.proto", fileDescriptor_blockchain_2d2fd2925c4a5efb) }
+
+var fileDescriptor_blockchain_2d2fd2925c4a5efb = []byte{
+	// 1933 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0x4b, 0x6f, 0x24, 0x49,
+	0x11, 0x76, 0xf5, 0xdb, 0xd1, 0xdd, 0x76, 0x4f, 0x8e, 0x19, 0x8a, 0xd1, 0x6a, 0x65, 0x4a, 0xcb,
+	0x62, 0x2d, 0x8b, 0x01, 0x0f, 0xac, 0x40, 0x42, 0xa0, 0xb1, 0xc7, 0x52, 0x0f, 0x78, 0x77, 0x5a,
+	0x69, 0xb3, 0x1c, 0xd9, 0x7a, 0xa4, 0xdb, 0x25, 0x77, 0x57, 0x96, 0x32, 0xb3, 0xbc, 0xe3, 0x5f,
+	0xc0, 0x8d, 0x03, 0x37, 0x2e, 0x70, 0x44, 0x5c, 0x91, 0x90, 0xb8, 0x70, 0xe7, 0xc4, 0x8f, 0x40,
+	0x20, 0xf1, 0x33, 0x50, 0xe4, 0xa3, 0x5e, 0xdd, 0x36, 0x20, 0xf6, 0xd4, 0x1d, 0x5f, 0x46, 0x46,
+	0x65, 0xbc, 0x23, 0x13, 0x66, 0xd1, 0x8a, 0xc7, 0xb7, 0xf1, 0x4d, 0x98, 0x66, 0xc7, 0xb9, 0xe0,
+	0x8a, 0x93, 0x41, 0xaa, 0x7f, 0x83, 0x3f, 0x7b, 0x00, 0x57, 0x22, 0xcc, 0xe4, 0x35, 0x13, 0x8b,
+	0x88, 0x3c, 0x83, 0x41, 0xb8, 0xe6, 0x45, 0xa6, 0x7c, 0xef, 0xd0, 0x3b, 0x9a, 0x50, 0x4b, 0x21,
+	0x2e, 0x59, 0x96, 0x30, 0xe1, 0x77, 0x0e, 0xbd, 0xa3, 0x5d, 0x6a, 0x29, 0xf2, 0x0e, 0xec, 0x0a,
+	0x16, 0xa7, 0x79, 0xca, 0x32, 0xe5, 0x77, 0xf5, 0x52, 0x05, 0x10, 0x1f, 0x86, 0x79, 0x78, 0xbf,
+	0xe2, 0x61, 0xe2, 0xf7, 0xb4, 0x38, 0x47, 0x92, 0x00, 0x26, 0x46, 0xc2, 0xa2, 0x88, 0x7e, 0xca,
+	0xee, 0xfd, 0xbe, 0x5e, 0x6e, 0x60, 0xe4, 0x5d, 0x80, 0x54, 0x9e, 0xf1, 0x34, 0x8b, 0x42, 0xc9,
+	0xfc, 0xc1, 0xa1, 0x77, 0x34, 0xa2, 0x35, 0x24, 0xf8, 0x95, 0x07, 0x83, 0x4f, 0xb9, 0x62, 0x8b,
+	0x08, 0x8f, 0xa1, 0xd2, 0x35, 0x93, 0x2a, 0x5c, 0xe7, 0xfa, 0xe4, 0x3d, 0x5a, 0x01, 0x28, 0x48,
+	0xb2, 0xd5, 0xf5, 0xa2, 0x88, 0x6e, 0xd9, 0xbd, 0x56, 0x60, 0x42, 0x6b, 0x08, 0x1e, 0xe6, 0x8e,
+	0x2b, 0x26, 0x5e, 0x26, 0x89, 0x60, 0x52, 0x5a, 0x3d, 0x1a, 0x98, 0xe3, 0x61, 0x8e, 0xa7, 0x57,
+	0xf1, 0x38, 0x2c, 0xf8, 0x8d, 0x07, 0xe3, 0xf3, 0xb7, 0x2c, 0x2e, 0x54, 0xca, 0xb3, 0x47, 0x8c,
+	0xf9, 0x1c, 0x46, 0x4c, 0xb3, 0x71, 0x67, 0xce, 0x92, 0xc6, 0xb5, 0x98, 0x67, 0x4a, 0x84, 0xb1,
+	0xb3, 0x67, 0x49, 0x93, 0xf7, 0x61, 0xcf, 0xf1, 0x59, 0xb3, 0x19, 0xab, 0xb6, 0x50, 0x42, 0xa0,
+	0x97, 0x84, 0x2a, 0xb4, 0x46, 0xd5, 0xff, 0x83, 0xcf, 0x60, 0x76, 0xc9, 0x62, 0xc1, 0xd4, 0x42,
+	0xf0, 0x9c, 0xcb, 0x70, 0x65, 0xce, 0x67, 0x9d, 0xea, 0x3d, 0xec, 0xd4, 0x4e, 0xdb, 0xa9, 0x7a,
+	0x17, 0x4a, 0xf2, 0xbb, 0x87, 0xdd, 0xa3, 0x29, 0xb5, 0x54, 0x70, 0x06, 0xfb, 0xe6, 0x0b, 0x3f,
+	0x4f, 0x55, 0xc6, 0xa4, 0x7c, 0xe4, 0x03, 0x3e, 0x0c, 0x3f, 0x37, 0x4c, 0x7e, 0xe7, 0xb0, 0x8b,
+	0x71, 0x61, 0xc9, 0xe0, 0x2f, 0x1e, 0xf4, 0x2f, 0xf8, 0x72, 0x11, 0x21, 0x4f, 0x68, 0x6d, 0x6d,
+	0x36, 0x3b, 0x12, 0xa5, 0x2a, 0x9e, 0xa7, 0xb1, 0xdb, 0x6c, 0xa9, 0x52, 0xed, 0x6e, 0xa5, 0x36,
+	0x39, 0x84, 0xb1, 0x0e, 0xfd, 0x4f, 0x8a, 0x75, 0xc4, 0x84, 0xb6, 0x57, 0x8f, 0xd6, 0x21, 0xfc,
+	0x8e, 0x7a, 0x9b, 0xcd, 0x43, 0x79, 0x63, 0xed, 0xe5, 0x48, 0x34, 0x83, 0x66, 0xd4, 0x6b, 0x03,
+	0xbd, 0x56, 0x01, 0xe4, 0x00, 0xfa, 0x69, 0x96, 0xb0, 0xb7, 0xfe, 0xf0, 0xd0, 0x3b, 0x9a, 0x52,
+	0x43, 0x04, 0x7f, 0xf5, 0x60, 0x97, 0xb2, 0x98, 0xa5, 0xb9, 0x5a, 0x44, 0xf8, 0x75, 0xc1, 0x54,
+	0x21, 0xb2, 0x4f, 0xc3, 0x55, 0xc1, 0x6c, 0x14, 0xd4, 0x21, 0x6d, 0x21, 0x15, 0xaa, 0x42, 0x6a,
+	0x3b, 0xf7, 0xa8, 0xa5, 0x50, 0x97, 0x1b, 0xfc, 0xac, 0xd5, 0x05, 0xff, 0xa3, 0xb4, 0x65, 0x28,
+	0xcf, 0x78, 0x26, 0x8b, 0x35, 0x4b, 0x9c, 0x2e, 0x35, 0x88, 0x1c, 0xc1, 0xbe, 0x0b, 0x16, 0x17,
+	0xa7, 0x7d, 0x6d, 0xbb, 0x36, 0x4c, 0xbe, 0x0a, 0xbd, 0x15, 0x5f, 0x4a, 0x7f, 0x70, 0xd8, 0x3d,
+	0x1a, 0x9f, 0x4c, 0x8f, 0x4d, 0x35, 0x38, 0xd6, 0xa6, 0xa7, 0x7a, 0x29, 0xf8, 0x5d, 0x07, 0xf6,
+	0x2f, 0x55, 0x28, 0xd4, 0x65, 0x11, 0x9d, 0x61, 0xe5, 0x30, 0x4e, 0xd1, 0x45, 0xe4, 0xf5, 0x2b,
+	0xad, 0xcc, 0x94, 0x3a, 0x12, 0x3f, 0x2d, 0x59, 0x5c, 0x88, 0x54, 0xdd, 0xbf, 0x62, 0x39, 0x97,
+	0xa9, 0xb2, 0x89, 0xd6, 0x86, 0xc9, 0x07, 0x30, 0xe3, 0x39, 0x13, 0x21, 0x26, 0x89, 0x63, 0x35,
+	0x6a, 0x6e, 0xe0, 0xa8, 0xb2, 0xc4, 0x23, 0xcc, 0x59, 0xba, 0xbc, 0x51, 0x4e, 0xe5, 0x1a, 0x44,
+	0x8e, 0x81, 0xe4, 0xa1, 0x60, 0x99, 0xa5, 0xdf, 0x5c, 0x5f, 0x4b, 0xa6, 0xb4, 0xd6, 0x3d, 0xba,
+	0x65, 0x05, 0xf3, 0x98, 0x7f, 0x9e, 0x55, 0xb9, 0x3e, 0x30, 0x79, 0x5c, 0xc7, 0x30, 0xcf, 0x34,
+	0xbd, 0x28, 0xa2, 0x55, 0x1a, 0x63, 0x9e, 0x0d, 0x4d, 0x9e, 0x35, 0xd1, 0xe0, 0x8f, 0x1e, 0xec,
+	0x5d, 0x2a, 0x9e, 0xff, 0x57, 0x06, 0xc2, 0x22, 0xa4, 0x78, 0x6e, 0x35, 0x31, 0xde, 0xae, 0x21,
+	0x18, 0x4f, 0x5a, 0xbc, 0xcd, 0x7a, 0x43, 0x6c, 0x39, 0x4a, 0x6f, 0xdb, 0x51, 0xb4, 0xf9, 0xed,
+	0x29, 0x5a, 0x9e, 0x6f, 0xc1, 0xc1, 0x6f, 0x3b, 0x00, 0x8b, 0x42, 0x9d, 0x62, 0x20, 0x3f, 0x7a,
+	0xe0, 0x67, 0x30, 0xb8, 0xa9, 0x1f, 0xd6, 0x52, 0x5b, 0x43, 0xf3, 0x5d, 0x80, 0x30, 0x46, 0xc7,
+	0x51, 0xce, 0x95, 0x3d, 0x62, 0x0d, 0xc1, 0x54, 0xc2, 0xc0, 0x66, 0x7a, 0xd9, 0xa4, 0x59, 0x05,
+	0x90, 0x0f, 0xe1, 0x49, 0x2e, 0x78, 0x52, 0xc4, 0x75, 0x3d, 0x4d, 0xc2, 0x6d, 0x2e, 0xa0, 0xc7,
+	0x59, 0x96, 0x70, 0x21, 0x79, 0x05, 0x4a, 0x7f, 0xa8, 0x4b, 0xc1, 0x96, 0x95, 0x3a, 0xff, 0x65,
+	0xba, 0xcc, 0x42, 0x55, 0x08, 0x26, 0xfd, 0x51, 0x93, 0xbf, 0x5a, 0x09, 0xfe, 0xd5, 0x81, 0x83,
+	0x57, 0xbc, 0x88, 0x56, 0x0c, 0xc1, 0xf3, 0xbb, 0x34, 0x61, 0x59, 0x8c, 0x4d, 0xe6, 0x39, 0x8c,
+	0x04, 0xcb, 0xb9, 0x50, 0x65, 0x3d, 0x2b, 0x69, 0xf4, 0x93, 0xfb, 0x6f, 0x4b, 0xb3, 0x89, 0xfe,
+	0x16, 0x8a, 0x32, 0xf8, 0xf5, 0xb5, 0xa9, 0x89, 0xb6, 0xbc, 0x3b, 0x9a, 0x7c, 0x03, 0x86, 0xe6,
+	0x38, 0x4c, 0x5b, 0x70, 0x7c, 0xf2, 0xc4, 0xa5, 0xe5, 0xb9, 0x81, 0x17, 0x11, 0x75, 0x1c, 0xe4,
+	0x25, 0x90, 0x98, 0x67, 0xd7, 0xab, 0x34, 0x56, 0x69, 0xb6, 0xb4, 0x0c, 0x7e, 0xff, 0xa1, 0x7d,
+	0x5b, 0x98, 0xc9, 0x77, 0x61, 0x94, 0xdb, 0x66, 0xa0, 0xad, 0x3d, 0x3e, 0xf1, 0xdd, 0x46, 0xd4,
+	0x9c, 0x25, 0x55, 0xab, 0xa0, 0x25, 0x27, 0xf9, 0x09, 0x3c, 0xad, 0xc9, 0x72, 0x2c, 0xfe, 0xf0,
+	0x3f, 0x08, 0xd8, 0xb6, 0x29, 0xb8, 0x83, 0x89, 0x66, 0x14, 0xb6, 0x8d, 0x1f, 0x40, 0xff, 0x8e,
+	0x57, 0xe6, 0x35, 0x04, 0x16, 0x81, 0x3b, 0x5e, 0x9a, 0xd0, 0x1a, 0xb6, 0x0e, 0x61, 0x78, 0xc5,
+	0x61, 0x96, 0xa4, 0x49, 0xa8, 0x98, 0x9b, 0x42, 0x4a, 0x80, 0xcc, 0xa0, 0x1b, 0x26, 0xa6, 0x5e,
+	0x8e, 0x28, 0xfe, 0x0d, 0xfe, 0xd9, 0x87, 0xd1, 0xcb, 0xd8, 0x76, 0x69, 0x1f, 0x86, 0x77, 0x4c,
+	0xc8, 0x94, 0x67, 0x2e, 0x03, 0x2c, 0x89, 0xc7, 0xc9, 0x78, 0x16, 0x33, 0x9b, 0x00, 0x86, 0x40,
+	0x17, 0x2e, 0x43, 0x79, 0x91, 0xae, 0x6d, 0xdd, 0xea, 0xd1, 0x92, 0xb6, 0x6b, 0x0b, 0x91, 0xc6,
+	0xcc, 0x66, 0x41, 0x49, 0xeb, 0x1c, 0x70, 0x51, 0x56, 0xe6, 0x80, 0x03, 0xc8, 0xb7, 0x61, 0xa4,
+	0xec, 0x18, 0xe6, 0x83, 0xb6, 0x25, 0x71, 0xb6, 0xac, 0xc6, 0xb3, 0xf9, 0x0e, 0x2d, 0xb9, 0xc8,
+	0x7b, 0xd0, 0x43, 0x1b, 0xf8, 0x63, 0xcd, 0xbd, 0xe7, 0xb8, 0x8d, 0x29, 0xe7, 0x3b, 0x54, 0xaf,
+	0x92, 0x17, 0xb0, 0xcb, 0xdc, 0x48, 0xe2, 0x4f, 0x34, 0xeb, 0xd3, 0x32, 0x3c, 0xaa, 0x59, 0x65,
+	0xbe, 0x43, 0x2b, 0x3e, 0x72, 0x0a, 0x7b, 0xb2, 0x31, 0x2c, 0xf8, 0xd3, 0x96, 0x7b, 0x5b, 0xa3,
+	0xc4, 0x7c, 0x87, 0xb6, 0x76, 0x90, 0x1f, 0xc3, 0x54, 0xd6, 0xc7, 0x01, 0x7f, 0x4f, 0x8b, 0xf8,
+	0x72, 0x53, 0x44, 0x39, 0x2b, 0xcc, 0x77, 0x68, 0x93, 0x5f, 0x0b, 0xa8, 0xb7, 0x1f, 0x7f, 0xbf,
+	0x25, 0xa0, 0xd9, 0x9b, 0xb4, 0x80, 0x3a, 0x44, 0x7e, 0x08, 0x13, 0x59, 0xab, 0xce, 0xfe, 0x4c,
+	0xef, 0x7f, 0x56, 0xed, 0xaf, 0x57, 0xee, 0xf9, 0x0e, 0x6d, 0x70, 0xa3, 0x43, 0x72, 0x5b, 0x26,
+	0xfd, 0x27, 0x4d, 0x87, 0x54, 0xe5, 0x13, 0x1d, 0xe2, 0xb8, 0xc8, 0x27, 0x40, 0x92, 0x8d, 0xba,
+	0xe1, 0x13, 0xbd, 0xf7, 0x1d, 0xb7, 0x77, 0x5b, 0x65, 0x99, 0xef, 0xd0, 0x2d, 0x3b, 0xc9, 0x47,
+	0x00, 0xb2, 0xcc, 0x0e, 0xff, 0xa9, 0x96, 0x73, 0xd0, 0x48, 0x30, 0x51, 0x3a, 0xbb, 0xc6, 0x79,
+	0x3a, 0x82, 0x81, 0x29, 0xbd, 0xc1, 0x9f, 0xba, 0x30, 0xd5, 0x67, 0x9b, 0xb3, 0x30, 0x61, 0xe2,
+	0xd1, 0x60, 0xaf, 0x35, 0x82, 0xce, 0x43, 0x8d, 0xa0, 0xdb, 0x68, 0x04, 0x8d, 0xa1, 0xbb, 0xd7,
+	0x1e, 0xba, 0xdf, 0x83, 0x69, 0x2e, 0xd8, 0xdd, 0x69, 0x39, 0x41, 0x99, 0x90, 0x6f, 0x82, 0x28,
+	0x5b, 0xbd, 0xd5, 0x5d, 0xc1, 0xd4, 0x7b, 0x4b, 0x35, 0x1b, 0xc6, 0xb0, 0xdd, 0x30, 0xf4, 0x5c,
+	0xa5, 0x87, 0x2c, 0xbd, 0x3e, 0x72, 0x73, 0x55, 0x09, 0x99, 0x5a, 0x2d, 0x99, 0xb8, 0x63, 0x89,
+	0xbf, 0x6b, 0x12, 0xd1, 0xd1, 0xcd, 0x44, 0x84, 0x76, 0x22, 0x3e, 0x83, 0x41, 0x6e, 0x2e, 0x0a,
+	0x63, 0x73, 0x22, 0x43, 0x61, 0x31, 0x48, 0x6e, 0x97, 0xaf, 0x5f, 0xe9, 0x24, 0x9a, 0x50, 0x43,
+	0xa0, 0xac, 0xe4, 0x76, 0x69, 0x6f, 0x16, 0x53, 0x23, 0xab, 0x04, 0x70, 0xd8, 0x48, 0x6e, 0x97,
+	0x65, 0x6f, 0xd1, 0x29, 0x30, 0xa1, 0x0d, 0x0c, 0xdb, 0xa9, 0x64, 0x2c, 0xd1, 0xd1, 0x3d, 0xa1,
+	0xfa, 0x7f, 0xf0, 0x77, 0x0f, 0x86, 0xae, 0x41, 0x7f, 0x13, 0xad, 0x1f, 0xba, 0x19, 0x7a, 0x7c,
+	0xf2, 0x25, 0x17, 0x01, 0x0d, 0xc7, 0x52, 0xcb, 0x44, 0x3e, 0x80, 0xa1, 0x71, 0xbe, 0x99, 0x8e,
+	0xc7, 0x27, 0x33, 0xc7, 0xef, 0x0a, 0x1e, 0x75, 0x0c, 0xe4, 0x7b, 0x30, 0xb1, 0xed, 0x64, 0xcd,
+	0x32, 0x25, 0xf5, 0x3c, 0xbf, 0xb5, 0x7b, 0x34, 0xd8, 0xc8, 0x39, 0x1c, 0x84, 0xcb, 0xa5, 0x60,
+	0xcb, 0x50, 0xb1, 0xf3, 0x6a, 0xe1, 0xe1, 0xa6, 0xb5, 0x95, 0x3d, 0xb8, 0x00, 0xd0, 0x2a, 0xbc,
+	0xc6, 0xc1, 0x19, 0xcd, 0xab, 0xb3, 0xd7, 0xde, 0xde, 0x0c, 0x81, 0xa5, 0x9b, 0x65, 0x89, 0xad,
+	0xbf, 0xf8, 0x17, 0xdd, 0xc3, 0xcd, 0x8c, 0x67, 0x6f, 0x1f, 0x86, 0x0a, 0x5e, 0xc0, 0xae, 0x96,
+	0x76, 0x79, 0x9f, 0xc5, 0x95, 0xb0, 0xce, 0x16, 0x61, 0xdd, 0x52, 0x58, 0xf0, 0x19, 0xec, 0xe9,
+	0x4d, 0x67, 0x3c, 0x53, 0x61, 0x8a, 0xf3, 0xd6, 0xd7, 0xa0, 0xaf, 0x47, 0x7c, 0x6b, 0xec, 0xfd,
+	0x86, 0xb1, 0x17, 0x11, 0x35, 0xab, 0xe4, 0xeb, 0x30, 0xd0, 0x7f, 0x9c, 0x91, 0x37, 0xf8, 0xec,
+	0x72, 0xf0, 0x11, 0x8c, 0x74, 0x39, 0xb9, 0x4a, 0xf3, 0x5a, 0x1e, 0x79, 0x5b, 0x07, 0xaa, 0x4e,
+	0x35, 0x50, 0x05, 0x3f, 0x80, 0xfd, 0x9a, 0x7f, 0x9b, 0x4a, 0x3d, 0x6e, 0xa1, 0xe0, 0x97, 0x1e,
+	0x1c, 0xd4, 0xf6, 0x56, 0xba, 0x7d, 0x0b, 0x86, 0x26, 0x48, 0xf0, 0x46, 0xd5, 0x7d, 0x38, 0x94,
+	0x1c, 0x17, 0xf9, 0x11, 0x4c, 0x62, 0x26, 0x54, 0x7a, 0x9d, 0xc6, 0xa1, 0x62, 0x4e, 0xd7, 0xe7,
+	0x8d, 0x5d, 0x67, 0x15, 0x03, 0x06, 0x4a, 0x9d, 0x3f, 0xf8, 0xb5, 0x07, 0x64, 0x93, 0x69, 0x23,
+	0xec, 0xbc, 0xff, 0x2f, 0xec, 0x3a, 0xff, 0x5b, 0xd8, 0xfd, 0xcd, 0x83, 0x5d, 0xd3, 0xa4, 0xec,
+	0x4c, 0x67, 0x26, 0x9b, 0x6a, 0xa6, 0x73, 0x74, 0x15, 0x0b, 0x9d, 0x47, 0x63, 0xe1, 0x00, 0xfa,
+	0x82, 0x17, 0x36, 0xb0, 0xa6, 0xd4, 0x10, 0x98, 0xfa, 0x4e, 0x10, 0x4e, 0xde, 0xee, 0xbd, 0xa0,
+	0x8e, 0xe1, 0xd0, 0xe8, 0xe8, 0xc6, 0x33, 0x48, 0x0b, 0x6d, 0x16, 0xac, 0x41, 0xab, 0x60, 0x05,
+	0x7f, 0xe8, 0xc2, 0x6e, 0xa9, 0xf4, 0x83, 0x41, 0xd6, 0xb8, 0xcc, 0x76, 0xda, 0x97, 0xd9, 0xef,
+	0x43, 0x5f, 0x5f, 0xa2, 0xb5, 0x0e, 0x7b, 0x27, 0xc1, 0x86, 0x31, 0x8f, 0x6b, 0x26, 0xbc, 0x42,
+	0x4e, 0x6a, 0x36, 0xa0, 0x01, 0xad, 0x97, 0x9c, 0x8e, 0x25, 0x8d, 0xfa, 0xb9, 0xff, 0x4d, 0xfd,
+	0x9a, 0x28, 0xca, 0x48, 0x58, 0x9c, 0xea, 0xae, 0x64, 0x9e, 0x79, 0x4a, 0xba, 0xa9, 0xfb, 0xb0,
+	0x5d, 0xac, 0x03, 0x98, 0x44, 0x2b, 0x59, 0x15, 0x58, 0xd3, 0x09, 0x1a, 0x18, 0x5a, 0x24, 0x4a,
+	0xd5, 0x3a, 0xcc, 0x6d, 0x23, 0xb0, 0x14, 0xde, 0x0b, 0xca, 0x00, 0xb9, 0x6c, 0xf5, 0x83, 0x2d,
+	0x2b, 0x95, 0x9f, 0xc7, 0x35, 0x3f, 0x07, 0x1f, 0xc2, 0xac, 0x6d, 0x1a, 0x32, 0x81, 0xd1, 0x82,
+	0xbe, 0x59, 0xbc, 0xb9, 0x7c, 0x79, 0x31, 0xdb, 0x21, 0x00, 0x83, 0xb3, 0x37, 0x1f, 0x7f, 0xfc,
+	0xfa, 0x6a, 0xe6, 0x05, 0xff, 0xf0, 0x60, 0xd6, 0x1e, 0x8d, 0x1f, 0x74, 0x59, 0xf9, 0xc1, 0x4e,
+	0x3d, 0xb0, 0x1a, 0x8e, 0xec, 0xb6, 0x1d, 0x59, 0x8f, 0xe7, 0x5e, 0x2b, 0x9e, 0xbf, 0x90, 0x70,
+	0xdb, 0x08, 0xec, 0xe1, 0x66, 0x60, 0x07, 0xbf, 0xf7, 0x60, 0xf7, 0xac, 0x9c, 0xbf, 0x1f, 0x7e,
+	0xc9, 0xb1, 0xf3, 0xbe, 0xb4, 0x01, 0x69, 0x08, 0xdb, 0x81, 0xf1, 0x7c, 0xdd, 0xb2, 0x03, 0xe3,
+	0xb9, 0xde, 0x87, 0xbd, 0x58, 0x30, 0xfd, 0x3e, 0xd0, 0x78, 0x0f, 0x68, 0xa1, 0xf8, 0xc0, 0xb0,
+	0x0a, 0xa5, 0xfa, 0x59, 0x8e, 0x5f, 0xb7, 0x9c, 0xe6, 0x41, 0x60, 0x03, 0x0f, 0x4e, 0x61, 0x5a,
+	0x1e, 0xf4, 0x22, 0x95, 0x8a, 0x7c, 0x07, 0xa0, 0xbc, 0x39, 0x6c, 0x94, 0xa6, 0x92, 0x95, 0xd6,
+	0x98, 0x82, 0x23, 0x18, 0x5f, 0x31, 0xa9, 0x16, 0xf6, 0x69, 0xf3, 0x2b, 0x30, 0x5a, 0xcb, 0xe5,
+	0x2f, 0x22, 0x9e, 0xdc, 0xdb, 0x17, 0x9f, 0xe1, 0x5a, 0x2e, 0x4f, 0x79, 0x72, 0x1f, 0x0d, 0xb4,
+	0x98, 0x17, 0xff, 0x1e, 0x00, 0xe2, 0xc2, 0x7c, 0x06, 0x8f, 0x15, 0x00, 0x00,
 }
@@ -34,6 +34,12 @@ const (
 	MsgProposeProtoMsgType uint32 = 6
 	// MsgEndorseProtoMsgType is for consensus endorse
 	MsgEndorseProtoMsgType uint32 = 7
+	// MsgChainTipType is for the tips of the chains which the peers advertise
+	MsgChainTipType uint32 = 8
+	// MsgBlockHeaderSyncReqType is for requests among peers to sync block headers
+	MsgBlockHeaderSyncReqType uint32 = 9
+	// MsgBlockHeaderSyncDataType is the response to messages of type MsgBlockHeaderSyncReqType
+	MsgBlockHeaderSyncDataType uint32 = 10
 	// TestPayloadType is a test payload message type
 	TestPayloadType uint32 = 10001
 )
@@ -47,6 +53,12 @@ func GetTypeFromProtoMsg(msg proto.Message) (uint32, error) {
 		return MsgBlockSyncReqType, nil
 	case *BlockContainer:
 		return MsgBlockSyncDataType, nil
+	case *ChainTip:
+		return MsgChainTipType, nil
+	case *BlockHeaderSync:
+		return MsgBlockHeaderSyncReqType, nil
+	case *BlockHeaderContainer:
+		return MsgBlockHeaderSyncDataType, nil
 	case *ActionPb:
 		return MsgActionType, nil
 	case *TestPayload:
@@ -74,6 +86,12 @@ func TypifyProtoMsg(tp uint32, msg []byte) (proto.Message, error) {
 		m = &BlockSync{}
 	case MsgBlockSyncDataType:
 		m = &BlockContainer{}
+	case MsgChainTipType:
+		m = &ChainTip{}
+	case MsgBlockHeaderSyncReqType:
+		m = &BlockHeaderSync{}
+	case MsgBlockHeaderSyncDataType:
+		m = &BlockHeaderContainer{}
 	case MsgActionType:
 		m = &ActionPb{}
 	case TestPayloadType:
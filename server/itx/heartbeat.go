@@ -75,10 +75,14 @@ func (h *HeartbeatHandler) Log() {
 		logger.Error().Msg("dispatcher is not the instance of IotxDispatcher")
 		return
 	}
-	numDPEvts := len(*dp.EventChan())
-	dpEvtsAudit, err := json.Marshal(dp.EventAudit())
+	dpEvts := dp.PendingEvents()
+	numDPEvts := 0
+	for _, n := range dpEvts {
+		numDPEvts += n
+	}
+	dpEvtsByLane, err := json.Marshal(dpEvts)
 	if err != nil {
-		logger.Error().Msg("error when serializing the dispatcher event audit map")
+		logger.Error().Msg("error when serializing the dispatcher pending events")
 		return
 	}
 
@@ -87,13 +91,13 @@ func (h *HeartbeatHandler) Log() {
 		Time("lastOut", lastOutTime).
 		Time("lastIn", lastInTime).
 		Int("pendingDispatcherEvents", numDPEvts).
-		Str("pendingDispatcherEventsAudit", string(dpEvtsAudit)).
+		Str("pendingDispatcherEventsByLane", string(dpEvtsByLane)).
 		Msg("node status")
 
 	heartbeatMtc.WithLabelValues("numPeers", "node").Set(float64(numPeers))
 	heartbeatMtc.WithLabelValues("pendingDispatcherEvents", "node").Set(float64(numDPEvts))
 	// chain service
-	for _, c := range h.s.chainservices {
+	for _, c := range h.s.chainServices() {
 		// Consensus metrics
 		cs, ok := c.Consensus().(*consensus.IotxConsensus)
 		if !ok {
@@ -130,6 +134,23 @@ func (h *HeartbeatHandler) Log() {
 		heartbeatMtc.WithLabelValues("blockchainHeight", chainIDStr).Set(float64(height))
 		heartbeatMtc.WithLabelValues("actpoolSize", chainIDStr).Set(float64(actPoolSize))
 		heartbeatMtc.WithLabelValues("actpoolCapacity", chainIDStr).Set(float64(actPoolCapacity))
+
+		// Block sync metrics
+		sync := c.BlockSync().SyncStatus()
+		failures := 0
+		for _, p := range sync.Peers {
+			failures += p.Failures
+		}
+		logger.Info().
+			Bool("synced", sync.Synced).
+			Uint64("targetHeight", sync.TargetHeight).
+			Uint64("confirmedHeight", sync.ConfirmedHeight).
+			Float64("blocksPerSecond", sync.BlocksPerSecond).
+			Dur("eta", sync.ETA).
+			Int("numSyncPeers", len(sync.Peers)).
+			Int("syncFailures", failures).
+			Uint32("chainID", c.ChainID()).
+			Msg("block sync status")
 	}
 
 }
@@ -10,6 +10,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sync"
 
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -25,10 +26,12 @@ import (
 
 // Server is the iotex server instance containing all components.
 type Server struct {
-	chainservices map[uint32]*chainservice.ChainService
-	p2p           network.Overlay
-	dispatcher    dispatcher.Dispatcher
-	rootChainAPI  explorer.Explorer
+	chainservices   map[uint32]*chainservice.ChainService
+	chainservicesMU sync.RWMutex
+	p2p             network.Overlay
+	dispatcher      dispatcher.Dispatcher
+	rootChainAPI    explorer.Explorer
+	subChainManager *subChainManager
 }
 
 // NewServer creates a new server
@@ -44,7 +47,25 @@ func NewInMemTestServer(cfg *config.Config) (*Server, error) {
 
 func newServer(cfg *config.Config, testing bool) (*Server, error) {
 	// create P2P network and BlockSync
-	p2p := network.NewOverlay(&cfg.Network)
+	netCfg := cfg.Network
+	if netCfg.NodeKeyPath == "" {
+		netCfg.NodeKeyPath = cfg.DataFilePath("node.key")
+	}
+	if cfg.Consensus.RollDPoS.StateDBPath == "" && !testing {
+		// The consensus state of the nodes sharing a directory must not be shared
+		copied := *cfg
+		copied.Consensus.RollDPoS.StateDBPath = cfg.DataFilePath("rolldpos.db")
+		cfg = &copied
+	}
+	if cfg.Consensus.PoA.CheckpointDBPath == "" && !testing {
+		copied := *cfg
+		copied.Consensus.PoA.CheckpointDBPath = cfg.DataFilePath("poa.db")
+		cfg = &copied
+	}
+	p2p, err := network.NewOverlay(&netCfg, cfg.Chain.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to create p2p network")
+	}
 
 	// create dispatcher instance
 	dispatcher, err := dispatcher.NewDispatcher(cfg)
@@ -68,17 +89,21 @@ func newServer(cfg *config.Config, testing bool) (*Server, error) {
 
 	chains[cs.ChainID()] = cs
 	dispatcher.AddSubscriber(cs.ChainID(), cs)
-	return &Server{
+	svr := &Server{
 		p2p:           p2p,
 		dispatcher:    dispatcher,
 		rootChainAPI:  cs.Explorer().Explorer(),
 		chainservices: chains,
-	}, nil
+	}
+	if cfg.SubChain.Enabled {
+		svr.subChainManager = newSubChainManager(svr, cfg, cs.Blockchain())
+	}
+	return svr, nil
 }
 
 // Start starts the server
 func (s *Server) Start(ctx context.Context) error {
-	for _, cs := range s.chainservices {
+	for _, cs := range s.chainServices() {
 		if err := cs.Start(ctx); err != nil {
 			return errors.Wrap(err, "error when stopping blockchain")
 		}
@@ -89,18 +114,28 @@ func (s *Server) Start(ctx context.Context) error {
 	if err := s.p2p.Start(ctx); err != nil {
 		return errors.Wrap(err, "error when starting P2P networks")
 	}
+	if s.subChainManager != nil {
+		if err := s.subChainManager.Start(ctx); err != nil {
+			return errors.Wrap(err, "error when starting sub-chain manager")
+		}
+	}
 	return nil
 }
 
 // Stop stops the server
 func (s *Server) Stop(ctx context.Context) error {
+	if s.subChainManager != nil {
+		if err := s.subChainManager.Stop(ctx); err != nil {
+			return errors.Wrap(err, "error when stopping sub-chain manager")
+		}
+	}
 	if err := s.p2p.Stop(ctx); err != nil {
 		return errors.Wrap(err, "error when stopping P2P networks")
 	}
 	if err := s.dispatcher.Stop(ctx); err != nil {
 		return errors.Wrap(err, "error when stopping dispatcher")
 	}
-	for _, cs := range s.chainservices {
+	for _, cs := range s.chainServices() {
 		if err := cs.Stop(ctx); err != nil {
 			return errors.Wrap(err, "error when stopping blockchain")
 		}
@@ -115,8 +150,7 @@ func (s *Server) NewChainService(cfg *config.Config) error {
 	if err != nil {
 		return err
 	}
-	s.chainservices[cs.ChainID()] = cs
-	s.dispatcher.AddSubscriber(cs.ChainID(), cs)
+	s.addChainService(cs)
 	return nil
 }
 
@@ -130,15 +164,14 @@ func (s *Server) NewTestingChainService(cfg *config.Config) error {
 	if err != nil {
 		return err
 	}
-	s.chainservices[cs.ChainID()] = cs
-	s.dispatcher.AddSubscriber(cs.ChainID(), cs)
+	s.addChainService(cs)
 	return nil
 }
 
 // StartChainService starts the chain service run in the server.
 func (s *Server) StartChainService(ctx context.Context, id uint32) error {
-	c, ok := s.chainservices[id]
-	if !ok {
+	c := s.ChainService(id)
+	if c == nil {
 		return errors.New("Chain ID does not match any existing chains")
 	}
 	return c.Start(ctx)
@@ -146,8 +179,8 @@ func (s *Server) StartChainService(ctx context.Context, id uint32) error {
 
 // StopChainService stops the chain service run in the server.
 func (s *Server) StopChainService(ctx context.Context, id uint32) error {
-	c, ok := s.chainservices[id]
-	if !ok {
+	c := s.ChainService(id)
+	if c == nil {
 		return errors.New("Chain ID does not match any existing chains")
 	}
 	return c.Stop(ctx)
@@ -159,7 +192,36 @@ func (s *Server) P2P() network.Overlay {
 }
 
 // ChainService returns the chainservice hold in Server with given id.
-func (s *Server) ChainService(id uint32) *chainservice.ChainService { return s.chainservices[id] }
+func (s *Server) ChainService(id uint32) *chainservice.ChainService {
+	s.chainservicesMU.RLock()
+	defer s.chainservicesMU.RUnlock()
+	return s.chainservices[id]
+}
+
+// chainServices returns a snapshot of all the chainservices hold in Server
+func (s *Server) chainServices() []*chainservice.ChainService {
+	s.chainservicesMU.RLock()
+	defer s.chainservicesMU.RUnlock()
+	chains := make([]*chainservice.ChainService, 0, len(s.chainservices))
+	for _, cs := range s.chainservices {
+		chains = append(chains, cs)
+	}
+	return chains
+}
+
+func (s *Server) addChainService(cs *chainservice.ChainService) {
+	s.chainservicesMU.Lock()
+	defer s.chainservicesMU.Unlock()
+	s.chainservices[cs.ChainID()] = cs
+	s.dispatcher.AddSubscriber(cs.ChainID(), cs)
+}
+
+func (s *Server) removeChainService(id uint32) {
+	s.chainservicesMU.Lock()
+	defer s.chainservicesMU.Unlock()
+	delete(s.chainservices, id)
+	s.dispatcher.RemoveSubscriber(id)
+}
 
 // Dispatcher returns the Dispatcher
 func (s *Server) Dispatcher() dispatcher.Dispatcher {
@@ -0,0 +1,267 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package itx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action/subchain"
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/logger"
+)
+
+// blockChSize is the size of the buffer of the root chain block notifications. The blocks are read from the chain, so
+// the notifications only trigger the processing, and the buffer lets the senders return without waiting for it.
+const blockChSize = 64
+
+// subChainManager watches the root chain for the start and stop sub-chain records, and creates, starts and stops the
+// matching chain services in the server accordingly
+type subChainManager struct {
+	s         *Server
+	cfg       config.SubChain
+	rootChain blockchain.Blockchain
+	// reservedPorts are the ports used by the root chain, which are never allocated to the sub-chain explorers
+	reservedPorts map[int]bool
+	// ports are the explorer ports allocated to the running sub-chains
+	ports map[uint32]int
+	// height is the root chain height which has been processed
+	height uint64
+	// starts are the sub-chains waiting for the root chain to reach their start heights
+	starts map[uint32]*subchain.StartSubChain
+	// stops are the stop heights of the sub-chains waiting for the root chain to reach them
+	stops   map[uint32]uint64
+	blockCh chan *blockchain.Block
+	quit    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// newSubChainManager creates a sub-chain manager watching the given root chain
+func newSubChainManager(s *Server, cfg *config.Config, rootChain blockchain.Blockchain) *subChainManager {
+	reservedPorts := make(map[int]bool)
+	for _, port := range []int{
+		cfg.Network.Port,
+		cfg.Explorer.Port,
+		cfg.System.HTTPMetricsPort,
+		cfg.System.HTTPProfilingPort,
+	} {
+		if port > 0 {
+			reservedPorts[port] = true
+		}
+	}
+	return &subChainManager{
+		s:             s,
+		cfg:           cfg.SubChain,
+		rootChain:     rootChain,
+		reservedPorts: reservedPorts,
+		ports:         make(map[uint32]int),
+		starts:        make(map[uint32]*subchain.StartSubChain),
+		stops:         make(map[uint32]uint64),
+		blockCh:       make(chan *blockchain.Block, blockChSize),
+		quit:          make(chan struct{}),
+	}
+}
+
+// Start catches up with the sub-chain records already on the root chain and then follows the new blocks
+func (m *subChainManager) Start(ctx context.Context) error {
+	if err := m.rootChain.SubscribeBlockCreation(m.blockCh); err != nil {
+		return errors.Wrap(err, "error when subscribing to root chain block creation")
+	}
+	if err := m.catchUp(ctx); err != nil {
+		return errors.Wrap(err, "error when catching up with sub-chain records on root chain")
+	}
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		for {
+			select {
+			case <-m.blockCh:
+				m.drain()
+				if err := m.catchUp(ctx); err != nil {
+					logger.Error().Err(err).Msg("error when processing sub-chain records on root chain")
+				}
+			case <-m.quit:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops following the root chain. The sub-chain services are stopped together with the server.
+func (m *subChainManager) Stop(_ context.Context) error {
+	if err := m.rootChain.UnSubscribeBlockCreation(m.blockCh); err != nil {
+		return errors.Wrap(err, "error when unsubscribing from root chain block creation")
+	}
+	close(m.quit)
+	m.wg.Wait()
+	// Release the notifications sent before unsubscribing
+	m.drain()
+	return nil
+}
+
+// drain discards the pending block notifications, which are all covered by the next catch-up
+func (m *subChainManager) drain() {
+	for {
+		select {
+		case <-m.blockCh:
+		default:
+			return
+		}
+	}
+}
+
+// catchUp processes the root chain blocks from the last processed height to the tip. Blocks are read from the chain
+// instead of being taken from the subscription, because the notifications are not guaranteed to arrive in order.
+func (m *subChainManager) catchUp(ctx context.Context) error {
+	tip := m.rootChain.TipHeight()
+	for m.height < tip {
+		blk, err := m.rootChain.GetBlockByHeight(m.height + 1)
+		if err != nil {
+			return errors.Wrapf(err, "error when getting root chain block at height %d", m.height+1)
+		}
+		m.handleBlock(ctx, blk)
+		m.height++
+	}
+	return nil
+}
+
+// handleBlock collects the sub-chain records in the block, and then starts or stops the sub-chains whose start or stop
+// height has been reached
+func (m *subChainManager) handleBlock(ctx context.Context, blk *blockchain.Block) {
+	for _, act := range blk.Actions {
+		switch act := act.(type) {
+		case *subchain.StartSubChain:
+			if act.ChainID() == m.rootChain.ChainID() {
+				logger.Warn().Uint32("chainID", act.ChainID()).Msg("Sub-chain cannot use the root chain ID")
+				continue
+			}
+			m.starts[act.ChainID()] = act
+		case *action.StopSubChain:
+			m.stops[act.ChainID()] = act.StopHeight()
+		}
+	}
+
+	for chainID, start := range m.starts {
+		if blk.Height() < start.StartHeight() {
+			continue
+		}
+		delete(m.starts, chainID)
+		if err := m.startSubChain(ctx, chainID); err != nil {
+			logger.Error().Err(err).Uint32("chainID", chainID).Msg("Failed to start sub-chain")
+		}
+	}
+	for chainID, stopHeight := range m.stops {
+		if blk.Height() < stopHeight {
+			continue
+		}
+		delete(m.stops, chainID)
+		// A sub-chain stopped before getting started will never be started
+		delete(m.starts, chainID)
+		if err := m.stopSubChain(ctx, chainID); err != nil {
+			logger.Error().Err(err).Uint32("chainID", chainID).Msg("Failed to stop sub-chain")
+		}
+	}
+}
+
+// startSubChain creates a chain service from the config template for the sub-chain, and starts it
+func (m *subChainManager) startSubChain(ctx context.Context, chainID uint32) error {
+	if m.s.ChainService(chainID) != nil {
+		logger.Info().Uint32("chainID", chainID).Msg("Sub-chain service already exists")
+		return nil
+	}
+	cfg, err := m.subChainConfig(chainID)
+	if err != nil {
+		return err
+	}
+	if err := m.s.NewChainService(cfg); err != nil {
+		m.releaseExplorerPort(chainID)
+		return errors.Wrap(err, "error when creating sub-chain service")
+	}
+	if err := m.s.StartChainService(ctx, chainID); err != nil {
+		m.s.removeChainService(chainID)
+		m.releaseExplorerPort(chainID)
+		return errors.Wrap(err, "error when starting sub-chain service")
+	}
+	logger.Info().Uint32("chainID", chainID).Msg("Started sub-chain service")
+	return nil
+}
+
+// stopSubChain stops the chain service of the sub-chain and removes it from the server
+func (m *subChainManager) stopSubChain(ctx context.Context, chainID uint32) error {
+	if m.s.ChainService(chainID) == nil {
+		return nil
+	}
+	if err := m.s.StopChainService(ctx, chainID); err != nil {
+		return errors.Wrap(err, "error when stopping sub-chain service")
+	}
+	m.s.removeChainService(chainID)
+	m.releaseExplorerPort(chainID)
+	logger.Info().Uint32("chainID", chainID).Msg("Stopped sub-chain service")
+	return nil
+}
+
+// subChainConfig loads the config template and specializes it with the chain ID and the data directory of the
+// sub-chain
+func (m *subChainManager) subChainConfig(chainID uint32) (*config.Config, error) {
+	cfg, err := config.NewSubFromPath(m.cfg.ConfigTemplatePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when loading sub-chain config template")
+	}
+	dataDir := filepath.Join(m.cfg.DataDir, strconv.FormatUint(uint64(chainID), 10))
+	if err := os.MkdirAll(dataDir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "error when creating sub-chain data dir %s", dataDir)
+	}
+	cfg.Chain.ID = chainID
+	cfg.Chain.ChainDBPath = filepath.Join(dataDir, "chain.db")
+	cfg.Chain.TrieDBPath = filepath.Join(dataDir, "trie.db")
+	cfg.Consensus.RollDPoS.StateDBPath = filepath.Join(dataDir, "rolldpos.db")
+	cfg.Consensus.PoA.CheckpointDBPath = filepath.Join(dataDir, "poa.db")
+	if cfg.Explorer.Enabled {
+		// Sub-chains created from the same template would otherwise collide on the explorer port
+		if cfg.Explorer.Port, err = m.allocExplorerPort(chainID); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// allocExplorerPort allocates the lowest free port in the configured range to the explorer of the sub-chain
+func (m *subChainManager) allocExplorerPort(chainID uint32) (int, error) {
+	if port, ok := m.ports[chainID]; ok {
+		return port, nil
+	}
+	used := make(map[int]bool, len(m.ports))
+	for _, port := range m.ports {
+		used[port] = true
+	}
+	for port := m.cfg.ExplorerPortStart; port <= m.cfg.ExplorerPortEnd; port++ {
+		if m.reservedPorts[port] || used[port] {
+			continue
+		}
+		m.ports[chainID] = port
+		return port, nil
+	}
+	return 0, errors.Errorf(
+		"no free explorer port in [%d, %d] for sub-chain %d",
+		m.cfg.ExplorerPortStart,
+		m.cfg.ExplorerPortEnd,
+		chainID,
+	)
+}
+
+// releaseExplorerPort releases the explorer port allocated to the sub-chain
+func (m *subChainManager) releaseExplorerPort(chainID uint32) {
+	delete(m.ports, chainID)
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package itx
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/subchain"
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/chainservice"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
+	"github.com/iotexproject/iotex-core/test/testaddress"
+)
+
+func TestSubChainManager_StartStop(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := config.Default
+	bc := mock_blockchain.NewMockBlockchain(ctrl)
+	bc.EXPECT().SubscribeBlockCreation(gomock.Any()).Return(nil).Times(1)
+	bc.EXPECT().UnSubscribeBlockCreation(gomock.Any()).Return(nil).Times(1)
+	bc.EXPECT().TipHeight().Return(uint64(0)).AnyTimes()
+	m := newSubChainManager(&Server{chainservices: make(map[uint32]*chainservice.ChainService)}, &cfg, bc)
+
+	require.NoError(m.Start(context.Background()))
+	for i := 0; i < 3; i++ {
+		m.blockCh <- &blockchain.Block{}
+	}
+	require.NoError(m.Stop(context.Background()))
+
+	// The notifications sent after stopping don't block the senders
+	for i := 0; i < blockChSize; i++ {
+		select {
+		case m.blockCh <- &blockchain.Block{}:
+		default:
+			require.Fail("block notification is blocked after stopping")
+		}
+	}
+}
+
+func TestSubChainManager_HandleBlock(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := config.Default
+	bc := mock_blockchain.NewMockBlockchain(ctrl)
+	bc.EXPECT().ChainID().Return(uint32(1)).AnyTimes()
+	m := newSubChainManager(&Server{chainservices: make(map[uint32]*chainservice.ChainService)}, &cfg, bc)
+
+	owner := testaddress.Addrinfo["producer"].RawAddress
+	start2 := subchain.NewStartSubChain(1, 2, owner, big.NewInt(0), big.NewInt(0), 3, 0, 0, big.NewInt(0))
+	startRoot := subchain.NewStartSubChain(2, 1, owner, big.NewInt(0), big.NewInt(0), 1, 0, 0, big.NewInt(0))
+	stop3, err := action.NewStopSubChain(owner, 3, 3, "", 2, 0, big.NewInt(0))
+	require.NoError(err)
+	blk := blockchain.NewBlock(1, 1, hash.ZeroHash32B, 0, nil, nil, nil, []action.Action{start2, startRoot, stop3})
+	m.handleBlock(context.Background(), blk)
+	// The sub-chains wait for their start and stop heights, and the root chain ID is never used by a sub-chain
+	require.Equal(map[uint32]*subchain.StartSubChain{2: start2}, m.starts)
+	require.Equal(map[uint32]uint64{3: 2}, m.stops)
+
+	// A sub-chain stopped before reaching its start height is never started
+	stop2, err := action.NewStopSubChain(owner, 4, 2, "", 2, 0, big.NewInt(0))
+	require.NoError(err)
+	blk = blockchain.NewBlock(1, 2, blk.HashBlock(), 0, nil, nil, nil, []action.Action{stop2})
+	m.handleBlock(context.Background(), blk)
+	require.Equal(0, len(m.starts))
+	require.Equal(0, len(m.stops))
+}
+
+func TestSubChainManager_AllocExplorerPort(t *testing.T) {
+	require := require.New(t)
+
+	cfg := config.Default
+	cfg.Network.Port = 14005
+	cfg.Explorer.Port = 14004
+	cfg.SubChain.ExplorerPortStart = 14003
+	cfg.SubChain.ExplorerPortEnd = 14006
+	m := newSubChainManager(&Server{}, &cfg, nil)
+
+	// The ports of the root chain are skipped
+	port, err := m.allocExplorerPort(2)
+	require.NoError(err)
+	require.Equal(14003, port)
+	port, err = m.allocExplorerPort(2)
+	require.NoError(err)
+	require.Equal(14003, port)
+	port, err = m.allocExplorerPort(3)
+	require.NoError(err)
+	require.Equal(14006, port)
+	_, err = m.allocExplorerPort(4)
+	require.Error(err)
+
+	// The released port is allocated again
+	m.releaseExplorerPort(2)
+	port, err = m.allocExplorerPort(4)
+	require.NoError(err)
+	require.Equal(14003, port)
+}
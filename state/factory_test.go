@@ -16,10 +16,13 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
 
 	"github.com/iotexproject/iotex-core/blockchain/action"
 	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/crypto"
 	"github.com/iotexproject/iotex-core/db"
 	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/pkg/hash"
@@ -711,6 +714,82 @@ func TestUnvote(t *testing.T) {
 	require.True(t, compareStrings(voteForm(sf.candidates()), []string{b.RawAddress + ":200"}))
 }
 
+func TestSlash(t *testing.T) {
+	a := testaddress.Addrinfo["alfa"]
+	b := testaddress.Addrinfo["bravo"]
+	c := testaddress.Addrinfo["charlie"]
+
+	testutil.CleanupPath(t, testTriePath)
+	defer testutil.CleanupPath(t, testTriePath)
+
+	cfg.Chain.NumCandidates = 2
+	f, err := NewFactory(cfg, PrecreatedTrieDBOption(db.NewBoltDB(testTriePath, &cfg.DB)))
+	require.NoError(t, err)
+	sf, ok := f.(*factory)
+	require.True(t, ok)
+
+	_, err = sf.LoadOrCreateState(a.RawAddress, uint64(100))
+	require.NoError(t, err)
+	_, err = sf.LoadOrCreateState(b.RawAddress, uint64(200))
+	require.NoError(t, err)
+
+	vote1, err := action.NewVote(0, a.RawAddress, a.RawAddress, uint64(100000), big.NewInt(10))
+	vote1.SetVoterPublicKey(a.PublicKey)
+	require.NoError(t, err)
+	vote2, err := action.NewVote(0, b.RawAddress, a.RawAddress, uint64(100000), big.NewInt(10))
+	vote2.SetVoterPublicKey(b.PublicKey)
+	require.NoError(t, err)
+	_, err = sf.RunActions(0, []*action.Transfer{}, []*action.Vote{vote1, vote2}, []*action.Execution{}, nil)
+	require.Nil(t, err)
+	require.Nil(t, sf.Commit(nil))
+	require.True(t, compareStrings(voteForm(sf.candidates()), []string{a.RawAddress + ":300"}))
+
+	signEndorse := func(blkHash hash.Hash32B) *action.SignedEndorse {
+		hash := blake2b.Sum256(action.EndorseByteStream(5, true, blkHash, true))
+		return &action.SignedEndorse{
+			Height:         5,
+			Commit:         true,
+			BlkHash:        blkHash,
+			Decision:       true,
+			Endorser:       a.RawAddress,
+			EndorserPubkey: a.PublicKey,
+			Signature:      crypto.EC283.Sign(a.PrivateKey, hash[:]),
+		}
+	}
+	// Evidence which doesn't prove a double sign is rejected
+	evidence, err := action.NewDoubleSignEvidence(
+		c.RawAddress, 1, signEndorse(hash.Hash32B{1}), signEndorse(hash.Hash32B{1}), 0, big.NewInt(0))
+	require.NoError(t, err)
+	_, err = sf.RunActions(0, []*action.Transfer{}, []*action.Vote{}, []*action.Execution{}, []action.Action{evidence})
+	require.Equal(t, action.ErrEvidence, errors.Cause(err))
+
+	// The offender loses the candidacy, and can't nominate itself again
+	evidence, err = action.NewDoubleSignEvidence(
+		c.RawAddress, 1, signEndorse(hash.Hash32B{1}), signEndorse(hash.Hash32B{2}), 0, big.NewInt(0))
+	require.NoError(t, err)
+	_, err = sf.RunActions(0, []*action.Transfer{}, []*action.Vote{}, []*action.Execution{}, []action.Action{evidence})
+	require.Nil(t, err)
+	require.Nil(t, sf.Commit(nil))
+	require.True(t, compareStrings(voteForm(sf.candidates()), []string{}))
+	state, err := sf.State(a.RawAddress)
+	require.NoError(t, err)
+	require.True(t, state.Slashed)
+	nonce, err := sf.Nonce(c.RawAddress)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), nonce)
+
+	vote3, err := action.NewVote(1, a.RawAddress, a.RawAddress, uint64(100000), big.NewInt(10))
+	vote3.SetVoterPublicKey(a.PublicKey)
+	require.NoError(t, err)
+	vote4, err := action.NewVote(0, c.RawAddress, c.RawAddress, uint64(100000), big.NewInt(10))
+	vote4.SetVoterPublicKey(c.PublicKey)
+	require.NoError(t, err)
+	_, err = sf.RunActions(0, []*action.Transfer{}, []*action.Vote{vote3, vote4}, []*action.Execution{}, nil)
+	require.Nil(t, err)
+	require.Nil(t, sf.Commit(nil))
+	require.True(t, compareStrings(voteForm(sf.candidates()), []string{c.RawAddress + ":0"}))
+}
+
 func TestLoadStoreHeight(t *testing.T) {
 	require := require.New(t)
 
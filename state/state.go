@@ -26,6 +26,8 @@ type State struct {
 	VotingWeight *big.Int
 	Votee        string
 	Voters       map[string]*big.Int
+	// Slashed indicates that the account has double signed as a delegate, and can't be a candidate anymore
+	Slashed bool
 }
 
 func stateToBytes(s *State) ([]byte, error) {
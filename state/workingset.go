@@ -200,6 +200,12 @@ func (ws *workingSet) RunActions(
 	if err := ws.handleVote(blockHeight, vote); err != nil {
 		return hash.ZeroHash32B, errors.Wrap(err, "failed to handle votes")
 	}
+	if err := ws.handleEvidence(actions); err != nil {
+		return hash.ZeroHash32B, errors.Wrap(err, "failed to handle double sign evidences")
+	}
+	if err := ws.handleSignerVotes(actions); err != nil {
+		return hash.ZeroHash32B, errors.Wrap(err, "failed to handle signer votes")
+	}
 
 	// update pending state changes to trie
 	for addr, state := range ws.cachedAccount {
@@ -451,7 +457,7 @@ func (ws *workingSet) getCandidates(height uint64) (CandidateList, error) {
 }
 
 //======================================
-// private transfer/vote functions
+// private transfer/vote/evidence functions
 //======================================
 func (ws *workingSet) handleTsf(tsf []*action.Transfer) error {
 	for _, tx := range tsf {
@@ -559,6 +565,10 @@ func (ws *workingSet) handleVote(blockHeight uint64, vote []*action.Vote) error
 		} else {
 			// Vote to self: self-nomination or cancel the previous vote case
 			voteFrom.Votee = v.Voter()
+			if voteFrom.Slashed {
+				// A slashed account can't nominate itself again
+				continue
+			}
 			voteFrom.IsCandidate = true
 			pkHash, err := iotxaddress.GetPubkeyHash(v.Voter())
 			if err != nil {
@@ -577,3 +587,58 @@ func (ws *workingSet) handleVote(blockHeight uint64, vote []*action.Vote) error
 	}
 	return nil
 }
+
+func (ws *workingSet) handleEvidence(actions []action.Action) error {
+	for _, act := range actions {
+		evidence, ok := act.(*action.DoubleSignEvidence)
+		if !ok {
+			continue
+		}
+		if err := evidence.Verify(); err != nil {
+			return errors.Wrapf(err, "failed to verify the evidence %x", evidence.Hash())
+		}
+		reporter, err := ws.LoadOrCreateState(evidence.Reporter(), 0)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load or create the state of reporter %s", evidence.Reporter())
+		}
+		// save state before modifying
+		ws.saveState(evidence.Reporter(), reporter)
+		// update reporter Nonce
+		if evidence.Nonce() > reporter.Nonce {
+			reporter.Nonce = evidence.Nonce()
+		}
+		offender, err := ws.LoadOrCreateState(evidence.Offender(), 0)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load or create the state of offender %s", evidence.Offender())
+		}
+		// save state before modifying
+		ws.saveState(evidence.Offender(), offender)
+		// The offender loses the candidacy for good, so that neither its own balance nor the votes to it count as
+		// the voting weight anymore. Slashing the same offender again changes nothing.
+		offender.IsCandidate = false
+		offender.Slashed = true
+	}
+	return nil
+}
+
+// handleSignerVotes only updates the nonces of the voters, because the signer set of proof of authority is tallied by
+// the consensus from the votes on the chain
+func (ws *workingSet) handleSignerVotes(actions []action.Action) error {
+	for _, act := range actions {
+		vote, ok := act.(*action.SignerVote)
+		if !ok {
+			continue
+		}
+		voter, err := ws.LoadOrCreateState(vote.Voter(), 0)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load or create the state of voter %s", vote.Voter())
+		}
+		// save state before modifying
+		ws.saveState(vote.Voter(), voter)
+		// update voter Nonce
+		if vote.Nonce() > voter.Nonce {
+			voter.Nonce = vote.Nonce()
+		}
+	}
+	return nil
+}
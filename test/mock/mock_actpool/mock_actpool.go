@@ -5,6 +5,7 @@
 package mock_actpool
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 	action "github.com/iotexproject/iotex-core/blockchain/action"
 	hash "github.com/iotexproject/iotex-core/pkg/hash"
@@ -34,6 +35,30 @@ func (m *MockActPool) EXPECT() *MockActPoolMockRecorder {
 	return m.recorder
 }
 
+// Start mocks base method
+func (m *MockActPool) Start(arg0 context.Context) error {
+	ret := m.ctrl.Call(m, "Start", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start
+func (mr *MockActPoolMockRecorder) Start(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockActPool)(nil).Start), arg0)
+}
+
+// Stop mocks base method
+func (m *MockActPool) Stop(arg0 context.Context) error {
+	ret := m.ctrl.Call(m, "Stop", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stop indicates an expected call of Stop
+func (mr *MockActPoolMockRecorder) Stop(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockActPool)(nil).Stop), arg0)
+}
+
 // Reset mocks base method
 func (m *MockActPool) Reset() {
 	m.ctrl.Call(m, "Reset")
@@ -59,6 +84,21 @@ func (mr *MockActPoolMockRecorder) PickActs() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PickActs", reflect.TypeOf((*MockActPool)(nil).PickActs))
 }
 
+// PickActsWithLimit mocks base method
+func (m *MockActPool) PickActsWithLimit(limit uint64) ([]*action.Transfer, []*action.Vote, []*action.Execution, []action.Action) {
+	ret := m.ctrl.Call(m, "PickActsWithLimit", limit)
+	ret0, _ := ret[0].([]*action.Transfer)
+	ret1, _ := ret[1].([]*action.Vote)
+	ret2, _ := ret[2].([]*action.Execution)
+	ret3, _ := ret[3].([]action.Action)
+	return ret0, ret1, ret2, ret3
+}
+
+// PickActsWithLimit indicates an expected call of PickActsWithLimit
+func (mr *MockActPoolMockRecorder) PickActsWithLimit(limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PickActsWithLimit", reflect.TypeOf((*MockActPool)(nil).PickActsWithLimit), limit)
+}
+
 // AddTsf mocks base method
 func (m *MockActPool) AddTsf(tsf *action.Transfer) error {
 	ret := m.ctrl.Call(m, "AddTsf", tsf)
@@ -450,16 +450,16 @@ func (mr *MockBlockchainMockRecorder) MintNewBlock(tsf, vote, executions, action
 }
 
 // MintNewDKGBlock mocks base method
-func (m *MockBlockchain) MintNewDKGBlock(tsf []*action.Transfer, vote []*action.Vote, executions []*action.Execution, actions []action.Action, producer *iotxaddress.Address, dkgAddress *iotxaddress.DKGAddress, seed []byte, data string) (*blockchain.Block, error) {
-	ret := m.ctrl.Call(m, "MintNewDKGBlock", tsf, vote, executions, actions, producer, dkgAddress, seed, data)
+func (m *MockBlockchain) MintNewDKGBlock(ctx context.Context, tsf []*action.Transfer, vote []*action.Vote, executions []*action.Execution, actions []action.Action, producer *iotxaddress.Address, dkgAddress *iotxaddress.DKGAddress, seed []byte, data string) (*blockchain.Block, error) {
+	ret := m.ctrl.Call(m, "MintNewDKGBlock", ctx, tsf, vote, executions, actions, producer, dkgAddress, seed, data)
 	ret0, _ := ret[0].(*blockchain.Block)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // MintNewDKGBlock indicates an expected call of MintNewDKGBlock
-func (mr *MockBlockchainMockRecorder) MintNewDKGBlock(tsf, vote, executions, actions, producer, dkgAddress, seed, data interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MintNewDKGBlock", reflect.TypeOf((*MockBlockchain)(nil).MintNewDKGBlock), tsf, vote, executions, actions, producer, dkgAddress, seed, data)
+func (mr *MockBlockchainMockRecorder) MintNewDKGBlock(ctx, tsf, vote, executions, actions, producer, dkgAddress, seed, data interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MintNewDKGBlock", reflect.TypeOf((*MockBlockchain)(nil).MintNewDKGBlock), ctx, tsf, vote, executions, actions, producer, dkgAddress, seed, data)
 }
 
 // MintNewSecretBlock mocks base method
@@ -511,6 +511,18 @@ func (mr *MockBlockchainMockRecorder) ValidateBlock(blk, containCoinbase interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateBlock", reflect.TypeOf((*MockBlockchain)(nil).ValidateBlock), blk, containCoinbase)
 }
 
+// VerifyHeader mocks base method
+func (m *MockBlockchain) VerifyHeader(blk *blockchain.Block) error {
+	ret := m.ctrl.Call(m, "VerifyHeader", blk)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyHeader indicates an expected call of VerifyHeader
+func (mr *MockBlockchainMockRecorder) VerifyHeader(blk interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyHeader", reflect.TypeOf((*MockBlockchain)(nil).VerifyHeader), blk)
+}
+
 // Validator mocks base method
 func (m *MockBlockchain) Validator() blockchain.Validator {
 	ret := m.ctrl.Call(m, "Validator")
@@ -533,6 +545,26 @@ func (mr *MockBlockchainMockRecorder) SetValidator(val interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetValidator", reflect.TypeOf((*MockBlockchain)(nil).SetValidator), val)
 }
 
+// SetEndorsersFunc mocks base method
+func (m *MockBlockchain) SetEndorsersFunc(f blockchain.EndorsersFunc) {
+	m.ctrl.Call(m, "SetEndorsersFunc", f)
+}
+
+// SetEndorsersFunc indicates an expected call of SetEndorsersFunc
+func (mr *MockBlockchainMockRecorder) SetEndorsersFunc(f interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEndorsersFunc", reflect.TypeOf((*MockBlockchain)(nil).SetEndorsersFunc), f)
+}
+
+// SetAggregateVerifierFunc mocks base method
+func (m *MockBlockchain) SetAggregateVerifierFunc(f blockchain.AggregateVerifierFunc) {
+	m.ctrl.Call(m, "SetAggregateVerifierFunc", f)
+}
+
+// SetAggregateVerifierFunc indicates an expected call of SetAggregateVerifierFunc
+func (mr *MockBlockchainMockRecorder) SetAggregateVerifierFunc(f interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAggregateVerifierFunc", reflect.TypeOf((*MockBlockchain)(nil).SetAggregateVerifierFunc), f)
+}
+
 // ExecuteContractRead mocks base method
 func (m *MockBlockchain) ExecuteContractRead(arg0 *action.Execution) ([]byte, error) {
 	ret := m.ctrl.Call(m, "ExecuteContractRead", arg0)
@@ -8,8 +8,10 @@ import (
 	context "context"
 	gomock "github.com/golang/mock/gomock"
 	blockchain "github.com/iotexproject/iotex-core/blockchain"
+	blocksync "github.com/iotexproject/iotex-core/blocksync"
 	network "github.com/iotexproject/iotex-core/network"
 	proto "github.com/iotexproject/iotex-core/proto"
+	net "net"
 	reflect "reflect"
 )
 
@@ -97,13 +99,61 @@ func (mr *MockBlockSyncMockRecorder) ProcessBlock(blk interface{}) *gomock.Call
 }
 
 // ProcessBlockSync mocks base method
-func (m *MockBlockSync) ProcessBlockSync(blk *blockchain.Block) error {
-	ret := m.ctrl.Call(m, "ProcessBlockSync", blk)
+func (m *MockBlockSync) ProcessBlockSync(sender net.Addr, blk *blockchain.Block) error {
+	ret := m.ctrl.Call(m, "ProcessBlockSync", sender, blk)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ProcessBlockSync indicates an expected call of ProcessBlockSync
-func (mr *MockBlockSyncMockRecorder) ProcessBlockSync(blk interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessBlockSync", reflect.TypeOf((*MockBlockSync)(nil).ProcessBlockSync), blk)
+func (mr *MockBlockSyncMockRecorder) ProcessBlockSync(sender, blk interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessBlockSync", reflect.TypeOf((*MockBlockSync)(nil).ProcessBlockSync), sender, blk)
+}
+
+// ProcessChainTip mocks base method
+func (m *MockBlockSync) ProcessChainTip(sender string, tip *proto.ChainTip) error {
+	ret := m.ctrl.Call(m, "ProcessChainTip", sender, tip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessChainTip indicates an expected call of ProcessChainTip
+func (mr *MockBlockSyncMockRecorder) ProcessChainTip(sender, tip interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessChainTip", reflect.TypeOf((*MockBlockSync)(nil).ProcessChainTip), sender, tip)
+}
+
+// ProcessHeaderSyncRequest mocks base method
+func (m *MockBlockSync) ProcessHeaderSyncRequest(sender string, sync *proto.BlockHeaderSync) error {
+	ret := m.ctrl.Call(m, "ProcessHeaderSyncRequest", sender, sync)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessHeaderSyncRequest indicates an expected call of ProcessHeaderSyncRequest
+func (mr *MockBlockSyncMockRecorder) ProcessHeaderSyncRequest(sender, sync interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessHeaderSyncRequest", reflect.TypeOf((*MockBlockSync)(nil).ProcessHeaderSyncRequest), sender, sync)
+}
+
+// ProcessBlockHeaders mocks base method
+func (m *MockBlockSync) ProcessBlockHeaders(sender net.Addr, headers []*blockchain.Block) error {
+	ret := m.ctrl.Call(m, "ProcessBlockHeaders", sender, headers)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessBlockHeaders indicates an expected call of ProcessBlockHeaders
+func (mr *MockBlockSyncMockRecorder) ProcessBlockHeaders(sender, headers interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessBlockHeaders", reflect.TypeOf((*MockBlockSync)(nil).ProcessBlockHeaders), sender, headers)
+}
+
+// SyncStatus mocks base method
+func (m *MockBlockSync) SyncStatus() blocksync.SyncStatus {
+	ret := m.ctrl.Call(m, "SyncStatus")
+	ret0, _ := ret[0].(blocksync.SyncStatus)
+	return ret0
+}
+
+// SyncStatus indicates an expected call of SyncStatus
+func (mr *MockBlockSyncMockRecorder) SyncStatus() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncStatus", reflect.TypeOf((*MockBlockSync)(nil).SyncStatus))
 }
@@ -62,15 +62,15 @@ func (mr *MockSubscriberMockRecorder) HandleBlock(arg0 interface{}) *gomock.Call
 }
 
 // HandleBlockSync mocks base method
-func (m *MockSubscriber) HandleBlockSync(arg0 *proto0.BlockPb) error {
-	ret := m.ctrl.Call(m, "HandleBlockSync", arg0)
+func (m *MockSubscriber) HandleBlockSync(arg0 net.Addr, arg1 *proto0.BlockPb) error {
+	ret := m.ctrl.Call(m, "HandleBlockSync", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // HandleBlockSync indicates an expected call of HandleBlockSync
-func (mr *MockSubscriberMockRecorder) HandleBlockSync(arg0 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleBlockSync", reflect.TypeOf((*MockSubscriber)(nil).HandleBlockSync), arg0)
+func (mr *MockSubscriberMockRecorder) HandleBlockSync(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleBlockSync", reflect.TypeOf((*MockSubscriber)(nil).HandleBlockSync), arg0, arg1)
 }
 
 // HandleSyncRequest mocks base method
@@ -85,6 +85,42 @@ func (mr *MockSubscriberMockRecorder) HandleSyncRequest(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleSyncRequest", reflect.TypeOf((*MockSubscriber)(nil).HandleSyncRequest), arg0, arg1)
 }
 
+// HandleChainTip mocks base method
+func (m *MockSubscriber) HandleChainTip(arg0 string, arg1 *proto0.ChainTip) error {
+	ret := m.ctrl.Call(m, "HandleChainTip", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleChainTip indicates an expected call of HandleChainTip
+func (mr *MockSubscriberMockRecorder) HandleChainTip(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleChainTip", reflect.TypeOf((*MockSubscriber)(nil).HandleChainTip), arg0, arg1)
+}
+
+// HandleHeaderSyncRequest mocks base method
+func (m *MockSubscriber) HandleHeaderSyncRequest(arg0 string, arg1 *proto0.BlockHeaderSync) error {
+	ret := m.ctrl.Call(m, "HandleHeaderSyncRequest", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleHeaderSyncRequest indicates an expected call of HandleHeaderSyncRequest
+func (mr *MockSubscriberMockRecorder) HandleHeaderSyncRequest(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleHeaderSyncRequest", reflect.TypeOf((*MockSubscriber)(nil).HandleHeaderSyncRequest), arg0, arg1)
+}
+
+// HandleBlockHeaders mocks base method
+func (m *MockSubscriber) HandleBlockHeaders(arg0 net.Addr, arg1 *proto0.BlockHeaderContainer) error {
+	ret := m.ctrl.Call(m, "HandleBlockHeaders", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleBlockHeaders indicates an expected call of HandleBlockHeaders
+func (mr *MockSubscriberMockRecorder) HandleBlockHeaders(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleBlockHeaders", reflect.TypeOf((*MockSubscriber)(nil).HandleBlockHeaders), arg0, arg1)
+}
+
 // HandleBlockPropose mocks base method
 func (m *MockSubscriber) HandleBlockPropose(arg0 *proto0.ProposePb) error {
 	ret := m.ctrl.Call(m, "HandleBlockPropose", arg0)
@@ -109,6 +145,39 @@ func (mr *MockSubscriberMockRecorder) HandleEndorse(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleEndorse", reflect.TypeOf((*MockSubscriber)(nil).HandleEndorse), arg0)
 }
 
+// MockPeerReporter is a mock of PeerReporter interface
+type MockPeerReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockPeerReporterMockRecorder
+}
+
+// MockPeerReporterMockRecorder is the mock recorder for MockPeerReporter
+type MockPeerReporterMockRecorder struct {
+	mock *MockPeerReporter
+}
+
+// NewMockPeerReporter creates a new mock instance
+func NewMockPeerReporter(ctrl *gomock.Controller) *MockPeerReporter {
+	mock := &MockPeerReporter{ctrl: ctrl}
+	mock.recorder = &MockPeerReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPeerReporter) EXPECT() *MockPeerReporterMockRecorder {
+	return m.recorder
+}
+
+// ReportPeer mocks base method
+func (m *MockPeerReporter) ReportPeer(sender net.Addr, msgType uint32, err error) {
+	m.ctrl.Call(m, "ReportPeer", sender, msgType, err)
+}
+
+// ReportPeer indicates an expected call of ReportPeer
+func (mr *MockPeerReporterMockRecorder) ReportPeer(sender, msgType, err interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportPeer", reflect.TypeOf((*MockPeerReporter)(nil).ReportPeer), sender, msgType, err)
+}
+
 // MockDispatcher is a mock of Dispatcher interface
 type MockDispatcher struct {
 	ctrl     *gomock.Controller
@@ -166,14 +235,34 @@ func (mr *MockDispatcherMockRecorder) AddSubscriber(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSubscriber", reflect.TypeOf((*MockDispatcher)(nil).AddSubscriber), arg0, arg1)
 }
 
+// RemoveSubscriber mocks base method
+func (m *MockDispatcher) RemoveSubscriber(arg0 uint32) {
+	m.ctrl.Call(m, "RemoveSubscriber", arg0)
+}
+
+// RemoveSubscriber indicates an expected call of RemoveSubscriber
+func (mr *MockDispatcherMockRecorder) RemoveSubscriber(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSubscriber", reflect.TypeOf((*MockDispatcher)(nil).RemoveSubscriber), arg0)
+}
+
+// AttachPeerReporter mocks base method
+func (m *MockDispatcher) AttachPeerReporter(arg0 dispatcher.PeerReporter) {
+	m.ctrl.Call(m, "AttachPeerReporter", arg0)
+}
+
+// AttachPeerReporter indicates an expected call of AttachPeerReporter
+func (mr *MockDispatcherMockRecorder) AttachPeerReporter(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachPeerReporter", reflect.TypeOf((*MockDispatcher)(nil).AttachPeerReporter), arg0)
+}
+
 // HandleBroadcast mocks base method
-func (m *MockDispatcher) HandleBroadcast(arg0 uint32, arg1 proto.Message, arg2 chan bool) {
-	m.ctrl.Call(m, "HandleBroadcast", arg0, arg1, arg2)
+func (m *MockDispatcher) HandleBroadcast(arg0 uint32, arg1 net.Addr, arg2 proto.Message, arg3 chan bool) {
+	m.ctrl.Call(m, "HandleBroadcast", arg0, arg1, arg2, arg3)
 }
 
 // HandleBroadcast indicates an expected call of HandleBroadcast
-func (mr *MockDispatcherMockRecorder) HandleBroadcast(arg0, arg1, arg2 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleBroadcast", reflect.TypeOf((*MockDispatcher)(nil).HandleBroadcast), arg0, arg1, arg2)
+func (mr *MockDispatcherMockRecorder) HandleBroadcast(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleBroadcast", reflect.TypeOf((*MockDispatcher)(nil).HandleBroadcast), arg0, arg1, arg2, arg3)
 }
 
 // HandleTell mocks base method
@@ -106,3 +106,25 @@ func (m *MockOverlay) GetPeers() []net.Addr {
 func (mr *MockOverlayMockRecorder) GetPeers() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPeers", reflect.TypeOf((*MockOverlay)(nil).GetPeers))
 }
+
+// PeerScore mocks base method
+func (m *MockOverlay) PeerScore(arg0 net.Addr) int {
+	ret := m.ctrl.Call(m, "PeerScore", arg0)
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// PeerScore indicates an expected call of PeerScore
+func (mr *MockOverlayMockRecorder) PeerScore(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PeerScore", reflect.TypeOf((*MockOverlay)(nil).PeerScore), arg0)
+}
+
+// ReportPeer mocks base method
+func (m *MockOverlay) ReportPeer(arg0 net.Addr, arg1 uint32, arg2 error) {
+	m.ctrl.Call(m, "ReportPeer", arg0, arg1, arg2)
+}
+
+// ReportPeer indicates an expected call of ReportPeer
+func (mr *MockOverlayMockRecorder) ReportPeer(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportPeer", reflect.TypeOf((*MockOverlay)(nil).ReportPeer), arg0, arg1, arg2)
+}
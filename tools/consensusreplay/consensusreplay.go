@@ -0,0 +1,101 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// This is a debugging tool to replay the consensus journal of a node, and print the state transitions of the consensus
+// FSM. The config should be the one of the node, with the chain, trie and consensus state DB paths pointing to copies
+// of them taken when the journal starts, because the blocks agreed on are committed to the chain again.
+// To use, run "make build" and "./bin/consensusreplay -config-path=<node config> -journal=<journal file>"
+package main
+
+import (
+	"context"
+	"flag"
+	"net"
+	"os"
+
+	"github.com/facebookgo/clock"
+	"github.com/golang/protobuf/proto"
+
+	"github.com/iotexproject/iotex-core/actpool"
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/consensus"
+	"github.com/iotexproject/iotex-core/consensus/scheme/rolldpos"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/logger"
+)
+
+// nopOverlay drops the messages broadcast by the consensus FSM in replay
+type nopOverlay struct{}
+
+func (o *nopOverlay) Start(context.Context) error                { return nil }
+func (o *nopOverlay) Stop(context.Context) error                 { return nil }
+func (o *nopOverlay) Broadcast(uint32, proto.Message) error      { return nil }
+func (o *nopOverlay) Tell(uint32, net.Addr, proto.Message) error { return nil }
+func (o *nopOverlay) Self() net.Addr                             { return nil }
+func (o *nopOverlay) GetPeers() []net.Addr                       { return nil }
+func (o *nopOverlay) PeerScore(net.Addr) int                     { return 0 }
+func (o *nopOverlay) ReportPeer(net.Addr, uint32, error)         {}
+
+func main() {
+	// journalPath is the path of the journal file to replay
+	var journalPath string
+	flag.StringVar(&journalPath, "journal", "", "path of the consensus journal to replay")
+	flag.Parse()
+	if journalPath == "" {
+		logger.Fatal().Msg("the journal path is not specified")
+	}
+
+	cfg, err := config.New()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to load the config")
+	}
+	ctx := context.Background()
+	chain := blockchain.NewBlockchain(cfg, blockchain.DefaultStateFactoryOption(), blockchain.BoltDBDaoOption())
+	if chain == nil {
+		logger.Fatal().Msg("Failed to create the blockchain")
+	}
+	if err := chain.Start(ctx); err != nil {
+		logger.Fatal().Err(err).Msg("Failed to start the blockchain")
+	}
+	defer func() {
+		if err := chain.Stop(ctx); err != nil {
+			logger.Error().Err(err).Msg("Failed to stop the blockchain")
+		}
+	}()
+	ap, err := actpool.NewActPool(chain, cfg.ActPool)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to create the actpool")
+	}
+
+	bd := rolldpos.NewRollDPoSBuilder().
+		SetAddr(consensus.GetAddr(cfg)).
+		SetConfig(cfg.Consensus.RollDPoS).
+		SetBlockchain(chain).
+		SetActPool(ap).
+		SetClock(clock.NewMock()).
+		SetP2P(&nopOverlay{})
+	if cfg.Consensus.RollDPoS.StateDBPath != "" {
+		bd = bd.SetStateDB(db.NewBoltDB(cfg.Consensus.RollDPoS.StateDBPath, &cfg.DB))
+	}
+	r, err := bd.Build()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to create the RollDPoS")
+	}
+
+	journal, err := os.Open(journalPath)
+	if err != nil {
+		logger.Fatal().Err(err).Str("path", journalPath).Msg("Failed to open the journal")
+	}
+	defer journal.Close()
+	mismatches, err := r.Replay(ctx, journal, os.Stdout)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to replay the journal")
+	}
+	if mismatches > 0 {
+		logger.Warn().Int("mismatches", mismatches).Msg("Replayed state transitions differ from the journaled ones")
+	}
+}
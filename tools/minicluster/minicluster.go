@@ -61,6 +61,7 @@ func main() {
 		explorerPort := 14004 + i
 		config := newConfig(genesisConfigPath, chainDBPath, trieDBPath, chainAddrs[i].PublicKey,
 			chainAddrs[i].PrivateKey, networkPort, explorerPort)
+		config.Consensus.RollDPoS.StateDBPath = fmt.Sprintf("./rolldpos%d.db", i+1)
 		configs[i] = config
 	}
 